@@ -0,0 +1,116 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	contextCMD "github.com/okteto/okteto/cmd/context"
+	"github.com/okteto/okteto/cmd/utils"
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/okteto/okteto/pkg/types"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+)
+
+var errAdminTokenRequired = errors.New("rotating credentials requires an admin token")
+
+// RotateCredentials strips the credentials recorded in the environment records of a namespace
+func RotateCredentials(ctx context.Context) *cobra.Command {
+	var namespace string
+	cmd := &cobra.Command{
+		Use:   "rotate-credentials",
+		Short: "Strip the credentials recorded in the environment records of a namespace",
+		Long: `Strip the credentials recorded in the environment records of a namespace.
+
+The next deploy or destroy of each environment resolves the token and certificates again.
+Records that are already clean are skipped, so the command can be run again to resume a partial rotation.`,
+		Args: utils.NoArgsAccepted(""),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctxResource := &model.ContextResource{}
+			if err := ctxResource.UpdateNamespace(namespace); err != nil {
+				return err
+			}
+
+			ctxOptions := &contextCMD.ContextOptions{
+				Namespace: ctxResource.Namespace,
+				Show:      true,
+			}
+			if err := contextCMD.NewContextCommand().Run(ctx, ctxOptions); err != nil {
+				return err
+			}
+
+			if !okteto.IsOkteto() {
+				return oktetoErrors.ErrContextIsNotOktetoCluster
+			}
+
+			c, _, err := okteto.NewK8sClientProvider().Provide(okteto.Context().Cfg)
+			if err != nil {
+				return err
+			}
+			return rotateCredentials(ctx, okteto.Context().Namespace, c, okteto.NewOktetoClientProvider())
+		},
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "namespace of the environment records (defaults to the current namespace)")
+	return cmd
+}
+
+func rotateCredentials(ctx context.Context, namespace string, c kubernetes.Interface, cp types.OktetoClientProvider) error {
+	if err := checkAdminToken(ctx, namespace, cp); err != nil {
+		return err
+	}
+
+	oktetoLog.Spinner(fmt.Sprintf("Rotating credentials of the environments in namespace '%s'...", namespace))
+	oktetoLog.StartSpinner()
+	result, err := pipeline.RotateCredentials(ctx, namespace, c)
+	oktetoLog.StopSpinner()
+	if err != nil {
+		return err
+	}
+
+	if len(result.Failed) > 0 {
+		return oktetoErrors.UserError{
+			E:    fmt.Errorf("could not rotate the credentials of %d environment records: %s", len(result.Failed), strings.Join(result.Failed, ", ")),
+			Hint: "Run the command again to resume the rotation, records already updated are skipped",
+		}
+	}
+	oktetoLog.Success("%d environment records updated, %d already clean", result.Updated, result.Skipped)
+	return nil
+}
+
+// checkAdminToken verifies the token has the owner role in the namespace
+func checkAdminToken(ctx context.Context, namespace string, cp types.OktetoClientProvider) error {
+	oc, err := cp.Provide()
+	if err != nil {
+		return err
+	}
+	role, err := oc.User().GetTokenRole(ctx, namespace)
+	if err != nil {
+		return fmt.Errorf("could not check the permissions of the token: %w", err)
+	}
+	if role != types.TokenRoleOwner {
+		return oktetoErrors.UserError{
+			E:    errAdminTokenRequired,
+			Hint: fmt.Sprintf("Your token has the '%s' role. Log in with an administrator account and try again", role),
+		}
+	}
+	return nil
+}
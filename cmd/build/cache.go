@@ -0,0 +1,47 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"github.com/okteto/okteto/cmd/utils"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/registry"
+	"github.com/spf13/cobra"
+)
+
+// Cache groups the maintenance commands of the local caches used by the build
+func Cache() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the local caches used when building images",
+		Args:  utils.NoArgsAccepted("https://okteto.com/docs/reference/cli/#build"),
+	}
+	cmd.AddCommand(clearCache(registry.ClearDigestCache))
+	return cmd
+}
+
+func clearCache(clearDigests func() error) *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Remove the image digests cached from the registry",
+		Args:  utils.NoArgsAccepted("https://okteto.com/docs/reference/cli/#build"),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if err := clearDigests(); err != nil {
+				return err
+			}
+			oktetoLog.Success("Build cache cleared")
+			return nil
+		},
+	}
+}
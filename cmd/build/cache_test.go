@@ -0,0 +1,41 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClearCache(t *testing.T) {
+	cleared := false
+	cmd := clearCache(func() error {
+		cleared = true
+		return nil
+	})
+	cmd.SetArgs([]string{})
+
+	assert.NoError(t, cmd.Execute())
+	assert.True(t, cleared)
+}
+
+func TestClearCacheError(t *testing.T) {
+	cmd := clearCache(func() error {
+		return assert.AnError
+	})
+	cmd.SetArgs([]string{})
+
+	assert.ErrorIs(t, cmd.Execute(), assert.AnError)
+}
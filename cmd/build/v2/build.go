@@ -181,7 +181,13 @@ func (bc *OktetoBuilder) Build(ctx context.Context, options *types.BuildOptions)
 				return fmt.Errorf("'build.%s.image' is required if your cluster doesn't have Okteto installed", svcToBuild)
 			}
 
+			if options.Renderer != nil {
+				options.Renderer.Start(svcToBuild)
+			}
 			imageTag, err := bc.buildService(ctx, options.Manifest, svcToBuild, options)
+			if options.Renderer != nil {
+				options.Renderer.Finish(svcToBuild, err)
+			}
 			if err != nil {
 				return fmt.Errorf("error building service '%s': %w", svcToBuild, err)
 			}
@@ -0,0 +1,127 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/okteto/okteto/pkg/cmd/artifacts"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	resolvedManifestArtifact = "okteto.yml"
+	appliedObjectsArtifact   = "applied-objects.json"
+	valuesArtifactsDir       = "values"
+)
+
+type artifactsUploader interface {
+	Upload(name string, content []byte) error
+}
+
+// getUploadArtifactsPolicy returns if the deploy artifacts are uploaded when the '--upload-artifacts' flag is not set
+func getUploadArtifactsPolicy(ctx context.Context) bool {
+	c, err := okteto.NewOktetoClientProvider().Provide()
+	if err != nil {
+		oktetoLog.Infof("could not get the upload artifacts policy: %s", err)
+		return false
+	}
+	metadata, err := c.User().GetClusterMetadata(ctx, okteto.Context().Namespace)
+	if err != nil {
+		oktetoLog.Infof("could not get the upload artifacts policy: %s", err)
+		return false
+	}
+	return metadata.UploadArtifacts
+}
+
+// uploadArtifacts bundles the resolved manifest, the helm values files and the objects applied by the deploy and uploads them to Okteto
+func (ld *localDeployer) uploadArtifacts(opts *Options) error {
+	secrets := append(oktetoLog.GetMaskedWords(), okteto.Context().Token)
+	bundle := artifacts.NewBundle(secrets)
+
+	manifest, err := yaml.Marshal(opts.Manifest)
+	if err != nil {
+		return fmt.Errorf("could not render the okteto manifest: %w", err)
+	}
+	bundle.Add(resolvedManifestArtifact, manifest)
+
+	for i, path := range getValuesFiles(opts.Manifest.Deploy.Commands) {
+		content, err := afero.ReadFile(ld.Fs, path)
+		if err != nil {
+			oktetoLog.Infof("could not read values file '%s': %s", path, err)
+			continue
+		}
+		bundle.Add(filepath.Join(valuesArtifactsDir, fmt.Sprintf("%d-%s", i, filepath.Base(path))), content)
+	}
+
+	if err := bundle.AddObjects(appliedObjectsArtifact, ld.Proxy.GetAppliedObjects()); err != nil {
+		return err
+	}
+
+	content, err := bundle.Compress(artifacts.MaxSize)
+	if err != nil {
+		return err
+	}
+
+	uploader := ld.artifactsUploader
+	if uploader == nil {
+		uploader, err = okteto.NewArtifactsClient(okteto.Context().Name, okteto.Context().Token, okteto.Context().Namespace)
+		if err != nil {
+			return err
+		}
+	}
+	if err := uploader.Upload(opts.Name, content); err != nil {
+		return err
+	}
+	oktetoLog.Information("Deploy artifacts uploaded to Okteto")
+	return nil
+}
+
+// getValuesFiles returns the values files passed to the helm commands of the deploy section
+func getValuesFiles(commands []model.DeployCommand) []string {
+	result := []string{}
+	for _, command := range commands {
+		args := strings.Fields(command.Command)
+		if !isHelmCommand(args) {
+			continue
+		}
+		for i, arg := range args {
+			switch {
+			case (arg == "-f" || arg == "--values") && i+1 < len(args):
+				result = append(result, strings.Trim(args[i+1], `"'`))
+			case strings.HasPrefix(arg, "--values="):
+				result = append(result, strings.Trim(strings.TrimPrefix(arg, "--values="), `"'`))
+			case strings.HasPrefix(arg, "-f="):
+				result = append(result, strings.Trim(strings.TrimPrefix(arg, "-f="), `"'`))
+			}
+		}
+	}
+	return result
+}
+
+func isHelmCommand(args []string) bool {
+	for _, arg := range args {
+		if arg == "helm" || strings.HasSuffix(arg, "/helm") {
+			return true
+		}
+	}
+	return false
+}
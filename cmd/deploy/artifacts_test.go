@@ -0,0 +1,138 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+	"testing"
+
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeArtifactsUploader struct {
+	name    string
+	content []byte
+	err     error
+}
+
+func (f *fakeArtifactsUploader) Upload(name string, content []byte) error {
+	f.name = name
+	f.content = content
+	return f.err
+}
+
+func TestGetValuesFiles(t *testing.T) {
+	commands := []model.DeployCommand{
+		{Command: "helm upgrade --install api chart -f values.yml --values=prod.yml"},
+		{Command: "/usr/local/bin/helm upgrade --install db chart --values 'db.yml' -f=extra.yml"},
+		{Command: "kubectl apply -f k8s.yml"},
+	}
+	assert.Equal(t, []string{"values.yml", "prod.yml", "db.yml", "extra.yml"}, getValuesFiles(commands))
+}
+
+func TestUploadArtifactsScrubsSecrets(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "okteto-t0ken",
+			},
+		},
+		CurrentContext: "test",
+	}
+	oktetoLog.AddMaskedWord("sup3r-s3cret")
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "values.yml", []byte("password: sup3r-s3cret\ntoken: okteto-t0ken\n"), 0600))
+	encoded := base64.StdEncoding.EncodeToString([]byte("db-password"))
+	uploader := &fakeArtifactsUploader{}
+	ld := &localDeployer{
+		Fs: fs,
+		Proxy: &fakeProxy{
+			appliedObjects: [][]byte{
+				[]byte(`{"kind":"Secret","metadata":{"name":"db"},"data":{"password":"` + encoded + `"}}`),
+				[]byte(`{"kind":"ConfigMap","metadata":{"name":"cfg"},"data":{"password":"sup3r-s3cret"}}`),
+			},
+		},
+		artifactsUploader: uploader,
+	}
+	opts := &Options{
+		Name: "movies",
+		Manifest: &model.Manifest{
+			Name: "movies",
+			Deploy: &model.DeployInfo{
+				Commands: []model.DeployCommand{
+					{Name: "helm", Command: "helm upgrade --install movies chart -f values.yml --set password=sup3r-s3cret"},
+				},
+			},
+		},
+	}
+
+	require.NoError(t, ld.uploadArtifacts(opts))
+	assert.Equal(t, "movies", uploader.name)
+
+	gz, err := gzip.NewReader(bytes.NewReader(uploader.content))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	names := []string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		names = append(names, header.Name)
+		for _, secret := range []string{"sup3r-s3cret", "okteto-t0ken", encoded} {
+			assert.NotContains(t, string(content), secret, "secret leaked in %s", header.Name)
+		}
+	}
+	assert.ElementsMatch(t, []string{appliedObjectsArtifact, resolvedManifestArtifact, "values/0-values.yml"}, names)
+}
+
+func TestUploadArtifactsError(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+			},
+		},
+		CurrentContext: "test",
+	}
+	ld := &localDeployer{
+		Fs:                afero.NewMemMapFs(),
+		Proxy:             &fakeProxy{},
+		artifactsUploader: &fakeArtifactsUploader{err: assert.AnError},
+	}
+	opts := &Options{
+		Name: "movies",
+		Manifest: &model.Manifest{
+			Deploy: &model.DeployInfo{},
+		},
+	}
+
+	require.ErrorIs(t, ld.uploadArtifacts(opts), assert.AnError)
+}
@@ -31,14 +31,24 @@ type configMapHandler interface {
 	updateConfigMap(context.Context, *apiv1.ConfigMap, *pipeline.CfgData, error) error
 	updateEnvsFromCommands(context.Context, string, string, []string) error
 	getConfigmapVariablesEncoded(ctx context.Context, name, namespace string) (string, error)
+	getDeployStages(ctx context.Context, name, namespace string) ([]pipeline.DeployStage, error)
+	updateDeployStages(ctx context.Context, name, namespace string, stages []pipeline.DeployStage) error
+	getDependencyRevisions(ctx context.Context, name, namespace string) (map[string]string, error)
+	updateDependencyRevisions(ctx context.Context, name, namespace string, revisions map[string]string) error
+	addClusterResources(ctx context.Context, name, namespace string, resources []pipeline.ClusterResource) error
+	updateTrail(ctx context.Context, name, namespace string, trail []pipeline.TrailEntry) error
 }
 
 // deployInsideDeployConfigMapHandler is the runner used when the okteto is executed
 // inside an okteto deploy command
-type deployInsideDeployConfigMapHandler struct{}
+type deployInsideDeployConfigMapHandler struct {
+	k8sClientProvider okteto.K8sClientProvider
+}
 
-func newDeployInsideDeployConfigMapHandler() *deployInsideDeployConfigMapHandler {
-	return &deployInsideDeployConfigMapHandler{}
+func newDeployInsideDeployConfigMapHandler(provider okteto.K8sClientProvider) *deployInsideDeployConfigMapHandler {
+	return &deployInsideDeployConfigMapHandler{
+		k8sClientProvider: provider,
+	}
 }
 
 // oktetoDefaultConfigMapHandler is the runner used when the okteto is executed
@@ -55,7 +65,7 @@ func newDefaultConfigMapHandler(provider okteto.K8sClientProvider) *defaultConfi
 
 func NewConfigmapHandler(provider okteto.K8sClientProvider) configMapHandler {
 	if utils.LoadBoolean(constants.OKtetoDeployRemote) {
-		return newDeployInsideDeployConfigMapHandler()
+		return newDeployInsideDeployConfigMapHandler(provider)
 	}
 	return newDefaultConfigMapHandler(provider)
 }
@@ -104,6 +114,63 @@ func (h *defaultConfigMapHandler) updateEnvsFromCommands(ctx context.Context, na
 	return nil
 }
 
+// getDeployStages returns the stages completed by the last deploy
+func (h *defaultConfigMapHandler) getDeployStages(ctx context.Context, name, namespace string) ([]pipeline.DeployStage, error) {
+	c, _, err := h.k8sClientProvider.Provide(okteto.Context().Cfg)
+	if err != nil {
+		return nil, err
+	}
+	return pipeline.GetDeployStages(ctx, name, namespace, c)
+}
+
+// updateDeployStages stores the stages completed by the current deploy
+func (h *defaultConfigMapHandler) updateDeployStages(ctx context.Context, name, namespace string, stages []pipeline.DeployStage) error {
+	c, _, err := h.k8sClientProvider.Provide(okteto.Context().Cfg)
+	if err != nil {
+		return err
+	}
+	return pipeline.UpdateDeployStages(ctx, name, namespace, stages, c)
+}
+
+// getDependencyRevisions returns the SHA each dependency was deployed from
+func (h *defaultConfigMapHandler) getDependencyRevisions(ctx context.Context, name, namespace string) (map[string]string, error) {
+	c, _, err := h.k8sClientProvider.Provide(okteto.Context().Cfg)
+	if err != nil {
+		return nil, err
+	}
+	return pipeline.GetDependencyRevisions(ctx, name, namespace, c)
+}
+
+// updateDependencyRevisions stores the SHA each dependency was deployed from
+func (h *defaultConfigMapHandler) updateDependencyRevisions(ctx context.Context, name, namespace string, revisions map[string]string) error {
+	c, _, err := h.k8sClientProvider.Provide(okteto.Context().Cfg)
+	if err != nil {
+		return err
+	}
+	return pipeline.UpdateDependencyRevisions(ctx, name, namespace, revisions, c)
+}
+
+// addClusterResources records the cluster-scoped objects applied by the deploy
+func (h *defaultConfigMapHandler) addClusterResources(ctx context.Context, name, namespace string, resources []pipeline.ClusterResource) error {
+	if len(resources) == 0 {
+		return nil
+	}
+	c, _, err := h.k8sClientProvider.Provide(okteto.Context().Cfg)
+	if err != nil {
+		return err
+	}
+	return pipeline.AddClusterResources(ctx, name, namespace, resources, c)
+}
+
+// updateTrail records the mutating requests sent by the deploy commands
+func (h *defaultConfigMapHandler) updateTrail(ctx context.Context, name, namespace string, trail []pipeline.TrailEntry) error {
+	c, _, err := h.k8sClientProvider.Provide(okteto.Context().Cfg)
+	if err != nil {
+		return err
+	}
+	return pipeline.SetTrail(ctx, name, namespace, trail, c)
+}
+
 // translateConfigMapAndDeploy with the receiver deployInsideDeployConfigMapHandler doesn't do anything
 // because we have to  control the cfmap in the main execution. If both handled the configmap we will be
 // overwritten the cfmap and leave it in a inconsistent status
@@ -128,3 +195,50 @@ func (*deployInsideDeployConfigMapHandler) updateConfigMap(_ context.Context, _
 func (*deployInsideDeployConfigMapHandler) updateEnvsFromCommands(_ context.Context, _ string, _ string, _ []string) error {
 	return nil
 }
+
+// getDeployStages with the receiver deployInsideDeployConfigMapHandler doesn't return anything
+// because the stages are only tracked by the main execution
+func (*deployInsideDeployConfigMapHandler) getDeployStages(_ context.Context, _, _ string) ([]pipeline.DeployStage, error) {
+	return nil, nil
+}
+
+// updateDeployStages with the receiver deployInsideDeployConfigMapHandler doesn't do anything
+// because we have to  control the cfmap in the main execution
+func (*deployInsideDeployConfigMapHandler) updateDeployStages(_ context.Context, _, _ string, _ []pipeline.DeployStage) error {
+	return nil
+}
+
+// getDependencyRevisions with the receiver deployInsideDeployConfigMapHandler doesn't return anything
+// because the dependencies are only deployed by the main execution
+func (*deployInsideDeployConfigMapHandler) getDependencyRevisions(_ context.Context, _, _ string) (map[string]string, error) {
+	return nil, nil
+}
+
+// updateDependencyRevisions with the receiver deployInsideDeployConfigMapHandler doesn't do anything
+// because we have to  control the cfmap in the main execution
+func (*deployInsideDeployConfigMapHandler) updateDependencyRevisions(_ context.Context, _, _ string, _ map[string]string) error {
+	return nil
+}
+
+// addClusterResources with the receiver deployInsideDeployConfigMapHandler records the cluster-scoped objects
+// because only the execution running the proxy knows them. It is safe as the main execution reloads the cfmap before updating it
+func (h *deployInsideDeployConfigMapHandler) addClusterResources(ctx context.Context, name, namespace string, resources []pipeline.ClusterResource) error {
+	if len(resources) == 0 {
+		return nil
+	}
+	c, _, err := h.k8sClientProvider.Provide(okteto.Context().Cfg)
+	if err != nil {
+		return err
+	}
+	return pipeline.AddClusterResources(ctx, name, namespace, resources, c)
+}
+
+// updateTrail with the receiver deployInsideDeployConfigMapHandler records the trail because only
+// the execution running the proxy knows it. It is safe as the main execution reloads the cfmap before updating it
+func (h *deployInsideDeployConfigMapHandler) updateTrail(ctx context.Context, name, namespace string, trail []pipeline.TrailEntry) error {
+	c, _, err := h.k8sClientProvider.Provide(okteto.Context().Cfg)
+	if err != nil {
+		return err
+	}
+	return pipeline.SetTrail(ctx, name, namespace, trail, c)
+}
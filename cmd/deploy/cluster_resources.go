@@ -0,0 +1,147 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// nonPersistedGroups are the API groups whose cluster-scoped kinds are requests, like access reviews, instead of objects
+var nonPersistedGroups = map[string]bool{
+	"authorization.k8s.io":  true,
+	"authentication.k8s.io": true,
+}
+
+// getClusterResource returns the cluster-scoped object created or modified by the request.
+// The body is used to get the name and the kind of the object when the path doesn't include them
+func getClusterResource(r *http.Request, body []byte) (pipeline.ClusterResource, bool) {
+	if r.URL.Query().Get("dryRun") != "" {
+		return pipeline.ClusterResource{}, false
+	}
+
+	var resource pipeline.ClusterResource
+	var rest []string
+	var ok bool
+	resource.Group, resource.Version, rest, ok = splitAPIPath(r.URL.Path)
+	if !ok {
+		return pipeline.ClusterResource{}, false
+	}
+
+	// namespaced objects, subresources and namespaces, which are managed by okteto, are not cluster resources
+	if len(rest) == 0 || len(rest) > 2 || rest[0] == "namespaces" || nonPersistedGroups[resource.Group] {
+		return pipeline.ClusterResource{}, false
+	}
+	resource.Resource = rest[0]
+	if len(rest) == 2 {
+		resource.Name = rest[1]
+	}
+
+	if len(body) > 0 {
+		var obj struct {
+			metav1.TypeMeta `json:",inline"`
+			Metadata        metav1.ObjectMeta `json:"metadata"`
+		}
+		if err := json.Unmarshal(body, &obj); err == nil {
+			resource.Kind = obj.Kind
+			if resource.Name == "" {
+				resource.Name = obj.Metadata.Name
+			}
+		}
+	}
+	return resource, true
+}
+
+// splitAPIPath returns the group and version of a kubernetes API path and the segments after them
+func splitAPIPath(path string) (group, version string, rest []string, ok bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(segments) >= 2 && segments[0] == "api":
+		return "", segments[1], segments[2:], true
+	case len(segments) >= 3 && segments[0] == "apis":
+		return segments[1], segments[2], segments[3:], true
+	default:
+		return "", "", nil, false
+	}
+}
+
+// admitClusterResource records the cluster-scoped object and returns if the deploy is allowed to apply it
+func (ph *proxyHandler) admitClusterResource(resource pipeline.ClusterResource) bool {
+	ph.objectsMu.Lock()
+	defer ph.objectsMu.Unlock()
+	if !ph.allowClusterResources {
+		ph.refusedClusterResources = append(ph.refusedClusterResources, resource)
+		return false
+	}
+	// objects created with 'generateName' can't be tracked
+	if resource.Name != "" {
+		ph.clusterResources = append(ph.clusterResources, resource)
+	}
+	return true
+}
+
+func (ph *proxyHandler) getClusterResources() (applied, refused []pipeline.ClusterResource) {
+	ph.objectsMu.Lock()
+	defer ph.objectsMu.Unlock()
+	return append([]pipeline.ClusterResource{}, ph.clusterResources...), append([]pipeline.ClusterResource{}, ph.refusedClusterResources...)
+}
+
+// writeClusterResourceForbidden answers the request with a kubernetes status so clients show the reason
+func writeClusterResourceForbidden(rw http.ResponseWriter, resource pipeline.ClusterResource) {
+	status := metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Message:  fmt.Sprintf("%s is cluster-scoped and cluster resources are not allowed in this development environment", resource),
+		Reason:   metav1.StatusReasonForbidden,
+		Code:     http.StatusForbidden,
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusForbidden)
+	if err := json.NewEncoder(rw).Encode(status); err != nil {
+		oktetoLog.Infof("could not write the response for %s: %s", resource, err)
+	}
+}
+
+// isClusterResourcesAllowed returns if the deploy can apply cluster-scoped objects
+func isClusterResourcesAllowed(opts *Options) bool {
+	if opts.AllowClusterResources {
+		return true
+	}
+	return opts.Manifest != nil && opts.Manifest.Deploy != nil && opts.Manifest.Deploy.AllowClusterResources
+}
+
+// newClusterResourcesRefusedError lists the cluster-scoped objects refused during the deploy
+func newClusterResourcesRefusedError(refused []pipeline.ClusterResource) error {
+	seen := map[string]bool{}
+	var names []string
+	for _, r := range refused {
+		name := r.String()
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return oktetoErrors.UserError{
+		E:    fmt.Errorf("the deploy tried to apply cluster-scoped resources: %s", strings.Join(names, ", ")),
+		Hint: "Set 'allowClusterResources: true' in the 'deploy' section of your okteto manifest or use the flag '--allow-cluster-resources'",
+	}
+}
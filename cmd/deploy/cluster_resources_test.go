@@ -0,0 +1,157 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var clusterRoleResource = pipeline.ClusterResource{
+	Group:    "rbac.authorization.k8s.io",
+	Version:  "v1",
+	Resource: "clusterroles",
+	Kind:     "ClusterRole",
+	Name:     "reader",
+}
+
+func TestGetClusterResource(t *testing.T) {
+	var tests = []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		expected *pipeline.ClusterResource
+	}{
+		{
+			name:     "create cluster role",
+			method:   http.MethodPost,
+			url:      "/apis/rbac.authorization.k8s.io/v1/clusterroles",
+			body:     `{"kind":"ClusterRole","apiVersion":"rbac.authorization.k8s.io/v1","metadata":{"name":"reader"}}`,
+			expected: &clusterRoleResource,
+		},
+		{
+			name:   "patch crd",
+			method: http.MethodPatch,
+			url:    "/apis/apiextensions.k8s.io/v1/customresourcedefinitions/foos.example.com",
+			expected: &pipeline.ClusterResource{
+				Group:    "apiextensions.k8s.io",
+				Version:  "v1",
+				Resource: "customresourcedefinitions",
+				Name:     "foos.example.com",
+			},
+		},
+		{
+			name:   "core persistent volume",
+			method: http.MethodPut,
+			url:    "/api/v1/persistentvolumes/data",
+			body:   `{"kind":"PersistentVolume","apiVersion":"v1","metadata":{"name":"data"}}`,
+			expected: &pipeline.ClusterResource{
+				Version:  "v1",
+				Resource: "persistentvolumes",
+				Kind:     "PersistentVolume",
+				Name:     "data",
+			},
+		},
+		{
+			name:   "namespaced object",
+			method: http.MethodPost,
+			url:    "/apis/apps/v1/namespaces/test/deployments",
+			body:   `{"kind":"Deployment","apiVersion":"apps/v1","metadata":{"name":"api"}}`,
+		},
+		{
+			name:   "namespace",
+			method: http.MethodPost,
+			url:    "/api/v1/namespaces",
+			body:   `{"kind":"Namespace","apiVersion":"v1","metadata":{"name":"test"}}`,
+		},
+		{
+			name:   "subresource",
+			method: http.MethodPut,
+			url:    "/apis/apiextensions.k8s.io/v1/customresourcedefinitions/foos.example.com/status",
+		},
+		{
+			name:   "access review",
+			method: http.MethodPost,
+			url:    "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews",
+			body:   `{"kind":"SelfSubjectAccessReview","apiVersion":"authorization.k8s.io/v1","metadata":{}}`,
+		},
+		{
+			name:   "dry run",
+			method: http.MethodPost,
+			url:    "/apis/rbac.authorization.k8s.io/v1/clusterroles?dryRun=All",
+			body:   `{"kind":"ClusterRole","apiVersion":"rbac.authorization.k8s.io/v1","metadata":{"name":"reader"}}`,
+		},
+		{
+			name:   "not an api path",
+			method: http.MethodPost,
+			url:    "/version",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.url, nil)
+			resource, ok := getClusterResource(r, []byte(tt.body))
+			if tt.expected == nil {
+				assert.False(t, ok)
+				return
+			}
+			assert.True(t, ok)
+			assert.Equal(t, *tt.expected, resource)
+		})
+	}
+}
+
+func TestAdmitClusterResource(t *testing.T) {
+	generated := clusterRoleResource
+	generated.Name = ""
+
+	allowed := &proxyHandler{allowClusterResources: true}
+	assert.True(t, allowed.admitClusterResource(clusterRoleResource))
+	assert.True(t, allowed.admitClusterResource(generated))
+	applied, refused := allowed.getClusterResources()
+	assert.Equal(t, []pipeline.ClusterResource{clusterRoleResource}, applied)
+	assert.Empty(t, refused)
+
+	notAllowed := &proxyHandler{}
+	assert.False(t, notAllowed.admitClusterResource(clusterRoleResource))
+	applied, refused = notAllowed.getClusterResources()
+	assert.Empty(t, applied)
+	assert.Equal(t, []pipeline.ClusterResource{clusterRoleResource}, refused)
+}
+
+func TestWriteClusterResourceForbidden(t *testing.T) {
+	rw := httptest.NewRecorder()
+	writeClusterResourceForbidden(rw, clusterRoleResource)
+
+	assert.Equal(t, http.StatusForbidden, rw.Code)
+	var status metav1.Status
+	require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &status))
+	assert.Equal(t, metav1.StatusReasonForbidden, status.Reason)
+	assert.Contains(t, status.Message, "ClusterRole 'reader'")
+}
+
+func TestIsClusterResourcesAllowed(t *testing.T) {
+	assert.False(t, isClusterResourcesAllowed(&Options{Manifest: &model.Manifest{Deploy: &model.DeployInfo{}}}))
+	assert.True(t, isClusterResourcesAllowed(&Options{AllowClusterResources: true}))
+	assert.True(t, isClusterResourcesAllowed(&Options{Manifest: &model.Manifest{Deploy: &model.DeployInfo{AllowClusterResources: true}}}))
+}
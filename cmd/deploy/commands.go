@@ -0,0 +1,59 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"fmt"
+	"strings"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/model"
+)
+
+// selectDeployCommands returns the deploy commands whose name is in names, in manifest order.
+// It fails if any of the names doesn't match a deploy command, so nothing runs with a typo
+func selectDeployCommands(commands []model.DeployCommand, names []string) ([]model.DeployCommand, error) {
+	available := map[string]bool{}
+	for _, command := range commands {
+		available[command.Name] = true
+	}
+
+	selected := map[string]bool{}
+	var unknown []string
+	for _, name := range names {
+		if !available[name] {
+			unknown = append(unknown, fmt.Sprintf("'%s'", name))
+			continue
+		}
+		selected[name] = true
+	}
+	if len(unknown) > 0 {
+		var availableNames []string
+		for _, command := range commands {
+			availableNames = append(availableNames, fmt.Sprintf("'%s'", command.Name))
+		}
+		return nil, oktetoErrors.UserError{
+			E:    fmt.Errorf("the deploy commands %s are not defined in your okteto manifest", strings.Join(unknown, ", ")),
+			Hint: fmt.Sprintf("Use the 'name' field of the deploy commands. Available commands: %s", strings.Join(availableNames, ", ")),
+		}
+	}
+
+	result := []model.DeployCommand{}
+	for _, command := range commands {
+		if selected[command.Name] {
+			result = append(result, command)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"testing"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectDeployCommands(t *testing.T) {
+	commands := []model.DeployCommand{
+		{Name: "build", Command: "make build"},
+		{Name: "helm", Command: "helm upgrade --install movies chart"},
+		{Name: "kubectl apply -f k8s", Command: "kubectl apply -f k8s"},
+		{Name: "migrations", Command: "make migrate"},
+	}
+	var tests = []struct {
+		name      string
+		names     []string
+		expected  []model.DeployCommand
+		expectErr bool
+	}{
+		{
+			name:     "single command",
+			names:    []string{"helm"},
+			expected: []model.DeployCommand{commands[1]},
+		},
+		{
+			name:     "commands keep the manifest order",
+			names:    []string{"migrations", "build"},
+			expected: []model.DeployCommand{commands[0], commands[3]},
+		},
+		{
+			name:     "command without name is selected by its command",
+			names:    []string{"kubectl apply -f k8s"},
+			expected: []model.DeployCommand{commands[2]},
+		},
+		{
+			name:     "repeated names",
+			names:    []string{"helm", "helm"},
+			expected: []model.DeployCommand{commands[1]},
+		},
+		{
+			name:      "unknown command",
+			names:     []string{"helm", "unknown"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := selectDeployCommands(commands, tt.names)
+			if tt.expectErr {
+				var userErr oktetoErrors.UserError
+				require.ErrorAs(t, err, &userErr)
+				assert.Contains(t, userErr.E.Error(), "'unknown'")
+				assert.Contains(t, userErr.Hint, "'helm'")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
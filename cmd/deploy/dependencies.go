@@ -0,0 +1,65 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+)
+
+// getDependencyRevision returns the ref used to deploy a dependency and the SHA recorded for it.
+// With --locked the dependency is deployed from the recorded SHA, with --update-deps from the latest SHA of its branch or tag
+func (dc *DeployCommand) getDependencyRevision(ctx context.Context, depName string, dep *model.Dependency, recorded map[string]string, opts *Options) (string, string, error) {
+	if opts.Locked {
+		sha, ok := recorded[depName]
+		if !ok {
+			return "", "", oktetoErrors.UserError{
+				E:    fmt.Errorf("there is no revision recorded for the dependency '%s'", depName),
+				Hint: "Run 'okteto deploy --update-deps' to record the revisions of the dependencies",
+			}
+		}
+		return sha, sha, nil
+	}
+
+	ref := dep.GetRef()
+	// dependencies that already exist are not deployed again, so they keep the revision recorded when they were deployed
+	if !opts.Dependencies && !opts.UpdateDeps {
+		if sha, ok := recorded[depName]; ok {
+			return ref, sha, nil
+		}
+	}
+	if dc.ResolveDependencyRef == nil {
+		return ref, "", nil
+	}
+
+	sha, err := dc.ResolveDependencyRef(ctx, dep.Repository, ref)
+	if err != nil {
+		if opts.UpdateDeps {
+			return "", "", oktetoErrors.UserError{
+				E:    fmt.Errorf("could not resolve the revision of the dependency '%s': %w", depName, err),
+				Hint: "Check that the repository is reachable and that its 'branch' or 'ref' exists",
+			}
+		}
+		oktetoLog.Warning("could not resolve the revision of the dependency '%s', it won't be re-deployed with '--locked': %s", depName, err)
+		return ref, "", nil
+	}
+	if opts.UpdateDeps {
+		return sha, sha, nil
+	}
+	return ref, sha, nil
+}
@@ -0,0 +1,227 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"testing"
+
+	pipelineCMD "github.com/okteto/okteto/cmd/pipeline"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	apiHeadSHA   = "1111111111111111111111111111111111111111"
+	apiLockedSHA = "2222222222222222222222222222222222222222"
+	dbTagSHA     = "3333333333333333333333333333333333333333"
+)
+
+type recordingPipelineDeployer struct {
+	deployed map[string]*pipelineCMD.DeployOptions
+}
+
+func (fd *recordingPipelineDeployer) ExecuteDeployPipeline(_ context.Context, opts *pipelineCMD.DeployOptions) error {
+	if fd.deployed == nil {
+		fd.deployed = map[string]*pipelineCMD.DeployOptions{}
+	}
+	fd.deployed[opts.Name] = opts
+	return nil
+}
+
+type fakeRefResolver struct {
+	shas     map[string]string
+	err      error
+	resolved []string
+}
+
+func (fr *fakeRefResolver) resolve(_ context.Context, repository, ref string) (string, error) {
+	fr.resolved = append(fr.resolved, ref)
+	if fr.err != nil {
+		return "", fr.err
+	}
+	return fr.shas[repository+"@"+ref], nil
+}
+
+func newDependenciesManifest() *model.Manifest {
+	return &model.Manifest{
+		Namespace: "test",
+		Dependencies: model.ManifestDependencies{
+			"api": &model.Dependency{Repository: "https://github.com/okteto/api", Branch: "main"},
+			"db":  &model.Dependency{Repository: "https://github.com/okteto/db", Ref: "v1.0.0"},
+		},
+	}
+}
+
+func newDependenciesResolver() *fakeRefResolver {
+	return &fakeRefResolver{
+		shas: map[string]string{
+			"https://github.com/okteto/api@main":  apiHeadSHA,
+			"https://github.com/okteto/db@v1.0.0": dbTagSHA,
+		},
+	}
+}
+
+func setDependenciesContext() {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+			},
+		},
+		CurrentContext: "test",
+	}
+}
+
+func TestDeployDependenciesRecordsResolvedRevisions(t *testing.T) {
+	setDependenciesContext()
+	pipeline := &recordingPipelineDeployer{}
+	cmap := &fakeCmapHandler{}
+	resolver := newDependenciesResolver()
+	dc := &DeployCommand{
+		PipelineCMD:          pipeline,
+		CfgMapHandler:        cmap,
+		ResolveDependencyRef: resolver.resolve,
+	}
+
+	err := dc.deployDependencies(context.Background(), &Options{Name: "movies", Manifest: newDependenciesManifest(), Dependencies: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"api": apiHeadSHA, "db": dbTagSHA}, cmap.revisions)
+	assert.Equal(t, "main", pipeline.deployed["api"].Branch)
+	assert.Equal(t, "v1.0.0", pipeline.deployed["db"].Branch)
+	assert.False(t, pipeline.deployed["api"].SkipIfExists)
+}
+
+func TestDeployDependenciesKeepsRevisionsOfSkippedDependencies(t *testing.T) {
+	setDependenciesContext()
+	pipeline := &recordingPipelineDeployer{}
+	cmap := &fakeCmapHandler{revisions: map[string]string{"api": apiLockedSHA, "removed": apiLockedSHA}}
+	resolver := newDependenciesResolver()
+	dc := &DeployCommand{
+		PipelineCMD:          pipeline,
+		CfgMapHandler:        cmap,
+		ResolveDependencyRef: resolver.resolve,
+	}
+
+	err := dc.deployDependencies(context.Background(), &Options{Name: "movies", Manifest: newDependenciesManifest()})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"api": apiLockedSHA, "db": dbTagSHA}, cmap.revisions)
+	assert.Equal(t, []string{"v1.0.0"}, resolver.resolved)
+	assert.True(t, pipeline.deployed["api"].SkipIfExists)
+}
+
+func TestDeployDependenciesLocked(t *testing.T) {
+	setDependenciesContext()
+	pipeline := &recordingPipelineDeployer{}
+	cmap := &fakeCmapHandler{revisions: map[string]string{"api": apiLockedSHA, "db": dbTagSHA}}
+	resolver := newDependenciesResolver()
+	dc := &DeployCommand{
+		PipelineCMD:          pipeline,
+		CfgMapHandler:        cmap,
+		ResolveDependencyRef: resolver.resolve,
+	}
+
+	err := dc.deployDependencies(context.Background(), &Options{Name: "movies", Manifest: newDependenciesManifest(), Locked: true})
+	require.NoError(t, err)
+
+	assert.Empty(t, resolver.resolved)
+	assert.Equal(t, apiLockedSHA, pipeline.deployed["api"].Branch)
+	assert.Equal(t, dbTagSHA, pipeline.deployed["db"].Branch)
+	assert.False(t, pipeline.deployed["api"].SkipIfExists)
+	assert.Equal(t, map[string]string{"api": apiLockedSHA, "db": dbTagSHA}, cmap.revisions)
+}
+
+func TestDeployDependenciesLockedWithoutRecordedRevision(t *testing.T) {
+	setDependenciesContext()
+	pipeline := &recordingPipelineDeployer{}
+	dc := &DeployCommand{
+		PipelineCMD:          pipeline,
+		CfgMapHandler:        &fakeCmapHandler{revisions: map[string]string{"api": apiLockedSHA}},
+		ResolveDependencyRef: newDependenciesResolver().resolve,
+	}
+	manifest := newDependenciesManifest()
+	delete(manifest.Dependencies, "api")
+
+	err := dc.deployDependencies(context.Background(), &Options{Name: "movies", Manifest: manifest, Locked: true})
+	assert.ErrorAs(t, err, &oktetoErrors.UserError{})
+	assert.Empty(t, pipeline.deployed)
+}
+
+func TestDeployDependenciesInsideRemoteDeploy(t *testing.T) {
+	setDependenciesContext()
+	pipeline := &recordingPipelineDeployer{}
+	dc := &DeployCommand{
+		PipelineCMD:          pipeline,
+		CfgMapHandler:        newDeployInsideDeployConfigMapHandler(nil),
+		ResolveDependencyRef: newDependenciesResolver().resolve,
+		isRemote:             true,
+	}
+
+	err := dc.deployDependencies(context.Background(), &Options{Name: "movies", Manifest: newDependenciesManifest(), Locked: true})
+	require.NoError(t, err)
+	assert.Empty(t, pipeline.deployed)
+}
+
+func TestGetDeployFlagsDoesNotForwardDependencyFlags(t *testing.T) {
+	assert.Empty(t, getDeployFlags(&Options{Locked: true, UpdateDeps: true, Dependencies: true}))
+}
+
+func TestDeployDependenciesUpdateDeps(t *testing.T) {
+	setDependenciesContext()
+	pipeline := &recordingPipelineDeployer{}
+	cmap := &fakeCmapHandler{revisions: map[string]string{"api": apiLockedSHA, "db": dbTagSHA}}
+	resolver := newDependenciesResolver()
+	dc := &DeployCommand{
+		PipelineCMD:          pipeline,
+		CfgMapHandler:        cmap,
+		ResolveDependencyRef: resolver.resolve,
+	}
+
+	err := dc.deployDependencies(context.Background(), &Options{Name: "movies", Manifest: newDependenciesManifest(), UpdateDeps: true})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"main", "v1.0.0"}, resolver.resolved)
+	assert.Equal(t, apiHeadSHA, pipeline.deployed["api"].Branch)
+	assert.False(t, pipeline.deployed["api"].SkipIfExists)
+	assert.Equal(t, map[string]string{"api": apiHeadSHA, "db": dbTagSHA}, cmap.revisions)
+}
+
+func TestDeployDependenciesUnresolvedRevision(t *testing.T) {
+	setDependenciesContext()
+	resolver := &fakeRefResolver{err: assert.AnError}
+
+	pipeline := &recordingPipelineDeployer{}
+	cmap := &fakeCmapHandler{}
+	dc := &DeployCommand{
+		PipelineCMD:          pipeline,
+		CfgMapHandler:        cmap,
+		ResolveDependencyRef: resolver.resolve,
+	}
+	err := dc.deployDependencies(context.Background(), &Options{Name: "movies", Manifest: newDependenciesManifest(), Dependencies: true})
+	require.NoError(t, err)
+	assert.Empty(t, cmap.revisions)
+	assert.Equal(t, "main", pipeline.deployed["api"].Branch)
+
+	pipeline = &recordingPipelineDeployer{}
+	dc.PipelineCMD = pipeline
+	err = dc.deployDependencies(context.Background(), &Options{Name: "movies", Manifest: newDependenciesManifest(), UpdateDeps: true})
+	assert.ErrorAs(t, err, &oktetoErrors.UserError{})
+	assert.Empty(t, pipeline.deployed)
+}
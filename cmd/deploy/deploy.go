@@ -15,9 +15,11 @@ package deploy
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"time"
 
 	buildv2 "github.com/okteto/okteto/cmd/build/v2"
@@ -26,6 +28,7 @@ import (
 	pipelineCMD "github.com/okteto/okteto/cmd/pipeline"
 	"github.com/okteto/okteto/cmd/utils"
 	"github.com/okteto/okteto/pkg/analytics"
+	"github.com/okteto/okteto/pkg/chaos"
 	"github.com/okteto/okteto/pkg/cmd/pipeline"
 	"github.com/okteto/okteto/pkg/config"
 	"github.com/okteto/okteto/pkg/constants"
@@ -37,7 +40,9 @@ import (
 	"github.com/okteto/okteto/pkg/model"
 	"github.com/okteto/okteto/pkg/okteto"
 	oktetoPath "github.com/okteto/okteto/pkg/path"
+	"github.com/okteto/okteto/pkg/repository"
 	"github.com/okteto/okteto/pkg/types"
+	"github.com/okteto/okteto/pkg/varprofiles"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -56,17 +61,68 @@ type Options struct {
 	ManifestPathFlag string
 	// ManifestPath is the path to the manifest used though the command execution.
 	// This might change its value during execution
-	ManifestPath     string
-	Name             string
-	Namespace        string
-	K8sContext       string
-	Variables        []string
-	Manifest         *model.Manifest
-	Build            bool
-	Dependencies     bool
-	RunWithoutBash   bool
-	RunInRemote      bool
+	ManifestPath   string
+	Name           string
+	Namespace      string
+	K8sContext     string
+	Variables      []string
+	Manifest       *model.Manifest
+	Build          bool
+	Dependencies   bool
+	RunWithoutBash bool
+	RunInRemote    bool
+	// RunLocal runs the deploy commands locally even if OKTETO_FORCE_REMOTE or the manifest run them in remote
+	RunLocal bool
+	// RemoteRunImage is the image used to run the deploy commands in remote. It takes priority over the manifest and the cluster default
+	RemoteRunImage string
+	ListVariables  bool
+	// DefaultResources are the resources injected into the containers lacking requests/limits, e.g. 'cpu=100m,limits.memory=1Gi'
+	DefaultResources string
+	// EnforceResources overrides explicit requests/limits with the default resources
+	EnforceResources bool
+	// Resume skips the deploy commands that completed in the previous deploy if their inputs didn't change
+	Resume bool
+	// NoResume ignores the deploy commands completed in the previous deploy
+	NoResume bool
+	// UploadArtifacts uploads the resolved manifest, values files and applied objects to Okteto after the deploy
+	UploadArtifacts bool
+	// AllowClusterResources lets the deploy commands apply cluster-scoped objects
+	AllowClusterResources bool
+	// NoTrail disables recording the mutating requests sent by the deploy commands
+	NoTrail bool
+	// Commands are the names of the deploy commands to run, empty runs all of them
+	Commands []string
+	// Locked deploys the dependencies from the SHAs recorded by the previous deploy instead of their branch heads
+	Locked bool
+	// UpdateDeps resolves the branches and tags of the dependencies again and records their SHAs
+	UpdateDeps bool
+	// StrictImages fails the deploy when the workloads reference images that can't be found
+	StrictImages bool
+	// DryRun prints the plan of the deploy instead of running it. With RunInRemote it prints the dockerfile used to deploy in remote
+	DryRun bool
+	// InsecureSkipTLSVerify skips the verification of the okteto server certificate in the remote deploy
+	InsecureSkipTLSVerify bool
+	// FollowBuild is the service whose build logs are displayed in full, the others only show their progress line
+	FollowBuild string
+	// SaveVars is the profile where the resolved variables are saved
+	SaveVars string
+	// FromSavedVars is the profile whose variables are used as baseline, the ones set with '--var' take priority
+	FromSavedVars string
+	// ExportManifests is the folder where the resources of the deploy are written instead of applying them
+	ExportManifests string
+	// RequireClean fails the remote deploy when the working tree has uncommitted changes
+	RequireClean bool
+	// Contexts is a comma separated list of contexts where the deploy runs, one after the other
+	Contexts string
+	// ParallelContexts runs the deploy of every context of Contexts at the same time
+	ParallelContexts bool
 	servicesToDeploy []string
+	// builtImages are the images built by the deploy, which are not checked in the registry
+	builtImages []string
+	// userVariables are the variables set by the user, used to invalidate the stages on --resume
+	userVariables []string
+	// dirtyWorkingTree is set by the remote deploy when the working tree has uncommitted changes
+	dirtyWorkingTree bool
 
 	Repository string
 	Branch     string
@@ -90,6 +146,9 @@ type DeployCommand struct {
 	Fs                 afero.Fs
 	DivertDriver       divert.Driver
 	PipelineCMD        pipelineCMD.PipelineDeployerInterface
+	VarProfiles        varProfileStore
+	// ResolveDependencyRef returns the SHA of a branch, tag or commit of a dependency repository
+	ResolveDependencyRef func(ctx context.Context, repository, ref string) (string, error)
 
 	PipelineType       model.Archetype
 	isRemote           bool
@@ -117,7 +176,17 @@ func Deploy(ctx context.Context) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "deploy [service...]",
 		Short: "Execute locally the list of commands specified in the 'deploy' section of your okteto manifest",
+		Long: `Execute locally the list of commands specified in the 'deploy' section of your okteto manifest.
+
+` + utils.RunModePrecedenceHelp,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateContextsFlags("deploy", options.Contexts, options.K8sContext, options.ParallelContexts); err != nil {
+				return err
+			}
+			if options.Contexts != "" {
+				return utils.RunCommandInContexts(ctx, cmd, args, options.Variables, options.Contexts, options.ParallelContexts)
+			}
+
 			// validate cmd options
 			if options.Dependencies && !okteto.IsOkteto() {
 				return fmt.Errorf("'dependencies' is only supported in clusters that have Okteto installed")
@@ -127,6 +196,61 @@ func Deploy(ctx context.Context) *cobra.Command {
 				return err
 			}
 
+			if options.RemoteRunImage != "" {
+				if err := utils.ValidateImageReference("remote-run-image", options.RemoteRunImage); err != nil {
+					return err
+				}
+			}
+
+			if err := utils.ValidateRunModeFlags("deploy", options.RunInRemote, options.RunLocal); err != nil {
+				return err
+			}
+
+			if options.ExportManifests != "" {
+				if options.RunInRemote || options.DryRun {
+					return oktetoErrors.UserError{
+						E:    fmt.Errorf("flag '--export-manifests' can't be used with '--remote' or '--dry-run'"),
+						Hint: "Run 'okteto deploy --export-manifests <dir>' to write the resources of the deploy without applying them",
+					}
+				}
+				// the folder is relative to the directory where okteto runs, which changes when the manifest is loaded
+				exportDir, err := filepath.Abs(options.ExportManifests)
+				if err != nil {
+					return err
+				}
+				options.ExportManifests = exportDir
+			}
+
+			if options.Resume && options.NoResume {
+				return oktetoErrors.UserError{
+					E:    fmt.Errorf("flags '--resume' and '--no-resume' can't be used together"),
+					Hint: "Use '--resume' to skip the commands completed in the previous deploy or '--no-resume' to run all of them",
+				}
+			}
+
+			if options.Resume && options.RunInRemote {
+				return errResumeInRemote
+			}
+
+			if options.Locked && options.UpdateDeps {
+				return oktetoErrors.UserError{
+					E:    fmt.Errorf("flags '--locked' and '--update-deps' can't be used together"),
+					Hint: "Use '--locked' to deploy the dependencies from the recorded SHAs or '--update-deps' to record their latest SHAs",
+				}
+			}
+
+			for _, profile := range []string{options.SaveVars, options.FromSavedVars} {
+				if profile == "" {
+					continue
+				}
+				if err := varprofiles.ValidateProfileName(profile); err != nil {
+					return oktetoErrors.UserError{
+						E:    err,
+						Hint: "Run 'okteto vars list' to see the saved profiles",
+					}
+				}
+			}
+
 			// This is needed because the deploy command needs the original kubeconfig configuration even in the execution within another
 			// deploy command. If not, we could be proxying a proxy and we would be applying the incorrect deployed-by label
 			os.Setenv(constants.OktetoSkipConfigCredentialsUpdate, "false")
@@ -151,6 +275,18 @@ func Deploy(ctx context.Context) *cobra.Command {
 				options.ManifestPath = uptManifestPath
 			}
 
+			if options.ListVariables {
+				manifest, err := model.GetManifestV2(options.ManifestPath)
+				if err != nil {
+					return err
+				}
+				return listVariables(os.Stdout, manifest.Variables, os.LookupEnv)
+			}
+
+			if options.ExportManifests != "" {
+				return exportManifests(ctx, options)
+			}
+
 			// Loads, updates and uses the context from path. If not found, it creates and uses a new context
 			if err := contextCMD.LoadContextFromPath(ctx, options.Namespace, options.K8sContext, options.ManifestPath); err != nil {
 				if err.Error() == fmt.Errorf(oktetoErrors.ErrNotLogged, okteto.CloudURL).Error() {
@@ -162,18 +298,36 @@ func Deploy(ctx context.Context) *cobra.Command {
 			}
 
 			if okteto.IsOkteto() {
-				create, err := utils.ShouldCreateNamespace(ctx, okteto.Context().Namespace)
-				if err != nil {
-					return err
-				}
-				if create {
-					nsCmd, err := namespace.NewCommand()
+				// the remote deploy was already checked by the outer deploy
+				readOnly := false
+				if !utils.LoadBoolean(constants.OKtetoDeployRemote) {
+					ro, err := utils.CheckTokenPermissions(ctx, okteto.NewOktetoClientProvider(), "deploy", okteto.Context().Namespace, options.DryRun)
 					if err != nil {
 						return err
 					}
-					if err := nsCmd.Create(ctx, &namespace.CreateOptions{Namespace: okteto.Context().Namespace}); err != nil {
+					readOnly = ro
+				}
+
+				// a read-only token can't create the namespace, the plan is shown for the existing one
+				if !readOnly && !options.DryRun {
+					create, err := utils.ShouldCreateNamespace(ctx, okteto.Context().Namespace)
+					if err != nil {
 						return err
 					}
+					if create {
+						nsCmd, err := namespace.NewCommand()
+						if err != nil {
+							return err
+						}
+						if err := nsCmd.Create(ctx, &namespace.CreateOptions{Namespace: okteto.Context().Namespace}); err != nil {
+							return err
+						}
+					}
+				}
+
+				// the remote deploy forwards the value resolved by the outer deploy
+				if !cmd.Flags().Changed("upload-artifacts") && !utils.LoadBoolean(constants.OKtetoDeployRemote) {
+					options.UploadArtifacts = getUploadArtifactsPolicy(ctx)
 				}
 			}
 
@@ -188,17 +342,19 @@ func Deploy(ctx context.Context) *cobra.Command {
 			c := &DeployCommand{
 				GetManifest: model.GetManifestV2,
 
-				GetExternalControl: NewDeployExternalK8sControl,
-				K8sClientProvider:  k8sClientProvider,
-				GetDeployer:        GetDeployer,
-				Builder:            buildv2.NewBuilderFromScratch(),
-				DeployWaiter:       NewDeployWaiter(k8sClientProvider),
-				EndpointGetter:     NewEndpointGetter,
-				isRemote:           utils.LoadBoolean(constants.OKtetoDeployRemote),
-				CfgMapHandler:      NewConfigmapHandler(k8sClientProvider),
-				Fs:                 afero.NewOsFs(),
-				PipelineCMD:        pc,
-				runningInInstaller: config.RunningInInstaller(),
+				GetExternalControl:   NewDeployExternalK8sControl,
+				K8sClientProvider:    k8sClientProvider,
+				GetDeployer:          GetDeployer,
+				Builder:              buildv2.NewBuilderFromScratch(),
+				DeployWaiter:         NewDeployWaiter(k8sClientProvider),
+				EndpointGetter:       NewEndpointGetter,
+				isRemote:             utils.LoadBoolean(constants.OKtetoDeployRemote),
+				CfgMapHandler:        NewConfigmapHandler(k8sClientProvider),
+				Fs:                   afero.NewOsFs(),
+				PipelineCMD:          pc,
+				VarProfiles:          varprofiles.NewStore(afero.NewOsFs()),
+				ResolveDependencyRef: repository.ResolveRemoteRef,
+				runningInInstaller:   config.RunningInInstaller(),
 			}
 			startTime := time.Now()
 
@@ -261,13 +417,36 @@ func Deploy(ctx context.Context) *cobra.Command {
 	cmd.Flags().StringVarP(&options.ManifestPath, "file", "f", "", "path to the okteto manifest file")
 	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "overwrites the namespace where the development environment is deployed")
 	cmd.Flags().StringVarP(&options.K8sContext, "context", "c", "", "context where the development environment is deployed")
-	cmd.Flags().StringArrayVarP(&options.Variables, "var", "v", []string{}, "set a variable (can be set more than once)")
+	cmd.Flags().StringArrayVarP(&options.Variables, "var", "v", []string{}, "set a variable (can be set more than once). Use 'ctx:KEY=VALUE' to set it only in one of '--contexts'")
+	cmd.Flags().StringVarP(&options.Contexts, utils.ContextsFlag, "", "", "comma separated list of contexts where the development environment is deployed, one after the other")
+	cmd.Flags().BoolVarP(&options.ParallelContexts, utils.ParallelContextsFlag, "", false, "deploy in every context of '--contexts' at the same time")
 	cmd.Flags().BoolVarP(&options.Build, "build", "", false, "force build of images when deploying the development environment")
 	cmd.Flags().BoolVarP(&options.Dependencies, "dependencies", "", false, "deploy the dependencies from manifest")
 	cmd.Flags().BoolVarP(&options.RunWithoutBash, "no-bash", "", false, "execute commands without bash")
 	cmd.Flags().BoolVarP(&options.RunInRemote, "remote", "", false, "force run deploy commands in remote")
-
-	cmd.Flags().BoolVarP(&options.Wait, "wait", "w", false, "wait until the development environment is deployed (defaults to false)")
+	cmd.Flags().BoolVarP(&options.RunLocal, "local", "", false, "force run deploy commands locally, overriding OKTETO_FORCE_REMOTE and the manifest 'deploy.image'")
+	cmd.Flags().StringVarP(&options.RemoteRunImage, "remote-run-image", "", "", "image used to run the deploy commands in remote (overrides the manifest 'deploy.image')")
+	cmd.Flags().BoolVarP(&options.ListVariables, "list-vars", "", false, "list the variables declared in the okteto manifest and their current values")
+	cmd.Flags().StringVarP(&options.ExportManifests, "export-manifests", "", "", "write the kubernetes resources the deploy would apply to a folder, one file per resource, without deploying")
+	cmd.Flags().BoolVarP(&options.Resume, "resume", "", false, "skip the deploy commands that completed in the previous deploy and whose inputs didn't change")
+	cmd.Flags().BoolVarP(&options.NoResume, "no-resume", "", false, "run all the deploy commands, ignoring the ones completed in the previous deploy")
+	cmd.Flags().StringVarP(&options.DefaultResources, "default-resources", "", "", "resources applied to the containers without requests/limits (e.g. cpu=100m,memory=128Mi,limits.cpu=500m)")
+	cmd.Flags().BoolVarP(&options.UploadArtifacts, "upload-artifacts", "", false, "upload the resolved manifest, helm values files and applied objects to Okteto, with secrets redacted (defaults to the Okteto instance policy)")
+	cmd.Flags().BoolVarP(&options.AllowClusterResources, "allow-cluster-resources", "", false, "allow the deploy commands to apply cluster-scoped resources, like ClusterRoles or CRDs")
+	cmd.Flags().BoolVarP(&options.NoTrail, "no-trail", "", false, "do not record the changes that the deploy commands make to the cluster (shown by 'okteto status --trail')")
+	cmd.Flags().StringArrayVarP(&options.Commands, "command", "", []string{}, "run only the deploy command with this name (can be set more than once). The commands run in manifest order")
+	cmd.Flags().BoolVarP(&options.Locked, "locked", "", false, "re-deploy the dependencies from the SHAs recorded by the previous deploy instead of their branch heads")
+	cmd.Flags().BoolVarP(&options.UpdateDeps, "update-deps", "", false, "re-deploy the dependencies from the latest SHA of their branch or tag and record it for '--locked'")
+	cmd.Flags().BoolVarP(&options.StrictImages, "strict-images", "", false, "fail the deploy if the workloads reference images that can't be found, instead of warning")
+	cmd.Flags().BoolVarP(&options.DryRun, "dry-run", "", false, "print the commands, variables, builds and dependencies of the deploy instead of running it. With '--remote', print the dockerfile used to deploy in remote, with the okteto token redacted")
+	cmd.Flags().BoolVarP(&options.InsecureSkipTLSVerify, "insecure-skip-tls-verify", "", false, "skip the verification of the okteto server certificate in the deploy run in remote. This will make its connections insecure")
+	cmd.Flags().BoolVarP(&options.RequireClean, utils.RequireCleanFlag, "", false, "fail the deploy run in remote if the working tree has uncommitted changes, instead of warning")
+	cmd.Flags().StringVarP(&options.FollowBuild, "follow-build", "", "", "display the full build logs of a service, the other services only show their progress")
+	cmd.Flags().StringVarP(&options.SaveVars, "save-vars", "", "", "save the resolved variables in a profile, secret variables are saved by name only")
+	cmd.Flags().StringVarP(&options.FromSavedVars, "from-saved-vars", "", "", "use the variables of a profile saved with '--save-vars', the ones set with '--var' take priority")
+	cmd.Flags().BoolVarP(&options.EnforceResources, "enforce-resources", "", false, "override the requests/limits of every container with the default resources")
+
+	cmd.Flags().BoolVarP(&options.Wait, "wait", "w", false, "wait until the deployments, statefulsets and jobs of the development environment are ready (defaults to false)")
 	cmd.Flags().DurationVarP(&options.Timeout, "timeout", "t", getDefaultTimeout(), "the length of time to wait for completion, zero means never. Any other values should contain a corresponding time unit e.g. 1s, 2m, 3h ")
 
 	return cmd
@@ -290,6 +469,36 @@ func (dc *DeployCommand) RunDeploy(ctx context.Context, deployOptions *Options)
 	if len(deployOptions.servicesToDeploy) > 0 && deployOptions.Manifest.Deploy != nil && deployOptions.Manifest.Deploy.ComposeSection == nil {
 		return oktetoErrors.ErrDeployCantDeploySvcsIfNotCompose
 	}
+	if len(deployOptions.Commands) > 0 {
+		if deployOptions.Manifest.Deploy == nil {
+			return oktetoErrors.ErrManifestFoundButNoDeployCommands
+		}
+		commands, err := selectDeployCommands(deployOptions.Manifest.Deploy.Commands, deployOptions.Commands)
+		if err != nil {
+			return err
+		}
+		deployOptions.Manifest.Deploy.Commands = commands
+	}
+
+	if deployOptions.FromSavedVars != "" {
+		if err := dc.loadSavedVariables(ctx, deployOptions); err != nil {
+			return err
+		}
+	}
+	if err := deployOptions.Manifest.Variables.ApplyDefaults(os.LookupEnv, os.Setenv); err != nil {
+		return err
+	}
+	if err := deployOptions.Manifest.Variables.CheckRequired(os.LookupEnv); err != nil {
+		return oktetoErrors.UserError{
+			E:    err,
+			Hint: "Set them using the '--var' flag. Run 'okteto deploy --list-vars' to see the variables declared in your okteto manifest",
+		}
+	}
+	if deployOptions.SaveVars != "" {
+		if err := dc.saveVariables(ctx, deployOptions); err != nil {
+			return err
+		}
+	}
 
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -308,6 +517,24 @@ func (dc *DeployCommand) RunDeploy(ctx context.Context, deployOptions *Options)
 		return err
 	}
 
+	// the dry-run only prints the plan or renders the remote dockerfile, so nothing is stored in the cluster
+	if deployOptions.DryRun {
+		if !deployOptions.RunInRemote {
+			plan, err := dc.getDeployPlan(ctx, deployOptions)
+			if err != nil {
+				return err
+			}
+			return printDeployPlan(os.Stdout, plan, oktetoLog.GetOutputFormat())
+		}
+		if deployOptions.Manifest.Deploy == nil {
+			return oktetoErrors.ErrManifestFoundButNoDeployCommands
+		}
+		if deployOptions.RemoteRunImage != "" {
+			deployOptions.Manifest.Deploy.Image = deployOptions.RemoteRunImage
+		}
+		return newRemoteDeployer(dc.Builder).deploy(ctx, deployOptions)
+	}
+
 	if dc.isRemote || dc.runningInInstaller {
 		currentVars, err := dc.CfgMapHandler.getConfigmapVariablesEncoded(ctx, deployOptions.Name, deployOptions.Manifest.Namespace)
 		if err != nil {
@@ -341,6 +568,7 @@ func (dc *DeployCommand) RunDeploy(ctx context.Context, deployOptions *Options)
 	if err != nil {
 		return err
 	}
+	chaos.FailAt(chaos.DeployConfigMapCreatedStage)
 
 	os.Setenv(constants.OktetoNameEnvVar, deployOptions.Name)
 
@@ -358,6 +586,10 @@ func (dc *DeployCommand) RunDeploy(ctx context.Context, deployOptions *Options)
 	if err := buildImages(ctx, dc.Builder.Build, dc.Builder.GetServicesToBuild, deployOptions); err != nil {
 		return dc.CfgMapHandler.updateConfigMap(ctx, cfg, data, err)
 	}
+	chaos.FailAt(chaos.DeployImagesBuiltStage)
+	if dc.Builder != nil {
+		deployOptions.builtImages = getBuiltImages(dc.Builder.GetBuildEnvVars())
+	}
 
 	if err := dc.recreateFailedPods(ctx, deployOptions.Name); err != nil {
 		oktetoLog.Infof("failed to recreate failed pods: %s", err.Error())
@@ -373,9 +605,14 @@ func (dc *DeployCommand) RunDeploy(ctx context.Context, deployOptions *Options)
 		if err == oktetoErrors.ErrIntSig {
 			return nil
 		}
-		err = oktetoErrors.UserError{E: err}
+		// keep the hint of the errors that are already user errors
+		var userErr oktetoErrors.UserError
+		if !errors.As(err, &userErr) {
+			err = oktetoErrors.UserError{E: err}
+		}
 		data.Status = pipeline.ErrorStatus
 	} else {
+		chaos.FailAt(chaos.DeployCommandsExecutedStage)
 		oktetoLog.SetStage("")
 		hasDeployed, err := pipeline.HasDeployedSomething(ctx, deployOptions.Name, deployOptions.Manifest.Namespace, c)
 		if err != nil {
@@ -407,6 +644,7 @@ func (dc *DeployCommand) RunDeploy(ctx context.Context, deployOptions *Options)
 		data.Status = pipeline.DeployedStatus
 	}
 
+	data.Dirty = deployOptions.dirtyWorkingTree
 	if err := dc.CfgMapHandler.updateConfigMap(ctx, cfg, data, err); err != nil {
 		return err
 	}
@@ -415,26 +653,14 @@ func (dc *DeployCommand) RunDeploy(ctx context.Context, deployOptions *Options)
 }
 
 func buildImages(ctx context.Context, build func(context.Context, *types.BuildOptions) error, getServicesToBuild func(context.Context, *model.Manifest, []string) ([]string, error), deployOptions *Options) error {
-	var stackServicesWithBuild map[string]bool
-
-	if stack := deployOptions.Manifest.GetStack(); stack != nil {
-		stackServicesWithBuild = stack.GetServicesWithBuildSection()
-	}
-
-	allServicesWithBuildSection := deployOptions.Manifest.GetBuildServices()
-	oktetoManifestServicesWithBuild := setDifference(allServicesWithBuildSection, stackServicesWithBuild) // Warning: this way of getting the oktetoManifestServicesWithBuild is highly dependent on the manifest struct as it is now. We are assuming that: *okteto* manifest build = manifest build - stack build section
-	servicesToDeployWithBuild := setIntersection(allServicesWithBuildSection, sliceToSet(deployOptions.servicesToDeploy))
-	// We need to build:
-	// - All the services that have a build section defined in the *okteto* manifest
-	// - Services from *deployOptions.servicesToDeploy* that have a build section
-
-	servicesToBuildSet := setUnion(oktetoManifestServicesWithBuild, servicesToDeployWithBuild)
+	servicesToBuildSet := getServicesToBuildSet(deployOptions)
 
 	if deployOptions.Build {
 		buildOptions := &types.BuildOptions{
 			EnableStages: true,
 			Manifest:     deployOptions.Manifest,
 			CommandArgs:  setToSlice(servicesToBuildSet),
+			Renderer:     newBuildRenderer(deployOptions, servicesToBuildSet),
 		}
 		oktetoLog.Debug("force build from manifest definition")
 		if errBuild := build(ctx, buildOptions); errBuild != nil {
@@ -451,6 +677,7 @@ func buildImages(ctx context.Context, build func(context.Context, *types.BuildOp
 				EnableStages: true,
 				Manifest:     deployOptions.Manifest,
 				CommandArgs:  servicesToBuild,
+				Renderer:     newBuildRenderer(deployOptions, sliceToSet(servicesToBuild)),
 			}
 
 			if errBuild := build(ctx, buildOptions); errBuild != nil {
@@ -462,6 +689,32 @@ func buildImages(ctx context.Context, build func(context.Context, *types.BuildOp
 	return nil
 }
 
+// getServicesToBuildSet returns the services with a build section that are built by the deploy
+func getServicesToBuildSet(deployOptions *Options) map[string]bool {
+	var stackServicesWithBuild map[string]bool
+
+	if stack := deployOptions.Manifest.GetStack(); stack != nil {
+		stackServicesWithBuild = stack.GetServicesWithBuildSection()
+	}
+
+	allServicesWithBuildSection := deployOptions.Manifest.GetBuildServices()
+	oktetoManifestServicesWithBuild := setDifference(allServicesWithBuildSection, stackServicesWithBuild) // Warning: this way of getting the oktetoManifestServicesWithBuild is highly dependent on the manifest struct as it is now. We are assuming that: *okteto* manifest build = manifest build - stack build section
+	servicesToDeployWithBuild := setIntersection(allServicesWithBuildSection, sliceToSet(deployOptions.servicesToDeploy))
+	// We need to build:
+	// - All the services that have a build section defined in the *okteto* manifest
+	// - Services from *deployOptions.servicesToDeploy* that have a build section
+	return setUnion(oktetoManifestServicesWithBuild, servicesToDeployWithBuild)
+}
+
+// newBuildRenderer returns the renderer of the builds of the services, with one progress line per service in a tty
+func newBuildRenderer(deployOptions *Options, servicesToBuild map[string]bool) *oktetoLog.BuildRenderer {
+	if deployOptions.FollowBuild != "" && !servicesToBuild[deployOptions.FollowBuild] {
+		oktetoLog.Warning("The service '%s' set with '--follow-build' is not built by this deploy", deployOptions.FollowBuild)
+	}
+	tty := oktetoLog.GetOutputFormat() == oktetoLog.TTYFormat && oktetoLog.IsInteractive()
+	return oktetoLog.NewBuildRenderer(oktetoLog.GetOutput(), tty, deployOptions.FollowBuild)
+}
+
 func sliceToSet[T comparable](slice []T) map[T]bool {
 	set := make(map[T]bool)
 	for _, value := range slice {
@@ -526,14 +779,28 @@ func getDefaultTimeout() time.Duration {
 	return parsed
 }
 
+// errResumeInRemote is returned when '--resume' is used with a deploy that runs in remote
+var errResumeInRemote = oktetoErrors.UserError{
+	E:    fmt.Errorf("flag '--resume' can't be used when the deploy runs in remote"),
+	Hint: "The commands completed by a deploy in remote are not recorded. Run 'okteto deploy --local --resume' or deploy in remote without '--resume'",
+}
+
 func GetDeployer(ctx context.Context, manifest *model.Manifest, opts *Options, builder *buildv2.OktetoBuilder, cmapHandler configMapHandler) (deployerInterface, error) {
 
 	// isDeployRemote represents wheather the process is comming from a remote deploy
 	// if true it should get the local deployer
 	isDeployRemote := utils.LoadBoolean(constants.OKtetoDeployRemote)
 
-	// remote deployment should be done when flag RunInRemote is active OR deploy.image is fulfilled
-	if !isDeployRemote && (opts.RunInRemote || opts.Manifest.Deploy.Image != "") {
+	if !isDeployRemote && opts.RemoteRunImage != "" {
+		opts.Manifest.Deploy.Image = opts.RemoteRunImage
+	}
+
+	// remote deployment should be done when flag RunInRemote is active, OKTETO_FORCE_REMOTE is set or deploy.image is fulfilled
+	if !isDeployRemote && utils.ShouldRunInRemote(opts.RunInRemote, opts.RunLocal, opts.Manifest.Deploy.Image, os.LookupEnv) {
+		// the deploy commands completed in remote are not tracked, so they can't be skipped
+		if opts.Resume {
+			return nil, errResumeInRemote
+		}
 		// run remote
 		oktetoLog.Info("Deploying remotely...")
 		return newRemoteDeployer(builder), nil
@@ -551,6 +818,21 @@ func GetDeployer(ctx context.Context, manifest *model.Manifest, opts *Options, b
 
 // deployDependencies deploy the dependencies in the manifest
 func (dc *DeployCommand) deployDependencies(ctx context.Context, deployOptions *Options) error {
+	if len(deployOptions.Manifest.Dependencies) == 0 {
+		return nil
+	}
+	// the deploy run in remote doesn't deploy the dependencies again, they are deployed by the main
+	// execution, which is the one recording their revisions for '--locked' and '--update-deps'
+	if dc.isRemote {
+		oktetoLog.Infof("skipping the dependencies, they are deployed by the main execution")
+		return nil
+	}
+	recorded, err := dc.CfgMapHandler.getDependencyRevisions(ctx, deployOptions.Name, deployOptions.Manifest.Namespace)
+	if err != nil {
+		return err
+	}
+	revisions := map[string]string{}
+
 	for depName, dep := range deployOptions.Manifest.Dependencies {
 		oktetoLog.Information("Deploying dependency '%s'", depName)
 		oktetoLog.SetStage(fmt.Sprintf("Deploying dependency %s", depName))
@@ -562,15 +844,24 @@ func (dc *DeployCommand) deployDependencies(ctx context.Context, deployOptions *
 		if dep.Namespace != "" {
 			namespace = dep.Namespace
 		}
+
+		ref, revision, err := dc.getDependencyRevision(ctx, depName, dep, recorded, deployOptions)
+		if err != nil {
+			return err
+		}
+		if revision != "" {
+			revisions[depName] = revision
+		}
+
 		pipOpts := &pipelineCMD.DeployOptions{
 			Name:         depName,
 			Repository:   dep.Repository,
-			Branch:       dep.Branch,
+			Branch:       ref,
 			File:         dep.ManifestPath,
 			Variables:    model.SerializeEnvironmentVars(dep.Variables),
 			Wait:         dep.Wait,
 			Timeout:      dep.GetTimeout(deployOptions.Timeout),
-			SkipIfExists: !deployOptions.Dependencies,
+			SkipIfExists: !deployOptions.Dependencies && !deployOptions.Locked && !deployOptions.UpdateDeps,
 			Namespace:    namespace,
 		}
 
@@ -579,6 +870,10 @@ func (dc *DeployCommand) deployDependencies(ctx context.Context, deployOptions *
 		}
 	}
 	oktetoLog.SetStage("")
+
+	if err := dc.CfgMapHandler.updateDependencyRevisions(ctx, deployOptions.Name, deployOptions.Manifest.Namespace, revisions); err != nil {
+		oktetoLog.Infof("could not record the revisions of the dependencies: %s", err)
+	}
 	return nil
 }
 
@@ -38,6 +38,7 @@ import (
 	"github.com/okteto/okteto/pkg/types"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/apps/v1"
 	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -161,6 +162,20 @@ type fakeProxy struct {
 	token         string
 	started       bool
 	shutdown      bool
+
+	appliedObjects [][]byte
+
+	allowClusterResources   bool
+	clusterResources        []pipeline.ClusterResource
+	refusedClusterResources []pipeline.ClusterResource
+
+	recordTrail   bool
+	trailCommands []int
+	trail         []pipeline.TrailEntry
+
+	builtImages       []string
+	strictImages      bool
+	unreachableImages []unreachableImage
 }
 
 type fakeExecutor struct {
@@ -174,6 +189,10 @@ type fakeKubeConfig struct {
 
 type fakeCmapHandler struct {
 	errUpdatingWithEnvs error
+	stages              []pipeline.DeployStage
+	clusterResources    []pipeline.ClusterResource
+	trail               []pipeline.TrailEntry
+	revisions           map[string]string
 }
 
 func (*fakeCmapHandler) translateConfigMapAndDeploy(context.Context, *pipeline.CfgData) (*apiv1.ConfigMap, error) {
@@ -192,6 +211,34 @@ func (f *fakeCmapHandler) updateEnvsFromCommands(context.Context, string, string
 	return f.errUpdatingWithEnvs
 }
 
+func (f *fakeCmapHandler) getDeployStages(context.Context, string, string) ([]pipeline.DeployStage, error) {
+	return f.stages, nil
+}
+
+func (f *fakeCmapHandler) addClusterResources(_ context.Context, _, _ string, resources []pipeline.ClusterResource) error {
+	f.clusterResources = append(f.clusterResources, resources...)
+	return nil
+}
+
+func (f *fakeCmapHandler) updateTrail(_ context.Context, _, _ string, trail []pipeline.TrailEntry) error {
+	f.trail = trail
+	return nil
+}
+
+func (f *fakeCmapHandler) updateDeployStages(_ context.Context, _, _ string, stages []pipeline.DeployStage) error {
+	f.stages = stages
+	return nil
+}
+
+func (f *fakeCmapHandler) getDependencyRevisions(context.Context, string, string) (map[string]string, error) {
+	return f.revisions, nil
+}
+
+func (f *fakeCmapHandler) updateDependencyRevisions(_ context.Context, _, _ string, revisions map[string]string) error {
+	f.revisions = revisions
+	return nil
+}
+
 func (*fakeKubeConfig) Read() (*rest.Config, error) {
 	return nil, nil
 }
@@ -211,6 +258,36 @@ func (*fakeProxy) SetName(_ string) {}
 
 func (*fakeProxy) SetDivert(_ divert.Driver) {}
 
+func (*fakeProxy) SetDefaultResources(_ *model.ResourceRequirements, _ bool) {}
+
+func (*fakeProxy) RecordAppliedObjects() {}
+
+func (fk *fakeProxy) GetAppliedObjects() [][]byte { return fk.appliedObjects }
+
+func (fk *fakeProxy) SetAllowClusterResources(allow bool) { fk.allowClusterResources = allow }
+
+func (fk *fakeProxy) GetClusterResources() (applied, refused []pipeline.ClusterResource) {
+	return fk.clusterResources, fk.refusedClusterResources
+}
+
+func (fk *fakeProxy) RecordTrail() { fk.recordTrail = true }
+
+func (fk *fakeProxy) SetTrailCommand(i int) { fk.trailCommands = append(fk.trailCommands, i) }
+
+func (fk *fakeProxy) GetTrail() []pipeline.TrailEntry {
+	if !fk.recordTrail {
+		return nil
+	}
+	return fk.trail
+}
+
+func (fk *fakeProxy) CheckImages(builtImages []string, strict bool) {
+	fk.builtImages = builtImages
+	fk.strictImages = strict
+}
+
+func (fk *fakeProxy) GetUnreachableImages() []unreachableImage { return fk.unreachableImages }
+
 func (fk *fakeProxy) Shutdown(_ context.Context) error {
 	if fk.errOnShutdown != nil {
 		return fk.errOnShutdown
@@ -289,10 +366,11 @@ func TestDeployWithErrorReadingManifestFile(t *testing.T) {
 		GetManifest: getManifestWithError,
 		GetDeployer: func(ctx context.Context, manifest *model.Manifest, opts *Options, _ *buildv2.OktetoBuilder, _ configMapHandler) (deployerInterface, error) {
 			return &localDeployer{
-				Proxy:      p,
-				Executor:   e,
-				Kubeconfig: &fakeKubeConfig{},
-				Fs:         afero.NewMemMapFs(),
+				ConfigMapHandler: &fakeCmapHandler{},
+				Proxy:            p,
+				Executor:         e,
+				Kubeconfig:       &fakeKubeConfig{},
+				Fs:               afero.NewMemMapFs(),
 			}, nil
 		},
 		K8sClientProvider: test.NewFakeK8sProvider(),
@@ -328,10 +406,11 @@ func TestDeployWithNeitherDeployNorDependencyInManifestFile(t *testing.T) {
 		GetManifest: getManifestWithNoDeployNorDependency,
 		GetDeployer: func(ctx context.Context, manifest *model.Manifest, opts *Options, _ *buildv2.OktetoBuilder, _ configMapHandler) (deployerInterface, error) {
 			return &localDeployer{
-				Proxy:      p,
-				Executor:   e,
-				Kubeconfig: &fakeKubeConfig{},
-				Fs:         afero.NewMemMapFs(),
+				ConfigMapHandler: &fakeCmapHandler{},
+				Proxy:            p,
+				Executor:         e,
+				Kubeconfig:       &fakeKubeConfig{},
+				Fs:               afero.NewMemMapFs(),
 			}, nil
 		},
 		K8sClientProvider: test.NewFakeK8sProvider(),
@@ -372,6 +451,7 @@ func TestCreateConfigMapWithBuildError(t *testing.T) {
 		GetManifest: getErrorManifest,
 		GetDeployer: func(ctx context.Context, manifest *model.Manifest, opts *Options, _ *buildv2.OktetoBuilder, _ configMapHandler) (deployerInterface, error) {
 			return &localDeployer{
+				ConfigMapHandler:  &fakeCmapHandler{},
 				Proxy:             p,
 				Executor:          e,
 				Kubeconfig:        &fakeKubeConfig{},
@@ -447,6 +527,7 @@ func TestDeployWithErrorExecutingCommands(t *testing.T) {
 		GetManifest: getFakeManifest,
 		GetDeployer: func(ctx context.Context, manifest *model.Manifest, opts *Options, _ *buildv2.OktetoBuilder, _ configMapHandler) (deployerInterface, error) {
 			return &localDeployer{
+				ConfigMapHandler:  &fakeCmapHandler{},
 				Proxy:             p,
 				Executor:          e,
 				Kubeconfig:        &fakeKubeConfig{},
@@ -527,6 +608,7 @@ func TestDeployWithErrorBecauseOtherPipelineRunning(t *testing.T) {
 		GetManifest: getFakeManifest,
 		GetDeployer: func(ctx context.Context, manifest *model.Manifest, opts *Options, _ *buildv2.OktetoBuilder, _ configMapHandler) (deployerInterface, error) {
 			return &localDeployer{
+				ConfigMapHandler:  &fakeCmapHandler{},
 				Proxy:             p,
 				Executor:          e,
 				Kubeconfig:        &fakeKubeConfig{},
@@ -706,6 +788,67 @@ func TestDeployWithoutErrors(t *testing.T) {
 	assert.Equal(t, pipeline.DeployedStatus, cfg.Data["status"])
 }
 
+func TestDeployOnlySelectedCommands(t *testing.T) {
+	p := &fakeProxy{}
+	e := &fakeExecutor{}
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Namespace: "test",
+			},
+		},
+		CurrentContext: "test",
+	}
+	cp := fakeExternalControlProvider{
+		control: &fakeExternalControl{},
+	}
+	clientProvider := test.NewFakeK8sProvider()
+	c := &DeployCommand{
+		GetManifest: func(_ string) (*model.Manifest, error) {
+			return &model.Manifest{
+				Deploy: &model.DeployInfo{
+					Commands: append([]model.DeployCommand{}, fakeManifest.Deploy.Commands...),
+				},
+			}, nil
+		},
+		K8sClientProvider:  clientProvider,
+		EndpointGetter:     getFakeEndpoint,
+		GetExternalControl: cp.getFakeExternalControl,
+		Fs:                 afero.NewMemMapFs(),
+		CfgMapHandler:      newDefaultConfigMapHandler(clientProvider),
+		GetDeployer: func(ctx context.Context, manifest *model.Manifest, opts *Options, _ *buildv2.OktetoBuilder, _ configMapHandler) (deployerInterface, error) {
+			return &localDeployer{
+				Proxy:              p,
+				Executor:           e,
+				Kubeconfig:         &fakeKubeConfig{},
+				ConfigMapHandler:   &fakeCmapHandler{},
+				K8sClientProvider:  clientProvider,
+				GetExternalControl: cp.getFakeExternalControl,
+				Fs:                 afero.NewMemMapFs(),
+			}, nil
+		},
+	}
+	ctx := context.Background()
+
+	opts := &Options{
+		Name:      "movies",
+		Variables: []string{},
+		Commands:  []string{"cat /tmp/test.txt", "printenv"},
+	}
+	require.NoError(t, c.RunDeploy(ctx, opts))
+	assert.Equal(t, []model.DeployCommand{fakeManifest.Deploy.Commands[0], fakeManifest.Deploy.Commands[2]}, e.executed)
+
+	e.executed = nil
+	opts = &Options{
+		Name:      "movies",
+		Variables: []string{},
+		Commands:  []string{"printenv", "unknown"},
+	}
+	err := c.RunDeploy(ctx, opts)
+	assert.ErrorAs(t, err, &oktetoErrors.UserError{})
+	assert.Empty(t, e.executed)
+}
+
 func getManifestWithError(_ string) (*model.Manifest, error) {
 	return nil, assert.AnError
 }
@@ -1049,7 +1192,8 @@ func TestDeployDependencies(t *testing.T) {
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
 			dc := &DeployCommand{
-				PipelineCMD: fakePipelineDeployer{tc.config.pipelineErr},
+				PipelineCMD:   fakePipelineDeployer{tc.config.pipelineErr},
+				CfgMapHandler: &fakeCmapHandler{},
 			}
 			assert.ErrorIs(t, tc.expected, dc.deployDependencies(context.Background(), &Options{Manifest: fakeManifest}))
 		})
@@ -1110,3 +1254,159 @@ func TestDeployOnlyDependencies(t *testing.T) {
 
 	assert.NoError(t, err)
 }
+
+func TestGetDeployerWithRemoteRunImage(t *testing.T) {
+	t.Setenv(constants.OKtetoDeployRemote, "false")
+	opts := &Options{
+		RemoteRunImage: "okteto/runner:test",
+		Manifest: &model.Manifest{
+			Deploy: &model.DeployInfo{
+				Image: "okteto/runner:manifest",
+			},
+		},
+	}
+	deployer, err := GetDeployer(context.Background(), opts.Manifest, opts, nil, &fakeCmapHandler{})
+	assert.NoError(t, err)
+	assert.IsType(t, &remoteDeployCommand{}, deployer)
+	assert.Equal(t, "okteto/runner:test", opts.Manifest.Deploy.Image)
+}
+
+func TestGetDeployerRejectsResumeInRemote(t *testing.T) {
+	t.Setenv(constants.OKtetoDeployRemote, "false")
+	opts := &Options{
+		Resume: true,
+		Manifest: &model.Manifest{
+			Deploy: &model.DeployInfo{
+				Image: "okteto/runner:manifest",
+			},
+		},
+	}
+	_, err := GetDeployer(context.Background(), opts.Manifest, opts, nil, &fakeCmapHandler{})
+	assert.ErrorIs(t, err, errResumeInRemote)
+}
+
+func TestDeployWithClusterResources(t *testing.T) {
+	crd := pipeline.ClusterResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions", Name: "foos.example.com"}
+	var tests = []struct {
+		name          string
+		proxy         *fakeProxy
+		expectedErr   string
+		expectedCmaps []pipeline.ClusterResource
+	}{
+		{
+			name: "refused cluster resources are listed",
+			proxy: &fakeProxy{
+				refusedClusterResources: []pipeline.ClusterResource{clusterRoleResource, crd, clusterRoleResource},
+			},
+			expectedErr: "the deploy tried to apply cluster-scoped resources: ClusterRole 'reader', customresourcedefinitions 'foos.example.com'",
+		},
+		{
+			name: "applied cluster resources are recorded",
+			proxy: &fakeProxy{
+				clusterResources: []pipeline.ClusterResource{clusterRoleResource},
+			},
+			expectedCmaps: []pipeline.ClusterResource{clusterRoleResource},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			okteto.CurrentStore = &okteto.OktetoContextStore{
+				Contexts: map[string]*okteto.OktetoContext{
+					"test": {
+						Namespace: "test",
+					},
+				},
+				CurrentContext: "test",
+			}
+			clientProvider := test.NewFakeK8sProvider()
+			cmapHandler := &fakeCmapHandler{}
+			c := &DeployCommand{
+				GetManifest: getFakeManifest,
+				GetDeployer: func(context.Context, *model.Manifest, *Options, *buildv2.OktetoBuilder, configMapHandler) (deployerInterface, error) {
+					return &localDeployer{
+						ConfigMapHandler:  cmapHandler,
+						Proxy:             tt.proxy,
+						Executor:          &fakeExecutor{},
+						Kubeconfig:        &fakeKubeConfig{},
+						K8sClientProvider: clientProvider,
+						Fs:                afero.NewMemMapFs(),
+					}, nil
+				},
+				K8sClientProvider: clientProvider,
+				CfgMapHandler:     newDefaultConfigMapHandler(clientProvider),
+				Fs:                afero.NewMemMapFs(),
+			}
+
+			err := c.RunDeploy(context.Background(), &Options{Name: "movies", Variables: []string{}})
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				var userErr oktetoErrors.UserError
+				require.ErrorAs(t, err, &userErr)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				assert.Contains(t, userErr.Hint, "allowClusterResources")
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedCmaps, cmapHandler.clusterResources)
+		})
+	}
+}
+
+func TestDeployWithUnreachableImages(t *testing.T) {
+	unreachable := []unreachableImage{{Workload: "Deployment/api", Image: "okteto/api:latst", Err: errImageNotFound}}
+	var tests = []struct {
+		name         string
+		strictImages bool
+		expectedErr  string
+	}{
+		{
+			name: "warns",
+		},
+		{
+			name:         "strict images fail the deploy",
+			strictImages: true,
+			expectedErr:  "the deploy references images that can't be found: okteto/api:latst (used by Deployment/api)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			okteto.CurrentStore = &okteto.OktetoContextStore{
+				Contexts: map[string]*okteto.OktetoContext{
+					"test": {
+						Namespace: "test",
+					},
+				},
+				CurrentContext: "test",
+			}
+			clientProvider := test.NewFakeK8sProvider()
+			proxy := &fakeProxy{unreachableImages: unreachable}
+			c := &DeployCommand{
+				GetManifest: getFakeManifest,
+				GetDeployer: func(context.Context, *model.Manifest, *Options, *buildv2.OktetoBuilder, configMapHandler) (deployerInterface, error) {
+					return &localDeployer{
+						ConfigMapHandler:  &fakeCmapHandler{},
+						Proxy:             proxy,
+						Executor:          &fakeExecutor{},
+						Kubeconfig:        &fakeKubeConfig{},
+						K8sClientProvider: clientProvider,
+						Fs:                afero.NewMemMapFs(),
+					}, nil
+				},
+				K8sClientProvider: clientProvider,
+				CfgMapHandler:     newDefaultConfigMapHandler(clientProvider),
+				Fs:                afero.NewMemMapFs(),
+			}
+
+			err := c.RunDeploy(context.Background(), &Options{Name: "movies", Variables: []string{}, StrictImages: tt.strictImages})
+			assert.Equal(t, tt.strictImages, proxy.strictImages)
+			if tt.expectedErr != "" {
+				var userErr oktetoErrors.UserError
+				require.ErrorAs(t, err, &userErr)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				assert.Contains(t, userErr.Hint, "--strict-images")
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
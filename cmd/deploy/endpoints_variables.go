@@ -0,0 +1,70 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"fmt"
+	"os"
+
+	envsubst "github.com/a8m/envsubst/parse"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/model"
+)
+
+// expandEndpoints returns the endpoints with the variables of their names, paths and services expanded. The variables
+// are looked up in 'variables', the last value of a variable taking precedence, and then in the environment, which
+// has the '--var' flags and the OKTETO_BUILD_<SVC>_* variables of the images built by the deploy
+func expandEndpoints(endpoints model.EndpointSpec, variables []string) (model.EndpointSpec, error) {
+	env := make([]string, 0, len(variables))
+	// the parser uses the first value of a variable
+	for i := len(variables) - 1; i >= 0; i-- {
+		env = append(env, variables[i])
+	}
+	env = append(env, os.Environ()...)
+	expand := func(value string) (string, error) {
+		return envsubst.New("endpoints", env, &envsubst.Restrictions{}).Parse(value)
+	}
+
+	result := make(model.EndpointSpec, len(endpoints))
+	for name, endpoint := range endpoints {
+		expandedName, err := expand(name)
+		if err != nil {
+			return nil, newEndpointsExpansionError(name, "name", err)
+		}
+		if _, ok := result[expandedName]; ok {
+			return nil, newEndpointsExpansionError(name, "name", fmt.Errorf("the endpoint '%s' is already defined", expandedName))
+		}
+
+		rules := make([]model.EndpointRule, 0, len(endpoint.Rules))
+		for _, rule := range endpoint.Rules {
+			if rule.Path, err = expand(rule.Path); err != nil {
+				return nil, newEndpointsExpansionError(name, "path", err)
+			}
+			if rule.Service, err = expand(rule.Service); err != nil {
+				return nil, newEndpointsExpansionError(name, "service", err)
+			}
+			rules = append(rules, rule)
+		}
+		endpoint.Rules = rules
+		result[expandedName] = endpoint
+	}
+	return result, nil
+}
+
+func newEndpointsExpansionError(endpoint, field string, err error) error {
+	return oktetoErrors.UserError{
+		E:    fmt.Errorf("could not expand the %s of the endpoint '%s' in the 'deploy.endpoints' section of your okteto manifest: %w", field, endpoint, err),
+		Hint: "Check the syntax of the variables used in the 'deploy.endpoints' section, like '${VERSION}' or '${OKTETO_BUILD_API_TAG}'",
+	}
+}
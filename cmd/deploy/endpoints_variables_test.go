@@ -0,0 +1,146 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"testing"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandEndpoints(t *testing.T) {
+	endpoints := model.EndpointSpec{
+		"api-${ENVIRONMENT}": model.Endpoint{
+			Rules: []model.EndpointRule{
+				{Path: "/api/${VERSION}", Service: "api-${OKTETO_BUILD_API_TAG}", Port: 8080},
+				{Path: "/", Service: "frontend", Port: 80},
+			},
+		},
+	}
+
+	var tests = []struct {
+		name      string
+		env       map[string]string
+		variables []string
+		expected  model.EndpointSpec
+	}{
+		{
+			name: "variables from flags",
+			env: map[string]string{
+				"ENVIRONMENT":          "dev",
+				"VERSION":              "v1",
+				"OKTETO_BUILD_API_TAG": "",
+			},
+			expected: model.EndpointSpec{
+				"api-dev": model.Endpoint{
+					Rules: []model.EndpointRule{
+						{Path: "/api/v1", Service: "api-", Port: 8080},
+						{Path: "/", Service: "frontend", Port: 80},
+					},
+				},
+			},
+		},
+		{
+			name: "variables from the okteto env file take precedence",
+			env: map[string]string{
+				"ENVIRONMENT": "dev",
+				"VERSION":     "v1",
+			},
+			variables: []string{"VERSION=v2", "ENVIRONMENT=staging", "VERSION=v3"},
+			expected: model.EndpointSpec{
+				"api-staging": model.Endpoint{
+					Rules: []model.EndpointRule{
+						{Path: "/api/v3", Service: "api-", Port: 8080},
+						{Path: "/", Service: "frontend", Port: 80},
+					},
+				},
+			},
+		},
+		{
+			name: "variables from the build outputs",
+			env: map[string]string{
+				"ENVIRONMENT":          "dev",
+				"VERSION":              "v1",
+				"OKTETO_BUILD_API_TAG": "sha256-123",
+			},
+			expected: model.EndpointSpec{
+				"api-dev": model.Endpoint{
+					Rules: []model.EndpointRule{
+						{Path: "/api/v1", Service: "api-sha256-123", Port: 8080},
+						{Path: "/", Service: "frontend", Port: 80},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OKTETO_BUILD_API_TAG", "")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			result, err := expandEndpoints(endpoints, tt.variables)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	// the endpoints of the manifest are not modified
+	assert.Equal(t, "/api/${VERSION}", endpoints["api-${ENVIRONMENT}"].Rules[0].Path)
+}
+
+func TestExpandEndpointsErrors(t *testing.T) {
+	var tests = []struct {
+		name      string
+		endpoints model.EndpointSpec
+		expected  string
+	}{
+		{
+			name: "invalid path",
+			endpoints: model.EndpointSpec{
+				"api": model.Endpoint{Rules: []model.EndpointRule{{Path: "/api/${VERSION", Service: "api"}}},
+			},
+			expected: "could not expand the path of the endpoint 'api' in the 'deploy.endpoints' section of your okteto manifest",
+		},
+		{
+			name: "invalid service",
+			endpoints: model.EndpointSpec{
+				"api": model.Endpoint{Rules: []model.EndpointRule{{Path: "/", Service: "${SERVICE"}}},
+			},
+			expected: "could not expand the service of the endpoint 'api' in the 'deploy.endpoints' section of your okteto manifest",
+		},
+		{
+			name: "duplicated name",
+			endpoints: model.EndpointSpec{
+				"api":         model.Endpoint{},
+				"${ENDPOINT}": model.Endpoint{},
+			},
+			expected: "the endpoint 'api' is already defined",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ENDPOINT", "api")
+			_, err := expandEndpoints(tt.endpoints, nil)
+			var userErr oktetoErrors.UserError
+			require.ErrorAs(t, err, &userErr)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
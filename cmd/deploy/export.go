@@ -0,0 +1,427 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kballard/go-shellquote"
+	"github.com/okteto/okteto/pkg/cmd/stack"
+	"github.com/okteto/okteto/pkg/format"
+	"github.com/okteto/okteto/pkg/k8s/ingresses"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/spf13/afero"
+	yaml3 "gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	exportIndexFileName = "index.yaml"
+
+	// shellOperators can't be rendered without running the command in a shell
+	shellOperators = "&|;<>$`"
+)
+
+// exportIndex lists the resources written by '--export-manifests' and the deploy steps that couldn't be rendered
+type exportIndex struct {
+	Resources     []exportedResource  `json:"resources"`
+	NonRenderable []nonRenderableStep `json:"nonRenderable,omitempty"`
+}
+
+// exportedResource is a resource written to its own file
+type exportedResource struct {
+	File       string `json:"file"`
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Source     string `json:"source"`
+}
+
+// nonRenderableStep is a deploy step whose resources are only known when it runs
+type nonRenderableStep struct {
+	Source  string `json:"source"`
+	Command string `json:"command,omitempty"`
+	Reason  string `json:"reason"`
+}
+
+// commandRunner runs a command and returns its standard output
+type commandRunner func(ctx context.Context, dir, name string, args ...string) ([]byte, error)
+
+// manifestExporter renders the resources a deploy would apply without connecting to the cluster
+type manifestExporter struct {
+	fs  afero.Fs
+	run commandRunner
+	// wd is the folder where the deploy commands run
+	wd string
+	// ingressV1 renders the ingresses with networking.k8s.io/v1, the default of the supported clusters
+	ingressV1 bool
+
+	index exportIndex
+	// documents are the resources to write, in the same order as index.Resources
+	documents []map[string]interface{}
+}
+
+func newManifestExporter(fs afero.Fs, wd string) *manifestExporter {
+	return &manifestExporter{
+		fs:        fs,
+		run:       runCommandOutput,
+		wd:        wd,
+		ingressV1: true,
+	}
+}
+
+// runCommandOutput runs the command in dir and returns its standard output
+func runCommandOutput(ctx context.Context, dir, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("'%s %s' failed: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// exportManifests writes the resources the deploy would apply into the folder of the options instead of deploying them
+func exportManifests(ctx context.Context, opts *Options) error {
+	manifest, err := model.GetManifestV2(opts.ManifestPath)
+	if err != nil {
+		return err
+	}
+	if err := manifest.Variables.ApplyDefaults(os.LookupEnv, os.Setenv); err != nil {
+		return err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get the current working directory: %w", err)
+	}
+	name := opts.Name
+	if name == "" {
+		name = manifest.Name
+	}
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = manifest.Namespace
+	}
+
+	e := newManifestExporter(afero.NewOsFs(), cwd)
+	if err := e.export(ctx, manifest, name, namespace, opts.ExportManifests); err != nil {
+		return err
+	}
+	oktetoLog.Success("%d resources exported to '%s'", len(e.index.Resources), opts.ExportManifests)
+	if len(e.index.NonRenderable) > 0 {
+		oktetoLog.Warning("%d deploy steps can't be rendered without running them, they are listed in '%s'", len(e.index.NonRenderable), filepath.Join(opts.ExportManifests, exportIndexFileName))
+	}
+	return nil
+}
+
+// export writes every resource of the deploy of the manifest as a file in dir, and the index listing them
+func (e *manifestExporter) export(ctx context.Context, manifest *model.Manifest, name, namespace, dir string) error {
+	if manifest.Deploy == nil {
+		return errors.New("the okteto manifest doesn't have a deploy section")
+	}
+
+	for _, command := range manifest.Deploy.Commands {
+		if err := e.renderCommand(ctx, command); err != nil {
+			return err
+		}
+	}
+
+	if manifest.Deploy.ComposeSection != nil && manifest.Deploy.ComposeSection.Stack != nil {
+		s := manifest.Deploy.ComposeSection.Stack
+		s.Namespace = namespace
+		// as in the deploy, the name of the development environment overrides the one of the compose
+		if name != "" {
+			s.Name = name
+		}
+		for _, obj := range stack.Render(s, e.ingressV1) {
+			if err := e.addObject(obj, "compose"); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(manifest.Deploy.Endpoints) > 0 {
+		translateOptions := &ingresses.TranslateOptions{
+			Namespace: namespace,
+			Name:      format.ResourceK8sMetaString(name),
+		}
+		endpoints, err := expandEndpoints(manifest.Deploy.Endpoints, nil)
+		if err != nil {
+			return err
+		}
+		for _, endpointName := range sortedKeys(endpoints) {
+			ingress := ingresses.Translate(endpointName, endpoints[endpointName], translateOptions)
+			var obj runtime.Object = ingress.V1Beta1
+			if e.ingressV1 {
+				obj = ingress.V1
+			}
+			if err := e.addObject(obj, "endpoints"); err != nil {
+				return err
+			}
+		}
+	}
+
+	if manifest.Deploy.Divert != nil {
+		e.addNonRenderable("divert", "", "the divert resources are created from the resources running in the cluster")
+	}
+	for _, externalName := range sortedKeys(manifest.External) {
+		e.addNonRenderable(fmt.Sprintf("external: %s", externalName), "", "the external resources are created from the environment of the deploy commands")
+	}
+	for _, dependencyName := range sortedKeys(manifest.Dependencies) {
+		e.addNonRenderable(fmt.Sprintf("dependency: %s", dependencyName), "", "the dependencies are deployed as okteto pipelines")
+	}
+
+	return e.write(dir)
+}
+
+// renderCommand adds the resources applied by the command when they are known without running it
+func (e *manifestExporter) renderCommand(ctx context.Context, command model.DeployCommand) error {
+	source := fmt.Sprintf("command: %s", command.Name)
+	if strings.ContainsAny(command.Command, shellOperators) {
+		e.addNonRenderable(source, command.Command, "the command uses shell operators or variables")
+		return nil
+	}
+	args, err := shellquote.Split(command.Command)
+	if err != nil || len(args) < 2 {
+		e.addNonRenderable(source, command.Command, "the command is not a helm, kustomize or kubectl apply command")
+		return nil
+	}
+
+	var output []byte
+	switch {
+	case args[0] == "helm" && (args[1] == "install" || args[1] == "upgrade"):
+		output, err = e.run(ctx, e.wd, "helm", getHelmTemplateArgs(args[2:])...)
+	case args[0] == "kubectl" && args[1] == "apply":
+		kustomization, files, ok := getKubectlApplySources(args[2:])
+		if !ok {
+			e.addNonRenderable(source, command.Command, "the command applies remote or standard input manifests")
+			return nil
+		}
+		if kustomization != "" {
+			output, err = e.run(ctx, e.wd, "kubectl", "kustomize", kustomization)
+		} else {
+			output, err = e.readManifestFiles(files)
+		}
+	default:
+		e.addNonRenderable(source, command.Command, "the command is not a helm, kustomize or kubectl apply command")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not render the command '%s': %w", command.Name, err)
+	}
+	return e.addDocuments(output, source)
+}
+
+// helmUpgradeOnlyFlags are the flags of 'helm upgrade' not supported by 'helm template'. The value tells if they take an argument
+var helmUpgradeOnlyFlags = map[string]bool{
+	"--install":         false,
+	"-i":                false,
+	"--atomic":          false,
+	"--wait":            false,
+	"--wait-for-jobs":   false,
+	"--cleanup-on-fail": false,
+	"--force":           false,
+	"--reset-values":    false,
+	"--reuse-values":    false,
+	"--timeout":         true,
+	"--history-max":     true,
+}
+
+// getHelmTemplateArgs returns the 'helm template' arguments equivalent to the ones of 'helm install' or 'helm upgrade'
+func getHelmTemplateArgs(args []string) []string {
+	result := []string{"template"}
+	for i := 0; i < len(args); i++ {
+		flagName, _, hasValue := strings.Cut(args[i], "=")
+		takesValue, upgradeOnly := helmUpgradeOnlyFlags[flagName]
+		if !upgradeOnly {
+			result = append(result, args[i])
+			continue
+		}
+		if takesValue && !hasValue {
+			i++
+		}
+	}
+	return result
+}
+
+// getKubectlApplySources returns the kustomization or the files applied by 'kubectl apply'.
+// It returns false when they are urls or the standard input
+func getKubectlApplySources(args []string) (string, []string, bool) {
+	var kustomization string
+	var files []string
+	for i := 0; i < len(args); i++ {
+		flagName, value, hasValue := strings.Cut(args[i], "=")
+		if flagName != "-f" && flagName != "--filename" && flagName != "-k" && flagName != "--kustomize" {
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return "", nil, false
+			}
+			i++
+			value = args[i]
+		}
+		if value == "-" || strings.Contains(value, "://") {
+			return "", nil, false
+		}
+		if flagName == "-k" || flagName == "--kustomize" {
+			kustomization = value
+			continue
+		}
+		files = append(files, value)
+	}
+	if kustomization == "" && len(files) == 0 {
+		return "", nil, false
+	}
+	return kustomization, files, true
+}
+
+// readManifestFiles returns the content of the files, or the yaml and json files of the folders, as a multi-document yaml
+func (e *manifestExporter) readManifestFiles(paths []string) ([]byte, error) {
+	var result bytes.Buffer
+	for _, path := range paths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(e.wd, path)
+		}
+		files := []string{path}
+		info, err := e.fs.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			files = nil
+			for _, ext := range []string{"*.yaml", "*.yml", "*.json"} {
+				matches, err := afero.Glob(e.fs, filepath.Join(path, ext))
+				if err != nil {
+					return nil, err
+				}
+				files = append(files, matches...)
+			}
+		}
+		for _, file := range files {
+			content, err := afero.ReadFile(e.fs, file)
+			if err != nil {
+				return nil, err
+			}
+			result.WriteString("\n---\n")
+			result.Write(content)
+		}
+	}
+	return result.Bytes(), nil
+}
+
+// addDocuments adds every resource of a multi-document yaml
+func (e *manifestExporter) addDocuments(content []byte, source string) error {
+	decoder := yaml3.NewDecoder(bytes.NewReader(content))
+	for {
+		var doc map[string]interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("invalid yaml rendered by '%s': %w", source, err)
+		}
+		if len(doc) == 0 {
+			continue
+		}
+		e.addDocument(doc, source)
+	}
+}
+
+// addObject adds a resource translated by okteto
+func (e *manifestExporter) addObject(obj runtime.Object, source string) error {
+	gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+	if err != nil {
+		return err
+	}
+	doc, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	doc["apiVersion"], doc["kind"] = gvks[0].GroupVersion().String(), gvks[0].Kind
+	e.addDocument(doc, source)
+	return nil
+}
+
+func (e *manifestExporter) addDocument(doc map[string]interface{}, source string) {
+	apiVersion, _ := doc["apiVersion"].(string)
+	kind, _ := doc["kind"].(string)
+	name := ""
+	if metadata, ok := doc["metadata"].(map[string]interface{}); ok {
+		name, _ = metadata["name"].(string)
+	}
+	fileName := fmt.Sprintf("%03d-%s-%s.yaml", len(e.documents)+1, strings.ToLower(kind), name)
+	e.index.Resources = append(e.index.Resources, exportedResource{
+		File:       fileName,
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Name:       name,
+		Source:     source,
+	})
+	e.documents = append(e.documents, doc)
+}
+
+func (e *manifestExporter) addNonRenderable(source, command, reason string) {
+	e.index.NonRenderable = append(e.index.NonRenderable, nonRenderableStep{
+		Source:  source,
+		Command: command,
+		Reason:  reason,
+	})
+}
+
+// write writes a file per resource and the index in dir
+func (e *manifestExporter) write(dir string) error {
+	if err := e.fs.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	for i, doc := range e.documents {
+		content, err := yaml.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		if err := afero.WriteFile(e.fs, filepath.Join(dir, e.index.Resources[i].File), content, 0600); err != nil {
+			return err
+		}
+	}
+	content, err := yaml.Marshal(e.index)
+	if err != nil {
+		return err
+	}
+	return afero.WriteFile(e.fs, filepath.Join(dir, exportIndexFileName), content, 0600)
+}
+
+// sortedKeys returns the keys of m in alphabetical order
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,211 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files")
+
+const helmTemplateOutput = `---
+# Source: api/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+spec:
+  ports:
+  - port: 8080
+---
+# Source: api/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  template:
+    spec:
+      containers:
+      - name: api
+        image: okteto/api
+`
+
+func TestExportManifests(t *testing.T) {
+	fixture, err := filepath.Abs(filepath.Join("testdata", "export"))
+	require.NoError(t, err)
+	golden, err := filepath.Abs(filepath.Join("testdata", "export-golden"))
+	require.NoError(t, err)
+
+	// the deploy loads the manifest from its folder
+	initialCWD, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(fixture))
+	defer func() {
+		require.NoError(t, os.Chdir(initialCWD))
+	}()
+	manifest, err := model.GetManifestV2("okteto.yml")
+	require.NoError(t, err)
+
+	var helmArgs []string
+	e := newManifestExporter(afero.NewOsFs(), fixture)
+	e.run = func(_ context.Context, dir, name string, args ...string) ([]byte, error) {
+		assert.Equal(t, fixture, dir)
+		assert.Equal(t, "helm", name)
+		helmArgs = args
+		return []byte(helmTemplateOutput), nil
+	}
+
+	out := t.TempDir()
+	require.NoError(t, e.export(context.Background(), manifest, "movies", "test", out))
+	assert.Equal(t, []string{"template", "api", "chart", "--set", "image=okteto/api"}, helmArgs)
+
+	if *updateGolden {
+		require.NoError(t, os.RemoveAll(golden))
+		require.NoError(t, os.MkdirAll(golden, 0700))
+		files, err := os.ReadDir(out)
+		require.NoError(t, err)
+		for _, f := range files {
+			content, err := os.ReadFile(filepath.Join(out, f.Name()))
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(golden, f.Name()), content, 0600))
+		}
+	}
+
+	assert.Equal(t, readDirFiles(t, golden), readDirFiles(t, out))
+}
+
+// readDirFiles returns the content of the files of dir by name
+func readDirFiles(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	result := map[string]string{}
+	for _, f := range files {
+		content, err := os.ReadFile(filepath.Join(dir, f.Name()))
+		require.NoError(t, err)
+		result[f.Name()] = string(content)
+	}
+	return result
+}
+
+func TestExportManifestsEndpoints(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	manifest := &model.Manifest{
+		Deploy: &model.DeployInfo{
+			Endpoints: model.EndpointSpec{
+				"web": model.Endpoint{
+					Rules: []model.EndpointRule{{Path: "/", Service: "frontend", Port: 80}},
+				},
+			},
+		},
+	}
+
+	e := newManifestExporter(fs, "/app")
+	require.NoError(t, e.export(context.Background(), manifest, "movies", "test", "/out"))
+	require.Len(t, e.index.Resources, 1)
+	assert.Equal(t, exportedResource{
+		File:       "001-ingress-web.yaml",
+		APIVersion: "networking.k8s.io/v1",
+		Kind:       "Ingress",
+		Name:       "web",
+		Source:     "endpoints",
+	}, e.index.Resources[0])
+
+	files, err := afero.ReadDir(fs, "/out")
+	require.NoError(t, err)
+	names := []string{}
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"001-ingress-web.yaml", "index.yaml"}, names)
+}
+
+func TestGetHelmTemplateArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name:     "install",
+			args:     []string{"api", "chart", "-f", "values.yaml"},
+			expected: []string{"template", "api", "chart", "-f", "values.yaml"},
+		},
+		{
+			name:     "upgrade only flags",
+			args:     []string{"--install", "api", "chart", "--atomic", "--timeout", "5m", "--history-max=3", "--namespace", "test"},
+			expected: []string{"template", "api", "chart", "--namespace", "test"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, getHelmTemplateArgs(tt.args))
+		})
+	}
+}
+
+func TestGetKubectlApplySources(t *testing.T) {
+	tests := []struct {
+		name                  string
+		args                  []string
+		expectedKustomization string
+		expectedFiles         []string
+		expectedOK            bool
+	}{
+		{
+			name:          "files",
+			args:          []string{"-f", "k8s.yml", "--filename=manifests"},
+			expectedFiles: []string{"k8s.yml", "manifests"},
+			expectedOK:    true,
+		},
+		{
+			name:                  "kustomization",
+			args:                  []string{"--namespace", "test", "-k", "overlays/dev"},
+			expectedKustomization: "overlays/dev",
+			expectedOK:            true,
+		},
+		{
+			name: "standard input",
+			args: []string{"-f", "-"},
+		},
+		{
+			name: "url",
+			args: []string{"-f", "https://example.com/k8s.yml"},
+		},
+		{
+			name: "no sources",
+			args: []string{"--prune"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kustomization, files, ok := getKubectlApplySources(tt.args)
+			assert.Equal(t, tt.expectedKustomization, kustomization)
+			assert.Equal(t, tt.expectedFiles, files)
+			assert.Equal(t, tt.expectedOK, ok)
+		})
+	}
+}
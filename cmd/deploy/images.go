@@ -0,0 +1,330 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxImageLookups bounds the registry requests in flight while checking the images of a deploy
+const maxImageLookups = 8
+
+var errImageNotFound = errors.New("image not found")
+
+// workloadKinds are the kinds of the resources with a pod spec, by resource name
+var workloadKinds = map[string]string{
+	"pods":                   "Pod",
+	"deployments":            "Deployment",
+	"statefulsets":           "StatefulSet",
+	"daemonsets":             "DaemonSet",
+	"replicasets":            "ReplicaSet",
+	"replicationcontrollers": "ReplicationController",
+	"jobs":                   "Job",
+	"cronjobs":               "CronJob",
+}
+
+// podSpecPaths are the fields to follow to get the pod spec of a workload, by resource name
+var podSpecPaths = map[string][]string{
+	"pods":                   {"spec"},
+	"deployments":            {"spec", "template", "spec"},
+	"statefulsets":           {"spec", "template", "spec"},
+	"daemonsets":             {"spec", "template", "spec"},
+	"replicasets":            {"spec", "template", "spec"},
+	"replicationcontrollers": {"spec", "template", "spec"},
+	"jobs":                   {"spec", "template", "spec"},
+	"cronjobs":               {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// workloadImage is an image referenced by a workload, e.g. 'Deployment/api'
+type workloadImage struct {
+	Workload string
+	Image    string
+}
+
+// unreachableImage is an image referenced by a workload that doesn't exist or couldn't be checked
+type unreachableImage struct {
+	Workload string
+	Image    string
+	Err      error
+}
+
+func (u unreachableImage) String() string {
+	return fmt.Sprintf("%s (used by %s): %s", u.Image, u.Workload, u.Err)
+}
+
+// getWorkloadImages returns the container images of the workload created, updated or patched by the request
+func getWorkloadImages(r *http.Request, body []byte) []workloadImage {
+	if len(body) == 0 || r.URL.Query().Get("dryRun") != "" {
+		return nil
+	}
+	_, _, rest, ok := splitAPIPath(r.URL.Path)
+	if !ok || len(rest) == 0 {
+		return nil
+	}
+	if rest[0] == "namespaces" && len(rest) > 2 {
+		rest = rest[2:]
+	}
+	// subresources like 'status' or 'scale' don't change the images
+	if len(rest) > 2 {
+		return nil
+	}
+	path, ok := podSpecPaths[rest[0]]
+	if !ok {
+		return nil
+	}
+
+	var obj struct {
+		Metadata metav1.ObjectMeta `json:"metadata"`
+	}
+	// json patches are lists of operations and are not checked
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil
+	}
+	workloadName := obj.Metadata.Name
+	if len(rest) == 2 {
+		workloadName = rest[1]
+	}
+
+	raw := json.RawMessage(body)
+	for _, field := range path {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil
+		}
+		if raw, ok = fields[field]; !ok {
+			return nil
+		}
+	}
+	type container struct {
+		Image string `json:"image"`
+	}
+	var podSpec struct {
+		InitContainers      []container `json:"initContainers"`
+		Containers          []container `json:"containers"`
+		EphemeralContainers []container `json:"ephemeralContainers"`
+	}
+	if err := json.Unmarshal(raw, &podSpec); err != nil {
+		return nil
+	}
+
+	workload := fmt.Sprintf("%s/%s", workloadKinds[rest[0]], workloadName)
+	seen := map[string]bool{}
+	var result []workloadImage
+	for _, containers := range [][]container{podSpec.InitContainers, podSpec.Containers, podSpec.EphemeralContainers} {
+		for _, c := range containers {
+			if c.Image == "" || seen[c.Image] {
+				continue
+			}
+			seen[c.Image] = true
+			result = append(result, workloadImage{Workload: workload, Image: c.Image})
+		}
+	}
+	return result
+}
+
+// imageChecker checks that the images referenced by the workloads exist in their registries.
+// Every image is looked up once per deploy
+type imageChecker struct {
+	imageExists func(image string) (bool, error)
+	// builtRepositories are the repositories of the images built by the deploy, which are not checked
+	builtRepositories map[string]bool
+	sem               chan struct{}
+
+	mu      sync.Mutex
+	lookups map[string]*imageLookup
+}
+
+type imageLookup struct {
+	done chan struct{}
+	err  error
+}
+
+func newImageChecker(imageExists func(image string) (bool, error), builtImages []string) *imageChecker {
+	builtRepositories := map[string]bool{}
+	for _, image := range builtImages {
+		builtRepositories[getImageRepository(image)] = true
+	}
+	return &imageChecker{
+		imageExists:       imageExists,
+		builtRepositories: builtRepositories,
+		sem:               make(chan struct{}, maxImageLookups),
+		lookups:           map[string]*imageLookup{},
+	}
+}
+
+// getImageRepository returns the image without tag or digest, or the image itself if it isn't a valid reference
+func getImageRepository(image string) string {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return image
+	}
+	return ref.Context().Name()
+}
+
+// check returns the images that don't exist or couldn't be checked
+func (ic *imageChecker) check(images []workloadImage) []unreachableImage {
+	errs := make([]error, len(images))
+	var wg sync.WaitGroup
+	for i, image := range images {
+		if ic.builtRepositories[getImageRepository(image.Image)] {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, image string) {
+			defer wg.Done()
+			errs[i] = ic.lookup(image)
+		}(i, image.Image)
+	}
+	wg.Wait()
+
+	var result []unreachableImage
+	for i, err := range errs {
+		if err != nil {
+			result = append(result, unreachableImage{Workload: images[i].Workload, Image: images[i].Image, Err: err})
+		}
+	}
+	return result
+}
+
+// lookup returns nil if the image exists. Concurrent lookups of the same image wait for the first one
+func (ic *imageChecker) lookup(image string) error {
+	ic.mu.Lock()
+	l, ok := ic.lookups[image]
+	if !ok {
+		l = &imageLookup{done: make(chan struct{})}
+		ic.lookups[image] = l
+	}
+	ic.mu.Unlock()
+	if ok {
+		<-l.done
+		return l.err
+	}
+
+	ic.sem <- struct{}{}
+	exists, err := ic.imageExists(image)
+	<-ic.sem
+	if err != nil {
+		l.err = err
+	} else if !exists {
+		l.err = errImageNotFound
+	}
+	close(l.done)
+	return l.err
+}
+
+func (ph *proxyHandler) setImageChecker(checker *imageChecker, strict bool) {
+	ph.objectsMu.Lock()
+	defer ph.objectsMu.Unlock()
+	ph.imageChecker = checker
+	ph.strictImages = strict
+}
+
+func (ph *proxyHandler) getImageChecker() (*imageChecker, bool) {
+	ph.objectsMu.Lock()
+	defer ph.objectsMu.Unlock()
+	return ph.imageChecker, ph.strictImages
+}
+
+// admitImages checks the images of the workload applied by the request. It returns the unreachable images
+// and if the request can be sent to the cluster, which is refused only with strict images
+func (ph *proxyHandler) admitImages(r *http.Request, body []byte) ([]unreachableImage, bool) {
+	checker, strict := ph.getImageChecker()
+	if checker == nil {
+		return nil, true
+	}
+	unreachable := checker.check(getWorkloadImages(r, body))
+	if len(unreachable) == 0 {
+		return nil, true
+	}
+
+	ph.objectsMu.Lock()
+	defer ph.objectsMu.Unlock()
+	for _, u := range unreachable {
+		isRecorded := false
+		for _, recorded := range ph.unreachableImages {
+			if recorded.Workload == u.Workload && recorded.Image == u.Image {
+				isRecorded = true
+				break
+			}
+		}
+		if !isRecorded {
+			ph.unreachableImages = append(ph.unreachableImages, u)
+		}
+	}
+	return unreachable, !strict
+}
+
+func (ph *proxyHandler) getUnreachableImages() []unreachableImage {
+	ph.objectsMu.Lock()
+	defer ph.objectsMu.Unlock()
+	return append([]unreachableImage{}, ph.unreachableImages...)
+}
+
+// writeUnreachableImagesInvalid answers the request with a kubernetes status so clients show the reason
+func writeUnreachableImagesInvalid(rw http.ResponseWriter, unreachable []unreachableImage) {
+	status := metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Message:  fmt.Sprintf("the images of %s are not reachable: %s", unreachable[0].Workload, listUnreachableImages(unreachable)),
+		Reason:   metav1.StatusReasonInvalid,
+		Code:     http.StatusUnprocessableEntity,
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusUnprocessableEntity)
+	if err := json.NewEncoder(rw).Encode(status); err != nil {
+		oktetoLog.Infof("could not write the response for %s: %s", unreachable[0].Workload, err)
+	}
+}
+
+func listUnreachableImages(unreachable []unreachableImage) string {
+	items := make([]string, 0, len(unreachable))
+	for _, u := range unreachable {
+		items = append(items, u.String())
+	}
+	return strings.Join(items, ", ")
+}
+
+// getBuiltImages returns the images built by the deploy from the okteto build env vars
+func getBuiltImages(buildEnvVars map[string]string) []string {
+	var images []string
+	for k, v := range buildEnvVars {
+		if strings.HasPrefix(k, "OKTETO_BUILD_") && strings.HasSuffix(k, "_IMAGE") && v != "" {
+			images = append(images, v)
+		}
+	}
+	return images
+}
+
+// warnUnreachableImages warns about the images that the workloads of the deploy can't pull
+func warnUnreachableImages(unreachable []unreachableImage) {
+	oktetoLog.Warning("The following images can't be found and the pods using them will fail to start: %s", listUnreachableImages(unreachable))
+}
+
+// newUnreachableImagesError lists the images that refused the deploy of their workloads
+func newUnreachableImagesError(unreachable []unreachableImage) error {
+	return oktetoErrors.UserError{
+		E:    fmt.Errorf("the deploy references images that can't be found: %s", listUnreachableImages(unreachable)),
+		Hint: "Check the image names and tags of your deploy commands, or run without '--strict-images' to only get a warning",
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	imagesDeploymentBody = `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"api"},"spec":{"template":{"spec":{"initContainers":[{"name":"init","image":"busybox"}],"containers":[{"name":"api","image":"okteto/api:latst"},{"name":"sidecar","image":"busybox"}]}}}}`
+	imagesCronJobBody    = `{"apiVersion":"batch/v1","kind":"CronJob","metadata":{"name":"backup"},"spec":{"jobTemplate":{"spec":{"template":{"spec":{"containers":[{"name":"backup","image":"okteto/backup:1.0"}]}}}}}}`
+)
+
+func TestGetWorkloadImages(t *testing.T) {
+	var tests = []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		expected []workloadImage
+	}{
+		{
+			name:   "deployment",
+			method: http.MethodPost,
+			url:    "/apis/apps/v1/namespaces/test/deployments",
+			body:   imagesDeploymentBody,
+			expected: []workloadImage{
+				{Workload: "Deployment/api", Image: "busybox"},
+				{Workload: "Deployment/api", Image: "okteto/api:latst"},
+			},
+		},
+		{
+			name:     "cronjob",
+			method:   http.MethodPut,
+			url:      "/apis/batch/v1/namespaces/test/cronjobs/backup",
+			body:     imagesCronJobBody,
+			expected: []workloadImage{{Workload: "CronJob/backup", Image: "okteto/backup:1.0"}},
+		},
+		{
+			name:     "pod",
+			method:   http.MethodPost,
+			url:      "/api/v1/namespaces/test/pods",
+			body:     `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"debug"},"spec":{"containers":[{"name":"debug","image":"alpine:3"}]}}`,
+			expected: []workloadImage{{Workload: "Pod/debug", Image: "alpine:3"}},
+		},
+		{
+			name:     "statefulset patch takes the name from the path",
+			method:   http.MethodPatch,
+			url:      "/apis/apps/v1/namespaces/test/statefulsets/db",
+			body:     `{"spec":{"template":{"spec":{"containers":[{"name":"db","image":"postgres:15"}]}}}}`,
+			expected: []workloadImage{{Workload: "StatefulSet/db", Image: "postgres:15"}},
+		},
+		{
+			name:   "patch without images",
+			method: http.MethodPatch,
+			url:    "/apis/apps/v1/namespaces/test/deployments/api",
+			body:   `{"spec":{"replicas":2}}`,
+		},
+		{
+			name:   "json patch",
+			method: http.MethodPatch,
+			url:    "/apis/apps/v1/namespaces/test/deployments/api",
+			body:   `[{"op":"replace","path":"/spec/template/spec/containers/0/image","value":"okteto/api:2"}]`,
+		},
+		{
+			name:   "not a workload",
+			method: http.MethodPost,
+			url:    "/api/v1/namespaces/test/configmaps",
+			body:   `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"api"}}`,
+		},
+		{
+			name:   "subresource",
+			method: http.MethodPut,
+			url:    "/apis/apps/v1/namespaces/test/deployments/api/status",
+			body:   imagesDeploymentBody,
+		},
+		{
+			name:   "dry run",
+			method: http.MethodPost,
+			url:    "/apis/apps/v1/namespaces/test/deployments?dryRun=All",
+			body:   imagesDeploymentBody,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.url, nil)
+			assert.Equal(t, tt.expected, getWorkloadImages(r, []byte(tt.body)))
+		})
+	}
+}
+
+func TestImageCheckerCachesLookups(t *testing.T) {
+	var calls int32
+	checker := newImageChecker(func(image string) (bool, error) {
+		atomic.AddInt32(&calls, 1)
+		// give the concurrent lookups of the same image the chance to overlap
+		time.Sleep(10 * time.Millisecond)
+		return image != "okteto/api:latst", nil
+	}, nil)
+
+	images := []workloadImage{
+		{Workload: "Deployment/api", Image: "okteto/api:latst"},
+		{Workload: "Deployment/worker", Image: "okteto/api:latst"},
+		{Workload: "Deployment/api", Image: "busybox"},
+	}
+	unreachable := checker.check(images)
+	require.Len(t, unreachable, 2)
+	assert.Equal(t, "Deployment/api", unreachable[0].Workload)
+	assert.Equal(t, "Deployment/worker", unreachable[1].Workload)
+	assert.ErrorIs(t, unreachable[0].Err, errImageNotFound)
+
+	checker.check(images)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestImageCheckerBoundsConcurrency(t *testing.T) {
+	var inFlight, maxInFlight int32
+	checker := newImageChecker(func(_ string) (bool, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		return true, nil
+	}, nil)
+
+	images := []workloadImage{}
+	for i := 0; i < 4*maxImageLookups; i++ {
+		images = append(images, workloadImage{Workload: "Deployment/api", Image: "okteto/api:" + strings.Repeat("1", i+1)})
+	}
+	assert.Empty(t, checker.check(images))
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(maxImageLookups))
+}
+
+func TestImageCheckerSkipsBuiltImages(t *testing.T) {
+	var mu sync.Mutex
+	var checked []string
+	checker := newImageChecker(func(image string) (bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		checked = append(checked, image)
+		return false, errors.New("unauthorized")
+	}, []string{"registry.okteto.example.com/test/api:okteto"})
+
+	unreachable := checker.check([]workloadImage{
+		{Workload: "Deployment/api", Image: "registry.okteto.example.com/test/api@sha256:0123456789012345678901234567890123456789012345678901234567890123"},
+		{Workload: "Deployment/web", Image: "registry.okteto.example.com/test/web:okteto"},
+	})
+	require.Len(t, unreachable, 1)
+	assert.Equal(t, "registry.okteto.example.com/test/web:okteto (used by Deployment/web): unauthorized", unreachable[0].String())
+	assert.Equal(t, []string{"registry.okteto.example.com/test/web:okteto"}, checked)
+}
+
+func TestGetBuiltImages(t *testing.T) {
+	images := getBuiltImages(map[string]string{
+		"OKTETO_BUILD_API_IMAGE":      "okteto.dev/api:1",
+		"OKTETO_BUILD_API_REGISTRY":   "okteto.dev",
+		"OKTETO_BUILD_WORKER_IMAGE":   "okteto.dev/worker:1",
+		"OKTETO_BUILD_WORKER_SHA":     "1",
+		"OKTETO_BUILD_FRONTEND_IMAGE": "",
+	})
+	sort.Strings(images)
+	assert.Equal(t, []string{"okteto.dev/api:1", "okteto.dev/worker:1"}, images)
+}
+
+func TestProxyChecksImages(t *testing.T) {
+	var received []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Method+" "+r.URL.Path)
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	imageExists := func(image string) (bool, error) { return image != "okteto/api:latst", nil }
+	var tests = []struct {
+		name         string
+		strict       bool
+		expectedCode int
+	}{
+		{
+			name:         "warns",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "strict refuses the workload",
+			strict:       true,
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = nil
+			ph := &proxyHandler{trailCommand: noTrailCommand}
+			ph.setImageChecker(newImageChecker(imageExists, nil), tt.strict)
+			handler, err := ph.getProxyHandler("token", &rest.Config{
+				Host:            server.URL,
+				TLSClientConfig: rest.TLSClientConfig{Insecure: true},
+			})
+			require.NoError(t, err)
+
+			for _, method := range []string{http.MethodPost, http.MethodPatch} {
+				url := "/apis/apps/v1/namespaces/test/deployments"
+				if method == http.MethodPatch {
+					url += "/api"
+				}
+				r := httptest.NewRequest(method, url, strings.NewReader(imagesDeploymentBody))
+				r.Header.Set("Authorization", "Bearer token")
+				rw := httptest.NewRecorder()
+				handler.ServeHTTP(rw, r)
+				assert.Equal(t, tt.expectedCode, rw.Code)
+				if tt.strict {
+					assert.Contains(t, rw.Body.String(), "okteto/api:latst (used by Deployment/api)")
+				}
+			}
+
+			if tt.strict {
+				assert.Empty(t, received)
+			} else {
+				assert.Len(t, received, 2)
+			}
+			unreachable := ph.getUnreachableImages()
+			require.Len(t, unreachable, 1)
+			assert.Equal(t, "okteto/api:latst", unreachable[0].Image)
+		})
+	}
+}
+
+func TestNewUnreachableImagesError(t *testing.T) {
+	err := newUnreachableImagesError([]unreachableImage{{Workload: "Deployment/api", Image: "okteto/api:latst", Err: errImageNotFound}})
+	assert.EqualError(t, err, "the deploy references images that can't be found: okteto/api:latst (used by Deployment/api): image not found")
+}
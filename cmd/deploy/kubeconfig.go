@@ -20,6 +20,7 @@ import (
 
 	"github.com/okteto/okteto/pkg/config"
 	"github.com/okteto/okteto/pkg/format"
+	"github.com/okteto/okteto/pkg/k8s/kubeconfig"
 	oktetoLog "github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/okteto"
 	"k8s.io/client-go/rest"
@@ -72,7 +73,7 @@ func (k *KubeConfig) Modify(port int, sessionToken, destKubeconfigFile string) e
 	}
 
 	// Save on disk the config changes
-	if err := clientcmd.WriteToFile(*proxyCfg, destKubeconfigFile); err != nil {
+	if err := kubeconfig.Write(proxyCfg, destKubeconfigFile); err != nil {
 		oktetoLog.Errorf("could not modify the k8s config: %s", err)
 		return err
 	}
@@ -19,14 +19,17 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/compose-spec/godotenv"
 	stackCMD "github.com/okteto/okteto/cmd/stack"
 	"github.com/okteto/okteto/cmd/utils/executor"
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
 	"github.com/okteto/okteto/pkg/cmd/stack"
 	"github.com/okteto/okteto/pkg/constants"
 	"github.com/okteto/okteto/pkg/devenvironment"
 	"github.com/okteto/okteto/pkg/divert"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
 	"github.com/okteto/okteto/pkg/format"
 	"github.com/okteto/okteto/pkg/k8s/ingresses"
 	kconfig "github.com/okteto/okteto/pkg/k8s/kubeconfig"
@@ -52,6 +55,8 @@ type localDeployer struct {
 	isRemote     bool
 	Fs           afero.Fs
 	DivertDriver divert.Driver
+
+	artifactsUploader artifactsUploader
 }
 
 // newLocalDeployer initializes a local deployer from a name and a boolean indicating if we should run with bash or not
@@ -121,6 +126,14 @@ func (ld *localDeployer) deploy(ctx context.Context, deployOptions *Options) err
 	}
 
 	ld.Proxy.SetName(format.ResourceK8sMetaString(deployOptions.Name))
+	defaultResources, err := getDefaultResources(deployOptions.Manifest, deployOptions.DefaultResources)
+	if err != nil {
+		return oktetoErrors.UserError{
+			E:    err,
+			Hint: "Use the format 'cpu=100m,memory=128Mi,limits.cpu=500m'",
+		}
+	}
+	ld.Proxy.SetDefaultResources(defaultResources, deployOptions.EnforceResources)
 	if deployOptions.Manifest.Deploy.Divert != nil {
 		driver, err := divert.New(deployOptions.Manifest, c)
 		if err != nil {
@@ -146,6 +159,7 @@ func (ld *localDeployer) deploy(ctx context.Context, deployOptions *Options) err
 
 	defer ld.cleanUp(ctx, nil)
 
+	deployOptions.userVariables = append([]string{}, deployOptions.Variables...)
 	for _, variable := range deployOptions.Variables {
 		value := strings.SplitN(variable, "=", 2)[1]
 		if strings.TrimSpace(value) != "" {
@@ -176,11 +190,67 @@ func (ld *localDeployer) deploy(ctx context.Context, deployOptions *Options) err
 			fmt.Sprintf("%s=%s", model.OktetoDomainEnvVar, okteto.GetSubdomain()),
 		)
 	}
+	if deployOptions.UploadArtifacts {
+		ld.Proxy.RecordAppliedObjects()
+	}
+	ld.Proxy.SetAllowClusterResources(isClusterResourcesAllowed(deployOptions))
+	if !deployOptions.NoTrail {
+		ld.Proxy.RecordTrail()
+	}
+	ld.Proxy.CheckImages(deployOptions.builtImages, deployOptions.StrictImages)
 	oktetoLog.EnableMasking()
 	err = ld.runDeploySection(ctx, deployOptions)
 	oktetoLog.DisableMasking()
-	oktetoLog.SetStage("done")
-	oktetoLog.AddToBuffer(oktetoLog.InfoLevel, "EOF")
+	if unreachable := ld.Proxy.GetUnreachableImages(); len(unreachable) > 0 {
+		if deployOptions.StrictImages {
+			// the failure of the deploy commands is caused by the refused requests
+			err = newUnreachableImagesError(unreachable)
+		} else {
+			warnUnreachableImages(unreachable)
+		}
+	}
+	appliedClusterResources, refusedClusterResources := ld.Proxy.GetClusterResources()
+	if len(refusedClusterResources) > 0 {
+		// the failure of the deploy commands is caused by the refused requests
+		err = newClusterResourcesRefusedError(refusedClusterResources)
+	}
+	if errRecord := ld.ConfigMapHandler.addClusterResources(ctx, deployOptions.Name, deployOptions.Manifest.Namespace, appliedClusterResources); errRecord != nil {
+		oktetoLog.Warning("could not record the cluster resources of the development environment: %s", errRecord)
+	}
+	// the trail of a previous deploy is removed when the trail is disabled, as it is no longer accurate
+	if errTrail := ld.ConfigMapHandler.updateTrail(ctx, deployOptions.Name, deployOptions.Manifest.Namespace, ld.Proxy.GetTrail()); errTrail != nil {
+		oktetoLog.Warning("could not record the trail of the development environment: %s", errTrail)
+	}
+	if deployOptions.UploadArtifacts {
+		if err := ld.uploadArtifacts(deployOptions); err != nil {
+			oktetoLog.Warning("could not upload the deploy artifacts: %s", err)
+		}
+	}
+	oktetoLog.FinishBuffer()
+	return err
+}
+
+// sleepBeforeRetry waits before running a failed deploy command again
+var sleepBeforeRetry = time.Sleep
+
+// executeWithRetries runs the command and runs it again up to 'retries' times when it fails. Before each retry
+// the $OKTETO_ENV file is read again, so the command gets the values written by the previous attempts
+func (ld *localDeployer) executeWithRetries(command model.DeployCommand, variables []string, oktetoEnvFile string) error {
+	err := ld.Executor.Execute(command, variables)
+	for attempt := 1; err != nil && attempt <= command.Retries; attempt++ {
+		oktetoLog.Warning("Command '%s' failed: %s. Retrying in %s (retry %d/%d)", command.Name, err, command.RetryInterval, attempt, command.Retries)
+		sleepBeforeRetry(command.RetryInterval)
+
+		envs, errRead := godotenv.Read(oktetoEnvFile)
+		if errRead != nil {
+			oktetoLog.Warning("no valid format used in the okteto env file: %s", errRead.Error())
+		}
+		retryVariables := append([]string{}, variables...)
+		for k, v := range envs {
+			retryVariables = append(retryVariables, fmt.Sprintf("%s=%s", k, v))
+		}
+		err = ld.Executor.Execute(command, retryVariables)
+	}
 	return err
 }
 
@@ -197,13 +267,34 @@ func (ld *localDeployer) runDeploySection(ctx context.Context, opts *Options) er
 	}()
 
 	var envMapFromOktetoEnvFile map[string]string
+	stages, skip, err := ld.getStagesToResume(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	if skip > 0 {
+		// restore the $OKTETO_ENV content generated by the skipped commands
+		envMapFromOktetoEnvFile = stages[skip-1].Envs
+		if err := ld.restoreOktetoEnvFile(oktetoEnvFile.Name(), envMapFromOktetoEnvFile, opts); err != nil {
+			return err
+		}
+	}
+
 	// deploy commands if any
-	for _, command := range opts.Manifest.Deploy.Commands {
+	for i, command := range opts.Manifest.Deploy.Commands {
+		if i < skip {
+			oktetoLog.Information("Skipping '%s': completed in the previous deploy and its inputs didn't change", command.Name)
+			continue
+		}
+
 		oktetoLog.Information("Running '%s'", command.Name)
 		oktetoLog.SetStage(command.Name)
 		oktetoLog.AddToBuffer(oktetoLog.InfoLevel, "Executing command '%s'...", command.Name)
 
-		if err := ld.Executor.Execute(command, opts.Variables); err != nil {
+		ld.Proxy.SetTrailCommand(i)
+		err = ld.executeWithRetries(command, opts.Variables, oktetoEnvFile.Name())
+		ld.Proxy.SetTrailCommand(noTrailCommand)
+		if err != nil {
 			oktetoLog.AddToBuffer(oktetoLog.ErrorLevel, "error executing command '%s': %s", command.Name, err.Error())
 			return fmt.Errorf("error executing command '%s': %s", command.Name, err.Error())
 		}
@@ -226,6 +317,11 @@ func (ld *localDeployer) runDeploySection(ctx context.Context, opts *Options) er
 		opts.Variables = append(opts.Variables, envsFromOktetoEnvFile...)
 		oktetoLog.SetStage("")
 		oktetoLog.SetLevel("")
+
+		stages[i].Envs = envMapFromOktetoEnvFile
+		if err := ld.ConfigMapHandler.updateDeployStages(ctx, opts.Name, opts.Manifest.Namespace, stages[:i+1]); err != nil {
+			return fmt.Errorf("could not update config map with the deploy stages: %w", err)
+		}
 	}
 
 	err = ld.ConfigMapHandler.updateEnvsFromCommands(ctx, opts.Name, opts.Manifest.Namespace, opts.Variables)
@@ -326,7 +422,13 @@ func (ld *localDeployer) deployEndpoints(ctx context.Context, opts *Options) err
 		Name:      format.ResourceK8sMetaString(opts.Manifest.Name),
 	}
 
-	for name, endpoint := range opts.Manifest.Deploy.Endpoints {
+	// the endpoints are expanded after the deploy commands, so they can use the variables of the images built
+	// and the ones the commands write to $OKTETO_ENV
+	endpoints, err := expandEndpoints(opts.Manifest.Deploy.Endpoints, opts.Variables)
+	if err != nil {
+		return err
+	}
+	for name, endpoint := range endpoints {
 		ingress := ingresses.Translate(name, endpoint, translateOptions)
 		if err := iClient.Deploy(ctx, ingress); err != nil {
 			return err
@@ -379,6 +481,63 @@ func (ld *localDeployer) cleanUp(ctx context.Context, err error) {
 	}
 }
 
+// getStagesToResume returns the stages of the deploy commands and how many of them can be skipped.
+// The stages of the commands that will run are reset in the configmap
+func (ld *localDeployer) getStagesToResume(ctx context.Context, opts *Options) ([]pipeline.DeployStage, int, error) {
+	commands := opts.Manifest.Deploy.Commands
+	hasher := newStageHasher(ld.Fs, ld.cwd, opts.userVariables)
+	stages := make([]pipeline.DeployStage, len(commands))
+	hashes := make([]string, len(commands))
+	for i, command := range commands {
+		hash, err := hasher.hash(command)
+		if err != nil {
+			return nil, 0, fmt.Errorf("could not compute the inputs of command '%s': %w", command.Name, err)
+		}
+		hashes[i] = hash
+		stages[i] = pipeline.DeployStage{Name: command.Name, Hash: hash}
+	}
+
+	skip := 0
+	if opts.Resume && !opts.NoResume {
+		previous, err := ld.ConfigMapHandler.getDeployStages(ctx, opts.Name, opts.Manifest.Namespace)
+		if err != nil {
+			return nil, 0, fmt.Errorf("could not get the deploy stages of the previous deploy: %w", err)
+		}
+		var reason string
+		skip, reason = getStagesToSkip(previous, commands, hashes)
+		for i := 0; i < skip; i++ {
+			stages[i].Envs = previous[i].Envs
+		}
+		if skip < len(commands) {
+			oktetoLog.Information("Resuming deploy from '%s': %s", commands[skip].Name, reason)
+		}
+	}
+
+	if err := ld.ConfigMapHandler.updateDeployStages(ctx, opts.Name, opts.Manifest.Namespace, stages[:skip]); err != nil {
+		return nil, 0, fmt.Errorf("could not update config map with the deploy stages: %w", err)
+	}
+	return stages, skip, nil
+}
+
+// restoreOktetoEnvFile writes the variables generated by the skipped commands to $OKTETO_ENV
+// and adds them to the variables of the next commands
+func (ld *localDeployer) restoreOktetoEnvFile(path string, envs map[string]string, opts *Options) error {
+	if len(envs) == 0 {
+		return nil
+	}
+	content, err := godotenv.Marshal(envs)
+	if err != nil {
+		return err
+	}
+	if err := afero.WriteFile(ld.Fs, path, []byte(content), 0600); err != nil {
+		return err
+	}
+	for k, v := range envs {
+		opts.Variables = append(opts.Variables, fmt.Sprintf("%s=%s", k, v))
+	}
+	return nil
+}
+
 func (ld *localDeployer) createTempOktetoEnvFile() (afero.File, error) {
 	oktetoEnvFileDir, err := afero.TempDir(ld.Fs, "", "")
 	if err != nil {
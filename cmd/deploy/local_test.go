@@ -15,10 +15,14 @@ package deploy
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/okteto/okteto/pkg/model"
 	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -41,3 +45,110 @@ func TestDeployNotRemovingEnvFile(t *testing.T) {
 	require.NoError(t, err)
 
 }
+
+// flakyExecutor fails the first 'failures' executions and records the variables of each execution
+type flakyExecutor struct {
+	failures  int
+	variables [][]string
+	onExecute func(attempt int)
+}
+
+func (fe *flakyExecutor) Execute(_ model.DeployCommand, variables []string) error {
+	fe.variables = append(fe.variables, variables)
+	if fe.onExecute != nil {
+		fe.onExecute(len(fe.variables))
+	}
+	if len(fe.variables) <= fe.failures {
+		return assert.AnError
+	}
+	return nil
+}
+
+func (*flakyExecutor) CleanUp(_ error) {}
+
+func TestExecuteWithRetries(t *testing.T) {
+	var tests = []struct {
+		name               string
+		command            model.DeployCommand
+		failures           int
+		expectedExecutions int
+		expectedSleeps     []time.Duration
+		expectErr          bool
+	}{
+		{
+			name:               "command succeeds",
+			command:            model.DeployCommand{Name: "wait", Command: "curl", Retries: 3, RetryInterval: time.Second},
+			expectedExecutions: 1,
+		},
+		{
+			name:               "command fails without retries",
+			command:            model.DeployCommand{Name: "wait", Command: "curl"},
+			failures:           1,
+			expectedExecutions: 1,
+			expectErr:          true,
+		},
+		{
+			name:               "command succeeds after retrying",
+			command:            model.DeployCommand{Name: "wait", Command: "curl", Retries: 3, RetryInterval: 10 * time.Second},
+			failures:           2,
+			expectedExecutions: 3,
+			expectedSleeps:     []time.Duration{10 * time.Second, 10 * time.Second},
+		},
+		{
+			name:               "command fails after all the retries",
+			command:            model.DeployCommand{Name: "wait", Command: "curl", Retries: 2, RetryInterval: time.Second},
+			failures:           5,
+			expectedExecutions: 3,
+			expectedSleeps:     []time.Duration{time.Second, time.Second},
+			expectErr:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sleeps []time.Duration
+			previousSleep := sleepBeforeRetry
+			sleepBeforeRetry = func(d time.Duration) { sleeps = append(sleeps, d) }
+			defer func() { sleepBeforeRetry = previousSleep }()
+
+			envFile := filepath.Join(t.TempDir(), ".env")
+			require.NoError(t, os.WriteFile(envFile, nil, 0600))
+			e := &flakyExecutor{failures: tt.failures}
+			ld := &localDeployer{Executor: e}
+
+			err := ld.executeWithRetries(tt.command, []string{"A=1"}, envFile)
+			if tt.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Len(t, e.variables, tt.expectedExecutions)
+			assert.Equal(t, tt.expectedSleeps, sleeps)
+		})
+	}
+}
+
+func TestExecuteWithRetriesReadsOktetoEnv(t *testing.T) {
+	previousSleep := sleepBeforeRetry
+	sleepBeforeRetry = func(time.Duration) {}
+	defer func() { sleepBeforeRetry = previousSleep }()
+
+	envFile := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(envFile, nil, 0600))
+	e := &flakyExecutor{
+		failures: 1,
+		onExecute: func(attempt int) {
+			// the failed attempt writes a value to $OKTETO_ENV before failing
+			if attempt == 1 {
+				require.NoError(t, os.WriteFile(envFile, []byte("TOKEN=abc\n"), 0600))
+			}
+		},
+	}
+	ld := &localDeployer{Executor: e}
+
+	command := model.DeployCommand{Name: "wait", Command: "curl", Retries: 1}
+	require.NoError(t, ld.executeWithRetries(command, []string{"A=1"}, envFile))
+	require.Len(t, e.variables, 2)
+	assert.Equal(t, []string{"A=1"}, e.variables[0])
+	assert.Equal(t, []string{"A=1", "TOKEN=abc"}, e.variables[1])
+}
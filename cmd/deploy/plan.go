@@ -0,0 +1,259 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+)
+
+const (
+	planActionBuild         = "build"
+	planActionSkipBuild     = "skip, the image is already built"
+	planActionDeploy        = "deploy"
+	planActionDeployMissing = "deploy if it doesn't exist"
+)
+
+// deployPlan is what 'okteto deploy --dry-run' would do, in the order it would do it
+type deployPlan struct {
+	Name         string           `json:"name"`
+	Namespace    string           `json:"namespace"`
+	Variables    []planVariable   `json:"variables,omitempty"`
+	Dependencies []planDependency `json:"dependencies,omitempty"`
+	Builds       []planBuild      `json:"builds,omitempty"`
+	Commands     []planCommand    `json:"commands,omitempty"`
+	Compose      []string         `json:"compose,omitempty"`
+	Divert       *planDivert      `json:"divert,omitempty"`
+	External     []string         `json:"external,omitempty"`
+}
+
+type planVariable struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type planDependency struct {
+	Name       string `json:"name"`
+	Repository string `json:"repository"`
+	Branch     string `json:"branch,omitempty"`
+	Ref        string `json:"ref,omitempty"`
+	Namespace  string `json:"namespace"`
+	Action     string `json:"action"`
+}
+
+type planBuild struct {
+	Service string `json:"service"`
+	Action  string `json:"action"`
+}
+
+type planCommand struct {
+	Name    string `json:"name"`
+	Command string `json:"command"`
+}
+
+type planDivert struct {
+	Driver    string `json:"driver"`
+	Namespace string `json:"namespace"`
+}
+
+// getDeployPlan resolves what the deploy would do without changing anything in the cluster
+func (dc *DeployCommand) getDeployPlan(ctx context.Context, opts *Options) (*deployPlan, error) {
+	manifest := opts.Manifest
+	plan := &deployPlan{
+		Name:      opts.Name,
+		Namespace: manifest.Namespace,
+		Variables: getPlanVariables(manifest.Variables, opts.Variables, os.LookupEnv),
+	}
+
+	for depName, dep := range manifest.Dependencies {
+		namespace := manifest.Namespace
+		if dep.Namespace != "" {
+			namespace = dep.Namespace
+		}
+		action := planActionDeployMissing
+		if opts.Dependencies || opts.Locked || opts.UpdateDeps {
+			action = planActionDeploy
+		}
+		plan.Dependencies = append(plan.Dependencies, planDependency{
+			Name:       depName,
+			Repository: dep.Repository,
+			Branch:     dep.Branch,
+			Ref:        dep.Ref,
+			Namespace:  namespace,
+			Action:     action,
+		})
+	}
+	sort.Slice(plan.Dependencies, func(i, j int) bool {
+		return plan.Dependencies[i].Name < plan.Dependencies[j].Name
+	})
+
+	if manifest.Deploy == nil {
+		return plan, nil
+	}
+
+	builds, err := dc.getPlanBuilds(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	plan.Builds = builds
+
+	for _, command := range manifest.Deploy.Commands {
+		plan.Commands = append(plan.Commands, planCommand{Name: command.Name, Command: command.Command})
+	}
+	if manifest.Deploy.ComposeSection != nil {
+		plan.Compose = append([]string{}, opts.servicesToDeploy...)
+		sort.Strings(plan.Compose)
+	}
+	// the divert resources are only created when diverting from another namespace
+	if manifest.Deploy.Divert != nil && manifest.Deploy.Divert.Namespace != manifest.Namespace {
+		plan.Divert = &planDivert{
+			Driver:    manifest.Deploy.Divert.Driver,
+			Namespace: manifest.Deploy.Divert.Namespace,
+		}
+	}
+	for name := range manifest.External {
+		plan.External = append(plan.External, name)
+	}
+	sort.Strings(plan.External)
+	return plan, nil
+}
+
+// getPlanBuilds returns the services built by the deploy. Without '--build' the images already in the registry are skipped
+func (dc *DeployCommand) getPlanBuilds(ctx context.Context, opts *Options) ([]planBuild, error) {
+	services := setToSlice(getServicesToBuildSet(opts))
+	sort.Strings(services)
+	if len(services) == 0 {
+		return nil, nil
+	}
+
+	toBuild := sliceToSet(services)
+	if !opts.Build && dc.Builder != nil {
+		servicesToBuild, err := dc.Builder.GetServicesToBuild(ctx, opts.Manifest, services)
+		if err != nil {
+			return nil, err
+		}
+		toBuild = sliceToSet(servicesToBuild)
+	}
+
+	builds := make([]planBuild, 0, len(services))
+	for _, svc := range services {
+		action := planActionSkipBuild
+		if toBuild[svc] {
+			action = planActionBuild
+		}
+		builds = append(builds, planBuild{Service: svc, Action: action})
+	}
+	return builds, nil
+}
+
+// getPlanVariables returns the variables of the manifest and the ones set with '--var', with the secret values masked
+func getPlanVariables(declared model.ManifestVariables, variables []string, lookupEnv func(string) (string, bool)) []planVariable {
+	secrets := map[string]bool{}
+	values := map[string]string{}
+	for _, v := range declared {
+		secrets[v.Name] = v.Secret
+		value, ok := lookupEnv(v.Name)
+		if !ok {
+			value = v.Default
+		}
+		values[v.Name] = value
+	}
+	// the last value of a variable set more than once is the one used by the commands
+	for _, v := range variables {
+		name, value, found := strings.Cut(v, "=")
+		if !found {
+			continue
+		}
+		values[name] = value
+	}
+
+	result := make([]planVariable, 0, len(values))
+	for name, value := range values {
+		if secrets[name] && value != "" {
+			value = maskedValue
+		}
+		result = append(result, planVariable{Name: name, Value: value})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// printDeployPlan writes the plan as tables, or as a json object with '--log-output json'
+func printDeployPlan(out io.Writer, plan *deployPlan, format string) error {
+	if format == oktetoLog.JSONFormat {
+		return json.NewEncoder(out).Encode(plan)
+	}
+
+	fmt.Fprintf(out, "Deploy plan of '%s' in namespace '%s'\n", plan.Name, plan.Namespace)
+	w := tabwriter.NewWriter(out, 1, 1, 2, ' ', 0)
+	if len(plan.Variables) > 0 {
+		fmt.Fprintf(w, "\nVariable\tValue\n")
+		for _, v := range plan.Variables {
+			fmt.Fprintf(w, "%s\t%s\n", v.Name, v.Value)
+		}
+	}
+	if len(plan.Dependencies) > 0 {
+		fmt.Fprintf(w, "\nDependency\tRepository\tBranch\tRef\tNamespace\tAction\n")
+		for _, d := range plan.Dependencies {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", d.Name, d.Repository, valueOrDash(d.Branch), valueOrDash(d.Ref), d.Namespace, d.Action)
+		}
+	}
+	if len(plan.Builds) > 0 {
+		fmt.Fprintf(w, "\nBuild\tAction\n")
+		for _, b := range plan.Builds {
+			fmt.Fprintf(w, "%s\t%s\n", b.Service, b.Action)
+		}
+	}
+	if len(plan.Commands) > 0 {
+		fmt.Fprintf(w, "\n#\tName\tCommand\n")
+		for i, c := range plan.Commands {
+			fmt.Fprintf(w, "%d\t%s\t%s\n", i+1, c.Name, c.Command)
+		}
+	}
+	if len(plan.Compose) > 0 {
+		fmt.Fprintf(w, "\nCompose service\n")
+		for _, svc := range plan.Compose {
+			fmt.Fprintf(w, "%s\n", svc)
+		}
+	}
+	if plan.Divert != nil {
+		fmt.Fprintf(w, "\nDivert driver\tNamespace\n")
+		fmt.Fprintf(w, "%s\t%s\n", valueOrDash(plan.Divert.Driver), plan.Divert.Namespace)
+	}
+	if len(plan.External) > 0 {
+		fmt.Fprintf(w, "\nExternal resource\n")
+		for _, name := range plan.External {
+			fmt.Fprintf(w, "%s\n", name)
+		}
+	}
+	return w.Flush()
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,210 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	buildv2 "github.com/okteto/okteto/cmd/build/v2"
+	"github.com/okteto/okteto/internal/test"
+	"github.com/okteto/okteto/pkg/externalresource"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newPlanManifest() *model.Manifest {
+	return &model.Manifest{
+		Namespace: "test",
+		Variables: model.ManifestVariables{
+			{Name: "REGION", Default: "eu-west-1"},
+			{Name: "DB_PASSWORD", Secret: true},
+		},
+		Build: model.ManifestBuild{
+			"api":      &model.BuildInfo{Context: "api"},
+			"frontend": &model.BuildInfo{Context: "frontend"},
+		},
+		Dependencies: model.ManifestDependencies{
+			"db": &model.Dependency{Repository: "https://github.com/okteto/db", Branch: "main"},
+			"auth": &model.Dependency{
+				Repository: "https://github.com/okteto/auth",
+				Namespace:  "shared",
+			},
+		},
+		Deploy: &model.DeployInfo{
+			Commands: []model.DeployCommand{
+				{Name: "helm", Command: "helm upgrade --install movies chart"},
+				{Name: "kubectl apply -f k8s", Command: "kubectl apply -f k8s"},
+			},
+			Divert: &model.DivertDeploy{Driver: "nginx", Namespace: "staging"},
+		},
+		External: externalresource.ExternalResourceSection{
+			"functions": &externalresource.ExternalResource{},
+			"db":        &externalresource.ExternalResource{},
+		},
+	}
+}
+
+func TestGetDeployPlan(t *testing.T) {
+	t.Setenv("REGION", "us-east-1")
+	dc := &DeployCommand{}
+	opts := &Options{
+		Name:      "movies",
+		Manifest:  newPlanManifest(),
+		Variables: []string{"DB_PASSWORD=secret", "DEBUG=true"},
+		Build:     true,
+	}
+
+	plan, err := dc.getDeployPlan(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, &deployPlan{
+		Name:      "movies",
+		Namespace: "test",
+		Variables: []planVariable{
+			{Name: "DB_PASSWORD", Value: maskedValue},
+			{Name: "DEBUG", Value: "true"},
+			{Name: "REGION", Value: "us-east-1"},
+		},
+		Dependencies: []planDependency{
+			{Name: "auth", Repository: "https://github.com/okteto/auth", Namespace: "shared", Action: planActionDeployMissing},
+			{Name: "db", Repository: "https://github.com/okteto/db", Branch: "main", Namespace: "test", Action: planActionDeployMissing},
+		},
+		Builds: []planBuild{
+			{Service: "api", Action: planActionBuild},
+			{Service: "frontend", Action: planActionBuild},
+		},
+		Commands: []planCommand{
+			{Name: "helm", Command: "helm upgrade --install movies chart"},
+			{Name: "kubectl apply -f k8s", Command: "kubectl apply -f k8s"},
+		},
+		Divert:   &planDivert{Driver: "nginx", Namespace: "staging"},
+		External: []string{"db", "functions"},
+	}, plan)
+}
+
+func TestGetDeployPlanDependenciesAndDivert(t *testing.T) {
+	dc := &DeployCommand{}
+	manifest := newPlanManifest()
+	// diverting from the namespace of the deploy doesn't create divert resources
+	manifest.Deploy.Divert.Namespace = "test"
+	opts := &Options{
+		Name:         "movies",
+		Manifest:     manifest,
+		Dependencies: true,
+		Build:        true,
+	}
+
+	plan, err := dc.getDeployPlan(context.Background(), opts)
+	require.NoError(t, err)
+	for _, d := range plan.Dependencies {
+		assert.Equal(t, planActionDeploy, d.Action)
+	}
+	assert.Nil(t, plan.Divert)
+}
+
+func TestGetDeployPlanWithoutDeploySection(t *testing.T) {
+	dc := &DeployCommand{}
+	manifest := newPlanManifest()
+	manifest.Deploy = nil
+	plan, err := dc.getDeployPlan(context.Background(), &Options{Name: "movies", Manifest: manifest})
+	require.NoError(t, err)
+	assert.Len(t, plan.Dependencies, 2)
+	assert.Empty(t, plan.Builds)
+	assert.Empty(t, plan.Commands)
+}
+
+func TestPrintDeployPlan(t *testing.T) {
+	plan := &deployPlan{
+		Name:      "movies",
+		Namespace: "test",
+		Variables: []planVariable{{Name: "REGION", Value: "us-east-1"}},
+		Dependencies: []planDependency{
+			{Name: "db", Repository: "https://github.com/okteto/db", Ref: "v1.0.0", Namespace: "test", Action: planActionDeploy},
+		},
+		Builds:   []planBuild{{Service: "api", Action: planActionSkipBuild}},
+		Commands: []planCommand{{Name: "helm", Command: "helm upgrade --install movies chart"}},
+		Divert:   &planDivert{Driver: "nginx", Namespace: "staging"},
+		External: []string{"functions"},
+	}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, printDeployPlan(out, plan, oktetoLog.TTYFormat))
+	expected := `Deploy plan of 'movies' in namespace 'test'
+
+Variable  Value
+REGION    us-east-1
+
+Dependency  Repository                    Branch  Ref     Namespace  Action
+db          https://github.com/okteto/db  -       v1.0.0  test       deploy
+
+Build  Action
+api    skip, the image is already built
+
+#  Name  Command
+1  helm  helm upgrade --install movies chart
+
+Divert driver  Namespace
+nginx          staging
+
+External resource
+functions
+`
+	assert.Equal(t, expected, out.String())
+
+	out = &bytes.Buffer{}
+	require.NoError(t, printDeployPlan(out, plan, oktetoLog.JSONFormat))
+	var decoded deployPlan
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, *plan, decoded)
+}
+
+func TestDeployDryRunDoesNotRunAnything(t *testing.T) {
+	p := &fakeProxy{}
+	e := &fakeExecutor{}
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Namespace: "test",
+			},
+		},
+		CurrentContext: "test",
+	}
+	clientProvider := test.NewFakeK8sProvider()
+	cmapHandler := &fakeCmapHandler{}
+	c := &DeployCommand{
+		GetManifest:       getFakeManifest,
+		K8sClientProvider: clientProvider,
+		CfgMapHandler:     cmapHandler,
+		GetDeployer: func(ctx context.Context, manifest *model.Manifest, opts *Options, _ *buildv2.OktetoBuilder, _ configMapHandler) (deployerInterface, error) {
+			return &localDeployer{Proxy: p, Executor: e}, nil
+		},
+	}
+
+	err := c.RunDeploy(context.Background(), &Options{Name: "movies", DryRun: true})
+	require.NoError(t, err)
+	assert.Empty(t, e.executed)
+	assert.False(t, p.started)
+
+	fakeClient, _, err := clientProvider.Provide(nil)
+	require.NoError(t, err)
+	cmaps, err := fakeClient.CoreV1().ConfigMaps("test").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, cmaps.Items)
+}
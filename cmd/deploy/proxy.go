@@ -26,15 +26,18 @@ import (
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"syscall"
 
 	"github.com/google/uuid"
 	"github.com/okteto/okteto/cmd/utils"
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
 	"github.com/okteto/okteto/pkg/divert"
 	"github.com/okteto/okteto/pkg/k8s/labels"
 	oktetoLog "github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/model"
 	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/okteto/okteto/pkg/registry"
 	istioNetworkingV1beta1 "istio.io/api/networking/v1beta1"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
@@ -50,6 +53,16 @@ type proxyInterface interface {
 	GetToken() string
 	SetName(name string)
 	SetDivert(driver divert.Driver)
+	SetDefaultResources(resources *model.ResourceRequirements, enforce bool)
+	RecordAppliedObjects()
+	GetAppliedObjects() [][]byte
+	SetAllowClusterResources(allow bool)
+	GetClusterResources() (applied, refused []pipeline.ClusterResource)
+	RecordTrail()
+	SetTrailCommand(i int)
+	GetTrail() []pipeline.TrailEntry
+	CheckImages(builtImages []string, strict bool)
+	GetUnreachableImages() []unreachableImage
 }
 
 type proxyConfig struct {
@@ -68,6 +81,31 @@ type proxyHandler struct {
 	// Name is sanitized version of the pipeline name
 	Name         string
 	DivertDriver divert.Driver
+	// DefaultResources are injected into the containers lacking requests/limits
+	DefaultResources *model.ResourceRequirements
+	// EnforceResources overrides explicit requests/limits with DefaultResources
+	EnforceResources bool
+
+	// recordObjects keeps a copy of every object created or updated through the proxy
+	recordObjects  bool
+	objectsMu      sync.Mutex
+	appliedObjects [][]byte
+
+	// allowClusterResources lets the deploy commands create or update cluster-scoped objects
+	allowClusterResources   bool
+	clusterResources        []pipeline.ClusterResource
+	refusedClusterResources []pipeline.ClusterResource
+
+	// recordTrail keeps the mutating requests sent through the proxy, tagged with the deploy command running
+	recordTrail  bool
+	trailCommand int
+	trail        []pipeline.TrailEntry
+
+	// imageChecker checks the images of the workloads applied through the proxy, nil if images are not checked
+	imageChecker *imageChecker
+	// strictImages refuses the workloads with unreachable images
+	strictImages      bool
+	unreachableImages []unreachableImage
 }
 
 // NewProxy creates a new proxy
@@ -89,7 +127,7 @@ func NewProxy(kubeconfig *KubeConfig) (*Proxy, error) {
 		return nil, err
 	}
 
-	ph := &proxyHandler{}
+	ph := &proxyHandler{trailCommand: noTrailCommand}
 	handler, err := ph.getProxyHandler(sessionToken, clusterConfig)
 	if err != nil {
 		oktetoLog.Errorf("could not configure local proxy: %s", err)
@@ -179,6 +217,60 @@ func (p *Proxy) SetDivert(driver divert.Driver) {
 	p.proxyHandler.SetDivert(driver)
 }
 
+// SetDefaultResources sets the resources injected into the deployed containers
+func (p *Proxy) SetDefaultResources(resources *model.ResourceRequirements, enforce bool) {
+	p.proxyHandler.SetDefaultResources(resources, enforce)
+}
+
+// RecordAppliedObjects keeps a copy of the objects created or updated through the proxy
+func (p *Proxy) RecordAppliedObjects() {
+	p.proxyHandler.recordObjects = true
+}
+
+// GetAppliedObjects returns the objects created or updated through the proxy since RecordAppliedObjects was called
+func (p *Proxy) GetAppliedObjects() [][]byte {
+	return p.proxyHandler.getAppliedObjects()
+}
+
+// SetAllowClusterResources sets if the deploy commands can create or update cluster-scoped objects
+func (p *Proxy) SetAllowClusterResources(allow bool) {
+	p.proxyHandler.allowClusterResources = allow
+}
+
+// GetClusterResources returns the cluster-scoped objects applied and refused through the proxy
+func (p *Proxy) GetClusterResources() (applied, refused []pipeline.ClusterResource) {
+	return p.proxyHandler.getClusterResources()
+}
+
+// RecordTrail keeps the mutating requests sent through the proxy
+func (p *Proxy) RecordTrail() {
+	p.proxyHandler.objectsMu.Lock()
+	defer p.proxyHandler.objectsMu.Unlock()
+	p.proxyHandler.recordTrail = true
+}
+
+// SetTrailCommand sets the index of the deploy command sending the requests, -1 if no command is running
+func (p *Proxy) SetTrailCommand(i int) {
+	p.proxyHandler.setTrailCommand(i)
+}
+
+// GetTrail returns the mutating requests sent through the proxy since RecordTrail was called
+func (p *Proxy) GetTrail() []pipeline.TrailEntry {
+	return p.proxyHandler.getTrail()
+}
+
+// CheckImages checks that the images of the workloads applied through the proxy exist, except the built ones.
+// With strict, the workloads with unreachable images are refused
+func (p *Proxy) CheckImages(builtImages []string, strict bool) {
+	reg := registry.NewOktetoRegistry(okteto.Config{})
+	p.proxyHandler.setImageChecker(newImageChecker(reg.ImageExists, builtImages), strict)
+}
+
+// GetUnreachableImages returns the images of the workloads applied through the proxy that can't be found
+func (p *Proxy) GetUnreachableImages() []unreachableImage {
+	return p.proxyHandler.getUnreachableImages()
+}
+
 func (ph *proxyHandler) getProxyHandler(token string, clusterConfig *rest.Config) (http.Handler, error) {
 	// By default we don't disable HTTP/2
 	trans, err := newProtocolTransport(clusterConfig, false)
@@ -230,6 +322,35 @@ func (ph *proxyHandler) getProxyHandler(token string, clusterConfig *rest.Config
 		}
 
 		r.Host = destinationURL.Host
+		if r.Method == "PATCH" {
+			if resource, ok := getClusterResource(r, nil); ok && !ph.admitClusterResource(resource) {
+				writeClusterResourceForbidden(rw, resource)
+				return
+			}
+		}
+		checker, _ := ph.getImageChecker()
+		isCheckingPatch := r.Method == "PATCH" && checker != nil
+		if (r.Method == "PATCH" || r.Method == "DELETE") && (ph.isRecordingTrail() || isCheckingPatch) {
+			var b []byte
+			if r.Body != nil {
+				var err error
+				b, err = io.ReadAll(r.Body)
+				if err != nil {
+					oktetoLog.Infof("could not read the request body: %s", err)
+					rw.WriteHeader(500)
+					return
+				}
+				r.Body.Close()
+				r.Body = io.NopCloser(bytes.NewBuffer(b))
+			}
+			if isCheckingPatch {
+				if unreachable, ok := ph.admitImages(r, b); !ok {
+					writeUnreachableImagesInvalid(rw, unreachable)
+					return
+				}
+			}
+			ph.recordTrailEntry(r, b)
+		}
 		// Modify all resources updated or created to include the label.
 		if r.Method == "PUT" || r.Method == "POST" {
 			b, err := io.ReadAll(r.Body)
@@ -244,6 +365,16 @@ func (ph *proxyHandler) getProxyHandler(token string, clusterConfig *rest.Config
 				return
 			}
 
+			if resource, ok := getClusterResource(r, b); ok && !ph.admitClusterResource(resource) {
+				writeClusterResourceForbidden(rw, resource)
+				return
+			}
+			if unreachable, ok := ph.admitImages(r, b); !ok {
+				writeUnreachableImagesInvalid(rw, unreachable)
+				return
+			}
+			ph.recordTrailEntry(r, b)
+
 			b, err = ph.translateBody(b)
 			if err != nil {
 				oktetoLog.Info(err)
@@ -251,6 +382,8 @@ func (ph *proxyHandler) getProxyHandler(token string, clusterConfig *rest.Config
 				return
 			}
 
+			ph.recordAppliedObject(b)
+
 			// Needed to set the new Content-Length
 			r.ContentLength = int64(len(b))
 			r.Body = io.NopCloser(bytes.NewBuffer(b))
@@ -272,6 +405,26 @@ func (ph *proxyHandler) SetDivert(driver divert.Driver) {
 	ph.DivertDriver = driver
 }
 
+func (ph *proxyHandler) SetDefaultResources(resources *model.ResourceRequirements, enforce bool) {
+	ph.DefaultResources = resources
+	ph.EnforceResources = enforce
+}
+
+func (ph *proxyHandler) recordAppliedObject(b []byte) {
+	if !ph.recordObjects || len(b) == 0 {
+		return
+	}
+	ph.objectsMu.Lock()
+	defer ph.objectsMu.Unlock()
+	ph.appliedObjects = append(ph.appliedObjects, append([]byte{}, b...))
+}
+
+func (ph *proxyHandler) getAppliedObjects() [][]byte {
+	ph.objectsMu.Lock()
+	defer ph.objectsMu.Unlock()
+	return append([][]byte{}, ph.appliedObjects...)
+}
+
 func (ph *proxyHandler) translateBody(b []byte) ([]byte, error) {
 	var body map[string]json.RawMessage
 	if err := json.Unmarshal(b, &body); err != nil {
@@ -366,6 +519,7 @@ func (ph *proxyHandler) translateDeploymentSpec(body map[string]json.RawMessage)
 	}
 	labels.SetInMetadata(&spec.Template.ObjectMeta, model.DeployedByLabel, ph.Name)
 	spec.Template.Spec = ph.applyDivertToPod(spec.Template.Spec)
+	ph.applyDefaultResourcesToPod(body, &spec.Template.Spec)
 	specAsByte, err := json.Marshal(spec)
 	if err != nil {
 		return fmt.Errorf("could not process deployment's spec: %s", err)
@@ -382,6 +536,7 @@ func (ph *proxyHandler) translateStatefulSetSpec(body map[string]json.RawMessage
 	}
 	labels.SetInMetadata(&spec.Template.ObjectMeta, model.DeployedByLabel, ph.Name)
 	spec.Template.Spec = ph.applyDivertToPod(spec.Template.Spec)
+	ph.applyDefaultResourcesToPod(body, &spec.Template.Spec)
 	specAsByte, err := json.Marshal(spec)
 	if err != nil {
 		return fmt.Errorf("could not process statefulset's spec: %s", err)
@@ -398,6 +553,7 @@ func (ph *proxyHandler) translateJobSpec(body map[string]json.RawMessage) error
 	}
 	labels.SetInMetadata(&spec.Template.ObjectMeta, model.DeployedByLabel, ph.Name)
 	spec.Template.Spec = ph.applyDivertToPod(spec.Template.Spec)
+	ph.applyDefaultResourcesToPod(body, &spec.Template.Spec)
 	specAsByte, err := json.Marshal(spec)
 	if err != nil {
 		return fmt.Errorf("could not process job's spec: %s", err)
@@ -414,6 +570,7 @@ func (ph *proxyHandler) translateCronJobSpec(body map[string]json.RawMessage) er
 	}
 	labels.SetInMetadata(&spec.JobTemplate.Spec.Template.ObjectMeta, model.DeployedByLabel, ph.Name)
 	spec.JobTemplate.Spec.Template.Spec = ph.applyDivertToPod(spec.JobTemplate.Spec.Template.Spec)
+	ph.applyDefaultResourcesToPod(body, &spec.JobTemplate.Spec.Template.Spec)
 	specAsByte, err := json.Marshal(spec)
 	if err != nil {
 		return fmt.Errorf("could not process cronjob's spec: %s", err)
@@ -430,6 +587,7 @@ func (ph *proxyHandler) translateDaemonSetSpec(body map[string]json.RawMessage)
 	}
 	labels.SetInMetadata(&spec.Template.ObjectMeta, model.DeployedByLabel, ph.Name)
 	spec.Template.Spec = ph.applyDivertToPod(spec.Template.Spec)
+	ph.applyDefaultResourcesToPod(body, &spec.Template.Spec)
 	specAsByte, err := json.Marshal(spec)
 	if err != nil {
 		return fmt.Errorf("could not process daemonset's spec: %s", err)
@@ -446,6 +604,7 @@ func (ph *proxyHandler) translateReplicationControllerSpec(body map[string]json.
 	}
 	labels.SetInMetadata(&spec.Template.ObjectMeta, model.DeployedByLabel, ph.Name)
 	spec.Template.Spec = ph.applyDivertToPod(spec.Template.Spec)
+	ph.applyDefaultResourcesToPod(body, &spec.Template.Spec)
 	specAsByte, err := json.Marshal(spec)
 	if err != nil {
 		return fmt.Errorf("could not process replicationcontroller's spec: %s", err)
@@ -462,6 +621,7 @@ func (ph *proxyHandler) translateReplicaSetSpec(body map[string]json.RawMessage)
 	}
 	labels.SetInMetadata(&spec.Template.ObjectMeta, model.DeployedByLabel, ph.Name)
 	spec.Template.Spec = ph.applyDivertToPod(spec.Template.Spec)
+	ph.applyDefaultResourcesToPod(body, &spec.Template.Spec)
 	specAsByte, err := json.Marshal(spec)
 	if err != nil {
 		return fmt.Errorf("could not process replicaset's spec: %s", err)
@@ -477,6 +637,23 @@ func (ph *proxyHandler) applyDivertToPod(podSpec apiv1.PodSpec) apiv1.PodSpec {
 	return ph.DivertDriver.UpdatePod(podSpec)
 }
 
+func (ph *proxyHandler) applyDefaultResourcesToPod(body map[string]json.RawMessage, podSpec *apiv1.PodSpec) {
+	if !applyDefaultResources(podSpec, ph.DefaultResources, ph.EnforceResources) {
+		return
+	}
+	var resource struct {
+		Kind     string            `json:"kind"`
+		Metadata metav1.ObjectMeta `json:"metadata"`
+	}
+	if err := json.Unmarshal(body["kind"], &resource.Kind); err != nil {
+		oktetoLog.Infof("error unmarshalling kind on proxy: %s", err.Error())
+	}
+	if err := json.Unmarshal(body["metadata"], &resource.Metadata); err != nil {
+		oktetoLog.Infof("error unmarshalling objectmeta on proxy: %s", err.Error())
+	}
+	oktetoLog.Information("Default resources applied to %s '%s'", strings.ToLower(resource.Kind), resource.Metadata.Name)
+}
+
 func (ph *proxyHandler) translateVirtualServiceSpec(body map[string]json.RawMessage) error {
 	if ph.DivertDriver == nil {
 		return nil
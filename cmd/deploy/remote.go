@@ -19,16 +19,17 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
-	"text/template"
 
+	"github.com/alessio/shellescape"
 	builder "github.com/okteto/okteto/cmd/build"
 	remoteBuild "github.com/okteto/okteto/cmd/build/remote"
 	buildv2 "github.com/okteto/okteto/cmd/build/v2"
+	"github.com/okteto/okteto/cmd/utils"
 	"github.com/okteto/okteto/pkg/cmd/build"
 	"github.com/okteto/okteto/pkg/config"
 	"github.com/okteto/okteto/pkg/constants"
@@ -37,73 +38,12 @@ import (
 	oktetoLog "github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/model"
 	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/okteto/okteto/pkg/remote"
 	"github.com/okteto/okteto/pkg/types"
 	"github.com/spf13/afero"
 )
 
-const (
-	templateName           = "dockerfile"
-	dockerfileTemporalName = "deploy"
-	oktetoDockerignoreName = ".oktetodeployignore"
-	dockerfileTemplate     = `
-FROM {{ .OktetoCLIImage }} as okteto-cli
-
-FROM alpine as certs
-RUN apk update && apk add ca-certificates
-
-FROM {{ .InstallerImage }} as installer
-
-FROM {{ .UserDeployImage }} as deploy
-
-ENV PATH="${PATH}:/okteto/bin"
-COPY --from=certs /etc/ssl/certs /etc/ssl/certs
-COPY --from=installer /app/bin/* /okteto/bin/
-COPY --from=okteto-cli /usr/local/bin/* /okteto/bin/
-
-{{range $key, $val := .OktetoBuildEnvVars }}
-ENV {{$key}} {{$val}}
-{{end}}
-ENV {{ .NamespaceEnvVar }} {{ .NamespaceValue }}
-ENV {{ .ContextEnvVar }} {{ .ContextValue }}
-ENV {{ .TokenEnvVar }} {{ .TokenValue }}
-ENV {{ .RemoteDeployEnvVar }} true
-{{ if ne .ActionNameValue "" }}
-ENV {{ .ActionNameEnvVar }} {{ .ActionNameValue }}
-{{ end }}
-{{ if ne .GitCommitValue "" }}
-ENV {{ .GitCommitEnvVar }} {{ .GitCommitValue }}
-{{ end }}
-
-COPY . /okteto/src
-WORKDIR /okteto/src
-
-ENV OKTETO_INVALIDATE_CACHE {{ .RandomInt }}
-ARG OKTETO_TLS_CERT_BASE64
-ARG INTERNAL_SERVER_NAME=""
-RUN echo "$OKTETO_TLS_CERT_BASE64" | base64 -d > /etc/ssl/certs/okteto.crt
-RUN okteto deploy --log-output=json --server-name="$INTERNAL_SERVER_NAME" {{ .DeployFlags }}
-`
-)
-
-type dockerfileTemplateProperties struct {
-	OktetoCLIImage     string
-	InstallerImage     string
-	UserDeployImage    string
-	OktetoBuildEnvVars map[string]string
-	ContextEnvVar      string
-	ContextValue       string
-	NamespaceEnvVar    string
-	NamespaceValue     string
-	TokenEnvVar        string
-	TokenValue         string
-	ActionNameEnvVar   string
-	ActionNameValue    string
-	GitCommitEnvVar    string
-	GitCommitValue     string
-	RemoteDeployEnvVar string
-	DeployFlags        string
-	RandomInt          int
-}
+const dockerfileTemporalName = "deploy"
 
 type remoteDeployCommand struct {
 	builderV2            *buildv2.OktetoBuilder
@@ -112,6 +52,8 @@ type remoteDeployCommand struct {
 	workingDirectoryCtrl filesystem.WorkingDirectoryInterface
 	temporalCtrl         filesystem.TemporalDirectoryInterface
 	clusterMetadata      func(context.Context) (*types.ClusterMetadata, error)
+	// out is where the dry-run output is written
+	out io.Writer
 }
 
 // newRemoteDeployer creates the remote deployer from a
@@ -124,25 +66,44 @@ func newRemoteDeployer(builder *buildv2.OktetoBuilder) *remoteDeployCommand {
 		workingDirectoryCtrl: filesystem.NewOsWorkingDirectoryCtrl(),
 		temporalCtrl:         filesystem.NewTemporalDirectoryCtrl(fs),
 		clusterMetadata:      fetchRemoteServerConfig,
+		out:                  os.Stdout,
 	}
 }
 
 func (rd *remoteDeployCommand) deploy(ctx context.Context, deployOptions *Options) error {
+	if deployOptions.InsecureSkipTLSVerify {
+		oktetoLog.Warning("Insecure mode enabled: the deploy run in remote won't verify the certificate of the okteto server. Use it only with trusted networks")
+	}
 
-	sc, err := rd.clusterMetadata(ctx)
+	cwd, err := rd.getOriginalCWD(deployOptions.ManifestPathFlag)
 	if err != nil {
 		return err
 	}
 
-	if deployOptions.Manifest != nil && deployOptions.Manifest.Deploy != nil && deployOptions.Manifest.Deploy.Image == "" {
-		deployOptions.Manifest.Deploy.Image = sc.PipelineRunnerImage
+	dirty, err := utils.CheckWorkingTree(cwd, "deploy", deployOptions.RequireClean)
+	if err != nil {
+		return err
 	}
+	deployOptions.dirtyWorkingTree = dirty
 
-	cwd, err := rd.getOriginalCWD(deployOptions.ManifestPathFlag)
+	sc, err := rd.clusterMetadata(ctx)
 	if err != nil {
 		return err
 	}
 
+	deployImage := ""
+	if deployOptions.Manifest != nil && deployOptions.Manifest.Deploy != nil {
+		deployImage = deployOptions.Manifest.Deploy.Image
+	}
+	sc, err = utils.ResolveClusterMetadata(sc, deployImage, "deploy")
+	if err != nil {
+		return err
+	}
+
+	if deployOptions.Manifest != nil && deployOptions.Manifest.Deploy != nil && deployOptions.Manifest.Deploy.Image == "" {
+		deployOptions.Manifest.Deploy.Image = sc.PipelineRunnerImage
+	}
+
 	tmpDir, err := rd.temporalCtrl.Create()
 	if err != nil {
 		return err
@@ -159,6 +120,10 @@ func (rd *remoteDeployCommand) deploy(ctx context.Context, deployOptions *Option
 		}
 	}()
 
+	if deployOptions.DryRun {
+		return remote.PrintDryRun(rd.out, rd.fs, dockerfile, tmpDir, okteto.Context().Token)
+	}
+
 	buildInfo := &model.BuildInfo{
 		Dockerfile: dockerfile,
 	}
@@ -175,6 +140,13 @@ func (rd *remoteDeployCommand) deploy(ctx context.Context, deployOptions *Option
 		fmt.Sprintf("OKTETO_TLS_CERT_BASE64=%s", base64.StdEncoding.EncodeToString(sc.Certificate)),
 		fmt.Sprintf("INTERNAL_SERVER_NAME=%s", sc.ServerName),
 	)
+	includedFiles, err := rd.getIncludedFiles(cwd, deployOptions.Manifest)
+	if err != nil {
+		return err
+	}
+	for _, f := range includedFiles {
+		buildOptions.BuildArgs = append(buildOptions.BuildArgs, f.BuildArg())
+	}
 	// we need to call Build() method using a remote builder. This Builder will have
 	// the same behavior as the V1 builder but with a different output taking into
 	// account that we must not confuse the user with build messages since this logic is
@@ -196,8 +168,7 @@ func (rd *remoteDeployCommand) deploy(ctx context.Context, deployOptions *Option
 			E: err,
 		}
 	}
-	oktetoLog.SetStage("done")
-	oktetoLog.AddToBuffer(oktetoLog.InfoLevel, "EOF")
+	oktetoLog.FinishBuffer()
 
 	return nil
 }
@@ -210,31 +181,36 @@ func (rd *remoteDeployCommand) createDockerfile(tmpDir string, opts *Options, in
 		return "", err
 	}
 
-	tmpl := template.Must(template.New(templateName).Parse(dockerfileTemplate))
-
 	randomNumber, err := rand.Int(rand.Reader, big.NewInt(1000))
 	if err != nil {
 		return "", err
 	}
 
-	dockerfileSyntax := dockerfileTemplateProperties{
-		OktetoCLIImage:     getOktetoCLIVersion(config.VersionString),
-		UserDeployImage:    opts.Manifest.Deploy.Image,
+	originalCWD, err := rd.getOriginalCWD(opts.ManifestPathFlag)
+	if err != nil {
+		return "", err
+	}
+	includedFiles, err := rd.getIncludedFiles(originalCWD, opts.Manifest)
+	if err != nil {
+		return "", err
+	}
+
+	params := remote.Params{
+		Command:            "deploy",
+		Flags:              getDeployFlags(opts),
+		LogOutput:          oktetoLog.JSONFormat,
+		OktetoCLIImage:     remote.GetOktetoCLIImage(config.VersionString),
 		InstallerImage:     installerImage,
+		RunImage:           opts.Manifest.Deploy.Image,
 		OktetoBuildEnvVars: rd.builderV2.GetBuildEnvVars(),
-		ContextEnvVar:      model.OktetoContextEnvVar,
-		ContextValue:       okteto.Context().Name,
-		NamespaceEnvVar:    model.OktetoNamespaceEnvVar,
-		NamespaceValue:     okteto.Context().Namespace,
-		TokenEnvVar:        model.OktetoTokenEnvVar,
+		Namespace:          okteto.Context().Namespace,
+		Context:            okteto.Context().Name,
+		ActionName:         os.Getenv(model.OktetoActionNameEnvVar),
+		GitCommit:          os.Getenv(constants.OktetoGitCommitEnvVar),
 		TokenValue:         okteto.Context().Token,
-		ActionNameEnvVar:   model.OktetoActionNameEnvVar,
-		ActionNameValue:    os.Getenv(model.OktetoActionNameEnvVar),
-		GitCommitEnvVar:    constants.OktetoGitCommitEnvVar,
-		GitCommitValue:     os.Getenv(constants.OktetoGitCommitEnvVar),
-		RemoteDeployEnvVar: constants.OKtetoDeployRemote,
-		RandomInt:          int(randomNumber.Int64()),
-		DeployFlags:        strings.Join(getDeployFlags(opts), " "),
+		CacheKey:           randomNumber.String(),
+		IncludedFiles:      includedFiles,
+		SkipTLSVerify:      opts.InsecureSkipTLSVerify,
 	}
 
 	dockerfile, err := rd.fs.Create(filepath.Join(tmpDir, dockerfileTemporalName))
@@ -242,67 +218,67 @@ func (rd *remoteDeployCommand) createDockerfile(tmpDir string, opts *Options, in
 		return "", err
 	}
 
-	err = rd.createDockerignore(cwd, tmpDir)
-	if err != nil {
+	if err := remote.WriteDockerignore(rd.fs, cwd, tmpDir); err != nil {
 		return "", err
 	}
 
-	if err := tmpl.Execute(dockerfile, dockerfileSyntax); err != nil {
+	if err := remote.RenderDockerfile(dockerfile, params); err != nil {
 		return "", err
 	}
 	return dockerfile.Name(), nil
 }
 
-func (rd *remoteDeployCommand) createDockerignore(cwd, tmpDir string) error {
-	// if we do not create a .dockerignore (with or without content) used to create
-	// the remote executor, we would use the one located in root (the one used to
-	// build the services) so we would create a remote executor without certain files
-	// necessary for the later deployment which would cause an error when deploying
-	// remotely due to the lack of these files.
-	dockerignoreContent := []byte(``)
-	dockerignoreFilePath := filepath.Join(cwd, oktetoDockerignoreName)
-	if _, err := rd.fs.Stat(dockerignoreFilePath); err != nil {
-		if !errors.Is(err, os.ErrNotExist) {
-			return err
-		}
-
-	} else {
-		dockerignoreContent, err = afero.ReadFile(rd.fs, dockerignoreFilePath)
-		if err != nil {
-			return err
-		}
-	}
-	err := afero.WriteFile(rd.fs, fmt.Sprintf("%s/%s", tmpDir, ".dockerignore"), dockerignoreContent, 0600)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
 func getDeployFlags(opts *Options) []string {
 	var deployFlags []string
 
 	if opts.Name != "" {
-		deployFlags = append(deployFlags, fmt.Sprintf("--name \"%s\"", opts.Name))
+		deployFlags = append(deployFlags, fmt.Sprintf("--name %s", shellescape.Quote(opts.Name)))
 	}
 
 	if opts.Namespace != "" {
-		deployFlags = append(deployFlags, fmt.Sprintf("--namespace %s", opts.Namespace))
+		deployFlags = append(deployFlags, fmt.Sprintf("--namespace %s", shellescape.Quote(opts.Namespace)))
 	}
 
 	if opts.ManifestPathFlag != "" {
-		deployFlags = append(deployFlags, fmt.Sprintf("--file %s", opts.ManifestPathFlag))
+		deployFlags = append(deployFlags, fmt.Sprintf("--file %s", shellescape.Quote(opts.ManifestPathFlag)))
 	}
 
 	if len(opts.Variables) > 0 {
 		var varsToAddForDeploy []string
 		for _, v := range opts.Variables {
-			varsToAddForDeploy = append(varsToAddForDeploy, fmt.Sprintf("--var %s", v))
+			varsToAddForDeploy = append(varsToAddForDeploy, fmt.Sprintf("--var %s", shellescape.Quote(v)))
 		}
 		deployFlags = append(deployFlags, strings.Join(varsToAddForDeploy, " "))
 	}
 
+	if opts.DefaultResources != "" {
+		deployFlags = append(deployFlags, fmt.Sprintf("--default-resources %s", shellescape.Quote(opts.DefaultResources)))
+	}
+
+	if opts.EnforceResources {
+		deployFlags = append(deployFlags, "--enforce-resources")
+	}
+
+	if opts.UploadArtifacts {
+		deployFlags = append(deployFlags, "--upload-artifacts")
+	}
+
+	if opts.AllowClusterResources {
+		deployFlags = append(deployFlags, "--allow-cluster-resources")
+	}
+
+	if opts.NoTrail {
+		deployFlags = append(deployFlags, "--no-trail")
+	}
+
+	for _, command := range opts.Commands {
+		deployFlags = append(deployFlags, fmt.Sprintf("--command %s", shellescape.Quote(command)))
+	}
+
+	if opts.StrictImages {
+		deployFlags = append(deployFlags, "--strict-images")
+	}
+
 	return deployFlags
 }
 
@@ -316,38 +292,14 @@ func (rd *remoteDeployCommand) getOriginalCWD(manifestPath string) (string, erro
 	return strings.TrimSuffix(cwd, manifestPathDir), nil
 }
 
-func getOktetoCLIVersion(versionString string) string {
-	var version string
-	if match, _ := regexp.MatchString(`\d+\.\d+\.\d+`, versionString); match {
-		version = fmt.Sprintf(constants.OktetoCLIImageForRemoteTemplate, versionString)
-	} else {
-		remoteOktetoImage := os.Getenv(constants.OKtetoDeployRemoteImage)
-		if remoteOktetoImage != "" {
-			version = remoteOktetoImage
-		} else {
-			version = fmt.Sprintf(constants.OktetoCLIImageForRemoteTemplate, "latest")
-		}
+// getIncludedFiles returns the files included by the manifest that are outside the build context
+func (rd *remoteDeployCommand) getIncludedFiles(contextDir string, manifest *model.Manifest) ([]remote.IncludedFile, error) {
+	if manifest == nil {
+		return nil, nil
 	}
-
-	return version
+	return remote.GetIncludedFilesOutsideContext(rd.fs, contextDir, manifest.IncludedFiles)
 }
 
 func fetchRemoteServerConfig(ctx context.Context) (*types.ClusterMetadata, error) {
-	cp := okteto.NewOktetoClientProvider()
-	c, err := cp.Provide()
-	if err != nil {
-		return nil, fmt.Errorf("failed to provide okteto client for fetching certs: %s", err)
-	}
-	uc := c.User()
-
-	metadata, err := uc.GetClusterMetadata(ctx, okteto.Context().Namespace)
-	if err != nil {
-		return nil, err
-	}
-
-	if metadata.Certificate == nil {
-		metadata.Certificate, err = uc.GetClusterCertificate(ctx, okteto.Context().Name, okteto.Context().Namespace)
-	}
-
-	return &metadata, err
+	return utils.GetClusterMetadata(ctx, okteto.NewOktetoClientProvider(), okteto.Context().Name, okteto.Context().Namespace)
 }
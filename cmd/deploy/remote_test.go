@@ -14,7 +14,9 @@
 package deploy
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"path/filepath"
 	"testing"
 
@@ -24,6 +26,8 @@ import (
 	oktetoErrors "github.com/okteto/okteto/pkg/errors"
 	filesystem "github.com/okteto/okteto/pkg/filesystem/fake"
 	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/okteto/okteto/pkg/remote"
 	"github.com/okteto/okteto/pkg/types"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
@@ -41,6 +45,16 @@ func (f fakeBuilder) Build(_ context.Context, _ *types.BuildOptions) error {
 func (f fakeBuilder) IsV1() bool { return true }
 
 func TestRemoteTest(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
 	ctx := context.Background()
 	fakeManifest := &model.Manifest{
 		Deploy: &model.DeployInfo{
@@ -179,7 +193,7 @@ func TestGetDeployFlags(t *testing.T) {
 					Name: "test",
 				},
 			},
-			expected: []string{"--name \"test\""},
+			expected: []string{"--name test"},
 		},
 		{
 			name: "name multiple words",
@@ -188,7 +202,7 @@ func TestGetDeployFlags(t *testing.T) {
 					Name: "this is a test",
 				},
 			},
-			expected: []string{"--name \"this is a test\""},
+			expected: []string{"--name 'this is a test'"},
 		},
 		{
 			name: "namespace set",
@@ -208,6 +222,24 @@ func TestGetDeployFlags(t *testing.T) {
 			},
 			expected: []string{"--file /hello/this/is/a/test"},
 		},
+		{
+			name: "manifest path with spaces",
+			config: config{
+				opts: &Options{
+					ManifestPathFlag: "/hello/my app/okteto.yml",
+				},
+			},
+			expected: []string{"--file '/hello/my app/okteto.yml'"},
+		},
+		{
+			name: "name with shell characters",
+			config: config{
+				opts: &Options{
+					Name: "test$(whoami)",
+				},
+			},
+			expected: []string{"--name 'test$(whoami)'"},
+		},
 		{
 			name: "variables set",
 			config: config{
@@ -220,6 +252,65 @@ func TestGetDeployFlags(t *testing.T) {
 			},
 			expected: []string{"--var a=b --var c=d"},
 		},
+		{
+			name: "variables with spaces and equals",
+			config: config{
+				opts: &Options{
+					Variables: []string{
+						"a=b c",
+						"c=d=e",
+						"e=$(whoami)",
+					},
+				},
+			},
+			expected: []string{"--var 'a=b c' --var c=d=e --var 'e=$(whoami)'"},
+		},
+		{
+			name: "default resources",
+			config: config{
+				opts: &Options{
+					DefaultResources: "cpu=100m,limits.cpu=500m",
+					EnforceResources: true,
+				},
+			},
+			expected: []string{"--default-resources cpu=100m,limits.cpu=500m", "--enforce-resources"},
+		},
+		{
+			name: "upload artifacts",
+			config: config{
+				opts: &Options{
+					UploadArtifacts: true,
+				},
+			},
+			expected: []string{"--upload-artifacts"},
+		},
+		{
+			name: "no trail",
+			config: config{
+				opts: &Options{
+					NoTrail: true,
+				},
+			},
+			expected: []string{"--no-trail"},
+		},
+		{
+			name: "strict images",
+			config: config{
+				opts: &Options{
+					StrictImages: true,
+				},
+			},
+			expected: []string{"--strict-images"},
+		},
+		{
+			name: "selected commands",
+			config: config{
+				opts: &Options{
+					Commands: []string{"helm", "run migrations"},
+				},
+			},
+			expected: []string{"--command helm", "--command 'run migrations'"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -231,6 +322,16 @@ func TestGetDeployFlags(t *testing.T) {
 }
 
 func TestCreateDockerfile(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
 	wdCtrl := filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/"))
 	fs := afero.NewMemMapFs()
 	fakeManifest := &model.Manifest{
@@ -297,85 +398,161 @@ func TestCreateDockerfile(t *testing.T) {
 	}
 }
 
-func TestCreateDockerignoreIfNeeded(t *testing.T) {
+func TestCreateDockerfileWithWindowsWorkingDirectory(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	wd := `C:\Users\okteto\project`
+	tmpDir := `C:\Users\okteto\AppData\Local\Temp\okteto`
 	fs := afero.NewMemMapFs()
-	tempDir := "/temp"
-
-	dockerignoreWd := "/test/"
-	assert.NoError(t, fs.MkdirAll(dockerignoreWd, 0755))
-	assert.NoError(t, afero.WriteFile(fs, filepath.Join(dockerignoreWd, ".oktetodeployignore"), []byte("FROM alpine"), 0644))
-	type config struct {
-		wd string
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(wd, remote.DockerignoreName), []byte("node_modules"), 0600))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(wd, remote.OktetoDockerignoreName), []byte("!k8s"), 0600))
+	rdc := remoteDeployCommand{
+		builderV2:            &v2.OktetoBuilder{},
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(wd),
 	}
-	var tests = []struct {
-		name            string
-		config          config
-		expectedContent string
-	}{
-		{
-			name: "dockerignore present copy .oktetodeployignore to .dockerignore",
-			config: config{
-				wd: dockerignoreWd,
+
+	_, err := rdc.createDockerfile(tmpDir, &Options{Manifest: &model.Manifest{Deploy: &model.DeployInfo{Image: "test-image"}}}, "")
+	require.NoError(t, err)
+
+	content, err := afero.ReadFile(fs, filepath.Join(tmpDir, remote.DockerignoreName))
+	require.NoError(t, err)
+	assert.Equal(t, "node_modules\n!k8s", string(content))
+}
+
+func TestRemoteDeployDryRun(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "secret-token",
 			},
-			expectedContent: "FROM alpine",
-		},
-		{
-			name:            "without dockerignore generate empty dockerignore",
-			config:          config{},
-			expectedContent: "",
 		},
+		CurrentContext: "test",
 	}
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, filepath.Join("/", remote.DockerignoreName), []byte("node_modules"), 0600))
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			rdc := remoteDeployCommand{
-				fs: fs,
-			}
-			err := rdc.createDockerignore(tt.config.wd, tempDir)
-			b, _ := afero.ReadFile(rdc.fs, filepath.Join(tempDir, ".dockerignore"))
-			assert.Equal(t, tt.expectedContent, string(b))
-			assert.NoError(t, err)
-		})
+	out := &bytes.Buffer{}
+	rdc := remoteDeployCommand{
+		builderV2: &v2.OktetoBuilder{
+			Registry: newFakeRegistry(),
+		},
+		// the builder fails so the test catches any call to it
+		builderV1:            fakeBuilder{assert.AnError},
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		temporalCtrl:         filesystem.NewTemporalDirectoryCtrl(fs),
+		clusterMetadata: func(context.Context) (*types.ClusterMetadata, error) {
+			return &types.ClusterMetadata{}, nil
+		},
+		out: out,
 	}
+
+	err := rdc.deploy(context.Background(), &Options{
+		Name:     "movies",
+		DryRun:   true,
+		Manifest: &model.Manifest{Deploy: &model.DeployInfo{Image: "okteto/deploy:1.0"}},
+	})
+	require.NoError(t, err)
+	assert.NotContains(t, out.String(), "secret-token")
+	assert.Contains(t, out.String(), "ENV OKTETO_TOKEN <redacted>")
+	assert.Contains(t, out.String(), "FROM okteto/deploy:1.0 as deploy")
+	assert.Contains(t, out.String(), "# .dockerignore\nnode_modules\n")
 }
 
-func Test_getOktetoCLIVersion(t *testing.T) {
-	var tests = []struct {
-		name                                 string
-		versionString, expected, cliImageEnv string
-	}{
-		{
-			name:          "no version string and no env return latest",
-			versionString: "",
-			expected:      "okteto/okteto:latest",
+func TestRemoteDeployPartialClusterMetadata(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
 		},
-		{
-			name:          "no version string return env value",
-			versionString: "",
-			cliImageEnv:   "okteto/remote:test",
-			expected:      "okteto/remote:test",
+		CurrentContext: "test",
+	}
+	fs := afero.NewMemMapFs()
+	out := &bytes.Buffer{}
+	rdc := remoteDeployCommand{
+		builderV2: &v2.OktetoBuilder{
+			Registry: newFakeRegistry(),
 		},
-		{
-			name:          "found version string",
-			versionString: "2.2.2",
-			expected:      "okteto/okteto:2.2.2",
+		builderV1:            fakeBuilder{assert.AnError},
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		temporalCtrl:         filesystem.NewTemporalDirectoryCtrl(fs),
+		clusterMetadata: func(context.Context) (*types.ClusterMetadata, error) {
+			return &types.ClusterMetadata{PipelineInstallerImage: "okteto/installer:1.0"}, nil
 		},
-		{
-			name:          "found incorrect version string return latest ",
-			versionString: "2.a.2",
-			expected:      "okteto/okteto:latest",
+		out: out,
+	}
+
+	err := rdc.deploy(context.Background(), &Options{
+		DryRun:   true,
+		Manifest: &model.Manifest{Deploy: &model.DeployInfo{}},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), fmt.Sprintf("FROM %s as deploy", constants.OktetoPipelineRunnerImage))
+	assert.Contains(t, out.String(), "okteto/installer:1.0")
+}
+
+func TestRemoteDeployInvalidClusterMetadataImage(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	rdc := remoteDeployCommand{
+		builderV1:            fakeBuilder{assert.AnError},
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		temporalCtrl:         filesystem.NewTemporalDirectoryCtrl(fs),
+		clusterMetadata: func(context.Context) (*types.ClusterMetadata, error) {
+			return &types.ClusterMetadata{PipelineInstallerImage: "okteto/installer:not a tag"}, nil
 		},
 	}
 
-	for _, tt := range tests {
-		tt := tt
-		t.Run(tt.name, func(t *testing.T) {
-			if tt.cliImageEnv != "" {
-				t.Setenv(constants.OKtetoDeployRemoteImage, tt.cliImageEnv)
-			}
+	err := rdc.deploy(context.Background(), &Options{
+		Manifest: &model.Manifest{Deploy: &model.DeployInfo{}},
+	})
+	assert.ErrorAs(t, err, &oktetoErrors.UserError{})
+	assert.ErrorContains(t, err, "pipelineInstallerImage")
+}
 
-			version := getOktetoCLIVersion(tt.versionString)
-			require.Equal(t, version, tt.expected)
-		})
+func TestCreateDockerfileWithInsecureSkipTLSVerify(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	fs := afero.NewMemMapFs()
+	rdc := remoteDeployCommand{
+		builderV2:            &v2.OktetoBuilder{},
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
 	}
+	manifest := &model.Manifest{Deploy: &model.DeployInfo{Image: "test-image"}}
+
+	dockerfileName, err := rdc.createDockerfile("/test", &Options{Manifest: manifest}, "installer")
+	require.NoError(t, err)
+	content, err := afero.ReadFile(fs, dockerfileName)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), constants.OktetoTLSVerifyEnvVar)
+
+	dockerfileName, err = rdc.createDockerfile("/test", &Options{Manifest: manifest, InsecureSkipTLSVerify: true}, "installer")
+	require.NoError(t, err)
+	content, err = afero.ReadFile(fs, dockerfileName)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "ENV OKTETO_TLS_VERIFY=false\nRUN \\\n  okteto deploy")
 }
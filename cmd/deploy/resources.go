@@ -0,0 +1,144 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"fmt"
+	"strings"
+
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	requestsResourcePrefix = "requests."
+	limitsResourcePrefix   = "limits."
+)
+
+// parseDefaultResources parses a list of resources like 'cpu=100m,memory=128Mi,limits.cpu=500m'.
+// Resources without prefix are considered requests
+func parseDefaultResources(value string) (*model.ResourceRequirements, error) {
+	resources := &model.ResourceRequirements{
+		Requests: model.ResourceList{},
+		Limits:   model.ResourceList{},
+	}
+	if strings.TrimSpace(value) == "" {
+		return resources, nil
+	}
+	for _, item := range strings.Split(value, ",") {
+		kv := strings.SplitN(strings.TrimSpace(item), "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, fmt.Errorf("invalid resource '%s': must follow the format 'name=quantity'", item)
+		}
+		quantity, err := resource.ParseQuantity(kv[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity for resource '%s': %w", kv[0], err)
+		}
+		switch {
+		case strings.HasPrefix(kv[0], limitsResourcePrefix):
+			resources.Limits[apiv1.ResourceName(strings.TrimPrefix(kv[0], limitsResourcePrefix))] = quantity
+		default:
+			resources.Requests[apiv1.ResourceName(strings.TrimPrefix(kv[0], requestsResourcePrefix))] = quantity
+		}
+	}
+	return resources, nil
+}
+
+// getDefaultResources merges the default resources of the manifest with the ones defined by flag, taking precedence the latter
+func getDefaultResources(manifest *model.Manifest, flagValue string) (*model.ResourceRequirements, error) {
+	fromFlag, err := parseDefaultResources(flagValue)
+	if err != nil {
+		return nil, err
+	}
+	resources := &model.ResourceRequirements{
+		Requests: model.ResourceList{},
+		Limits:   model.ResourceList{},
+	}
+	if manifest != nil && manifest.Deploy != nil && manifest.Deploy.Resources != nil {
+		for k, v := range manifest.Deploy.Resources.Requests {
+			resources.Requests[k] = v
+		}
+		for k, v := range manifest.Deploy.Resources.Limits {
+			resources.Limits[k] = v
+		}
+	}
+	for k, v := range fromFlag.Requests {
+		resources.Requests[k] = v
+	}
+	for k, v := range fromFlag.Limits {
+		resources.Limits[k] = v
+	}
+	if len(resources.Requests) == 0 && len(resources.Limits) == 0 {
+		return nil, nil
+	}
+	return resources, nil
+}
+
+// applyDefaultResources sets the default resources to the containers and init containers of a pod.
+// Explicit values are only overridden when enforce is true. It returns if any container was modified
+func applyDefaultResources(podSpec *apiv1.PodSpec, defaults *model.ResourceRequirements, enforce bool) bool {
+	if defaults == nil {
+		return false
+	}
+	patched := false
+	for i := range podSpec.InitContainers {
+		if applyDefaultResourcesToContainer(&podSpec.InitContainers[i], defaults, enforce) {
+			patched = true
+		}
+	}
+	for i := range podSpec.Containers {
+		if applyDefaultResourcesToContainer(&podSpec.Containers[i], defaults, enforce) {
+			patched = true
+		}
+	}
+	return patched
+}
+
+func applyDefaultResourcesToContainer(container *apiv1.Container, defaults *model.ResourceRequirements, enforce bool) bool {
+	patched := false
+	if container.Resources.Requests == nil && len(defaults.Requests) > 0 {
+		container.Resources.Requests = apiv1.ResourceList{}
+	}
+	for name, quantity := range defaults.Requests {
+		current, ok := container.Resources.Requests[name]
+		if ok && (!enforce || current.Cmp(quantity) == 0) {
+			continue
+		}
+		// kubernetes defaults the request to the limit, so it is kept when only the limit is explicit
+		if _, hasLimit := container.Resources.Limits[name]; hasLimit && !ok && !enforce {
+			continue
+		}
+		container.Resources.Requests[name] = quantity
+		patched = true
+	}
+	for name, quantity := range defaults.Limits {
+		current, ok := container.Resources.Limits[name]
+		if ok && (!enforce || current.Cmp(quantity) == 0) {
+			continue
+		}
+		// a limit below the request is rejected by the API server, so the default limit is skipped
+		if request, hasRequest := container.Resources.Requests[name]; hasRequest && quantity.Cmp(request) < 0 {
+			oktetoLog.Infof("skipping the default %s limit %s of container '%s': it is lower than its request %s", name, quantity.String(), container.Name, request.String())
+			continue
+		}
+		if container.Resources.Limits == nil {
+			container.Resources.Limits = apiv1.ResourceList{}
+		}
+		container.Resources.Limits[name] = quantity
+		patched = true
+	}
+	return patched
+}
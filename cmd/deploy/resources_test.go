@@ -0,0 +1,306 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseDefaultResources(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  *model.ResourceRequirements
+		expectErr bool
+	}{
+		{
+			name:  "empty",
+			value: "",
+			expected: &model.ResourceRequirements{
+				Requests: model.ResourceList{},
+				Limits:   model.ResourceList{},
+			},
+		},
+		{
+			name:  "requests and limits",
+			value: "cpu=100m, memory=128Mi,requests.ephemeral-storage=1Gi,limits.cpu=500m",
+			expected: &model.ResourceRequirements{
+				Requests: model.ResourceList{
+					apiv1.ResourceCPU:              resource.MustParse("100m"),
+					apiv1.ResourceMemory:           resource.MustParse("128Mi"),
+					apiv1.ResourceEphemeralStorage: resource.MustParse("1Gi"),
+				},
+				Limits: model.ResourceList{
+					apiv1.ResourceCPU: resource.MustParse("500m"),
+				},
+			},
+		},
+		{
+			name:      "missing quantity",
+			value:     "cpu",
+			expectErr: true,
+		},
+		{
+			name:      "invalid quantity",
+			value:     "cpu=a lot",
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseDefaultResources(tt.value)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestGetDefaultResources(t *testing.T) {
+	manifest := &model.Manifest{
+		Deploy: &model.DeployInfo{
+			Resources: &model.ResourceRequirements{
+				Requests: model.ResourceList{
+					apiv1.ResourceCPU:    resource.MustParse("50m"),
+					apiv1.ResourceMemory: resource.MustParse("64Mi"),
+				},
+			},
+		},
+	}
+
+	result, err := getDefaultResources(manifest, "cpu=100m,limits.memory=1Gi")
+	assert.NoError(t, err)
+	assert.Equal(t, &model.ResourceRequirements{
+		Requests: model.ResourceList{
+			apiv1.ResourceCPU:    resource.MustParse("100m"),
+			apiv1.ResourceMemory: resource.MustParse("64Mi"),
+		},
+		Limits: model.ResourceList{
+			apiv1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+	}, result)
+
+	result, err = getDefaultResources(&model.Manifest{Deploy: &model.DeployInfo{}}, "")
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func TestApplyDefaultResources(t *testing.T) {
+	defaults := &model.ResourceRequirements{
+		Requests: model.ResourceList{
+			apiv1.ResourceCPU:    resource.MustParse("100m"),
+			apiv1.ResourceMemory: resource.MustParse("128Mi"),
+		},
+		Limits: model.ResourceList{
+			apiv1.ResourceCPU: resource.MustParse("500m"),
+		},
+	}
+	tests := []struct {
+		name            string
+		resources       apiv1.ResourceRequirements
+		enforce         bool
+		expected        apiv1.ResourceRequirements
+		expectedPatched bool
+	}{
+		{
+			name: "container without resources",
+			expected: apiv1.ResourceRequirements{
+				Requests: apiv1.ResourceList{
+					apiv1.ResourceCPU:    resource.MustParse("100m"),
+					apiv1.ResourceMemory: resource.MustParse("128Mi"),
+				},
+				Limits: apiv1.ResourceList{
+					apiv1.ResourceCPU: resource.MustParse("500m"),
+				},
+			},
+			expectedPatched: true,
+		},
+		{
+			name: "explicit values are kept",
+			resources: apiv1.ResourceRequirements{
+				Requests: apiv1.ResourceList{
+					apiv1.ResourceCPU: resource.MustParse("1"),
+				},
+				Limits: apiv1.ResourceList{
+					apiv1.ResourceCPU:    resource.MustParse("2"),
+					apiv1.ResourceMemory: resource.MustParse("64Mi"),
+				},
+			},
+			expected: apiv1.ResourceRequirements{
+				Requests: apiv1.ResourceList{
+					apiv1.ResourceCPU: resource.MustParse("1"),
+				},
+				Limits: apiv1.ResourceList{
+					apiv1.ResourceCPU:    resource.MustParse("2"),
+					apiv1.ResourceMemory: resource.MustParse("64Mi"),
+				},
+			},
+		},
+		{
+			name: "explicit values are overridden when enforced",
+			resources: apiv1.ResourceRequirements{
+				Requests: apiv1.ResourceList{
+					apiv1.ResourceCPU: resource.MustParse("1"),
+				},
+				Limits: apiv1.ResourceList{
+					apiv1.ResourceCPU: resource.MustParse("2"),
+				},
+			},
+			enforce: true,
+			expected: apiv1.ResourceRequirements{
+				Requests: apiv1.ResourceList{
+					apiv1.ResourceCPU:    resource.MustParse("100m"),
+					apiv1.ResourceMemory: resource.MustParse("128Mi"),
+				},
+				Limits: apiv1.ResourceList{
+					apiv1.ResourceCPU: resource.MustParse("500m"),
+				},
+			},
+			expectedPatched: true,
+		},
+		{
+			name: "default limit lower than the explicit request is skipped",
+			resources: apiv1.ResourceRequirements{
+				Requests: apiv1.ResourceList{
+					apiv1.ResourceCPU: resource.MustParse("1"),
+				},
+			},
+			expected: apiv1.ResourceRequirements{
+				Requests: apiv1.ResourceList{
+					apiv1.ResourceCPU:    resource.MustParse("1"),
+					apiv1.ResourceMemory: resource.MustParse("128Mi"),
+				},
+			},
+			expectedPatched: true,
+		},
+		{
+			name:      "already matching values when enforced",
+			resources: *defaultsAsK8s(defaults),
+			enforce:   true,
+			expected:  *defaultsAsK8s(defaults),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			podSpec := &apiv1.PodSpec{
+				InitContainers: []apiv1.Container{{Name: "init", Resources: *tt.resources.DeepCopy()}},
+				Containers:     []apiv1.Container{{Name: "app", Resources: *tt.resources.DeepCopy()}},
+			}
+			patched := applyDefaultResources(podSpec, defaults, tt.enforce)
+			assert.Equal(t, tt.expectedPatched, patched)
+			assert.Equal(t, tt.expected, podSpec.InitContainers[0].Resources)
+			assert.Equal(t, tt.expected, podSpec.Containers[0].Resources)
+		})
+	}
+}
+
+func defaultsAsK8s(r *model.ResourceRequirements) *apiv1.ResourceRequirements {
+	result := &apiv1.ResourceRequirements{Requests: apiv1.ResourceList{}, Limits: apiv1.ResourceList{}}
+	for k, v := range r.Requests {
+		result.Requests[k] = v
+	}
+	for k, v := range r.Limits {
+		result.Limits[k] = v
+	}
+	return result
+}
+
+func TestTranslateBodyWithDefaultResources(t *testing.T) {
+	podTemplate := apiv1.PodTemplateSpec{
+		Spec: apiv1.PodSpec{
+			InitContainers: []apiv1.Container{{Name: "init"}},
+			Containers: []apiv1.Container{
+				{Name: "app"},
+				{
+					Name: "explicit",
+					Resources: apiv1.ResourceRequirements{
+						Requests: apiv1.ResourceList{apiv1.ResourceCPU: resource.MustParse("1")},
+					},
+				},
+			},
+		},
+	}
+	objects := []interface{}{
+		&appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "deployment"},
+			Spec:       appsv1.DeploymentSpec{Template: podTemplate},
+		},
+		&appsv1.StatefulSet{
+			TypeMeta:   metav1.TypeMeta{Kind: "StatefulSet", APIVersion: "apps/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "statefulset"},
+			Spec:       appsv1.StatefulSetSpec{Template: podTemplate},
+		},
+		&batchv1.Job{
+			TypeMeta:   metav1.TypeMeta{Kind: "Job", APIVersion: "batch/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "job"},
+			Spec:       batchv1.JobSpec{Template: podTemplate},
+		},
+		&batchv1.CronJob{
+			TypeMeta:   metav1.TypeMeta{Kind: "CronJob", APIVersion: "batch/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "cronjob"},
+			Spec: batchv1.CronJobSpec{
+				JobTemplate: batchv1.JobTemplateSpec{Spec: batchv1.JobSpec{Template: podTemplate}},
+			},
+		},
+	}
+
+	ph := &proxyHandler{
+		Name: "test",
+		DefaultResources: &model.ResourceRequirements{
+			Requests: model.ResourceList{apiv1.ResourceCPU: resource.MustParse("100m")},
+		},
+	}
+	for _, obj := range objects {
+		b, err := json.Marshal(obj)
+		require.NoError(t, err)
+
+		translated, err := ph.translateBody(b)
+		require.NoError(t, err)
+
+		var result struct {
+			Kind string `json:"kind"`
+			Spec struct {
+				Template    apiv1.PodTemplateSpec `json:"template"`
+				JobTemplate struct {
+					Spec batchv1.JobSpec `json:"spec"`
+				} `json:"jobTemplate"`
+			} `json:"spec"`
+		}
+		require.NoError(t, json.Unmarshal(translated, &result))
+		podSpec := result.Spec.Template.Spec
+		if result.Kind == "CronJob" {
+			podSpec = result.Spec.JobTemplate.Spec.Template.Spec
+		}
+
+		expectedDefault := resource.MustParse("100m")
+		expectedExplicit := resource.MustParse("1")
+		assert.True(t, expectedDefault.Equal(podSpec.InitContainers[0].Resources.Requests[apiv1.ResourceCPU]), result.Kind)
+		assert.True(t, expectedDefault.Equal(podSpec.Containers[0].Resources.Requests[apiv1.ResourceCPU]), result.Kind)
+		assert.True(t, expectedExplicit.Equal(podSpec.Containers[1].Resources.Requests[apiv1.ResourceCPU]), result.Kind)
+	}
+}
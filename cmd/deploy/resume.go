@@ -0,0 +1,142 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/spf13/afero"
+)
+
+// stageHasher computes the hash identifying the inputs of a deploy command
+type stageHasher struct {
+	fs  afero.Fs
+	cwd string
+	// variablesHash is included in every stage so any change in the variables invalidates all of them
+	variablesHash string
+}
+
+func newStageHasher(fs afero.Fs, cwd string, variables []string) stageHasher {
+	return stageHasher{
+		fs:            fs,
+		cwd:           cwd,
+		variablesHash: hashVariables(variables),
+	}
+}
+
+// hashVariables returns a hash of the variables that doesn't depend on their order
+func hashVariables(variables []string) string {
+	sorted := make([]string, len(variables))
+	copy(sorted, variables)
+	sort.Strings(sorted)
+	h := sha256.New()
+	for _, v := range sorted {
+		fmt.Fprintf(h, "%s\n", v)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hash returns the hash of the command, the content of the files referenced by the command and the variables
+func (sh stageHasher) hash(command model.DeployCommand) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "variables:%s\n", sh.variablesHash)
+	fmt.Fprintf(h, "name:%s\n", command.Name)
+	fmt.Fprintf(h, "command:%s\n", command.Command)
+	for _, path := range sh.getReferencedPaths(command.Command) {
+		if err := sh.hashPath(h, path); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// getReferencedPaths returns the existing files and folders referenced by the arguments of a command
+func (sh stageHasher) getReferencedPaths(command string) []string {
+	seen := map[string]bool{}
+	var paths []string
+	for _, arg := range strings.Fields(command) {
+		// flags like --file=path
+		if i := strings.Index(arg, "="); i >= 0 {
+			arg = arg[i+1:]
+		}
+		arg = strings.Trim(arg, `"'`)
+		if arg == "" || strings.HasPrefix(arg, "-") {
+			continue
+		}
+		path := arg
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(sh.cwd, path)
+		}
+		path = filepath.Clean(path)
+		if seen[path] {
+			continue
+		}
+		if _, err := sh.fs.Stat(path); err != nil {
+			continue
+		}
+		seen[path] = true
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// hashPath adds the content of a file, or of all the files of a folder, to the hash
+func (sh stageHasher) hashPath(w io.Writer, path string) error {
+	return afero.Walk(sh.fs, path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		content, err := afero.ReadFile(sh.fs, p)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "file:%s:%d\n", p, len(content))
+		_, err = w.Write(content)
+		return err
+	})
+}
+
+// getStagesToSkip returns the number of commands that can be skipped because they completed successfully
+// in the previous deploy and their inputs didn't change, and the reason why the next command must run
+func getStagesToSkip(previous []pipeline.DeployStage, commands []model.DeployCommand, hashes []string) (int, string) {
+	for i, command := range commands {
+		if i >= len(previous) || previous[i].Name != command.Name {
+			return i, "it didn't complete in the previous deploy"
+		}
+		if previous[i].Hash != hashes[i] {
+			return i, "its inputs changed"
+		}
+	}
+	return len(commands), ""
+}
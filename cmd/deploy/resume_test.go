@@ -0,0 +1,233 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/okteto/okteto/internal/test"
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashVariables(t *testing.T) {
+	assert.Equal(t, hashVariables([]string{"A=1", "B=2"}), hashVariables([]string{"B=2", "A=1"}))
+	assert.NotEqual(t, hashVariables([]string{"A=1", "B=2"}), hashVariables([]string{"A=1", "B=3"}))
+	assert.NotEqual(t, hashVariables([]string{"A=1"}), hashVariables(nil))
+}
+
+func TestGetReferencedPaths(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	cwd := filepath.Clean("/app")
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(cwd, "values.yaml"), []byte("replicas: 1"), 0600))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(cwd, "k8s", "deployment.yaml"), []byte("kind: Deployment"), 0600))
+	sh := newStageHasher(fs, cwd, nil)
+
+	tests := []struct {
+		name     string
+		command  string
+		expected []string
+	}{
+		{
+			name:     "no files",
+			command:  "echo hello",
+			expected: nil,
+		},
+		{
+			name:     "file argument",
+			command:  "helm upgrade --install app chart -f values.yaml",
+			expected: []string{filepath.Join(cwd, "values.yaml")},
+		},
+		{
+			name:     "flag with value and folder",
+			command:  `kubectl apply --filename="k8s" --values=values.yaml`,
+			expected: []string{filepath.Join(cwd, "k8s"), filepath.Join(cwd, "values.yaml")},
+		},
+		{
+			name:     "repeated file",
+			command:  "cat values.yaml values.yaml",
+			expected: []string{filepath.Join(cwd, "values.yaml")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, sh.getReferencedPaths(tt.command))
+		})
+	}
+}
+
+func TestStageHash(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	cwd := filepath.Clean("/app")
+	manifestPath := filepath.Join(cwd, "k8s", "deployment.yaml")
+	require.NoError(t, afero.WriteFile(fs, manifestPath, []byte("replicas: 1"), 0600))
+	command := model.DeployCommand{Name: "deploy", Command: "kubectl apply -f k8s"}
+
+	hash := func(variables []string, command model.DeployCommand) string {
+		h, err := newStageHasher(fs, cwd, variables).hash(command)
+		require.NoError(t, err)
+		return h
+	}
+
+	original := hash([]string{"A=1"}, command)
+	assert.Equal(t, original, hash([]string{"A=1"}, command), "same inputs must produce the same hash")
+	assert.NotEqual(t, original, hash([]string{"A=2"}, command), "variables must invalidate the hash")
+	assert.NotEqual(t, original, hash([]string{"A=1"}, model.DeployCommand{Name: "deploy", Command: "kubectl apply -f k8s --wait"}), "command must invalidate the hash")
+	assert.NotEqual(t, original, hash([]string{"A=1"}, model.DeployCommand{Name: "other", Command: "kubectl apply -f k8s"}), "name must invalidate the hash")
+
+	require.NoError(t, afero.WriteFile(fs, manifestPath, []byte("replicas: 2"), 0600))
+	assert.NotEqual(t, original, hash([]string{"A=1"}, command), "referenced files must invalidate the hash")
+}
+
+func TestGetStagesToSkip(t *testing.T) {
+	commands := []model.DeployCommand{
+		{Name: "build", Command: "okteto build"},
+		{Name: "deploy", Command: "helm upgrade"},
+		{Name: "test", Command: "make test"},
+	}
+	hashes := []string{"a", "b", "c"}
+
+	tests := []struct {
+		name           string
+		previous       []pipeline.DeployStage
+		expectedSkip   int
+		expectedReason string
+	}{
+		{
+			name:           "no previous deploy",
+			expectedSkip:   0,
+			expectedReason: "it didn't complete in the previous deploy",
+		},
+		{
+			name: "failed in the second command",
+			previous: []pipeline.DeployStage{
+				{Name: "build", Hash: "a"},
+			},
+			expectedSkip:   1,
+			expectedReason: "it didn't complete in the previous deploy",
+		},
+		{
+			name: "inputs of the second command changed",
+			previous: []pipeline.DeployStage{
+				{Name: "build", Hash: "a"},
+				{Name: "deploy", Hash: "old"},
+				{Name: "test", Hash: "c"},
+			},
+			expectedSkip:   1,
+			expectedReason: "its inputs changed",
+		},
+		{
+			name: "commands reordered",
+			previous: []pipeline.DeployStage{
+				{Name: "deploy", Hash: "b"},
+				{Name: "build", Hash: "a"},
+			},
+			expectedSkip:   0,
+			expectedReason: "it didn't complete in the previous deploy",
+		},
+		{
+			name: "everything completed",
+			previous: []pipeline.DeployStage{
+				{Name: "build", Hash: "a"},
+				{Name: "deploy", Hash: "b"},
+				{Name: "test", Hash: "c"},
+			},
+			expectedSkip: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skip, reason := getStagesToSkip(tt.previous, commands, hashes)
+			assert.Equal(t, tt.expectedSkip, skip)
+			assert.Equal(t, tt.expectedReason, reason)
+		})
+	}
+}
+
+func TestRunDeploySectionWithResume(t *testing.T) {
+	ctx := context.Background()
+	commands := []model.DeployCommand{
+		{Name: "build", Command: "okteto build"},
+		{Name: "deploy", Command: "helm upgrade"},
+		{Name: "test", Command: "make test"},
+	}
+	newOptions := func(resume bool, variables []string) *Options {
+		return &Options{
+			Name:          "test",
+			Resume:        resume,
+			userVariables: variables,
+			Manifest: &model.Manifest{
+				Namespace: "test",
+				Deploy:    &model.DeployInfo{Commands: commands},
+			},
+		}
+	}
+
+	cmapHandler := &fakeCmapHandler{}
+	executor := &fakeExecutor{err: assert.AnError}
+	proxy := &fakeProxy{}
+	ld := localDeployer{
+		ConfigMapHandler:  cmapHandler,
+		Executor:          executor,
+		Proxy:             proxy,
+		Fs:                afero.NewMemMapFs(),
+		K8sClientProvider: test.NewFakeK8sProvider(),
+	}
+
+	// first deploy fails in the first command: nothing is recorded
+	assert.Error(t, ld.runDeploySection(ctx, newOptions(true, nil)))
+	assert.Empty(t, cmapHandler.stages)
+
+	// second deploy completes everything
+	executor.err = nil
+	executor.executed = nil
+	proxy.trailCommands = nil
+	assert.NoError(t, ld.runDeploySection(ctx, newOptions(false, nil)))
+	assert.Equal(t, commands, executor.executed)
+	assert.Len(t, cmapHandler.stages, 3)
+	// the requests sent through the proxy are tagged with the command running
+	assert.Equal(t, []int{0, noTrailCommand, 1, noTrailCommand, 2, noTrailCommand}, proxy.trailCommands)
+
+	// resume skips everything
+	executor.executed = nil
+	assert.NoError(t, ld.runDeploySection(ctx, newOptions(true, nil)))
+	assert.Empty(t, executor.executed)
+	assert.Len(t, cmapHandler.stages, 3)
+
+	// resume with different variables runs everything
+	executor.executed = nil
+	assert.NoError(t, ld.runDeploySection(ctx, newOptions(true, []string{"A=1"})))
+	assert.Equal(t, commands, executor.executed)
+
+	// last command fails: the next resume only runs the last command
+	cmapHandler.stages = cmapHandler.stages[:2]
+	cmapHandler.stages[1].Envs = map[string]string{"IMAGE": "okteto/app:1"}
+	executor.executed = nil
+	opts := newOptions(true, []string{"A=1"})
+	assert.NoError(t, ld.runDeploySection(ctx, opts))
+	assert.Equal(t, commands[2:], executor.executed)
+	assert.Contains(t, opts.Variables, "IMAGE=okteto/app:1")
+
+	// no resume runs everything
+	executor.executed = nil
+	opts = newOptions(true, []string{"A=1"})
+	opts.NoResume = true
+	assert.NoError(t, ld.runDeploySection(ctx, opts))
+	assert.Equal(t, commands, executor.executed)
+}
@@ -0,0 +1,144 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/okteto/okteto/pkg/devenvironment"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/okteto/okteto/pkg/varprofiles"
+)
+
+// varProfileStore reads and writes the variables saved with '--save-vars'
+type varProfileStore interface {
+	Get(key varprofiles.Key) (*varprofiles.Profile, error)
+	Save(key varprofiles.Key, variables []varprofiles.Variable) error
+}
+
+// getVarProfileKey returns the key of a profile for the development environment being deployed
+func (dc *DeployCommand) getVarProfileKey(ctx context.Context, deployOptions *Options, profile string) (varprofiles.Key, error) {
+	name := deployOptions.Name
+	if name == "" {
+		name = deployOptions.Manifest.Name
+	}
+	if name == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return varprofiles.Key{}, fmt.Errorf("failed to get the current working directory: %w", err)
+		}
+		c, _, err := dc.K8sClientProvider.Provide(okteto.Context().Cfg)
+		if err != nil {
+			return varprofiles.Key{}, err
+		}
+		name = devenvironment.NewNameInferer(c).InferName(ctx, cwd, okteto.Context().Namespace, deployOptions.ManifestPathFlag)
+	}
+	return varprofiles.Key{
+		Context:     okteto.Context().Name,
+		Namespace:   okteto.Context().Namespace,
+		Environment: name,
+		Profile:     profile,
+	}, nil
+}
+
+// loadSavedVariables sets the variables of the profile set with '--from-saved-vars'
+func (dc *DeployCommand) loadSavedVariables(ctx context.Context, deployOptions *Options) error {
+	key, err := dc.getVarProfileKey(ctx, deployOptions, deployOptions.FromSavedVars)
+	if err != nil {
+		return err
+	}
+	profile, err := dc.VarProfiles.Get(key)
+	if err != nil {
+		if errors.Is(err, varprofiles.ErrProfileNotFound) {
+			return oktetoErrors.UserError{
+				E:    fmt.Errorf("variables profile '%s' not found for '%s' in namespace '%s'", key.Profile, key.Environment, key.Namespace),
+				Hint: "Run 'okteto vars list' to see the saved profiles or save one with 'okteto deploy --save-vars'",
+			}
+		}
+		return err
+	}
+	return applySavedVariables(profile.Variables, deployOptions, os.LookupEnv, os.Setenv)
+}
+
+// applySavedVariables uses the saved variables as baseline: the ones set with '--var' take priority.
+// The secret variables are saved by reference, so their value must be set in the environment
+func applySavedVariables(saved []varprofiles.Variable, deployOptions *Options, lookupEnv func(string) (string, bool), setEnv func(string, string) error) error {
+	explicit := map[string]bool{}
+	for _, v := range deployOptions.Variables {
+		explicit[strings.SplitN(v, "=", 2)[0]] = true
+	}
+	for _, v := range saved {
+		if explicit[v.Name] {
+			continue
+		}
+		if v.Secret {
+			if value, ok := lookupEnv(v.Name); !ok || value == "" {
+				oktetoLog.Warning("The secret variable '%s' of the profile is not set in your environment", v.Name)
+			}
+			continue
+		}
+		if err := setEnv(v.Name, v.Value); err != nil {
+			return err
+		}
+		deployOptions.Variables = append(deployOptions.Variables, fmt.Sprintf("%s=%s", v.Name, v.Value))
+	}
+	return nil
+}
+
+// saveVariables saves the resolved variables in the profile set with '--save-vars'
+func (dc *DeployCommand) saveVariables(ctx context.Context, deployOptions *Options) error {
+	key, err := dc.getVarProfileKey(ctx, deployOptions, deployOptions.SaveVars)
+	if err != nil {
+		return err
+	}
+	variables := getVariablesToSave(deployOptions.Manifest.Variables, deployOptions.Variables, os.LookupEnv)
+	if err := dc.VarProfiles.Save(key, variables); err != nil {
+		return err
+	}
+	oktetoLog.Information("Variables saved in the profile '%s'", key.Profile)
+	return nil
+}
+
+// getVariablesToSave returns the values of the variables declared in the manifest and the ones set with '--var'.
+// The secret variables only keep their name
+func getVariablesToSave(declared model.ManifestVariables, variables []string, lookupEnv func(string) (string, bool)) []varprofiles.Variable {
+	result := []varprofiles.Variable{}
+	seen := map[string]bool{}
+	for _, v := range declared {
+		seen[v.Name] = true
+		if v.Secret {
+			result = append(result, varprofiles.Variable{Name: v.Name, Secret: true})
+			continue
+		}
+		if value, ok := lookupEnv(v.Name); ok {
+			result = append(result, varprofiles.Variable{Name: v.Name, Value: value})
+		}
+	}
+	for _, v := range variables {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 || seen[kv[0]] {
+			continue
+		}
+		seen[kv[0]] = true
+		result = append(result, varprofiles.Variable{Name: kv[0], Value: kv[1]})
+	}
+	return result
+}
@@ -0,0 +1,160 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"testing"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/okteto/okteto/pkg/varprofiles"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeVarProfileStore struct {
+	profiles map[varprofiles.Key][]varprofiles.Variable
+}
+
+func (s *fakeVarProfileStore) Get(key varprofiles.Key) (*varprofiles.Profile, error) {
+	variables, ok := s.profiles[key]
+	if !ok {
+		return nil, varprofiles.ErrProfileNotFound
+	}
+	return &varprofiles.Profile{Key: key, Variables: variables}, nil
+}
+
+func (s *fakeVarProfileStore) Save(key varprofiles.Key, variables []varprofiles.Variable) error {
+	s.profiles[key] = variables
+	return nil
+}
+
+func TestApplySavedVariables(t *testing.T) {
+	saved := []varprofiles.Variable{
+		{Name: "REPLICAS", Value: "2"},
+		{Name: "REGION", Value: "eu"},
+		{Name: "TOKEN", Secret: true},
+	}
+	tests := []struct {
+		name              string
+		variables         []string
+		env               map[string]string
+		expectedVariables []string
+		expectedEnv       map[string]string
+	}{
+		{
+			name:              "saved variables are the baseline",
+			env:               map[string]string{"REGION": "us"},
+			expectedVariables: []string{"REPLICAS=2", "REGION=eu"},
+			expectedEnv:       map[string]string{"REPLICAS": "2", "REGION": "eu"},
+		},
+		{
+			name:              "explicit variables take priority",
+			variables:         []string{"REGION=us"},
+			env:               map[string]string{"REGION": "us"},
+			expectedVariables: []string{"REGION=us", "REPLICAS=2"},
+			expectedEnv:       map[string]string{"REPLICAS": "2", "REGION": "us"},
+		},
+		{
+			name:              "secret variables are read from the environment",
+			env:               map[string]string{"TOKEN": "s3cr3t"},
+			expectedVariables: []string{"REPLICAS=2", "REGION=eu"},
+			expectedEnv:       map[string]string{"REPLICAS": "2", "REGION": "eu", "TOKEN": "s3cr3t"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{}
+			for k, v := range tt.env {
+				env[k] = v
+			}
+			lookupEnv := func(k string) (string, bool) {
+				v, ok := env[k]
+				return v, ok
+			}
+			setEnv := func(k, v string) error {
+				env[k] = v
+				return nil
+			}
+			opts := &Options{Variables: tt.variables}
+			require.NoError(t, applySavedVariables(saved, opts, lookupEnv, setEnv))
+			assert.Equal(t, tt.expectedVariables, opts.Variables)
+			assert.Equal(t, tt.expectedEnv, env)
+		})
+	}
+}
+
+func TestGetVariablesToSave(t *testing.T) {
+	declared := model.ManifestVariables{
+		{Name: "REPLICAS", Default: "1"},
+		{Name: "TOKEN", Secret: true},
+		{Name: "UNSET"},
+	}
+	env := map[string]string{"REPLICAS": "3", "TOKEN": "s3cr3t", "REGION": "eu"}
+	lookupEnv := func(k string) (string, bool) {
+		v, ok := env[k]
+		return v, ok
+	}
+
+	result := getVariablesToSave(declared, []string{"TOKEN=s3cr3t", "REGION=eu"}, lookupEnv)
+	expected := []varprofiles.Variable{
+		{Name: "REPLICAS", Value: "3"},
+		{Name: "TOKEN", Secret: true},
+		{Name: "REGION", Value: "eu"},
+	}
+	assert.Equal(t, expected, result)
+}
+
+func TestLoadAndSaveVariables(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "ns",
+			},
+		},
+		CurrentContext: "test",
+	}
+	t.Setenv("SAVED_VARS_REGION", "")
+	key := varprofiles.Key{Context: "test", Namespace: "ns", Environment: "app", Profile: "staging"}
+	store := &fakeVarProfileStore{profiles: map[varprofiles.Key][]varprofiles.Variable{
+		key: {{Name: "SAVED_VARS_REGION", Value: "eu"}},
+	}}
+	dc := &DeployCommand{VarProfiles: store}
+
+	opts := &Options{
+		Name:          "app",
+		FromSavedVars: "staging",
+		SaveVars:      "prod",
+		Variables:     []string{"SAVED_VARS_REPLICAS=2"},
+		Manifest:      &model.Manifest{},
+	}
+	require.NoError(t, dc.loadSavedVariables(context.Background(), opts))
+	assert.Equal(t, []string{"SAVED_VARS_REPLICAS=2", "SAVED_VARS_REGION=eu"}, opts.Variables)
+
+	require.NoError(t, dc.saveVariables(context.Background(), opts))
+	prodKey := key
+	prodKey.Profile = "prod"
+	expected := []varprofiles.Variable{
+		{Name: "SAVED_VARS_REPLICAS", Value: "2"},
+		{Name: "SAVED_VARS_REGION", Value: "eu"},
+	}
+	assert.Equal(t, expected, store.profiles[prodKey])
+
+	opts.FromSavedVars = "missing"
+	err := dc.loadSavedVariables(context.Background(), opts)
+	assert.ErrorAs(t, err, &oktetoErrors.UserError{})
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// noTrailCommand is the command index of the requests sent while no deploy command is running
+const noTrailCommand = -1
+
+// trailVerbs maps the mutating http methods to kubernetes verbs
+var trailVerbs = map[string]string{
+	http.MethodPost:   "create",
+	http.MethodPut:    "update",
+	http.MethodPatch:  "patch",
+	http.MethodDelete: "delete",
+}
+
+// getTrailEntry returns the trail entry of a mutating request to the kubernetes API
+func getTrailEntry(r *http.Request, body []byte) (pipeline.TrailEntry, bool) {
+	verb, ok := trailVerbs[r.Method]
+	if !ok || r.URL.Query().Get("dryRun") != "" {
+		return pipeline.TrailEntry{}, false
+	}
+	group, version, rest, ok := splitAPIPath(r.URL.Path)
+	if !ok || len(rest) == 0 {
+		return pipeline.TrailEntry{}, false
+	}
+
+	entry := pipeline.TrailEntry{
+		Time:    time.Now().UTC(),
+		Verb:    verb,
+		Group:   group,
+		Version: version,
+	}
+	if rest[0] == "namespaces" && len(rest) > 2 {
+		entry.Namespace = rest[1]
+		rest = rest[2:]
+	}
+	entry.Resource = rest[0]
+	if len(rest) > 1 {
+		entry.Name = rest[1]
+	}
+	isSubresource := len(rest) > 2
+	if isSubresource {
+		entry.Resource = fmt.Sprintf("%s/%s", rest[0], strings.Join(rest[2:], "/"))
+	}
+	if verb == "delete" && entry.Name == "" {
+		entry.Verb = "deletecollection"
+	}
+
+	if len(body) > 0 {
+		sum := sha256.Sum256(body)
+		entry.BodySHA256 = hex.EncodeToString(sum[:])
+		if (r.Method == http.MethodPost || r.Method == http.MethodPut) && !isSubresource {
+			var obj struct {
+				metav1.TypeMeta `json:",inline"`
+				Metadata        metav1.ObjectMeta `json:"metadata"`
+			}
+			if err := json.Unmarshal(body, &obj); err == nil {
+				entry.Kind = obj.Kind
+				if entry.Name == "" {
+					entry.Name = obj.Metadata.Name
+				}
+			}
+		}
+	}
+	return entry, true
+}
+
+func (ph *proxyHandler) isRecordingTrail() bool {
+	ph.objectsMu.Lock()
+	defer ph.objectsMu.Unlock()
+	return ph.recordTrail
+}
+
+func (ph *proxyHandler) setTrailCommand(i int) {
+	ph.objectsMu.Lock()
+	defer ph.objectsMu.Unlock()
+	ph.trailCommand = i
+}
+
+// recordTrailEntry adds the request to the trail, tagged with the deploy command running
+func (ph *proxyHandler) recordTrailEntry(r *http.Request, body []byte) {
+	if !ph.isRecordingTrail() {
+		return
+	}
+	entry, ok := getTrailEntry(r, body)
+	if !ok {
+		return
+	}
+	ph.objectsMu.Lock()
+	defer ph.objectsMu.Unlock()
+	entry.Command = ph.trailCommand
+	ph.trail = append(ph.trail, entry)
+}
+
+func (ph *proxyHandler) getTrail() []pipeline.TrailEntry {
+	ph.objectsMu.Lock()
+	defer ph.objectsMu.Unlock()
+	return append([]pipeline.TrailEntry{}, ph.trail...)
+}
@@ -0,0 +1,228 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+const trailConfigMapBody = `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"api"}}`
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestGetTrailEntry(t *testing.T) {
+	var tests = []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		expected *pipeline.TrailEntry
+	}{
+		{
+			name:   "create namespaced object",
+			method: http.MethodPost,
+			url:    "/api/v1/namespaces/test/configmaps",
+			body:   trailConfigMapBody,
+			expected: &pipeline.TrailEntry{
+				Verb:       "create",
+				Version:    "v1",
+				Kind:       "ConfigMap",
+				Resource:   "configmaps",
+				Namespace:  "test",
+				Name:       "api",
+				BodySHA256: sha256Hex(trailConfigMapBody),
+			},
+		},
+		{
+			name:   "update object of a group",
+			method: http.MethodPut,
+			url:    "/apis/apps/v1/namespaces/test/deployments/api",
+			body:   `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"api"}}`,
+			expected: &pipeline.TrailEntry{
+				Verb:       "update",
+				Group:      "apps",
+				Version:    "v1",
+				Kind:       "Deployment",
+				Resource:   "deployments",
+				Namespace:  "test",
+				Name:       "api",
+				BodySHA256: sha256Hex(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"api"}}`),
+			},
+		},
+		{
+			name:   "patch subresource",
+			method: http.MethodPatch,
+			url:    "/apis/apps/v1/namespaces/test/deployments/api/scale",
+			body:   `{"spec":{"replicas":2}}`,
+			expected: &pipeline.TrailEntry{
+				Verb:       "patch",
+				Group:      "apps",
+				Version:    "v1",
+				Resource:   "deployments/scale",
+				Namespace:  "test",
+				Name:       "api",
+				BodySHA256: sha256Hex(`{"spec":{"replicas":2}}`),
+			},
+		},
+		{
+			name:   "delete cluster-scoped object",
+			method: http.MethodDelete,
+			url:    "/apis/rbac.authorization.k8s.io/v1/clusterroles/reader",
+			expected: &pipeline.TrailEntry{
+				Verb:     "delete",
+				Group:    "rbac.authorization.k8s.io",
+				Version:  "v1",
+				Resource: "clusterroles",
+				Name:     "reader",
+			},
+		},
+		{
+			name:   "delete collection",
+			method: http.MethodDelete,
+			url:    "/api/v1/namespaces/test/pods",
+			expected: &pipeline.TrailEntry{
+				Verb:      "deletecollection",
+				Version:   "v1",
+				Resource:  "pods",
+				Namespace: "test",
+			},
+		},
+		{
+			name:   "delete namespace",
+			method: http.MethodDelete,
+			url:    "/api/v1/namespaces/test",
+			expected: &pipeline.TrailEntry{
+				Verb:     "delete",
+				Version:  "v1",
+				Resource: "namespaces",
+				Name:     "test",
+			},
+		},
+		{
+			name:   "get is not recorded",
+			method: http.MethodGet,
+			url:    "/api/v1/namespaces/test/configmaps/api",
+		},
+		{
+			name:   "dry run is not recorded",
+			method: http.MethodPost,
+			url:    "/api/v1/namespaces/test/configmaps?dryRun=All",
+			body:   trailConfigMapBody,
+		},
+		{
+			name:   "non api path is not recorded",
+			method: http.MethodPost,
+			url:    "/version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.url, nil)
+			entry, ok := getTrailEntry(r, []byte(tt.body))
+			if tt.expected == nil {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.False(t, entry.Time.IsZero())
+			entry.Time = tt.expected.Time
+			assert.Equal(t, *tt.expected, entry)
+		})
+	}
+}
+
+func TestProxyRecordsTrail(t *testing.T) {
+	var received []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		received = append(received, r.Method+" "+string(b))
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ph := &proxyHandler{trailCommand: noTrailCommand, recordTrail: true}
+	handler, err := ph.getProxyHandler("token", &rest.Config{
+		Host:            server.URL,
+		TLSClientConfig: rest.TLSClientConfig{Insecure: true},
+	})
+	require.NoError(t, err)
+
+	send := func(method, url, body string) {
+		r := httptest.NewRequest(method, url, strings.NewReader(body))
+		r.Header.Set("Authorization", "Bearer token")
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, r)
+		require.Equal(t, http.StatusOK, rw.Code)
+	}
+
+	send(http.MethodGet, "/api/v1/namespaces/test/configmaps/api", "")
+	ph.setTrailCommand(0)
+	send(http.MethodPost, "/api/v1/namespaces/test/configmaps", trailConfigMapBody)
+	send(http.MethodPut, "/api/v1/namespaces/test/configmaps/api", trailConfigMapBody)
+	ph.setTrailCommand(1)
+	send(http.MethodPatch, "/api/v1/namespaces/test/configmaps/api", `{"data":{"a":"b"}}`)
+	ph.setTrailCommand(noTrailCommand)
+	send(http.MethodDelete, "/api/v1/namespaces/test/configmaps/api", "")
+
+	trail := ph.getTrail()
+	require.Len(t, trail, 4)
+	verbs := []string{}
+	commands := []int{}
+	for _, entry := range trail {
+		verbs = append(verbs, entry.Verb)
+		commands = append(commands, entry.Command)
+	}
+	assert.Equal(t, []string{"create", "update", "patch", "delete"}, verbs)
+	assert.Equal(t, []int{0, 0, 1, noTrailCommand}, commands)
+	assert.Equal(t, sha256Hex(`{"data":{"a":"b"}}`), trail[2].BodySHA256)
+
+	// the body read to record the trail is still forwarded to the cluster
+	assert.Contains(t, received, `PATCH {"data":{"a":"b"}}`)
+}
+
+func TestProxyWithoutTrail(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ph := &proxyHandler{trailCommand: noTrailCommand}
+	handler, err := ph.getProxyHandler("token", &rest.Config{
+		Host:            server.URL,
+		TLSClientConfig: rest.TLSClientConfig{Insecure: true},
+	})
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodDelete, "/api/v1/namespaces/test/configmaps/api", nil)
+	r.Header.Set("Authorization", "Bearer token")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	assert.Empty(t, ph.getTrail())
+}
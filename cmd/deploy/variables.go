@@ -0,0 +1,48 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/okteto/okteto/pkg/model"
+)
+
+const maskedValue = "***"
+
+// listVariables prints the variables declared in the manifest with their current resolved values
+func listVariables(out io.Writer, vars model.ManifestVariables, lookupEnv func(string) (string, bool)) error {
+	if len(vars) == 0 {
+		_, err := fmt.Fprintln(out, "There are no variables declared in the okteto manifest")
+		return err
+	}
+	w := tabwriter.NewWriter(out, 1, 1, 2, ' ', 0)
+	fmt.Fprintf(w, "Name\tRequired\tValue\tDescription\n")
+	for _, v := range vars {
+		value, ok := lookupEnv(v.Name)
+		if !ok {
+			value = v.Default
+		}
+		switch {
+		case value == "":
+			value = "-"
+		case v.Secret:
+			value = maskedValue
+		}
+		fmt.Fprintf(w, "%s\t%t\t%s\t%s\n", v.Name, v.Required, value, v.Description)
+	}
+	return w.Flush()
+}
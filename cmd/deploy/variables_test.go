@@ -0,0 +1,70 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListVariables(t *testing.T) {
+	tests := []struct {
+		name     string
+		vars     model.ManifestVariables
+		env      map[string]string
+		expected string
+	}{
+		{
+			name:     "no variables",
+			expected: "There are no variables declared in the okteto manifest\n",
+		},
+		{
+			name: "resolved values",
+			vars: model.ManifestVariables{
+				{Name: "API_REPLICAS", Default: "2", Description: "api replicas"},
+				{Name: "DB_PASSWORD", Required: true, Secret: true, Description: "db password"},
+				{Name: "DOMAIN"},
+			},
+			env: map[string]string{"DB_PASSWORD": "s3cr3t"},
+			expected: "Name          Required  Value  Description\n" +
+				"API_REPLICAS  false     2      api replicas\n" +
+				"DB_PASSWORD   true      ***    db password\n" +
+				"DOMAIN        false     -      \n",
+		},
+		{
+			name: "flag overrides default",
+			vars: model.ManifestVariables{
+				{Name: "API_REPLICAS", Default: "2"},
+			},
+			env: map[string]string{"API_REPLICAS": "5"},
+			expected: "Name          Required  Value  Description\n" +
+				"API_REPLICAS  false     5      \n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := listVariables(out, tt.vars, func(k string) (string, bool) {
+				v, ok := tt.env[k]
+				return v, ok
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, out.String())
+		})
+	}
+}
@@ -18,27 +18,35 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"time"
 
-	"github.com/okteto/okteto/pkg/cmd/pipeline"
 	oktetoErrors "github.com/okteto/okteto/pkg/errors"
-	"github.com/okteto/okteto/pkg/k8s/deployments"
-	"github.com/okteto/okteto/pkg/k8s/statefulsets"
 	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
 	"github.com/okteto/okteto/pkg/okteto"
+	"k8s.io/client-go/dynamic"
 )
 
 type DeployWaiter struct {
 	K8sClientProvider okteto.K8sClientProvider
+	// DynamicClientProvider provides the client used to check the custom resources deployed
+	DynamicClientProvider func() (dynamic.Interface, error)
 }
 
 func NewDeployWaiter(k8sClientProvider okteto.K8sClientProvider) DeployWaiter {
 	return DeployWaiter{
 		K8sClientProvider: k8sClientProvider,
+		DynamicClientProvider: func() (dynamic.Interface, error) {
+			c, _, err := okteto.GetDynamicClient()
+			return c, err
+		},
 	}
 }
 
 func (dw *DeployWaiter) wait(ctx context.Context, opts *Options) error {
+	oktetoLog.SetStage("Waiting for resources")
+	defer oktetoLog.SetStage("")
 	oktetoLog.Spinner(fmt.Sprintf("Waiting for %s to be deployed...", opts.Name))
 	oktetoLog.StartSpinner()
 	defer oktetoLog.StopSpinner()
@@ -63,47 +71,97 @@ func (dw *DeployWaiter) wait(ctx context.Context, opts *Options) error {
 	return nil
 }
 
+// waitInterval is the time between the checks of the resources deployed
+var waitInterval = 5 * time.Second
+
 func (dw *DeployWaiter) waitForResourcesToBeRunning(ctx context.Context, opts *Options) error {
-	ticker := time.NewTicker(5 * time.Second)
-	to := time.NewTicker(opts.Timeout)
 	c, _, err := dw.K8sClientProvider.Provide(okteto.Context().Cfg)
 	if err != nil {
 		return err
 	}
+	var manifestConditions []model.WaitCondition
+	if opts.Manifest.Deploy != nil {
+		manifestConditions = opts.Manifest.Deploy.WaitConditions
+	}
+	waitConditions, err := getWaitConditions(manifestConditions)
+	if err != nil {
+		return err
+	}
+	dynClient, err := dw.DynamicClientProvider()
+	if err != nil {
+		return err
+	}
 
+	ticker := time.NewTicker(waitInterval)
+	defer ticker.Stop()
+	// a zero timeout waits forever
+	var timeout <-chan time.Time
+	if opts.Timeout > 0 {
+		timer := time.NewTimer(opts.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	reported := map[string]bool{}
+	var notReady []workloadStatus
 	for {
-		select {
-		case <-to.C:
-			return fmt.Errorf("'%s' deploy didn't finish after %s", opts.Manifest.Name, opts.Timeout.String())
-		case <-ticker.C:
-			dList, err := pipeline.ListDeployments(ctx, opts.Manifest.Name, opts.Manifest.Namespace, c)
-			if err != nil {
-				return err
-			}
-			areAllRunning := true
-			for i := range dList {
-				d := &dList[i]
-				if !deployments.IsRunning(ctx, opts.Manifest.Namespace, d.Name, c) {
-					areAllRunning = false
+		statuses, err := getWorkloadsStatus(ctx, c, opts.Manifest.Name, opts.Manifest.Namespace)
+		if err != nil {
+			return err
+		}
+		notReady = nil
+		for _, st := range statuses {
+			switch {
+			case st.failed:
+				return oktetoErrors.UserError{
+					E:    fmt.Errorf("%s '%s' failed: %s", st.kind, st.name, st.detail),
+					Hint: fmt.Sprintf("Check the logs of the pods of the %s with 'kubectl logs'", strings.ToLower(st.kind)),
 				}
+			case st.ready:
+				if !reported[st.id()] {
+					reported[st.id()] = true
+					oktetoLog.Information("%s '%s' is ready", st.kind, st.name)
+				}
+			default:
+				notReady = append(notReady, st)
 			}
-			if !areAllRunning {
-				continue
-			}
-			sfsList, err := pipeline.ListStatefulsets(ctx, opts.Manifest.Name, opts.Manifest.Namespace, c)
+		}
+		oktetoLog.Spinner(fmt.Sprintf("Waiting for %s to be deployed: %d/%d resources ready...", opts.Name, len(statuses)-len(notReady), len(statuses)))
+
+		if len(notReady) == 0 {
+			ready, err := areCustomResourcesReady(ctx, dynClient, opts.Manifest.Name, opts.Manifest.Namespace, waitConditions)
 			if err != nil {
 				return err
 			}
-			for i := range sfsList {
-				ss := &sfsList[i]
-				if !statefulsets.IsRunning(ctx, opts.Manifest.Namespace, ss.Name, c) {
-					areAllRunning = false
-				}
-			}
-			if !areAllRunning {
-				continue
+			if ready {
+				return nil
 			}
-			return nil
 		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout:
+			return newWaitTimeoutError(opts, notReady)
+		case <-ticker.C:
+		}
+	}
+}
+
+// newWaitTimeoutError returns the error of a '--wait' that timed out listing the resources that are not ready
+func newWaitTimeoutError(opts *Options, notReady []workloadStatus) error {
+	if len(notReady) == 0 {
+		return oktetoErrors.UserError{
+			E:    fmt.Errorf("'%s' deploy didn't finish after %s: the custom resources declared in 'deploy.waitConditions' are not ready", opts.Name, opts.Timeout.String()),
+			Hint: "Increase the time to wait with the '--timeout' flag",
+		}
+	}
+	lines := make([]string, 0, len(notReady))
+	for _, st := range notReady {
+		lines = append(lines, fmt.Sprintf(" - %s '%s': %s", st.kind, st.name, st.detail))
+	}
+	return oktetoErrors.UserError{
+		E:    fmt.Errorf("'%s' deploy didn't finish after %s, the following resources are not ready:\n%s", opts.Name, opts.Timeout.String(), strings.Join(lines, "\n")),
+		Hint: "Check the events of the resources with 'kubectl describe' or increase the time to wait with the '--timeout' flag",
 	}
 }
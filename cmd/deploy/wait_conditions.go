@@ -0,0 +1,131 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/okteto/okteto/pkg/format"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// waitConditionKind is a kind of custom resource checked by '--wait'
+type waitConditionKind struct {
+	kind string
+	gvr  schema.GroupVersionResource
+	// condition is the type or reason of the status condition reporting the resource is ready. Empty means existence only
+	condition string
+}
+
+// defaultWaitConditions are the custom resources that report ready before they are actually usable
+var defaultWaitConditions = []waitConditionKind{
+	{
+		kind:      "ExternalSecret",
+		gvr:       schema.GroupVersionResource{Group: "external-secrets.io", Version: "v1beta1", Resource: "externalsecrets"},
+		condition: "SecretSynced",
+	},
+	{
+		kind:      "SealedSecret",
+		gvr:       schema.GroupVersionResource{Group: "bitnami.com", Version: "v1alpha1", Resource: "sealedsecrets"},
+		condition: "Synced",
+	},
+}
+
+// getWaitConditions returns the default wait conditions extended with the ones declared in the manifest.
+// A manifest entry overrides the default entry for the same kind and group
+func getWaitConditions(manifestConditions []model.WaitCondition) ([]waitConditionKind, error) {
+	result := append([]waitConditionKind{}, defaultWaitConditions...)
+	for _, wc := range manifestConditions {
+		gv, err := schema.ParseGroupVersion(wc.APIVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid apiVersion '%s' in 'deploy.waitConditions': %w", wc.APIVersion, err)
+		}
+		gvr, _ := meta.UnsafeGuessKindToResource(gv.WithKind(wc.Kind))
+		entry := waitConditionKind{
+			kind:      wc.Kind,
+			gvr:       gvr,
+			condition: wc.Condition,
+		}
+
+		overridden := false
+		for i := range result {
+			if result[i].kind == entry.kind && result[i].gvr.Group == entry.gvr.Group {
+				result[i] = entry
+				overridden = true
+			}
+		}
+		if !overridden {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+// areCustomResourcesReady checks that every custom resource deployed by the development environment reports its ready condition
+func areCustomResourcesReady(ctx context.Context, c dynamic.Interface, name, ns string, kinds []waitConditionKind) (bool, error) {
+	opts := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", model.DeployedByLabel, format.ResourceK8sMetaString(name)),
+	}
+	for _, k := range kinds {
+		if k.condition == "" {
+			continue
+		}
+		list, err := c.Resource(k.gvr).Namespace(ns).List(ctx, opts)
+		if err != nil {
+			if k8sErrors.IsNotFound(err) {
+				// the CRD is not installed in the cluster
+				continue
+			}
+			return false, fmt.Errorf("could not list %s: %w", k.gvr.Resource, err)
+		}
+		for i := range list.Items {
+			item := &list.Items[i]
+			if !hasReadyCondition(item, k.condition) {
+				oktetoLog.Infof("%s '%s' doesn't report condition '%s' yet", k.kind, item.GetName(), k.condition)
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// hasReadyCondition returns if the resource has a status condition with the given type or reason and status True
+func hasReadyCondition(u *unstructured.Unstructured, condition string) bool {
+	conditions, found, err := unstructured.NestedSlice(u.Object, "status", "conditions")
+	if err != nil || !found {
+		return false
+	}
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condType, _, _ := unstructured.NestedString(cond, "type")
+		reason, _, _ := unstructured.NestedString(cond, "reason")
+		status, _, _ := unstructured.NestedString(cond, "status")
+		if (condType == condition || reason == condition) && strings.EqualFold(status, "True") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+var certificateGVR = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+
+func newCustomResource(apiVersion, kind, name string, conditions ...map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "test",
+				"labels": map[string]interface{}{
+					model.DeployedByLabel: "movies",
+				},
+			},
+		},
+	}
+	if len(conditions) > 0 {
+		list := []interface{}{}
+		for _, c := range conditions {
+			list = append(list, c)
+		}
+		u.Object["status"] = map[string]interface{}{"conditions": list}
+	}
+	return u
+}
+
+func newFakeDynamicClient(objs ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		defaultWaitConditions[0].gvr: "ExternalSecretList",
+		defaultWaitConditions[1].gvr: "SealedSecretList",
+		certificateGVR:               "CertificateList",
+	}, objs...)
+}
+
+func TestGetWaitConditions(t *testing.T) {
+	conditions, err := getWaitConditions([]model.WaitCondition{
+		{APIVersion: "cert-manager.io/v1", Kind: "Certificate", Condition: "Ready"},
+		{APIVersion: "bitnami.com/v1alpha1", Kind: "SealedSecret"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []waitConditionKind{
+		defaultWaitConditions[0],
+		{kind: "SealedSecret", gvr: defaultWaitConditions[1].gvr},
+		{kind: "Certificate", gvr: certificateGVR, condition: "Ready"},
+	}, conditions)
+
+	_, err = getWaitConditions([]model.WaitCondition{{APIVersion: "a/b/c", Kind: "Certificate"}})
+	assert.Error(t, err)
+}
+
+func TestAreCustomResourcesReady(t *testing.T) {
+	synced := map[string]interface{}{"type": "Ready", "status": "True", "reason": "SecretSynced"}
+	notSynced := map[string]interface{}{"type": "Ready", "status": "False", "reason": "SecretSyncedError"}
+	sealedSynced := map[string]interface{}{"type": "Synced", "status": "True"}
+	certReady := map[string]interface{}{"type": "Ready", "status": "True"}
+
+	tests := []struct {
+		name     string
+		objs     []runtime.Object
+		manifest []model.WaitCondition
+		expected bool
+	}{
+		{
+			name:     "no custom resources",
+			expected: true,
+		},
+		{
+			name: "external secret synced",
+			objs: []runtime.Object{
+				newCustomResource("external-secrets.io/v1beta1", "ExternalSecret", "db", synced),
+			},
+			expected: true,
+		},
+		{
+			name: "external secret not synced",
+			objs: []runtime.Object{
+				newCustomResource("external-secrets.io/v1beta1", "ExternalSecret", "db", notSynced),
+			},
+			expected: false,
+		},
+		{
+			name: "external secret without status",
+			objs: []runtime.Object{
+				newCustomResource("external-secrets.io/v1beta1", "ExternalSecret", "db"),
+			},
+			expected: false,
+		},
+		{
+			name: "sealed secret synced and external secret pending",
+			objs: []runtime.Object{
+				newCustomResource("bitnami.com/v1alpha1", "SealedSecret", "api", sealedSynced),
+				newCustomResource("external-secrets.io/v1beta1", "ExternalSecret", "db", notSynced),
+			},
+			expected: false,
+		},
+		{
+			name: "kind without table entry only needs to exist",
+			objs: []runtime.Object{
+				newCustomResource("cert-manager.io/v1", "Certificate", "tls"),
+			},
+			expected: true,
+		},
+		{
+			name: "kind declared in the manifest not ready",
+			objs: []runtime.Object{
+				newCustomResource("cert-manager.io/v1", "Certificate", "tls"),
+			},
+			manifest: []model.WaitCondition{
+				{APIVersion: "cert-manager.io/v1", Kind: "Certificate", Condition: "Ready"},
+			},
+			expected: false,
+		},
+		{
+			name: "kind declared in the manifest ready",
+			objs: []runtime.Object{
+				newCustomResource("cert-manager.io/v1", "Certificate", "tls", certReady),
+			},
+			manifest: []model.WaitCondition{
+				{APIVersion: "cert-manager.io/v1", Kind: "Certificate", Condition: "Ready"},
+			},
+			expected: true,
+		},
+		{
+			name: "default condition overridden to existence only",
+			objs: []runtime.Object{
+				newCustomResource("external-secrets.io/v1beta1", "ExternalSecret", "db", notSynced),
+			},
+			manifest: []model.WaitCondition{
+				{APIVersion: "external-secrets.io/v1beta1", Kind: "ExternalSecret"},
+			},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kinds, err := getWaitConditions(tt.manifest)
+			require.NoError(t, err)
+			c := newFakeDynamicClient(tt.objs...)
+			ready, err := areCustomResourcesReady(context.Background(), c, "movies", "test", kinds)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ready)
+		})
+	}
+}
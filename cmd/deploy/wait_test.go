@@ -0,0 +1,86 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/okteto/okteto/internal/test"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+)
+
+func newTestDeployWaiter(objs ...runtime.Object) *DeployWaiter {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+			},
+		},
+		CurrentContext: "test",
+	}
+	return &DeployWaiter{
+		K8sClientProvider: test.NewFakeK8sProvider(objs...),
+		DynamicClientProvider: func() (dynamic.Interface, error) {
+			return newFakeDynamicClient(), nil
+		},
+	}
+}
+
+func TestWaitForResourcesToBeRunning(t *testing.T) {
+	interval := waitInterval
+	waitInterval = 10 * time.Millisecond
+	t.Cleanup(func() { waitInterval = interval })
+
+	opts := &Options{
+		Name:     "movies",
+		Timeout:  100 * time.Millisecond,
+		Manifest: &model.Manifest{Name: "movies", Namespace: "test", Deploy: &model.DeployInfo{}},
+	}
+
+	dw := newTestDeployWaiter(
+		newDeployment("api", 1, appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 1, AvailableReplicas: 1}),
+		newJob("migrate", batchv1.JobStatus{Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}}),
+	)
+	require.NoError(t, dw.waitForResourcesToBeRunning(context.Background(), opts))
+
+	dw = newTestDeployWaiter(
+		newDeployment("api", 2, appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 2, AvailableReplicas: 1}),
+		newStatefulSet("db", 1, appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 1}),
+		newJob("migrate", batchv1.JobStatus{Active: 1}),
+	)
+	err := dw.waitForResourcesToBeRunning(context.Background(), opts)
+	var userErr oktetoErrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Equal(t, "'movies' deploy didn't finish after 100ms, the following resources are not ready:\n - Deployment 'api': 1/2 replicas available\n - Job 'migrate': 0/1 completions", err.Error())
+	assert.Contains(t, userErr.Hint, "--timeout")
+
+	dw = newTestDeployWaiter(
+		newJob("migrate", batchv1.JobStatus{Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}}}),
+	)
+	err = dw.waitForResourcesToBeRunning(context.Background(), opts)
+	require.ErrorAs(t, err, &userErr)
+	assert.Equal(t, "Job 'migrate' failed: BackoffLimitExceeded", err.Error())
+}
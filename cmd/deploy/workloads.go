@@ -0,0 +1,133 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/okteto/okteto/pkg/format"
+	"github.com/okteto/okteto/pkg/k8s/deployments"
+	"github.com/okteto/okteto/pkg/k8s/jobs"
+	"github.com/okteto/okteto/pkg/k8s/statefulsets"
+	"github.com/okteto/okteto/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// workloadStatus is the status of a workload deployed by the development environment
+type workloadStatus struct {
+	kind   string
+	name   string
+	ready  bool
+	failed bool
+	// detail describes why the workload isn't ready
+	detail string
+}
+
+func (ws workloadStatus) id() string {
+	return fmt.Sprintf("%s/%s", ws.kind, ws.name)
+}
+
+// getWorkloadsStatus returns the status of the deployments, statefulsets and jobs deployed by the development environment
+func getWorkloadsStatus(ctx context.Context, c kubernetes.Interface, name, ns string) ([]workloadStatus, error) {
+	labels := fmt.Sprintf("%s=%s", model.DeployedByLabel, format.ResourceK8sMetaString(name))
+
+	dList, err := deployments.List(ctx, ns, labels, c)
+	if err != nil {
+		return nil, err
+	}
+	sfsList, err := statefulsets.List(ctx, ns, labels, c)
+	if err != nil {
+		return nil, err
+	}
+	jobList, err := jobs.List(ctx, ns, labels, c)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]workloadStatus, 0, len(dList)+len(sfsList)+len(jobList))
+	for i := range dList {
+		result = append(result, getDeploymentStatus(&dList[i]))
+	}
+	for i := range sfsList {
+		result = append(result, getStatefulSetStatus(&sfsList[i]))
+	}
+	for i := range jobList {
+		result = append(result, getJobStatus(&jobList[i]))
+	}
+	return result, nil
+}
+
+// getDeploymentStatus returns a deployment as ready when its rollout is complete
+func getDeploymentStatus(d *appsv1.Deployment) workloadStatus {
+	st := workloadStatus{kind: "Deployment", name: d.Name}
+	replicas := getDesiredReplicas(d.Spec.Replicas)
+	switch {
+	case d.Status.ObservedGeneration < d.Generation:
+		st.detail = "waiting for the rollout to start"
+	case d.Status.UpdatedReplicas < replicas:
+		st.detail = fmt.Sprintf("%d/%d replicas updated", d.Status.UpdatedReplicas, replicas)
+	case d.Status.AvailableReplicas < replicas:
+		st.detail = fmt.Sprintf("%d/%d replicas available", d.Status.AvailableReplicas, replicas)
+	default:
+		st.ready = true
+	}
+	return st
+}
+
+// getStatefulSetStatus returns a statefulset as ready when all its replicas are ready
+func getStatefulSetStatus(sfs *appsv1.StatefulSet) workloadStatus {
+	st := workloadStatus{kind: "StatefulSet", name: sfs.Name}
+	replicas := getDesiredReplicas(sfs.Spec.Replicas)
+	switch {
+	case sfs.Status.ObservedGeneration < sfs.Generation:
+		st.detail = "waiting for the rollout to start"
+	case sfs.Status.ReadyReplicas < replicas:
+		st.detail = fmt.Sprintf("%d/%d replicas ready", sfs.Status.ReadyReplicas, replicas)
+	default:
+		st.ready = true
+	}
+	return st
+}
+
+// getJobStatus returns a job as ready when it completes
+func getJobStatus(job *batchv1.Job) workloadStatus {
+	st := workloadStatus{kind: "Job", name: job.Name}
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			st.ready = true
+			return st
+		case batchv1.JobFailed:
+			st.failed = true
+			st.detail = cond.Message
+			return st
+		}
+	}
+	st.detail = fmt.Sprintf("%d/%d completions", job.Status.Succeeded, getDesiredReplicas(job.Spec.Completions))
+	return st
+}
+
+func getDesiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newWorkloadMeta(name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:       name,
+		Namespace:  "test",
+		Generation: 2,
+		Labels:     map[string]string{model.DeployedByLabel: "movies"},
+	}
+}
+
+func newDeployment(name string, replicas int32, status appsv1.DeploymentStatus) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: newWorkloadMeta(name),
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     status,
+	}
+}
+
+func newStatefulSet(name string, replicas int32, status appsv1.StatefulSetStatus) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: newWorkloadMeta(name),
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+		Status:     status,
+	}
+}
+
+func newJob(name string, status batchv1.JobStatus) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: newWorkloadMeta(name),
+		Status:     status,
+	}
+}
+
+func TestGetWorkloadsStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		obj      runtime.Object
+		expected workloadStatus
+	}{
+		{
+			name:     "deployment rolled out",
+			obj:      newDeployment("api", 2, appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 2, AvailableReplicas: 2}),
+			expected: workloadStatus{kind: "Deployment", name: "api", ready: true},
+		},
+		{
+			name:     "deployment rollout not observed",
+			obj:      newDeployment("api", 2, appsv1.DeploymentStatus{ObservedGeneration: 1, UpdatedReplicas: 2, AvailableReplicas: 2}),
+			expected: workloadStatus{kind: "Deployment", name: "api", detail: "waiting for the rollout to start"},
+		},
+		{
+			name:     "deployment rolling out",
+			obj:      newDeployment("api", 2, appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 1, AvailableReplicas: 2}),
+			expected: workloadStatus{kind: "Deployment", name: "api", detail: "1/2 replicas updated"},
+		},
+		{
+			name:     "deployment replicas not available",
+			obj:      newDeployment("api", 2, appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 2, AvailableReplicas: 1}),
+			expected: workloadStatus{kind: "Deployment", name: "api", detail: "1/2 replicas available"},
+		},
+		{
+			name:     "statefulset ready",
+			obj:      newStatefulSet("db", 1, appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 1}),
+			expected: workloadStatus{kind: "StatefulSet", name: "db", ready: true},
+		},
+		{
+			name:     "statefulset not ready",
+			obj:      newStatefulSet("db", 3, appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 1}),
+			expected: workloadStatus{kind: "StatefulSet", name: "db", detail: "1/3 replicas ready"},
+		},
+		{
+			name: "job complete",
+			obj: newJob("migrate", batchv1.JobStatus{Succeeded: 1, Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+			}}),
+			expected: workloadStatus{kind: "Job", name: "migrate", ready: true},
+		},
+		{
+			name:     "job running",
+			obj:      newJob("migrate", batchv1.JobStatus{Active: 1}),
+			expected: workloadStatus{kind: "Job", name: "migrate", detail: "0/1 completions"},
+		},
+		{
+			name: "job failed",
+			obj: newJob("migrate", batchv1.JobStatus{Failed: 6, Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "Job has reached the specified backoff limit"},
+			}}),
+			expected: workloadStatus{kind: "Job", name: "migrate", failed: true, detail: "Job has reached the specified backoff limit"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewSimpleClientset(tt.obj)
+			result, err := getWorkloadsStatus(context.Background(), c, "movies", "test")
+			require.NoError(t, err)
+			assert.Equal(t, []workloadStatus{tt.expected}, result)
+		})
+	}
+}
+
+func TestGetWorkloadsStatusIgnoresOtherEnvironments(t *testing.T) {
+	other := newDeployment("other", 1, appsv1.DeploymentStatus{})
+	other.Labels[model.DeployedByLabel] = "other"
+	c := fake.NewSimpleClientset(other)
+
+	result, err := getWorkloadsStatus(context.Background(), c, "movies", "test")
+	require.NoError(t, err)
+	assert.Empty(t, result)
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/okteto/okteto/pkg/model"
 	"github.com/okteto/okteto/pkg/okteto"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 )
 
 type localDestroyAllCommand struct {
@@ -26,6 +27,8 @@ type localDestroyAllCommand struct {
 	oktetoClient      *okteto.OktetoClient
 	secrets           secretHandler
 	k8sClientProvider okteto.K8sClientProvider
+	// dynamicClientProvider provides the client used to destroy the cluster resources
+	dynamicClientProvider func() (dynamic.Interface, error)
 }
 
 func newLocalDestroyerAll(
@@ -45,6 +48,10 @@ func newLocalDestroyerAll(
 		oktetoClient:      oktetoClient,
 		nsDestroyer:       nsDestroyer,
 		executor:          executor,
+		dynamicClientProvider: func() (dynamic.Interface, error) {
+			c, _, err := okteto.GetDynamicClient()
+			return c, err
+		},
 	}, nil
 }
 
@@ -0,0 +1,81 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+)
+
+const buildArgHint = "Use '--build-arg KEY=VALUE', where KEY is a valid environment variable name"
+
+// buildArgNameRegex matches the names accepted by the flag '--build-arg'. They are also rendered as env vars
+var buildArgNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// reservedBuildArgs are the build args set by okteto in the remote destroy image
+var reservedBuildArgs = []string{
+	"OKTETO_TLS_CERT_BASE64",
+	"INTERNAL_SERVER_NAME",
+	destroyRunIDArg,
+	extraCACertsArg,
+	impersonateUserArg,
+	impersonateGroupArg,
+}
+
+// buildArg is a build arg given with the flag '--build-arg'
+type buildArg struct {
+	Name  string
+	Value string
+}
+
+// String returns the build arg in the KEY=VALUE form expected by the builder
+func (a buildArg) String() string {
+	return fmt.Sprintf("%s=%s", a.Name, a.Value)
+}
+
+// parseBuildArgs parses the values of the flag '--build-arg'. The value is everything after the first '='
+func parseBuildArgs(values []string) ([]buildArg, error) {
+	result := make([]buildArg, 0, len(values))
+	for _, v := range values {
+		name, value, found := strings.Cut(v, "=")
+		if !found || !buildArgNameRegex.MatchString(name) {
+			return nil, oktetoErrors.UserError{
+				E:    fmt.Errorf("invalid value '%s' for flag '--build-arg'", v),
+				Hint: buildArgHint,
+			}
+		}
+		for _, reserved := range reservedBuildArgs {
+			if name == reserved {
+				return nil, oktetoErrors.UserError{
+					E:    fmt.Errorf("build arg '%s' is reserved by okteto", name),
+					Hint: fmt.Sprintf("Rename the build arg. The reserved build args are %s", strings.Join(reservedBuildArgs, ", ")),
+				}
+			}
+		}
+		result = append(result, buildArg{Name: name, Value: value})
+	}
+	return result, nil
+}
+
+// getBuildArgNames returns the names of the build args, in the order they were given
+func getBuildArgNames(args []buildArg) []string {
+	names := make([]string, 0, len(args))
+	for _, a := range args {
+		names = append(names, a.Name)
+	}
+	return names
+}
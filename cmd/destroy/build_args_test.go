@@ -0,0 +1,72 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"testing"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBuildArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		expected []buildArg
+		err      bool
+	}{
+		{
+			name:     "no build args",
+			expected: []buildArg{},
+		},
+		{
+			name:     "value with '=' and spaces",
+			values:   []string{"QUERY=a=b c", "EMPTY="},
+			expected: []buildArg{{Name: "QUERY", Value: "a=b c"}, {Name: "EMPTY", Value: ""}},
+		},
+		{
+			name:   "missing value",
+			values: []string{"KEY"},
+			err:    true,
+		},
+		{
+			name:   "invalid name",
+			values: []string{"MY-KEY=value"},
+			err:    true,
+		},
+		{
+			name:   "reserved tls cert",
+			values: []string{"OKTETO_TLS_CERT_BASE64=value"},
+			err:    true,
+		},
+		{
+			name:   "reserved server name",
+			values: []string{"INTERNAL_SERVER_NAME=value"},
+			err:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseBuildArgs(tt.values)
+			if tt.err {
+				require.ErrorAs(t, err, &oktetoErrors.UserError{})
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/okteto/okteto/pkg/discovery"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/model"
+)
+
+// getBuildContext returns the folder shipped as build context of the remote destroy. The flag takes priority over
+// the manifest 'destroy.context' and both default to the working directory. Relative paths in the manifest are
+// relative to the working directory, the flag is made absolute before moving to the folder of the manifest
+func (rd *remoteDestroyCommand) getBuildContext(cwd string, opts *Options) (string, error) {
+	contextDir := opts.RemoteContext
+	source := "the '--remote-context' flag"
+	if contextDir == "" && rd.manifest != nil && rd.manifest.Destroy != nil && rd.manifest.Destroy.Context != "" {
+		expanded, err := model.ExpandEnv(rd.manifest.Destroy.Context, false)
+		if err != nil {
+			return "", err
+		}
+		contextDir = expanded
+		source = "the manifest 'destroy.context'"
+	}
+	if contextDir == "" {
+		return cwd, nil
+	}
+
+	if !filepath.IsAbs(contextDir) {
+		contextDir = filepath.Join(cwd, contextDir)
+	}
+	contextDir = filepath.Clean(contextDir)
+	info, err := rd.fs.Stat(contextDir)
+	if err != nil || !info.IsDir() {
+		return "", oktetoErrors.UserError{
+			E:    fmt.Errorf("the build context '%s' set by %s is not a folder", contextDir, source),
+			Hint: "Set it to the folder with the files needed by your destroy commands",
+		}
+	}
+	return contextDir, nil
+}
+
+// getManifestPathInContext returns the path of the okteto manifest relative to the build context. The okteto destroy
+// run in remote starts at the root of the build context, so it is the '--file' forwarded to it.
+// It is empty when there is no manifest
+func getManifestPathInContext(cwd, contextDir string, opts *Options) (string, error) {
+	manifestPath := opts.ManifestPath
+	if manifestPath == "" {
+		path, err := discovery.GetOktetoManifestPath(cwd)
+		if err != nil {
+			return "", nil
+		}
+		manifestPath = path
+	} else if !filepath.IsAbs(manifestPath) {
+		manifestPath = filepath.Join(cwd, manifestPath)
+	}
+
+	rel, err := filepath.Rel(contextDir, manifestPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", oktetoErrors.UserError{
+			E:    fmt.Errorf("the okteto manifest '%s' is outside of the build context '%s'", manifestPath, contextDir),
+			Hint: "The build context of the remote destroy must contain your okteto manifest",
+		}
+	}
+	return filepath.ToSlash(rel), nil
+}
@@ -0,0 +1,212 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	filesystem "github.com/okteto/okteto/pkg/filesystem/fake"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/okteto/okteto/pkg/types"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBuildContext(t *testing.T) {
+	cwd := filepath.FromSlash("/app/services/api")
+	var tests = []struct {
+		name     string
+		opts     *Options
+		manifest *model.Manifest
+		env      map[string]string
+		expected string
+		userErr  bool
+	}{
+		{
+			name:     "defaults to the working directory",
+			opts:     &Options{},
+			expected: cwd,
+		},
+		{
+			name:     "manifest context relative to the working directory",
+			opts:     &Options{},
+			manifest: &model.Manifest{Destroy: &model.DestroyInfo{Context: "../.."}},
+			expected: filepath.FromSlash("/app"),
+		},
+		{
+			name:     "manifest context with variables",
+			opts:     &Options{},
+			manifest: &model.Manifest{Destroy: &model.DestroyInfo{Context: "${CONTEXT_DIR}"}},
+			env:      map[string]string{"CONTEXT_DIR": filepath.FromSlash("/app/services")},
+			expected: filepath.FromSlash("/app/services"),
+		},
+		{
+			name:     "flag overrides the manifest",
+			opts:     &Options{RemoteContext: filepath.FromSlash("/app/services")},
+			manifest: &model.Manifest{Destroy: &model.DestroyInfo{Context: "../.."}},
+			expected: filepath.FromSlash("/app/services"),
+		},
+		{
+			name:    "context not found",
+			opts:    &Options{RemoteContext: filepath.FromSlash("/other")},
+			userErr: true,
+		},
+		{
+			name:    "context is a file",
+			opts:    &Options{RemoteContext: filepath.FromSlash("/app/services/api/okteto.yml")},
+			userErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			fs := afero.NewMemMapFs()
+			require.NoError(t, fs.MkdirAll(cwd, 0700))
+			require.NoError(t, afero.WriteFile(fs, filepath.Join(cwd, "okteto.yml"), []byte("destroy: []"), 0600))
+			rd := &remoteDestroyCommand{fs: fs, manifest: tt.manifest}
+
+			contextDir, err := rd.getBuildContext(cwd, tt.opts)
+			if tt.userErr {
+				var userErr oktetoErrors.UserError
+				assert.ErrorAs(t, err, &userErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, contextDir)
+		})
+	}
+}
+
+func TestGetManifestPathInContext(t *testing.T) {
+	root := t.TempDir()
+	cwd := filepath.Join(root, "services", "api")
+	require.NoError(t, os.MkdirAll(cwd, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(cwd, "okteto.yml"), []byte("destroy: []"), 0600))
+	empty := filepath.Join(root, "empty")
+	require.NoError(t, os.MkdirAll(empty, 0700))
+
+	var tests = []struct {
+		name       string
+		cwd        string
+		contextDir string
+		opts       *Options
+		expected   string
+		userErr    bool
+	}{
+		{
+			name:       "discovered manifest",
+			cwd:        cwd,
+			contextDir: root,
+			opts:       &Options{},
+			expected:   "services/api/okteto.yml",
+		},
+		{
+			name:       "manifest set by flag",
+			cwd:        cwd,
+			contextDir: filepath.Join(root, "services"),
+			opts:       &Options{ManifestPath: "okteto.yml"},
+			expected:   "api/okteto.yml",
+		},
+		{
+			name:       "manifest outside of the context",
+			cwd:        cwd,
+			contextDir: empty,
+			opts:       &Options{ManifestPath: "okteto.yml"},
+			userErr:    true,
+		},
+		{
+			name:       "no manifest",
+			cwd:        empty,
+			contextDir: root,
+			opts:       &Options{},
+			expected:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifestPath, err := getManifestPathInContext(tt.cwd, tt.contextDir, tt.opts)
+			if tt.userErr {
+				var userErr oktetoErrors.UserError
+				assert.ErrorAs(t, err, &userErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, manifestPath)
+		})
+	}
+}
+
+func TestRemoteDestroyWithBuildContext(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	cwd := filepath.FromSlash("/app/services/api")
+	newCommand := func(b *recordingBuilder, out *bytes.Buffer) (*remoteDestroyCommand, afero.Fs) {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, fs.MkdirAll(cwd, 0700))
+		require.NoError(t, afero.WriteFile(fs, filepath.Join(cwd, "okteto.yml"), []byte("destroy: []"), 0600))
+		require.NoError(t, afero.WriteFile(fs, filepath.FromSlash("/app/.dockerignore"), []byte("node_modules"), 0600))
+		require.NoError(t, afero.WriteFile(fs, filepath.Join(cwd, ".dockerignore"), []byte("api-ignored"), 0600))
+		return &remoteDestroyCommand{
+			builder:              b,
+			fs:                   fs,
+			workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(cwd),
+			temporalCtrl:         filesystem.NewTemporalDirectoryCtrl(fs),
+			registry:             newFakeRegistry(),
+			manifest: &model.Manifest{
+				Destroy: &model.DestroyInfo{Context: "../.."},
+			},
+			clusterMetadata: func(ctx context.Context) (*types.ClusterMetadata, error) {
+				return &types.ClusterMetadata{Certificate: []byte("cert"), PipelineInstallerImage: "okteto/installer:1.0", PipelineRunnerImage: "okteto/runner:1.0"}, nil
+			},
+			out: out,
+		}, fs
+	}
+
+	t.Run("the context is shipped and the manifest path is relative to it", func(t *testing.T) {
+		b := &recordingBuilder{}
+		rdc, _ := newCommand(b, &bytes.Buffer{})
+		opts := &Options{ManifestPath: "okteto.yml", ManifestPathFlag: "okteto.yml"}
+
+		require.NoError(t, rdc.destroy(context.Background(), opts))
+		assert.Equal(t, filepath.FromSlash("/app"), b.opts.Path)
+		assert.Equal(t, "services/api/okteto.yml", opts.ManifestPathFlag)
+	})
+
+	t.Run("the ignore files are the ones of the context", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		rdc, _ := newCommand(&recordingBuilder{}, out)
+
+		require.NoError(t, rdc.destroy(context.Background(), &Options{ManifestPath: "okteto.yml", DryRun: true}))
+		assert.Contains(t, out.String(), "--file services/api/okteto.yml")
+		assert.Contains(t, out.String(), "node_modules")
+		assert.NotContains(t, out.String(), "api-ignored")
+	})
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	// extraCACertsArg is the build arg with the base64 encoded bundle of extra CA certificates
+	extraCACertsArg = "OKTETO_EXTRA_CA_CERTS_BASE64"
+	caCertsHint     = "Check the paths of 'destroy.caCerts' in your okteto manifest and the '--extra-ca-cert' flag"
+)
+
+// getCACertPaths returns the extra CA certificates trusted by the remote destroy image: the ones
+// defined in the manifest followed by the ones given with the '--extra-ca-cert' flag
+func (rd *remoteDestroyCommand) getCACertPaths(opts *Options) []string {
+	paths := []string{}
+	if rd.manifest != nil && rd.manifest.Destroy != nil {
+		paths = append(paths, rd.manifest.Destroy.CACerts...)
+	}
+	return append(paths, opts.ExtraCACerts...)
+}
+
+// readCACerts reads the extra CA certificates and returns them as a single PEM bundle.
+// Relative paths are resolved from cwd. Every file must contain at least one certificate
+func (rd *remoteDestroyCommand) readCACerts(cwd string, paths []string) ([]byte, error) {
+	var bundle bytes.Buffer
+	for _, path := range paths {
+		fullPath := path
+		if !filepath.IsAbs(fullPath) {
+			fullPath = filepath.Join(cwd, fullPath)
+		}
+		content, err := afero.ReadFile(rd.fs, fullPath)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, oktetoErrors.UserError{
+					E:    fmt.Errorf("CA certificate '%s' not found", path),
+					Hint: caCertsHint,
+				}
+			}
+			return nil, fmt.Errorf("error reading CA certificate '%s': %w", path, err)
+		}
+
+		certs := 0
+		for {
+			var block *pem.Block
+			block, content = pem.Decode(content)
+			if block == nil {
+				break
+			}
+			if block.Type != "CERTIFICATE" {
+				return nil, oktetoErrors.UserError{
+					E:    fmt.Errorf("CA certificate '%s' contains a '%s' block", path, block.Type),
+					Hint: "Only PEM encoded certificates are allowed",
+				}
+			}
+			if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+				return nil, oktetoErrors.UserError{
+					E:    fmt.Errorf("CA certificate '%s' is not valid: %w", path, err),
+					Hint: caCertsHint,
+				}
+			}
+			if err := pem.Encode(&bundle, block); err != nil {
+				return nil, err
+			}
+			certs++
+		}
+		if certs == 0 {
+			return nil, oktetoErrors.UserError{
+				E:    fmt.Errorf("CA certificate '%s' doesn't contain any PEM encoded certificate", path),
+				Hint: caCertsHint,
+			}
+		}
+	}
+	return bundle.Bytes(), nil
+}
@@ -0,0 +1,249 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	filesystem "github.com/okteto/okteto/pkg/filesystem/fake"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/okteto/okteto/pkg/types"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCACert(t *testing.T, name string) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestReadCACerts(t *testing.T) {
+	ca1 := newTestCACert(t, "ca1")
+	ca2 := newTestCACert(t, "ca2")
+	ca3 := newTestCACert(t, "ca3")
+	bundle := append(append([]byte("# corporate bundle\n"), ca2...), ca3...)
+
+	var tests = []struct {
+		name        string
+		files       map[string][]byte
+		paths       []string
+		expected    []byte
+		expectedErr string
+	}{
+		{
+			name:  "no certificates",
+			paths: []string{},
+		},
+		{
+			name: "multiple files and bundles",
+			files: map[string][]byte{
+				"/app/ca.pem":         ca1,
+				"/etc/ssl/bundle.pem": bundle,
+			},
+			paths:    []string{"ca.pem", "/etc/ssl/bundle.pem"},
+			expected: append(append(append([]byte{}, ca1...), ca2...), ca3...),
+		},
+		{
+			name:        "missing file",
+			paths:       []string{"missing.pem"},
+			expectedErr: "CA certificate 'missing.pem' not found",
+		},
+		{
+			name: "no certificates in file",
+			files: map[string][]byte{
+				"/app/ca.pem": []byte("not a certificate"),
+			},
+			paths:       []string{"ca.pem"},
+			expectedErr: "CA certificate 'ca.pem' doesn't contain any PEM encoded certificate",
+		},
+		{
+			name: "private key",
+			files: map[string][]byte{
+				"/app/ca.pem": append(append([]byte{}, ca1...), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("key")})...),
+			},
+			paths:       []string{"ca.pem"},
+			expectedErr: "CA certificate 'ca.pem' contains a 'PRIVATE KEY' block",
+		},
+		{
+			name: "invalid certificate",
+			files: map[string][]byte{
+				"/app/ca.pem": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("invalid")}),
+			},
+			paths:       []string{"ca.pem"},
+			expectedErr: "CA certificate 'ca.pem' is not valid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			for name, content := range tt.files {
+				require.NoError(t, afero.WriteFile(fs, filepath.FromSlash(name), content, 0600))
+			}
+			rd := remoteDestroyCommand{fs: fs}
+
+			got, err := rd.readCACerts(filepath.FromSlash("/app"), tt.paths)
+			if tt.expectedErr != "" {
+				var userErr oktetoErrors.UserError
+				require.ErrorAs(t, err, &userErr)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, string(tt.expected), string(got))
+		})
+	}
+}
+
+func TestGetCACertPaths(t *testing.T) {
+	rd := remoteDestroyCommand{
+		manifest: &model.Manifest{
+			Destroy: &model.DestroyInfo{CACerts: []string{"manifest.pem"}},
+		},
+	}
+	assert.Equal(t, []string{"manifest.pem", "flag.pem"}, rd.getCACertPaths(&Options{ExtraCACerts: []string{"flag.pem"}}))
+
+	rd = remoteDestroyCommand{}
+	assert.Empty(t, rd.getCACertPaths(&Options{}))
+}
+
+// recordingBuilder keeps the options of the last build
+type recordingBuilder struct {
+	opts *types.BuildOptions
+}
+
+func (b *recordingBuilder) Build(_ context.Context, opts *types.BuildOptions) error {
+	b.opts = opts
+	return nil
+}
+
+func (*recordingBuilder) IsV1() bool { return true }
+
+func TestRemoteDestroyWithExtraCACerts(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	ca1 := newTestCACert(t, "ca1")
+	ca2 := newTestCACert(t, "ca2")
+
+	t.Run("certificates are passed to the build", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, filepath.FromSlash("/ca1.pem"), ca1, 0600))
+		require.NoError(t, afero.WriteFile(fs, filepath.FromSlash("/ca2.pem"), ca2, 0600))
+		b := &recordingBuilder{}
+		rdc := remoteDestroyCommand{
+			builder:              b,
+			fs:                   fs,
+			workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+			temporalCtrl:         filesystem.NewTemporalDirectoryCtrl(fs),
+			registry:             newFakeRegistry(),
+			manifest: &model.Manifest{
+				Destroy: &model.DestroyInfo{CACerts: []string{"ca1.pem"}},
+			},
+			clusterMetadata: func(ctx context.Context) (*types.ClusterMetadata, error) {
+				return &types.ClusterMetadata{Certificate: []byte("cert"), PipelineInstallerImage: "okteto/installer:1.0", PipelineRunnerImage: "okteto/runner:1.0"}, nil
+			},
+		}
+
+		require.NoError(t, rdc.destroy(context.Background(), &Options{ExtraCACerts: []string{"ca2.pem"}}))
+
+		expected := extraCACertsArg + "=" + base64.StdEncoding.EncodeToString(append(append([]byte{}, ca1...), ca2...))
+		assert.Contains(t, b.opts.BuildArgs, expected)
+	})
+
+	t.Run("missing certificate fails before reaching the cluster", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		rdc := remoteDestroyCommand{
+			builder:              &recordingBuilder{},
+			fs:                   fs,
+			workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+			temporalCtrl:         filesystem.NewTemporalDirectoryCtrl(fs),
+			clusterMetadata: func(ctx context.Context) (*types.ClusterMetadata, error) {
+				t.Fatal("cluster metadata must not be fetched")
+				return nil, nil
+			},
+		}
+
+		err := rdc.destroy(context.Background(), &Options{ExtraCACerts: []string{"missing.pem"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing.pem")
+	})
+}
+
+func TestCreateDockerfileWithExtraCACerts(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	fs := afero.NewMemMapFs()
+	rdc := remoteDestroyCommand{
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+	}
+
+	dockerfileName, err := rdc.createDockerfile("/test", &Options{Name: "test"}, "installer")
+	require.NoError(t, err)
+	content, err := afero.ReadFile(fs, dockerfileName)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), extraCACertsArg)
+
+	dockerfileName, err = rdc.createDockerfile("/test", &Options{Name: "test", ExtraCACerts: []string{"ca.pem"}}, "installer")
+	require.NoError(t, err)
+	content, err = afero.ReadFile(fs, dockerfileName)
+	require.NoError(t, err)
+	dockerfile := string(content)
+	assert.Contains(t, dockerfile, "ARG "+extraCACertsArg+"\n")
+	assert.Contains(t, dockerfile, ">> /etc/ssl/certs/ca-certificates.crt")
+	assert.Less(t, strings.Index(dockerfile, "/etc/ssl/certs/okteto.crt"), strings.Index(dockerfile, "ARG "+extraCACertsArg))
+}
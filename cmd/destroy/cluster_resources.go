@@ -0,0 +1,65 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/okteto"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// destroyClusterResources destroys the cluster-scoped objects recorded by the deploys of the development
+// environment that are not recorded by any other development environment
+func (ld *localDestroyCommand) destroyClusterResources(ctx context.Context, cfg *apiv1.ConfigMap) error {
+	resources, err := pipeline.GetClusterResources(cfg)
+	if err != nil || len(resources) == 0 {
+		return err
+	}
+
+	c, _, err := ld.k8sClientProvider.Provide(okteto.Context().Cfg)
+	if err != nil {
+		return err
+	}
+	unreferenced, err := pipeline.GetUnreferencedClusterResources(ctx, cfg, c)
+	if err != nil {
+		// the resources could be used by other development environments, so they are kept
+		oktetoLog.Warning("The cluster resources of the development environment were not destroyed: %s", err)
+		return nil
+	}
+	if len(unreferenced) < len(resources) {
+		oktetoLog.Information("%d cluster resources are kept because other development environments use them", len(resources)-len(unreferenced))
+	}
+	if len(unreferenced) == 0 {
+		return nil
+	}
+
+	dc, err := ld.dynamicClientProvider()
+	if err != nil {
+		return err
+	}
+	for _, r := range unreferenced {
+		oktetoLog.Debugf("destroying cluster resource %s", r)
+		err := dc.Resource(r.GroupVersionResource()).Delete(ctx, r.Name, metav1.DeleteOptions{})
+		if err != nil && !oktetoErrors.IsNotFound(err) {
+			return fmt.Errorf("could not destroy %s: %w", r, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okteto/okteto/internal/test"
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var (
+	readerClusterRole = pipeline.ClusterResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles", Kind: "ClusterRole", Name: "reader"}
+	writerClusterRole = pipeline.ClusterResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles", Kind: "ClusterRole", Name: "writer"}
+	clusterRolesGVR   = schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}
+)
+
+func newEnvironmentConfigMap(t *testing.T, name, namespace string, resources ...pipeline.ClusterResource) *apiv1.ConfigMap {
+	t.Helper()
+	cmap := &apiv1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pipeline.TranslatePipelineName(name),
+			Namespace: namespace,
+			Labels:    map[string]string{model.GitDeployLabel: "true"},
+		},
+	}
+	c := fake.NewSimpleClientset(cmap)
+	require.NoError(t, pipeline.AddClusterResources(context.Background(), name, namespace, resources, c))
+	cmap, err := c.CoreV1().ConfigMaps(namespace).Get(context.Background(), cmap.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	return cmap
+}
+
+func newClusterRole(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "rbac.authorization.k8s.io/v1",
+			"kind":       "ClusterRole",
+			"metadata": map[string]interface{}{
+				"name": name,
+			},
+		},
+	}
+}
+
+func TestDestroyClusterResources(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Namespace: "test",
+			},
+		},
+		CurrentContext: "test",
+	}
+
+	var tests = []struct {
+		name      string
+		cmap      *apiv1.ConfigMap
+		others    []runtime.Object
+		remaining []string
+	}{
+		{
+			name:      "no cluster resources",
+			cmap:      newEnvironmentConfigMap(t, "movies", "test"),
+			remaining: []string{"reader", "writer"},
+		},
+		{
+			name:      "cluster resources not used by other environments",
+			cmap:      newEnvironmentConfigMap(t, "movies", "test", readerClusterRole, writerClusterRole),
+			remaining: []string{},
+		},
+		{
+			name: "cluster resource used by another environment",
+			cmap: newEnvironmentConfigMap(t, "movies", "test", readerClusterRole, writerClusterRole),
+			others: []runtime.Object{
+				newEnvironmentConfigMap(t, "api", "other", writerClusterRole),
+			},
+			remaining: []string{"writer"},
+		},
+		{
+			name: "cluster resource already deleted",
+			cmap: newEnvironmentConfigMap(t, "movies", "test", readerClusterRole, pipeline.ClusterResource{
+				Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles", Name: "deleted",
+			}),
+			remaining: []string{"writer"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dc := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+				clusterRolesGVR: "ClusterRoleList",
+			}, newClusterRole("reader"), newClusterRole("writer"))
+			ld := &localDestroyCommand{
+				localDestroyAllCommand: &localDestroyAllCommand{
+					k8sClientProvider: test.NewFakeK8sProvider(append(tt.others, tt.cmap)...),
+					dynamicClientProvider: func() (dynamic.Interface, error) {
+						return dc, nil
+					},
+				},
+			}
+
+			require.NoError(t, ld.destroyClusterResources(context.Background(), tt.cmap))
+
+			list, err := dc.Resource(clusterRolesGVR).List(context.Background(), metav1.ListOptions{})
+			require.NoError(t, err)
+			remaining := []string{}
+			for _, item := range list.Items {
+				remaining = append(remaining, item.GetName())
+			}
+			assert.ElementsMatch(t, tt.remaining, remaining)
+		})
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	contextCMD "github.com/okteto/okteto/cmd/context"
@@ -35,6 +36,8 @@ import (
 	"github.com/okteto/okteto/pkg/model"
 	"github.com/okteto/okteto/pkg/okteto"
 	oktetoPath "github.com/okteto/okteto/pkg/path"
+	"github.com/okteto/okteto/pkg/types"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	v1 "k8s.io/api/core/v1"
 )
@@ -62,8 +65,10 @@ type Options struct {
 	ManifestPathFlag string
 	// ManifestPath is the path to the manifest used though the command execution.
 	// This might change its value during execution
-	ManifestPath        string
-	Name                string
+	ManifestPath string
+	Name         string
+	// LabelSelector selects the development environments to destroy when no name is given
+	LabelSelector       string
 	Variables           []string
 	Namespace           string
 	DestroyVolumes      bool
@@ -73,6 +78,66 @@ type Options struct {
 	RunWithoutBash      bool
 	DestroyAll          bool
 	RunInRemote         bool
+	// RunLocal runs the destroy commands locally even if OKTETO_FORCE_REMOTE or the manifest run them in remote
+	RunLocal bool
+	// RemoteRunImage is the image used to run the destroy commands in remote. It takes priority over the manifest and the cluster default
+	RemoteRunImage string
+	// RemoteContext is the folder shipped as build context of the remote destroy. It takes priority over the manifest
+	RemoteContext string
+	// IgnoreNotFound makes remote destroy succeed when the development environment doesn't exist
+	IgnoreNotFound bool
+	// NoCache forces the remote destroy to invalidate the cache of all the layers
+	NoCache bool
+	// SkipPreflight skips the permission checks done before destroying the development environment
+	SkipPreflight bool
+	// DryRun prints the dockerfile used to destroy in remote instead of running it
+	DryRun bool
+	// LogLevel is the log level forwarded to the okteto destroy running in remote
+	LogLevel string
+	// RemoteLogOutput is the log output of the okteto destroy running in remote: json or plain
+	RemoteLogOutput string
+	// LocalBuild runs the remote destroy dockerfile with the local docker daemon instead of the okteto builder
+	LocalBuild bool
+	// Timeout is how long to wait for the destroy commands run in remote, zero means no timeout
+	Timeout time.Duration
+	// ExtraCACerts are paths to PEM files with CA certificates trusted by the remote destroy image
+	ExtraCACerts []string
+	// BuildRetries is how many times the remote destroy build is retried after a transient builder or registry failure
+	BuildRetries int
+	// Platform is the platform (os/arch[/variant]) used to build the remote destroy image
+	Platform string
+	// ForwardProxy sets the local HTTP_PROXY, HTTPS_PROXY and NO_PROXY variables in the remote destroy
+	ForwardProxy bool
+	// InsecureSkipTLSVerify skips the verification of the okteto server certificate in the remote destroy
+	InsecureSkipTLSVerify bool
+	// PreferImageCLI uses the okteto binary of the destroy image instead of copying the one of the okteto CLI image
+	PreferImageCLI bool
+	// ImpersonateUser is the kubernetes user impersonated by the remote destroy
+	ImpersonateUser string
+	// ImpersonateGroup is the kubernetes group impersonated by the remote destroy
+	ImpersonateGroup string
+	// BuildArgs are KEY=VALUE build args of the remote destroy image, also exposed as env vars to the destroy commands
+	BuildArgs []string
+	// Output prints a result document to stdout when set to json. Logs are written to stderr instead
+	Output string
+	// OutputFile is the path where a DestroyArtifact is written when the destroy succeeds
+	OutputFile string
+	// RequireClean fails the remote destroy when the working tree has uncommitted changes
+	RequireClean bool
+	// Reason is why the destroy was triggered. It is exposed to the destroy commands as OKTETO_DESTROY_REASON
+	Reason string
+	// ClusterMetadataOverride is used by the remote destroy instead of fetching the cluster metadata from the okteto API
+	ClusterMetadataOverride *types.ClusterMetadata
+
+	// Contexts is a comma separated list of contexts where the destroy runs, one after the other
+	Contexts string
+	// ParallelContexts runs the destroy of every context of Contexts at the same time
+	ParallelContexts bool
+
+	// result collects the result document printed when Output is json
+	result *resultRecorder
+	// dirtyWorkingTree is set by the remote destroy when the working tree has uncommitted changes
+	dirtyWorkingTree bool
 }
 
 type destroyInterface interface {
@@ -99,106 +164,72 @@ func Destroy(ctx context.Context) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "destroy",
 		Short: `Destroy everything created by the 'okteto deploy' command`,
-		Long:  `Destroy everything created by the 'okteto deploy' command. You can also include a 'destroy' section in your okteto manifest with a list of custom commands to be executed on destroy`,
-		Args:  utils.NoArgsAccepted("https://okteto.com/docs/reference/cli/#destroy"),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if options.ManifestPath != "" {
-				// if path is absolute, its transformed to rel from root
-				initialCWD, err := os.Getwd()
-				if err != nil {
-					return fmt.Errorf("failed to get the current working directory: %w", err)
-				}
-				manifestPathFlag, err := oktetoPath.GetRelativePathFromCWD(initialCWD, options.ManifestPath)
-				if err != nil {
-					return err
-				}
-				// as the installer uses root for executing the pipeline, we save the rel path from root as ManifestPathFlag option
-				options.ManifestPathFlag = manifestPathFlag
+		Long: `Destroy everything created by the 'okteto deploy' command. You can also include a 'destroy' section in your okteto manifest with a list of custom commands to be executed on destroy.
 
-				// when the manifest path is set by the cmd flag, we are moving cwd so the cmd is executed from that dir
-				uptManifestPath, err := model.UpdateCWDtoManifestPath(options.ManifestPath)
-				if err != nil {
-					return err
-				}
-				options.ManifestPath = uptManifestPath
-			}
-			if err := contextCMD.LoadContextFromPath(ctx, options.Namespace, options.K8sContext, options.ManifestPath); err != nil {
-				if err.Error() == fmt.Errorf(oktetoErrors.ErrNotLogged, okteto.CloudURL).Error() {
-					return err
-				}
-				if err := contextCMD.NewContextCommand().Run(ctx, &contextCMD.ContextOptions{Namespace: options.Namespace}); err != nil {
-					return err
+` + utils.RunModePrecedenceHelp,
+		Args: utils.NoArgsAccepted("https://okteto.com/docs/reference/cli/#destroy"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if options.Output != "" && options.Output != jsonOutput {
+				return oktetoErrors.UserError{
+					E:    fmt.Errorf("invalid value '%s' for flag '--output'", options.Output),
+					Hint: "Accepted value is 'json'",
 				}
 			}
-
-			cwd, err := os.Getwd()
-			if err != nil {
-				return fmt.Errorf("failed to get the current working directory: %w", err)
+			if err := utils.ValidateContextsFlags("destroy", options.Contexts, options.K8sContext, options.ParallelContexts); err != nil {
+				return err
 			}
-
-			if options.Name == "" {
-				c, _, err := okteto.NewK8sClientProvider().Provide(okteto.Context().Cfg)
-				if err != nil {
-					return err
+			if options.Contexts != "" {
+				if options.Output != "" {
+					return oktetoErrors.UserError{
+						E:    fmt.Errorf("flags '--output' and '--%s' can't be used together", utils.ContextsFlag),
+						Hint: "Run 'okteto destroy --output json' in each context",
+					}
 				}
-				inferer := devenvironment.NewNameInferer(c)
-				options.Name = inferer.InferName(ctx, cwd, okteto.Context().Namespace, options.ManifestPathFlag)
-				if err != nil {
-					return fmt.Errorf("could not infer environment name")
+				if options.OutputFile != "" {
+					return oktetoErrors.UserError{
+						E:    fmt.Errorf("flags '--output-file' and '--%s' can't be used together", utils.ContextsFlag),
+						Hint: "Run 'okteto destroy --output-file <path>' in each context",
+					}
 				}
+				return utils.RunCommandInContexts(ctx, cmd, args, options.Variables, options.Contexts, options.ParallelContexts)
 			}
-
-			dynClient, _, err := okteto.GetDynamicClient()
-			if err != nil {
-				return err
-			}
-			discClient, _, err := okteto.GetDiscoveryClient()
-			if err != nil {
-				return err
-			}
-			k8sClient, cfg, err := okteto.GetK8sClient()
-			if err != nil {
-				return err
-			}
-
-			if options.Namespace == "" {
-				options.Namespace = okteto.Context().Namespace
+			if options.Output != jsonOutput && options.OutputFile == "" {
+				return run(ctx, cmd, options)
 			}
 
-			var okClient = &okteto.OktetoClient{}
-			if okteto.Context().IsOkteto {
-				okClient, err = okteto.NewOktetoClient()
+			if options.OutputFile != "" {
+				// destroy changes the working directory to the folder of the manifest
+				outputFile, err := filepath.Abs(options.OutputFile)
 				if err != nil {
-					return err
+					return fmt.Errorf("failed to resolve the output file '%s': %w", options.OutputFile, err)
 				}
+				options.OutputFile = outputFile
 			}
-
-			c := &destroyCommand{
-				executor:          executor.NewExecutor(oktetoLog.GetOutputFormat(), options.RunWithoutBash, ""),
-				ConfigMapHandler:  NewConfigmapHandler(k8sClient),
-				nsDestroyer:       namespaces.NewNamespace(dynClient, discClient, cfg, k8sClient),
-				secrets:           secrets.NewSecrets(k8sClient),
-				k8sClientProvider: okteto.NewK8sClientProvider(),
-				oktetoClient:      okClient,
-				buildCtrl:         newBuildCtrl(options.Name),
+			if options.Output == jsonOutput {
+				// stdout is reserved for the result document
+				oktetoLog.SetOutput(os.Stderr)
+				oktetoLog.RecordWarnings()
 			}
-
-			kubeconfigPath := getTempKubeConfigFile(options.Name)
-			if err := kubeconfig.Write(okteto.Context().Cfg, kubeconfigPath); err != nil {
-				return err
+			options.result = newResultRecorder(time.Now)
+			err := run(ctx, cmd, options)
+			if options.Output == jsonOutput {
+				if errWrite := options.result.write(os.Stdout, options, err); errWrite != nil {
+					oktetoLog.Infof("%s", errWrite)
+				}
 			}
-			os.Setenv("KUBECONFIG", kubeconfigPath)
-			defer os.Remove(kubeconfigPath)
-
-			destroyer, err := c.getDestroyer(ctx, options)
-			if err != nil {
+			if err != nil || options.OutputFile == "" {
 				return err
 			}
-
-			return destroyer.destroy(ctx, options)
+			return options.result.writeArtifact(afero.NewOsFs(), options.OutputFile)
 		},
 	}
 
+	addFlags(cmd, options)
+	return cmd
+}
+
+// addFlags binds the flags of the okteto destroy command to the options
+func addFlags(cmd *cobra.Command, options *Options) {
 	cmd.Flags().StringVar(&options.Name, "name", "", "development environment name")
 	cmd.Flags().StringVarP(&options.ManifestPath, "file", "f", "", "path to the manifest file")
 	cmd.Flags().BoolVarP(&options.DestroyVolumes, "volumes", "v", false, "remove persistent volumes")
@@ -209,8 +240,242 @@ func Destroy(ctx context.Context) *cobra.Command {
 	cmd.Flags().BoolVarP(&options.RunWithoutBash, "no-bash", "", false, "execute commands without bash")
 	cmd.Flags().BoolVarP(&options.DestroyAll, "all", "", false, "destroy everything in the namespace")
 	cmd.Flags().BoolVarP(&options.RunInRemote, "remote", "", false, "force run destroy commands in remote")
+	cmd.Flags().BoolVarP(&options.RunLocal, "local", "", false, "force run destroy commands locally, overriding OKTETO_FORCE_REMOTE and the manifest 'destroy.image'")
+	cmd.Flags().StringVarP(&options.RemoteRunImage, "remote-run-image", "", "", "image used to run the destroy commands in remote (overrides the manifest 'destroy.image')")
+	cmd.Flags().StringVarP(&options.RemoteContext, "remote-context", "", "", "folder shipped to destroy in remote, it must contain the okteto manifest (overrides the manifest 'destroy.context', defaults to the working directory)")
+	cmd.Flags().BoolVarP(&options.IgnoreNotFound, "ignore-not-found", "", false, "do not fail if the development environment doesn't exist")
+	cmd.Flags().BoolVarP(&options.NoCache, "no-cache", "", false, "do not use the cache when running the destroy commands in remote")
+	cmd.Flags().StringVar(&options.LabelSelector, "label", "", "destroy all the development environments matching the label (key=value) when no name is given")
+	cmd.Flags().StringVarP(&options.RemoteLogOutput, "remote-log-output", "", oktetoLog.JSONFormat, "log output of the destroy commands run in remote (json, plain). Use 'plain' to troubleshoot the remote execution")
+	cmd.Flags().BoolVarP(&options.DryRun, "dry-run", "", false, "print the dockerfile used to destroy in remote, with the okteto token redacted, instead of running it")
+	cmd.Flags().BoolVarP(&options.LocalBuild, "local-build", "", false, "run the dockerfile used to destroy in remote with your local docker instead of the okteto builder, for debugging")
+	cmd.Flags().DurationVarP(&options.Timeout, "timeout", "", 0, "the length of time to wait for the destroy commands run in remote, zero means never. Any other values should contain a corresponding time unit e.g. 1s, 2m, 3h")
+	cmd.Flags().BoolVarP(&options.SkipPreflight, "skip-preflight", "", false, "skip checking the permissions needed to destroy the development environment")
+	cmd.Flags().StringArrayVarP(&options.Variables, "var", "", []string{}, "set a variable (can be set more than once). Use 'ctx:KEY=VALUE' to set it only in one of '--contexts'")
+	cmd.Flags().StringVarP(&options.Contexts, utils.ContextsFlag, "", "", "comma separated list of contexts where the development environment is destroyed, one after the other")
+	cmd.Flags().BoolVarP(&options.ParallelContexts, utils.ParallelContextsFlag, "", false, "destroy in every context of '--contexts' at the same time")
+	cmd.Flags().IntVarP(&options.BuildRetries, "build-retries", "", defaultBuildRetries, "number of times the destroy in remote is retried after a transient failure of the builder or the registry")
+	cmd.Flags().StringVarP(&options.Platform, "platform", "", "", "platform (os/arch[/variant]) used to build the image that destroys in remote, overrides the manifest 'destroy.platform'")
+	cmd.Flags().BoolVarP(&options.InsecureSkipTLSVerify, "insecure-skip-tls-verify", "", false, "skip the verification of the okteto server certificate in the destroy run in remote. This will make its connections insecure")
+	cmd.Flags().BoolVarP(&options.PreferImageCLI, "prefer-image-cli", "", false, "use the okteto binary of the destroy image in the destroy run in remote instead of copying the one matching your okteto version")
+	cmd.Flags().StringVarP(&options.ImpersonateUser, "impersonate-user", "", "", "kubernetes user impersonated by the destroy run in remote")
+	cmd.Flags().StringVarP(&options.ImpersonateGroup, "impersonate-group", "", "", "kubernetes group impersonated by the destroy run in remote")
+	cmd.Flags().BoolVarP(&options.RequireClean, utils.RequireCleanFlag, "", false, "fail the destroy run in remote if the working tree has uncommitted changes, instead of warning")
+	cmd.Flags().StringArrayVarP(&options.BuildArgs, "build-arg", "", []string{}, "set a build arg of the image that destroys in remote, also exposed as an env var to the destroy commands (can be set more than once)")
+	cmd.Flags().BoolVarP(&options.ForwardProxy, "forward-proxy", "", false, "forward the local HTTP_PROXY, HTTPS_PROXY and NO_PROXY variables to the destroy run in remote. They might contain credentials")
+	cmd.Flags().StringVarP(&options.Reason, "reason", "", userReason, "why the destroy was triggered, exposed to the destroy commands as OKTETO_DESTROY_REASON (user, ttl, ci, preview-closed)")
+	cmd.Flags().StringVarP(&options.Output, "output", "o", "", "print a json document with the result of the destroy to stdout and the logs to stderr (json)")
+	cmd.Flags().StringVarP(&options.OutputFile, "output-file", "", "", "write a json document with the resources deleted and the duration of the destroy to this path when it succeeds")
+	cmd.Flags().StringArrayVarP(&options.ExtraCACerts, "extra-ca-cert", "", []string{}, "path to a PEM file with CA certificates trusted when destroying in remote (can be set more than once)")
+}
 
-	return cmd
+func run(ctx context.Context, cmd *cobra.Command, options *Options) error {
+	if err := setVariablesAsEnvs(options.Variables, os.Setenv); err != nil {
+		return err
+	}
+
+	if options.RemoteRunImage != "" {
+		if err := utils.ValidateImageReference("remote-run-image", options.RemoteRunImage); err != nil {
+			return err
+		}
+	}
+
+	if err := utils.ValidateRunModeFlags("destroy", options.RunInRemote, options.RunLocal); err != nil {
+		return err
+	}
+
+	if options.RemoteLogOutput != oktetoLog.JSONFormat && options.RemoteLogOutput != oktetoLog.PlainFormat {
+		return oktetoErrors.UserError{
+			E:    fmt.Errorf("invalid value '%s' for flag '--remote-log-output'", options.RemoteLogOutput),
+			Hint: "Accepted values are 'json' and 'plain'",
+		}
+	}
+
+	// the log level is only forwarded to the remote destroy when it is explicitly set
+	if cmd.Flags().Changed("log-level") {
+		options.LogLevel = oktetoLog.GetLevel()
+	}
+
+	if options.Platform != "" {
+		if err := validatePlatform(options.Platform); err != nil {
+			return err
+		}
+	}
+
+	if options.Reason == "" {
+		options.Reason = userReason
+	}
+	if err := validateReason(options.Reason); err != nil {
+		return err
+	}
+
+	if options.DryRun && !options.RunInRemote {
+		return oktetoErrors.UserError{
+			E:    fmt.Errorf("flag '--dry-run' can only be used with '--remote'"),
+			Hint: "Run 'okteto destroy --remote --dry-run' to print the dockerfile used to destroy in remote",
+		}
+	}
+
+	if options.LocalBuild && !options.RunInRemote {
+		return oktetoErrors.UserError{
+			E:    fmt.Errorf("flag '--local-build' can only be used with '--remote'"),
+			Hint: "Run 'okteto destroy --remote --local-build' to run the remote destroy with your local docker",
+		}
+	}
+
+	if (options.ImpersonateUser != "" || options.ImpersonateGroup != "") && !options.RunInRemote {
+		return oktetoErrors.UserError{
+			E:    fmt.Errorf("flags '--impersonate-user' and '--impersonate-group' can only be used with '--remote'"),
+			Hint: "Run 'okteto destroy --remote --impersonate-user <user>' to destroy in remote as a different kubernetes user",
+		}
+	}
+
+	if len(options.BuildArgs) > 0 {
+		if !options.RunInRemote {
+			return oktetoErrors.UserError{
+				E:    fmt.Errorf("flag '--build-arg' can only be used with '--remote'"),
+				Hint: "Run 'okteto destroy --remote --build-arg KEY=VALUE' to set a build arg of the remote destroy",
+			}
+		}
+		if _, err := parseBuildArgs(options.BuildArgs); err != nil {
+			return err
+		}
+	}
+
+	if options.RemoteContext != "" {
+		// the build context is relative to where the command runs, before moving to the folder of the manifest
+		remoteContext, err := filepath.Abs(options.RemoteContext)
+		if err != nil {
+			return fmt.Errorf("failed to resolve the remote context '%s': %w", options.RemoteContext, err)
+		}
+		options.RemoteContext = remoteContext
+	}
+
+	if options.ManifestPath != "" {
+		// if path is absolute, its transformed to rel from root
+		initialCWD, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get the current working directory: %w", err)
+		}
+		manifestPathFlag, err := oktetoPath.GetRelativePathFromCWD(initialCWD, options.ManifestPath)
+		if err != nil {
+			return err
+		}
+		// as the installer uses root for executing the pipeline, we save the rel path from root as ManifestPathFlag option
+		options.ManifestPathFlag = manifestPathFlag
+
+		// when the manifest path is set by the cmd flag, we are moving cwd so the cmd is executed from that dir
+		uptManifestPath, err := model.UpdateCWDtoManifestPath(options.ManifestPath)
+		if err != nil {
+			return err
+		}
+		options.ManifestPath = uptManifestPath
+	}
+	if err := contextCMD.LoadContextFromPath(ctx, options.Namespace, options.K8sContext, options.ManifestPath); err != nil {
+		if err.Error() == fmt.Errorf(oktetoErrors.ErrNotLogged, okteto.CloudURL).Error() {
+			return err
+		}
+		if err := contextCMD.NewContextCommand().Run(ctx, &contextCMD.ContextOptions{Namespace: options.Namespace}); err != nil {
+			return err
+		}
+	}
+
+	// the remote destroy was already checked by the outer destroy
+	if okteto.IsOkteto() && !utils.LoadBoolean(constants.OKtetoDeployRemote) {
+		if _, err := utils.CheckTokenPermissions(ctx, okteto.NewOktetoClientProvider(), "destroy", okteto.Context().Namespace, options.DryRun); err != nil {
+			return err
+		}
+	}
+
+	// the remote destroy forwards the impersonation flags as build args, available here as env vars
+	if err := applyImpersonationFromEnv(okteto.Context().Cfg, os.LookupEnv); err != nil {
+		return err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get the current working directory: %w", err)
+	}
+
+	if options.Name == "" && options.LabelSelector == "" {
+		c, _, err := okteto.NewK8sClientProvider().Provide(okteto.Context().Cfg)
+		if err != nil {
+			return err
+		}
+		inferer := devenvironment.NewNameInferer(c)
+		options.Name = inferer.InferName(ctx, cwd, okteto.Context().Namespace, options.ManifestPathFlag)
+		if err != nil {
+			return fmt.Errorf("could not infer environment name")
+		}
+	}
+
+	dynClient, _, err := okteto.GetDynamicClient()
+	if err != nil {
+		return err
+	}
+	discClient, _, err := okteto.GetDiscoveryClient()
+	if err != nil {
+		return err
+	}
+	k8sClient, cfg, err := okteto.GetK8sClient()
+	if err != nil {
+		return err
+	}
+
+	if options.Namespace == "" {
+		options.Namespace = okteto.Context().Namespace
+	}
+
+	var okClient = &okteto.OktetoClient{}
+	if okteto.Context().IsOkteto {
+		okClient, err = okteto.NewOktetoClient()
+		if err != nil {
+			return err
+		}
+	}
+
+	c := &destroyCommand{
+		executor:          executor.NewExecutor(oktetoLog.GetOutputFormat(), options.RunWithoutBash, ""),
+		ConfigMapHandler:  NewConfigmapHandler(k8sClient),
+		nsDestroyer:       namespaces.NewNamespace(dynClient, discClient, cfg, k8sClient),
+		secrets:           secrets.NewSecrets(k8sClient),
+		k8sClientProvider: okteto.NewK8sClientProvider(),
+		oktetoClient:      okClient,
+		buildCtrl:         newBuildCtrl(options.Name),
+	}
+
+	kubeconfigPath := getTempKubeConfigFile(options.Name)
+	if err := kubeconfig.Write(okteto.Context().Cfg, kubeconfigPath); err != nil {
+		return err
+	}
+	os.Setenv("KUBECONFIG", kubeconfigPath)
+	defer os.Remove(kubeconfigPath)
+
+	destroyer, err := c.getDestroyer(ctx, options)
+	if err != nil {
+		return err
+	}
+	if _, ok := destroyer.(*remoteDestroyCommand); ok {
+		options.result.setMode(remoteMode)
+	} else {
+		options.result.setMode(localMode)
+	}
+
+	return destroyer.destroy(ctx, options)
+}
+
+// setVariablesAsEnvs validates that the variables follow the KEY=VALUE format and sets them as environment variables
+func setVariablesAsEnvs(variables []string, setEnv func(key, value string) error) error {
+	for _, v := range variables {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("invalid variable value '%s': must follow KEY=VALUE format", v)
+		}
+		if err := setEnv(kv[0], kv[1]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func getTempKubeConfigFile(name string) string {
@@ -234,6 +499,16 @@ func (dc *destroyCommand) getDestroyer(ctx context.Context, opts *Options) (dest
 		}
 
 		oktetoLog.Info("Destroying all...")
+	} else if opts.Name == "" && opts.LabelSelector != "" {
+		if !okteto.Context().IsOkteto {
+			return nil, oktetoErrors.ErrContextIsNotOktetoCluster
+		}
+		deployer, err = newLabelDestroyer(dc.k8sClientProvider)
+		if err != nil {
+			return nil, err
+		}
+
+		oktetoLog.Info("Destroying by label...")
 	} else {
 		manifest, err := model.GetManifestV2(opts.ManifestPath)
 		if err != nil {
@@ -263,10 +538,17 @@ func (dc *destroyCommand) getDestroyer(ctx context.Context, opts *Options) (dest
 				return nil, err
 			}
 		}
-		runInRemote := !isRemote && (destroyImage != "" || opts.RunInRemote)
+		if opts.RemoteRunImage != "" {
+			destroyImage = opts.RemoteRunImage
+		}
+		runInRemote := !isRemote && utils.ShouldRunInRemote(opts.RunInRemote, opts.RunLocal, destroyImage, os.LookupEnv)
 
 		if runInRemote {
-			deployer = newRemoteDestroyer(manifest)
+			rd := newRemoteDestroyer(manifest, destroyImage)
+			if opts.LocalBuild {
+				rd.builder = newLocalDockerBuilder(rd.fs)
+			}
+			deployer = rd
 			oktetoLog.Info("Destroying remotely...")
 		} else {
 			destroyerAll, err := newLocalDestroyerAll(dc.k8sClientProvider, dc.executor, dc.nsDestroyer, dc.oktetoClient)
@@ -274,6 +556,16 @@ func (dc *destroyCommand) getDestroyer(ctx context.Context, opts *Options) (dest
 				return nil, err
 			}
 
+			if !opts.SkipPreflight {
+				c, _, err := dc.k8sClientProvider.Provide(okteto.Context().Cfg)
+				if err != nil {
+					return nil, err
+				}
+				if err := newPreflightChecker(c).check(ctx, opts); err != nil {
+					return nil, err
+				}
+			}
+
 			deployer = newLocalDestroyer(manifest, destroyerAll)
 			oktetoLog.Info("Destroying locally...")
 		}
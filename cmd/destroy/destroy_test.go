@@ -67,6 +67,7 @@ type fakeSecretHandler struct {
 type fakeExecutor struct {
 	err      error
 	executed []model.DeployCommand
+	envs     [][]string
 }
 
 func (fd *fakeDestroyer) DestroyWithLabel(_ context.Context, _ string, _ namespaces.DeleteAllOptions) error {
@@ -95,8 +96,9 @@ func (fd *fakeSecretHandler) List(_ context.Context, _, _ string) ([]v1.Secret,
 	return fd.secrets, nil
 }
 
-func (fe *fakeExecutor) Execute(command model.DeployCommand, _ []string) error {
+func (fe *fakeExecutor) Execute(command model.DeployCommand, envs []string) error {
 	fe.executed = append(fe.executed, command)
+	fe.envs = append(fe.envs, envs)
 	if fe.err != nil {
 		return fe.err
 	}
@@ -978,3 +980,39 @@ func TestDestroyWithForceOptionAndFailedCommands(t *testing.T) {
 	cfg, _ := configmaps.Get(ctx, pipeline.TranslatePipelineName(opts.Name), okteto.Context().Namespace, fakeClient)
 	assert.Nil(t, cfg)
 }
+
+func TestSetVariablesAsEnvs(t *testing.T) {
+	tests := []struct {
+		name      string
+		variables []string
+		expected  map[string]string
+		expectErr bool
+	}{
+		{
+			name:      "valid variables",
+			variables: []string{"A=b", "B=c d", "C=d=e"},
+			expected:  map[string]string{"A": "b", "B": "c d", "C": "d=e"},
+		},
+		{
+			name:      "invalid variable",
+			variables: []string{"A"},
+			expected:  map[string]string{},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envs := map[string]string{}
+			err := setVariablesAsEnvs(tt.variables, func(k, v string) error {
+				envs[k] = v
+				return nil
+			})
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, envs)
+		})
+	}
+}
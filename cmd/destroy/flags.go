@@ -0,0 +1,95 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/okteto/okteto/cmd/utils"
+)
+
+// ToFlags returns the flags of the okteto destroy command that reproduce the options, to forward them to a
+// sub-process or log them. Each flag is returned as a single '--flag value' item with the value shell quoted,
+// like the flags of the remote destroy. Unset options are omitted
+func (o *Options) ToFlags() []string {
+	var flags []string
+
+	addString := func(name, value string) {
+		if value != "" {
+			flags = append(flags, formatShellFlag(name, value))
+		}
+	}
+	addBool := func(name string, value bool) {
+		if value {
+			flags = append(flags, fmt.Sprintf("--%s", name))
+		}
+	}
+
+	addString("name", o.Name)
+	addString("namespace", o.Namespace)
+	addString("context", o.K8sContext)
+	manifestPath := o.ManifestPathFlag
+	if manifestPath == "" {
+		manifestPath = o.ManifestPath
+	}
+	addString("file", manifestPath)
+	addString("label", o.LabelSelector)
+	for _, v := range o.Variables {
+		addString("var", v)
+	}
+	addBool("volumes", o.DestroyVolumes)
+	addBool("dependencies", o.DestroyDependencies)
+	addBool("force-destroy", o.ForceDestroy)
+	addBool("no-bash", o.RunWithoutBash)
+	addBool("all", o.DestroyAll)
+	addBool("remote", o.RunInRemote)
+	addBool("local", o.RunLocal)
+	addString("remote-run-image", o.RemoteRunImage)
+	addString("remote-context", o.RemoteContext)
+	addBool("ignore-not-found", o.IgnoreNotFound)
+	addBool("no-cache", o.NoCache)
+	addBool("skip-preflight", o.SkipPreflight)
+	addBool("dry-run", o.DryRun)
+	addString("log-level", o.LogLevel)
+	addString("remote-log-output", o.RemoteLogOutput)
+	addBool("local-build", o.LocalBuild)
+	if o.Timeout != 0 {
+		addString("timeout", o.Timeout.String())
+	}
+	for _, path := range o.ExtraCACerts {
+		addString("extra-ca-cert", path)
+	}
+	// zero retries is a valid value, so it is only omitted when it is the default
+	if o.BuildRetries != defaultBuildRetries {
+		addString("build-retries", strconv.Itoa(o.BuildRetries))
+	}
+	addString("platform", o.Platform)
+	addBool("forward-proxy", o.ForwardProxy)
+	addBool("insecure-skip-tls-verify", o.InsecureSkipTLSVerify)
+	addString("impersonate-user", o.ImpersonateUser)
+	addString("impersonate-group", o.ImpersonateGroup)
+	addString(utils.ContextsFlag, o.Contexts)
+	addBool(utils.ParallelContextsFlag, o.ParallelContexts)
+	addBool("prefer-image-cli", o.PreferImageCLI)
+	for _, arg := range o.BuildArgs {
+		addString("build-arg", arg)
+	}
+	addBool(utils.RequireCleanFlag, o.RequireClean)
+	addString("output", o.Output)
+	addString("output-file", o.OutputFile)
+	addString("reason", o.Reason)
+
+	return flags
+}
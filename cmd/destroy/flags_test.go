@@ -0,0 +1,229 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kballard/go-shellquote"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionsToFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     *Options
+		expected []string
+	}{
+		{
+			name:     "default options",
+			opts:     &Options{BuildRetries: defaultBuildRetries},
+			expected: nil,
+		},
+		{
+			name: "all the options",
+			opts: &Options{
+				Name:                "movies",
+				Namespace:           "test",
+				K8sContext:          "https://okteto.example.com",
+				ManifestPathFlag:    "okteto.yml",
+				ManifestPath:        "/app/okteto.yml",
+				LabelSelector:       "team=a",
+				Variables:           []string{"A=1", "B=with space"},
+				DestroyVolumes:      true,
+				DestroyDependencies: true,
+				ForceDestroy:        true,
+				RunWithoutBash:      true,
+				DestroyAll:          true,
+				RunInRemote:         true,
+				RunLocal:            true,
+				RemoteRunImage:      "okteto/destroy:1",
+				RemoteContext:       "/app/services",
+				IgnoreNotFound:      true,
+				NoCache:             true,
+				SkipPreflight:       true,
+				DryRun:              true,
+				LogLevel:            "debug",
+				RemoteLogOutput:     "plain",
+				LocalBuild:          true,
+				Timeout:             5 * time.Minute,
+				ExtraCACerts:        []string{"ca.pem"},
+				BuildRetries:        0,
+				Platform:            "linux/arm64",
+				ForwardProxy:        true,
+				ImpersonateUser:     "jane",
+				ImpersonateGroup:    "developers",
+				Contexts:            "dev,staging",
+				ParallelContexts:    true,
+				PreferImageCLI:      true,
+				BuildArgs:           []string{"VERSION=1.0", "MESSAGE=hello world"},
+				RequireClean:        true,
+				Output:              "json",
+				OutputFile:          "destroy.json",
+				Reason:              ciReason,
+			},
+			expected: []string{
+				"--name movies",
+				"--namespace test",
+				"--context https://okteto.example.com",
+				"--file okteto.yml",
+				"--label team=a",
+				"--var A=1",
+				"--var 'B=with space'",
+				"--volumes",
+				"--dependencies",
+				"--force-destroy",
+				"--no-bash",
+				"--all",
+				"--remote",
+				"--local",
+				"--remote-run-image okteto/destroy:1",
+				"--remote-context /app/services",
+				"--ignore-not-found",
+				"--no-cache",
+				"--skip-preflight",
+				"--dry-run",
+				"--log-level debug",
+				"--remote-log-output plain",
+				"--local-build",
+				"--timeout 5m0s",
+				"--extra-ca-cert ca.pem",
+				"--build-retries 0",
+				"--platform linux/arm64",
+				"--forward-proxy",
+				"--impersonate-user jane",
+				"--impersonate-group developers",
+				"--contexts dev,staging",
+				"--parallel-contexts",
+				"--prefer-image-cli",
+				"--build-arg VERSION=1.0",
+				"--build-arg 'MESSAGE=hello world'",
+				"--require-clean",
+				"--output json",
+				"--output-file destroy.json",
+				"--reason ci",
+			},
+		},
+		{
+			name:     "values are shell quoted",
+			opts:     &Options{Name: "my app", Variables: []string{"A='$(whoami)'"}, BuildRetries: defaultBuildRetries},
+			expected: []string{"--name 'my app'", `--var 'A='"'"'$(whoami)'"'"''`},
+		},
+		{
+			name:     "insecure skip tls verify",
+			opts:     &Options{InsecureSkipTLSVerify: true, BuildRetries: defaultBuildRetries},
+			expected: []string{"--insecure-skip-tls-verify"},
+		},
+		{
+			name:     "manifest path without flag",
+			opts:     &Options{ManifestPath: "/app/okteto.yml", BuildRetries: defaultBuildRetries},
+			expected: []string{"--file /app/okteto.yml"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.opts.ToFlags())
+		})
+	}
+}
+
+func TestOptionsToFlagsRoundTrip(t *testing.T) {
+	opts := &Options{
+		Name:                  "my app",
+		Namespace:             "test",
+		K8sContext:            "https://okteto.example.com",
+		ManifestPath:          "/my app/okteto.yml",
+		LabelSelector:         "team=a",
+		Variables:             []string{"A=1", "B=with space", "C='$(whoami)'"},
+		DestroyVolumes:        true,
+		DestroyDependencies:   true,
+		ForceDestroy:          true,
+		RunWithoutBash:        true,
+		DestroyAll:            true,
+		RunInRemote:           true,
+		RunLocal:              true,
+		RemoteRunImage:        "okteto/destroy:1",
+		RemoteContext:         "/app/services",
+		IgnoreNotFound:        true,
+		NoCache:               true,
+		SkipPreflight:         true,
+		DryRun:                true,
+		LogLevel:              "debug",
+		RemoteLogOutput:       "plain",
+		LocalBuild:            true,
+		Timeout:               5 * time.Minute,
+		ExtraCACerts:          []string{"ca.pem"},
+		BuildRetries:          0,
+		Platform:              "linux/arm64",
+		ForwardProxy:          true,
+		InsecureSkipTLSVerify: true,
+		ImpersonateUser:       "jane",
+		ImpersonateGroup:      "developers",
+		Contexts:              "dev,staging",
+		ParallelContexts:      true,
+		PreferImageCLI:        true,
+		BuildArgs:             []string{"VERSION=1.0", "MESSAGE=hello world"},
+		RequireClean:          true,
+		Output:                "json",
+		OutputFile:            "destroy.json",
+		Reason:                ciReason,
+	}
+
+	args, err := shellquote.Split(strings.Join(opts.ToFlags(), " "))
+	require.NoError(t, err)
+
+	parsed := &Options{}
+	cmd := &cobra.Command{}
+	addFlags(cmd, parsed)
+	// log-level is a persistent flag of the root command
+	cmd.Flags().StringVar(&parsed.LogLevel, "log-level", "", "")
+	require.NoError(t, cmd.Flags().Parse(args))
+	assert.Equal(t, opts, parsed)
+}
+
+func TestDestroyFlagsRoundTrip(t *testing.T) {
+	opts := &Options{
+		Name:                "my app",
+		Namespace:           "test",
+		ManifestPathFlag:    "/my app/okteto.yml",
+		Variables:           []string{"A=b c"},
+		DestroyVolumes:      true,
+		DestroyDependencies: true,
+		ForceDestroy:        true,
+		Reason:              ttlReason,
+	}
+
+	args, err := shellquote.Split(strings.Join(getDestroyFlags(opts), " "))
+	require.NoError(t, err)
+
+	cmd := Destroy(context.Background())
+	require.NoError(t, cmd.Flags().Parse(args))
+	for name, expected := range map[string]string{
+		"name":          "my app",
+		"namespace":     "test",
+		"file":          "/my app/okteto.yml",
+		"var":           "[A=b c]",
+		"volumes":       "true",
+		"dependencies":  "true",
+		"force-destroy": "true",
+		"reason":        ttlReason,
+	} {
+		assert.Equal(t, expected, cmd.Flags().Lookup(name).Value.String(), name)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"fmt"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+)
+
+// imageChecker is implemented by the registries able to check if an image exists
+type imageChecker interface {
+	ImageExists(image string) (bool, error)
+}
+
+// checkDestroyImageExists returns an error if the image used to destroy in remote doesn't exist. The check is
+// skipped when the registry can't be queried, for example because there are no credentials for it
+func (rd *remoteDestroyCommand) checkDestroyImageExists(image string) error {
+	checker, ok := rd.registry.(imageChecker)
+	if !ok {
+		return nil
+	}
+	exists, err := checker.ImageExists(image)
+	if err != nil {
+		oktetoLog.Debugf("skipping the check of the destroy image '%s': %s", image, err)
+		return nil
+	}
+	if !exists {
+		return oktetoErrors.UserError{
+			E:    fmt.Errorf("the image '%s' used to destroy in remote doesn't exist", image),
+			Hint: "Run 'okteto build' to build it or fix the 'destroy.image' field of your okteto manifest",
+		}
+	}
+	return nil
+}
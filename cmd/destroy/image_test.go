@@ -0,0 +1,105 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	filesystem "github.com/okteto/okteto/pkg/filesystem/fake"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/okteto/okteto/pkg/types"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkingRegistry is a fake registry that knows which images exist
+type checkingRegistry struct {
+	fakeRegistry
+	images map[string]bool
+	err    error
+}
+
+func (r checkingRegistry) ImageExists(image string) (bool, error) {
+	return r.images[image], r.err
+}
+
+func TestCheckDestroyImageExists(t *testing.T) {
+	var tests = []struct {
+		name     string
+		registry checkingRegistry
+		userErr  bool
+	}{
+		{
+			name:     "image exists",
+			registry: checkingRegistry{images: map[string]bool{"okteto/destroy:1.0": true}},
+		},
+		{
+			name:     "image doesn't exist",
+			registry: checkingRegistry{images: map[string]bool{}},
+			userErr:  true,
+		},
+		{
+			name:     "registry can't be queried",
+			registry: checkingRegistry{err: assert.AnError},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rd := remoteDestroyCommand{registry: tt.registry}
+			err := rd.checkDestroyImageExists("okteto/destroy:1.0")
+			if tt.userErr {
+				var userErr oktetoErrors.UserError
+				require.ErrorAs(t, err, &userErr)
+				assert.Contains(t, userErr.Error(), "okteto/destroy:1.0")
+				assert.Contains(t, userErr.Hint, "okteto build")
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRemoteDestroyFailsWhenTheImageDoesNotExist(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	fs := afero.NewMemMapFs()
+	b := &fakeBuilder{}
+	rdc := remoteDestroyCommand{
+		builder:              b,
+		destroyImage:         "okteto/destroy:missing",
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		temporalCtrl:         filesystem.NewTemporalDirectoryCtrl(fs),
+		registry:             checkingRegistry{fakeRegistry: newFakeRegistry(), images: map[string]bool{}},
+		clusterMetadata: func(ctx context.Context) (*types.ClusterMetadata, error) {
+			return &types.ClusterMetadata{Certificate: []byte("cert"), PipelineInstallerImage: "okteto/installer:1.0", PipelineRunnerImage: "okteto/runner:1.0"}, nil
+		},
+	}
+
+	err := rdc.destroy(context.Background(), &Options{})
+	assert.ErrorAs(t, err, &oktetoErrors.UserError{})
+	assert.ErrorContains(t, err, "okteto/destroy:missing")
+}
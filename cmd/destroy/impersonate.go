@@ -0,0 +1,73 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"fmt"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	// impersonateUserArg is the build arg and env var that sets the user impersonated by the remote destroy
+	impersonateUserArg = "KUBE_IMPERSONATE_USER"
+	// impersonateGroupArg is the build arg and env var that sets the group impersonated by the remote destroy
+	impersonateGroupArg = "KUBE_IMPERSONATE_GROUP"
+)
+
+// getImpersonationBuildArgs returns the build args forwarding the impersonation options to the remote destroy
+func getImpersonationBuildArgs(opts *Options) []string {
+	var args []string
+	if opts.ImpersonateUser != "" {
+		args = append(args, fmt.Sprintf("%s=%s", impersonateUserArg, opts.ImpersonateUser))
+	}
+	if opts.ImpersonateGroup != "" {
+		args = append(args, fmt.Sprintf("%s=%s", impersonateGroupArg, opts.ImpersonateGroup))
+	}
+	return args
+}
+
+// getImpersonationArgs returns the build args the remote destroy image declares for the impersonation options
+func getImpersonationArgs(opts *Options) []string {
+	if opts.ImpersonateUser == "" && opts.ImpersonateGroup == "" {
+		return nil
+	}
+	return []string{impersonateUserArg, impersonateGroupArg}
+}
+
+// applyImpersonationFromEnv configures the current context of cfg to impersonate the user and group
+// forwarded by the remote destroy, so every kubernetes client and the kubeconfig of the destroy commands use them
+func applyImpersonationFromEnv(cfg *clientcmdapi.Config, lookupEnv func(string) (string, bool)) error {
+	user, _ := lookupEnv(impersonateUserArg)
+	group, _ := lookupEnv(impersonateGroupArg)
+	if user == "" && group == "" {
+		return nil
+	}
+	if cfg == nil {
+		return fmt.Errorf("okteto context not initialized")
+	}
+	kubeContext, ok := cfg.Contexts[cfg.CurrentContext]
+	if !ok {
+		return fmt.Errorf("kubernetes context '%s' not found", cfg.CurrentContext)
+	}
+	authInfo, ok := cfg.AuthInfos[kubeContext.AuthInfo]
+	if !ok {
+		return fmt.Errorf("kubernetes user '%s' not found", kubeContext.AuthInfo)
+	}
+	authInfo.Impersonate = user
+	if group != "" {
+		authInfo.ImpersonateGroups = []string{group}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestGetImpersonationBuildArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     *Options
+		expected []string
+	}{
+		{
+			name: "no impersonation",
+			opts: &Options{},
+		},
+		{
+			name:     "user",
+			opts:     &Options{ImpersonateUser: "alice"},
+			expected: []string{"KUBE_IMPERSONATE_USER=alice"},
+		},
+		{
+			name:     "user and group",
+			opts:     &Options{ImpersonateUser: "alice", ImpersonateGroup: "devs"},
+			expected: []string{"KUBE_IMPERSONATE_USER=alice", "KUBE_IMPERSONATE_GROUP=devs"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, getImpersonationBuildArgs(tt.opts))
+		})
+	}
+}
+
+func TestApplyImpersonationFromEnv(t *testing.T) {
+	newCfg := func() *clientcmdapi.Config {
+		return &clientcmdapi.Config{
+			CurrentContext: "ctx",
+			Contexts:       map[string]*clientcmdapi.Context{"ctx": {AuthInfo: "user"}},
+			AuthInfos:      map[string]*clientcmdapi.AuthInfo{"user": {Token: "token"}},
+		}
+	}
+	tests := []struct {
+		name           string
+		cfg            *clientcmdapi.Config
+		env            map[string]string
+		expectedUser   string
+		expectedGroups []string
+		expectErr      bool
+	}{
+		{
+			name: "no env vars",
+			cfg:  newCfg(),
+		},
+		{
+			name:         "user",
+			cfg:          newCfg(),
+			env:          map[string]string{impersonateUserArg: "alice"},
+			expectedUser: "alice",
+		},
+		{
+			name:           "user and group",
+			cfg:            newCfg(),
+			env:            map[string]string{impersonateUserArg: "alice", impersonateGroupArg: "devs"},
+			expectedUser:   "alice",
+			expectedGroups: []string{"devs"},
+		},
+		{
+			name: "missing auth info",
+			cfg: &clientcmdapi.Config{
+				CurrentContext: "ctx",
+				Contexts:       map[string]*clientcmdapi.Context{"ctx": {AuthInfo: "user"}},
+			},
+			env:       map[string]string{impersonateUserArg: "alice"},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookupEnv := func(key string) (string, bool) {
+				v, ok := tt.env[key]
+				return v, ok
+			}
+			err := applyImpersonationFromEnv(tt.cfg, lookupEnv)
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			authInfo := tt.cfg.AuthInfos["user"]
+			assert.Equal(t, tt.expectedUser, authInfo.Impersonate)
+			assert.Equal(t, tt.expectedGroups, authInfo.ImpersonateGroups)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	pipelineCMD "github.com/okteto/okteto/cmd/pipeline"
+	"github.com/okteto/okteto/cmd/utils"
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/okteto"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const labelDestroyTimeout = 5 * time.Minute
+
+type pipelineDestroyer interface {
+	ExecuteDestroyPipeline(ctx context.Context, opts *pipelineCMD.DestroyOptions) error
+}
+
+// labelDestroyCommand destroys all the development environments matching a label selector
+type labelDestroyCommand struct {
+	k8sClientProvider okteto.K8sClientProvider
+	pipelineDestroyer pipelineDestroyer
+	askConfirmation   func(q string) (bool, error)
+}
+
+func newLabelDestroyer(k8sClientProvider okteto.K8sClientProvider) (*labelDestroyCommand, error) {
+	pipelineCmd, err := pipelineCMD.NewCommand()
+	if err != nil {
+		return nil, err
+	}
+	return &labelDestroyCommand{
+		k8sClientProvider: k8sClientProvider,
+		pipelineDestroyer: pipelineCmd,
+		askConfirmation: func(q string) (bool, error) {
+			return utils.AskYesNo(q, utils.YesNoDefault_No)
+		},
+	}, nil
+}
+
+func (ld *labelDestroyCommand) destroy(ctx context.Context, opts *Options) error {
+	if _, err := labels.Parse(opts.LabelSelector); err != nil {
+		return oktetoErrors.UserError{
+			E:    fmt.Errorf("invalid label selector '%s': %w", opts.LabelSelector, err),
+			Hint: "Use the format 'key=value'",
+		}
+	}
+
+	c, _, err := ld.k8sClientProvider.Provide(okteto.Context().Cfg)
+	if err != nil {
+		return err
+	}
+	names, err := pipeline.ListNamesByLabel(ctx, opts.Namespace, opts.LabelSelector, c)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return oktetoErrors.UserError{
+			E:    fmt.Errorf("no development environments found with label '%s' in namespace '%s'", opts.LabelSelector, opts.Namespace),
+			Hint: "Check the labels of your development environments or use the '--name' flag",
+		}
+	}
+
+	if len(names) > 1 && !opts.ForceDestroy {
+		q := fmt.Sprintf("The following development environments will be destroyed: %s. Do you want to continue?", strings.Join(names, ", "))
+		ok, err := ld.askConfirmation(q)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			oktetoLog.Information("Destroy canceled")
+			return nil
+		}
+	}
+
+	for _, name := range names {
+		oktetoLog.Information("Destroying development environment '%s'...", name)
+		destroyOpts := &pipelineCMD.DestroyOptions{
+			Name:           name,
+			Namespace:      opts.Namespace,
+			DestroyVolumes: opts.DestroyVolumes,
+			Wait:           true,
+			Timeout:        labelDestroyTimeout,
+		}
+		if err := ld.pipelineDestroyer.ExecuteDestroyPipeline(ctx, destroyOpts); err != nil {
+			return fmt.Errorf("could not destroy development environment '%s': %w", name, err)
+		}
+	}
+	return nil
+}
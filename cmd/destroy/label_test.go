@@ -0,0 +1,119 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"context"
+	"testing"
+
+	pipelineCMD "github.com/okteto/okteto/cmd/pipeline"
+	"github.com/okteto/okteto/internal/test"
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakePipelineDestroyer struct {
+	destroyed []string
+}
+
+func (fpd *fakePipelineDestroyer) ExecuteDestroyPipeline(_ context.Context, opts *pipelineCMD.DestroyOptions) error {
+	fpd.destroyed = append(fpd.destroyed, opts.Name)
+	return nil
+}
+
+func newEnvironmentConfigmap(name string, labels map[string]string) *v1.ConfigMap {
+	labels[model.GitDeployLabel] = "true"
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pipeline.TranslatePipelineName(name),
+			Namespace: "namespace",
+			Labels:    labels,
+		},
+		Data: map[string]string{"name": name},
+	}
+}
+
+func TestLabelDestroy(t *testing.T) {
+	tests := []struct {
+		name              string
+		opts              *Options
+		answer            bool
+		expectedDestroyed []string
+		expectedAsked     bool
+		expectErr         bool
+	}{
+		{
+			name:              "single match does not ask",
+			opts:              &Options{LabelSelector: "team=b", Namespace: "namespace"},
+			expectedDestroyed: []string{"app-b"},
+		},
+		{
+			name:              "several matches confirmed",
+			opts:              &Options{LabelSelector: "team=a", Namespace: "namespace"},
+			answer:            true,
+			expectedAsked:     true,
+			expectedDestroyed: []string{"app-a", "app-c"},
+		},
+		{
+			name:          "several matches rejected",
+			opts:          &Options{LabelSelector: "team=a", Namespace: "namespace"},
+			answer:        false,
+			expectedAsked: true,
+		},
+		{
+			name:              "several matches with force destroy",
+			opts:              &Options{LabelSelector: "team=a", Namespace: "namespace", ForceDestroy: true},
+			expectedDestroyed: []string{"app-a", "app-c"},
+		},
+		{
+			name:      "no matches",
+			opts:      &Options{LabelSelector: "team=c", Namespace: "namespace"},
+			expectErr: true,
+		},
+		{
+			name:      "invalid selector",
+			opts:      &Options{LabelSelector: "team==a=b", Namespace: "namespace"},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeDestroyer := &fakePipelineDestroyer{}
+			asked := false
+			ld := &labelDestroyCommand{
+				k8sClientProvider: test.NewFakeK8sProvider(
+					newEnvironmentConfigmap("app-a", map[string]string{"team": "a"}),
+					newEnvironmentConfigmap("app-b", map[string]string{"team": "b"}),
+					newEnvironmentConfigmap("app-c", map[string]string{"team": "a"}),
+				),
+				pipelineDestroyer: fakeDestroyer,
+				askConfirmation: func(string) (bool, error) {
+					asked = true
+					return tt.answer, nil
+				},
+			}
+			err := ld.destroy(context.Background(), tt.opts)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedAsked, asked)
+			assert.ElementsMatch(t, tt.expectedDestroyed, fakeDestroyer.destroyed)
+		})
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"strings"
 
 	pipelineCMD "github.com/okteto/okteto/cmd/pipeline"
+	"github.com/okteto/okteto/cmd/utils"
 	"github.com/okteto/okteto/pkg/analytics"
+	"github.com/okteto/okteto/pkg/chaos"
 	"github.com/okteto/okteto/pkg/cmd/pipeline"
 	"github.com/okteto/okteto/pkg/constants"
 	"github.com/okteto/okteto/pkg/divert"
@@ -94,11 +96,16 @@ func (ld *localDestroyCommand) runDestroy(ctx context.Context, opts *Options) er
 		Status:    pipeline.DestroyingStatus,
 		Filename:  opts.ManifestPathFlag,
 		Variables: opts.Variables,
+		// the reason is recorded in the configmap so it is known when the destroy fails
+		DestroyReason: opts.Reason,
+		// set by the remote destroy that runs this one
+		Dirty: utils.LoadBoolean(constants.OktetoDirtyWorkingTreeEnvVar),
 	}
 	cfg, err := ld.ConfigMapHandler.translateConfigMapAndDeploy(ctx, data)
 	if err != nil {
 		return err
 	}
+	chaos.FailAt(chaos.DestroyConfigMapUpdatedStage)
 
 	if ld.manifest.Context == "" {
 		ld.manifest.Context = okteto.Context().Name
@@ -159,10 +166,14 @@ func (ld *localDestroyCommand) runDestroy(ctx context.Context, opts *Options) er
 			exit <- nil
 			return
 		}
+		envs := opts.Variables
+		if opts.Reason != "" {
+			envs = append(append([]string{}, opts.Variables...), fmt.Sprintf("%s=%s", constants.OktetoDestroyReasonEnvVar, opts.Reason))
+		}
 		for _, command := range ld.manifest.Destroy.Commands {
 			oktetoLog.Information("Running '%s'", command.Name)
 			oktetoLog.SetStage(command.Name)
-			if err := ld.executor.Execute(command, opts.Variables); err != nil {
+			if err := ld.executor.Execute(command, envs); err != nil {
 				err = fmt.Errorf("error executing command '%s': %s", command.Name, err.Error())
 				if !opts.ForceDestroy {
 					if err := ld.ConfigMapHandler.setErrorStatus(ctx, cfg, data, err); err != nil {
@@ -194,6 +205,7 @@ func (ld *localDestroyCommand) runDestroy(ctx context.Context, opts *Options) er
 			return err
 		}
 	}
+	chaos.FailAt(chaos.DestroyCommandsExecutedStage)
 	oktetoLog.SetStage("")
 	oktetoLog.DisableMasking()
 
@@ -217,6 +229,9 @@ func (ld *localDestroyCommand) runDestroy(ctx context.Context, opts *Options) er
 		LabelSelector:  deployedBySelector,
 		IncludeVolumes: opts.DestroyVolumes,
 	}
+	if opts.result != nil {
+		deleteOpts.OnDeleted = opts.result.onDeleted
+	}
 
 	oktetoLog.SetStage("Destroying volumes")
 	if err := ld.nsDestroyer.DestroySFSVolumes(ctx, opts.Namespace, deleteOpts); err != nil {
@@ -246,6 +261,15 @@ func (ld *localDestroyCommand) runDestroy(ctx context.Context, opts *Options) er
 		return err
 	}
 
+	oktetoLog.SetStage("Destroying cluster resources")
+	if err := ld.destroyClusterResources(ctx, cfg); err != nil {
+		if err := ld.ConfigMapHandler.setErrorStatus(ctx, cfg, data, err); err != nil {
+			return err
+		}
+		return err
+	}
+
+	chaos.FailAt(chaos.DestroyResourcesDeletedStage)
 	oktetoLog.SetStage("Destroying configmap")
 
 	if err := ld.ConfigMapHandler.destroyConfigMap(ctx, cfg, namespace); err != nil {
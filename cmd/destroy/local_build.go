@@ -0,0 +1,99 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/remote"
+	"github.com/okteto/okteto/pkg/types"
+	"github.com/spf13/afero"
+)
+
+const dockerBinary = "docker"
+
+// localDockerBuilder runs the remote destroy dockerfile with the local docker daemon instead of the
+// okteto builder. It is meant to iterate quickly on the remote destroy without pushing to a registry
+type localDockerBuilder struct {
+	fs       afero.Fs
+	out      io.Writer
+	lookPath func(file string) (string, error)
+	run      func(cmd *exec.Cmd) error
+}
+
+func newLocalDockerBuilder(fs afero.Fs) *localDockerBuilder {
+	return &localDockerBuilder{
+		fs:       fs,
+		out:      os.Stdout,
+		lookPath: exec.LookPath,
+		run: func(cmd *exec.Cmd) error {
+			return cmd.Run()
+		},
+	}
+}
+
+// Build runs 'docker build' with the dockerfile, secrets and build args of the options
+func (b *localDockerBuilder) Build(ctx context.Context, opts *types.BuildOptions) error {
+	binary, err := b.lookPath(dockerBinary)
+	if err != nil {
+		return oktetoErrors.UserError{
+			E:    fmt.Errorf("'--local-build' requires the docker CLI: %w", err),
+			Hint: "Install docker and make sure it is in your PATH",
+		}
+	}
+
+	// docker reads the ignore rules of a dockerfile outside of the build context from '<dockerfile>.dockerignore'
+	ignoreRules, err := afero.ReadFile(b.fs, filepath.Join(filepath.Dir(opts.File), remote.DockerignoreName))
+	if err == nil {
+		if err := afero.WriteFile(b.fs, fmt.Sprintf("%s%s", opts.File, remote.DockerignoreName), ignoreRules, 0600); err != nil {
+			return err
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, binary, getLocalBuildArgs(opts)...)
+	cmd.Env = append(os.Environ(), "DOCKER_BUILDKIT=1")
+	cmd.Stdout = b.out
+	cmd.Stderr = b.out
+	oktetoLog.Infof("running '%s'", cmd.String())
+	if err := b.run(cmd); err != nil {
+		return fmt.Errorf("local docker build failed: %w", err)
+	}
+	return nil
+}
+
+// IsV1 returns false as the dockerfile is run by docker
+func (*localDockerBuilder) IsV1() bool {
+	return false
+}
+
+func getLocalBuildArgs(opts *types.BuildOptions) []string {
+	args := []string{"build", "--progress=plain", "-f", opts.File}
+	for _, secret := range opts.Secrets {
+		args = append(args, "--secret", secret)
+	}
+	for _, arg := range opts.BuildArgs {
+		args = append(args, "--build-arg", arg)
+	}
+	if opts.NoCache {
+		args = append(args, "--no-cache")
+	}
+	return append(args, opts.Path)
+}
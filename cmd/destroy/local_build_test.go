@@ -0,0 +1,101 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/remote"
+	"github.com/okteto/okteto/pkg/types"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLocalBuildArgs(t *testing.T) {
+	opts := &types.BuildOptions{
+		File:      "/tmp/okteto/Dockerfile",
+		Path:      "/app",
+		Secrets:   []string{"id=token,src=/tmp/okteto/token"},
+		BuildArgs: []string{"INTERNAL_SERVER_NAME=okteto", "OKTETO_DESTROY_RUN_ID=1"},
+		NoCache:   true,
+	}
+	expected := []string{
+		"build", "--progress=plain", "-f", "/tmp/okteto/Dockerfile",
+		"--secret", "id=token,src=/tmp/okteto/token",
+		"--build-arg", "INTERNAL_SERVER_NAME=okteto",
+		"--build-arg", "OKTETO_DESTROY_RUN_ID=1",
+		"--no-cache",
+		"/app",
+	}
+	assert.Equal(t, expected, getLocalBuildArgs(opts))
+}
+
+func TestLocalDockerBuilderBuild(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	tmpDir := filepath.Join("/tmp", "okteto")
+	dockerfile := filepath.Join(tmpDir, "Dockerfile")
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(tmpDir, remote.DockerignoreName), []byte("node_modules"), 0600))
+
+	var executed *exec.Cmd
+	out := &bytes.Buffer{}
+	b := &localDockerBuilder{
+		fs:  fs,
+		out: out,
+		lookPath: func(string) (string, error) {
+			return "/usr/bin/docker", nil
+		},
+		run: func(cmd *exec.Cmd) error {
+			executed = cmd
+			return nil
+		},
+	}
+
+	err := b.Build(context.Background(), &types.BuildOptions{File: dockerfile, Path: "/app"})
+	require.NoError(t, err)
+	require.NotNil(t, executed)
+	assert.Equal(t, []string{"/usr/bin/docker", "build", "--progress=plain", "-f", dockerfile, "/app"}, executed.Args)
+	assert.Contains(t, executed.Env, "DOCKER_BUILDKIT=1")
+	assert.Equal(t, out, executed.Stdout)
+
+	ignoreRules, err := afero.ReadFile(fs, dockerfile+remote.DockerignoreName)
+	require.NoError(t, err)
+	assert.Equal(t, "node_modules", string(ignoreRules))
+}
+
+func TestLocalDockerBuilderErrors(t *testing.T) {
+	b := &localDockerBuilder{
+		fs: afero.NewMemMapFs(),
+		lookPath: func(string) (string, error) {
+			return "", exec.ErrNotFound
+		},
+	}
+	err := b.Build(context.Background(), &types.BuildOptions{File: "/tmp/Dockerfile"})
+	var userErr oktetoErrors.UserError
+	require.ErrorAs(t, err, &userErr)
+
+	b.lookPath = func(string) (string, error) {
+		return "/usr/bin/docker", nil
+	}
+	b.run = func(*exec.Cmd) error {
+		return errors.New("exit status 1")
+	}
+	assert.ErrorContains(t, b.Build(context.Background(), &types.BuildOptions{File: "/tmp/Dockerfile"}), "local docker build failed")
+}
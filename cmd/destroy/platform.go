@@ -0,0 +1,126 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"fmt"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/okteto/okteto/pkg/constants"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+)
+
+// platformRegex matches the os/arch[/variant] syntax of the build platforms, e.g. linux/arm64 or linux/arm/v7
+var platformRegex = regexp.MustCompile(`^[a-z0-9]+/[a-z0-9_]+(/[a-z0-9]+)?$`)
+
+func validatePlatform(platform string) error {
+	if platformRegex.MatchString(platform) {
+		return nil
+	}
+	return oktetoErrors.UserError{
+		E:    fmt.Errorf("invalid platform '%s'", platform),
+		Hint: "The platform must follow the format os/arch[/variant], e.g. 'linux/amd64' or 'linux/arm/v7'",
+	}
+}
+
+// getPlatform returns the platform used to build the remote destroy image. The flag takes priority over the manifest
+func (rd *remoteDestroyCommand) getPlatform(opts *Options) (string, error) {
+	platform := opts.Platform
+	if platform == "" && rd.manifest != nil && rd.manifest.Destroy != nil {
+		expanded, err := model.ExpandEnv(rd.manifest.Destroy.Platform, false)
+		if err != nil {
+			return "", err
+		}
+		platform = expanded
+	}
+	if platform == "" {
+		return "", nil
+	}
+	if err := validatePlatform(platform); err != nil {
+		return "", err
+	}
+	return platform, nil
+}
+
+// checkImagePlatform fails when the destroy image is not available for the platform. Errors reaching the
+// registry are not fatal, the build reports them if the image can't be pulled
+func (rd *remoteDestroyCommand) checkImagePlatform(platform string) error {
+	platforms, err := rd.imagePlatforms(rd.destroyImage)
+	if err != nil {
+		oktetoLog.Infof("could not get the platforms of the image '%s': %s", rd.destroyImage, err)
+		return nil
+	}
+	for _, p := range platforms {
+		if platformMatches(platform, p) {
+			return nil
+		}
+	}
+	return oktetoErrors.UserError{
+		E:    fmt.Errorf("the destroy image '%s' is not available for the platform '%s'", rd.destroyImage, platform),
+		Hint: fmt.Sprintf("The image is available for: %s. Use one of them with the '--platform' flag or the 'destroy.platform' field of your okteto manifest", strings.Join(platforms, ", ")),
+	}
+}
+
+// platformMatches returns if an image platform satisfies the requested one. A request without variant matches any variant
+func platformMatches(requested, available string) bool {
+	if requested == available {
+		return true
+	}
+	return strings.Count(requested, "/") == 1 && strings.HasPrefix(available, requested+"/")
+}
+
+// getInstallerPlatform returns the platform of the installer image to pull when it is a multi-architecture image.
+// The platform of the destroy takes priority, then OKTETO_BUILD_PLATFORM and the architecture of the CLI.
+// It returns an empty platform for single architecture images or when the registry can't be reached
+func (rd *remoteDestroyCommand) getInstallerPlatform(opts *Options, installerImage string, lookupEnv func(string) (string, bool)) (string, error) {
+	if installerImage == "" || rd.imagePlatforms == nil {
+		return "", nil
+	}
+
+	platform, err := rd.getPlatform(opts)
+	if err != nil {
+		return "", err
+	}
+	if platform == "" {
+		if value, ok := lookupEnv(constants.OktetoBuildPlatformEnvVar); ok && value != "" {
+			if err := validatePlatform(value); err != nil {
+				return "", err
+			}
+			platform = value
+		} else {
+			platform = fmt.Sprintf("linux/%s", runtime.GOARCH)
+		}
+	}
+
+	platforms, err := rd.imagePlatforms(installerImage)
+	if err != nil {
+		oktetoLog.Infof("could not get the platforms of the installer image '%s': %s", installerImage, err)
+		return "", nil
+	}
+	// a single platform means the image is not a manifest list, there is no variant to choose
+	if len(platforms) < 2 {
+		return "", nil
+	}
+	for _, p := range platforms {
+		if platformMatches(platform, p) {
+			return platform, nil
+		}
+	}
+	oktetoLog.Infof("the installer image '%s' is not available for the platform '%s', using its default platform", installerImage, platform)
+	return "", nil
+}
@@ -0,0 +1,291 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/constants"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	filesystem "github.com/okteto/okteto/pkg/filesystem/fake"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/okteto/okteto/pkg/types"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePlatform(t *testing.T) {
+	var tests = []struct {
+		platform string
+		valid    bool
+	}{
+		{platform: "linux/amd64", valid: true},
+		{platform: "linux/arm64", valid: true},
+		{platform: "linux/arm/v7", valid: true},
+		{platform: "windows/amd64", valid: true},
+		{platform: "linux"},
+		{platform: "amd64"},
+		{platform: "linux/"},
+		{platform: "Linux/AMD64"},
+		{platform: "linux/arm/v7/extra"},
+		{platform: "linux/amd64,linux/arm64"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			err := validatePlatform(tt.platform)
+			if tt.valid {
+				assert.NoError(t, err)
+				return
+			}
+			var userErr oktetoErrors.UserError
+			assert.ErrorAs(t, err, &userErr)
+		})
+	}
+}
+
+func TestPlatformMatches(t *testing.T) {
+	assert.True(t, platformMatches("linux/amd64", "linux/amd64"))
+	assert.True(t, platformMatches("linux/arm64", "linux/arm64/v8"))
+	assert.True(t, platformMatches("linux/arm/v7", "linux/arm/v7"))
+	assert.False(t, platformMatches("linux/arm/v7", "linux/arm/v6"))
+	assert.False(t, platformMatches("linux/arm", "linux/arm64"))
+	assert.False(t, platformMatches("linux/arm64", "linux/amd64"))
+}
+
+func TestRemoteDestroyPlatform(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+
+	var tests = []struct {
+		platformsErr     error
+		name             string
+		flag             string
+		manifestPlatform string
+		expectedErr      string
+		expected         string
+		platforms        []string
+	}{
+		{
+			name:      "no platform",
+			platforms: []string{"linux/amd64"},
+		},
+		{
+			name:      "platform from the flag",
+			flag:      "linux/amd64",
+			platforms: []string{"linux/amd64", "linux/arm64"},
+			expected:  "linux/amd64",
+		},
+		{
+			name:             "flag overrides the manifest",
+			flag:             "linux/arm64",
+			manifestPlatform: "linux/amd64",
+			platforms:        []string{"linux/arm64/v8"},
+			expected:         "linux/arm64",
+		},
+		{
+			name:             "platform from the manifest",
+			manifestPlatform: "linux/amd64",
+			platforms:        []string{"linux/amd64"},
+			expected:         "linux/amd64",
+		},
+		{
+			name:             "invalid platform in the manifest",
+			manifestPlatform: "amd64",
+			expectedErr:      "invalid platform 'amd64'",
+		},
+		{
+			name:        "image not available for the platform",
+			flag:        "linux/arm64",
+			platforms:   []string{"linux/amd64"},
+			expectedErr: "the destroy image 'okteto/destroy:1.0' is not available for the platform 'linux/arm64'",
+		},
+		{
+			name:         "registry errors are not fatal",
+			flag:         "linux/arm64",
+			platformsErr: assert.AnError,
+			expected:     "linux/arm64",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			b := &recordingBuilder{}
+			rdc := remoteDestroyCommand{
+				builder:              b,
+				destroyImage:         "okteto/destroy:1.0",
+				fs:                   fs,
+				workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+				temporalCtrl:         filesystem.NewTemporalDirectoryCtrl(fs),
+				registry:             newFakeRegistry(),
+				manifest: &model.Manifest{
+					Destroy: &model.DestroyInfo{Platform: tt.manifestPlatform},
+				},
+				clusterMetadata: func(ctx context.Context) (*types.ClusterMetadata, error) {
+					return &types.ClusterMetadata{PipelineInstallerImage: "okteto/installer:1.0", PipelineRunnerImage: "okteto/runner:1.0"}, nil
+				},
+				imagePlatforms: func(image string) ([]string, error) {
+					if image == "okteto/installer:1.0" {
+						return nil, nil
+					}
+					assert.Equal(t, "okteto/destroy:1.0", image)
+					return tt.platforms, tt.platformsErr
+				},
+			}
+
+			err := rdc.destroy(context.Background(), &Options{Platform: tt.flag})
+			if tt.expectedErr != "" {
+				var userErr oktetoErrors.UserError
+				require.ErrorAs(t, err, &userErr)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				assert.Nil(t, b.opts)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, b.opts.Platform)
+		})
+	}
+}
+
+func TestGetInstallerPlatform(t *testing.T) {
+	multiArch := []string{"linux/amd64", "linux/arm64", "linux/arm/v7"}
+	var tests = []struct {
+		name         string
+		flag         string
+		env          map[string]string
+		platforms    []string
+		platformsErr error
+		expected     string
+		expectErr    bool
+	}{
+		{
+			name:      "platform from the flag",
+			flag:      "linux/arm64",
+			env:       map[string]string{constants.OktetoBuildPlatformEnvVar: "linux/amd64"},
+			platforms: multiArch,
+			expected:  "linux/arm64",
+		},
+		{
+			name:      "platform from OKTETO_BUILD_PLATFORM",
+			env:       map[string]string{constants.OktetoBuildPlatformEnvVar: "linux/arm/v7"},
+			platforms: multiArch,
+			expected:  "linux/arm/v7",
+		},
+		{
+			name:      "platform of the CLI",
+			platforms: []string{"linux/amd64", "linux/arm64", "linux/386", "linux/s390x", "linux/ppc64le"},
+			expected:  fmt.Sprintf("linux/%s", runtime.GOARCH),
+		},
+		{
+			name:      "platform without variant matches a variant",
+			flag:      "linux/arm",
+			platforms: multiArch,
+			expected:  "linux/arm",
+		},
+		{
+			name:      "single architecture image",
+			flag:      "linux/arm64",
+			platforms: []string{"linux/arm64"},
+		},
+		{
+			name:      "platform not available",
+			flag:      "windows/amd64",
+			platforms: multiArch,
+		},
+		{
+			name:         "registry not reachable",
+			flag:         "linux/arm64",
+			platformsErr: assert.AnError,
+		},
+		{
+			name:      "invalid OKTETO_BUILD_PLATFORM",
+			env:       map[string]string{constants.OktetoBuildPlatformEnvVar: "arm64"},
+			platforms: multiArch,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rd := &remoteDestroyCommand{
+				imagePlatforms: func(image string) ([]string, error) {
+					assert.Equal(t, "okteto/installer:1.0", image)
+					return tt.platforms, tt.platformsErr
+				},
+			}
+			lookupEnv := func(key string) (string, bool) {
+				v, ok := tt.env[key]
+				return v, ok
+			}
+			platform, err := rd.getInstallerPlatform(&Options{Platform: tt.flag}, "okteto/installer:1.0", lookupEnv)
+			if tt.expectErr {
+				assert.ErrorAs(t, err, &oktetoErrors.UserError{})
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, platform)
+		})
+	}
+}
+
+func TestCreateDockerfileWithInstallerPlatform(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	rdc := remoteDestroyCommand{
+		fs:                   afero.NewMemMapFs(),
+		destroyImage:         "okteto/destroy:1.0",
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		registry:             newFakeRegistry(),
+		imagePlatforms: func(image string) ([]string, error) {
+			return []string{"linux/amd64", "linux/arm64"}, nil
+		},
+	}
+
+	dockerfileName, err := rdc.createDockerfile("/test", &Options{Platform: "linux/arm64"}, "okteto/installer:1.0")
+	require.NoError(t, err)
+	content, err := afero.ReadFile(rdc.fs, dockerfileName)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "FROM --platform=linux/arm64 okteto/installer:1.0 as installer\n")
+
+	rdc.imagePlatforms = func(image string) ([]string, error) {
+		return []string{"linux/amd64"}, nil
+	}
+	dockerfileName, err = rdc.createDockerfile("/test", &Options{Platform: "linux/arm64"}, "okteto/installer:1.0")
+	require.NoError(t, err)
+	content, err = afero.ReadFile(rdc.fs, dockerfileName)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "FROM okteto/installer:1.0 as installer\n")
+}
@@ -0,0 +1,220 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/format"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// preflightResource is a kind of resource that destroy might need to delete
+type preflightResource struct {
+	group    string
+	resource string
+	count    func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) (int, error)
+}
+
+// preflightPermission is a verb over a resource that the destroy operation needs
+type preflightPermission struct {
+	verb     string
+	group    string
+	resource string
+}
+
+func (p preflightPermission) String() string {
+	if p.group == "" {
+		return fmt.Sprintf("%s %s", p.verb, p.resource)
+	}
+	return fmt.Sprintf("%s %s.%s", p.verb, p.resource, p.group)
+}
+
+var preflightResources = []preflightResource{
+	{
+		group:    "apps",
+		resource: "deployments",
+		count: func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) (int, error) {
+			l, err := c.AppsV1().Deployments(ns).List(ctx, opts)
+			if err != nil {
+				return 0, err
+			}
+			return len(l.Items), nil
+		},
+	},
+	{
+		group:    "apps",
+		resource: "statefulsets",
+		count: func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) (int, error) {
+			l, err := c.AppsV1().StatefulSets(ns).List(ctx, opts)
+			if err != nil {
+				return 0, err
+			}
+			return len(l.Items), nil
+		},
+	},
+	{
+		group:    "batch",
+		resource: "jobs",
+		count: func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) (int, error) {
+			l, err := c.BatchV1().Jobs(ns).List(ctx, opts)
+			if err != nil {
+				return 0, err
+			}
+			return len(l.Items), nil
+		},
+	},
+	{
+		group:    "batch",
+		resource: "cronjobs",
+		count: func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) (int, error) {
+			l, err := c.BatchV1().CronJobs(ns).List(ctx, opts)
+			if err != nil {
+				return 0, err
+			}
+			return len(l.Items), nil
+		},
+	},
+	{
+		resource: "services",
+		count: func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) (int, error) {
+			l, err := c.CoreV1().Services(ns).List(ctx, opts)
+			if err != nil {
+				return 0, err
+			}
+			return len(l.Items), nil
+		},
+	},
+	{
+		group:    "networking.k8s.io",
+		resource: "ingresses",
+		count: func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) (int, error) {
+			l, err := c.NetworkingV1().Ingresses(ns).List(ctx, opts)
+			if err != nil {
+				return 0, err
+			}
+			return len(l.Items), nil
+		},
+	},
+	{
+		resource: "secrets",
+		count: func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) (int, error) {
+			l, err := c.CoreV1().Secrets(ns).List(ctx, opts)
+			if err != nil {
+				return 0, err
+			}
+			return len(l.Items), nil
+		},
+	},
+}
+
+var pvcPreflightResource = preflightResource{
+	resource: "persistentvolumeclaims",
+	count: func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) (int, error) {
+		l, err := c.CoreV1().PersistentVolumeClaims(ns).List(ctx, opts)
+		if err != nil {
+			return 0, err
+		}
+		return len(l.Items), nil
+	},
+}
+
+// preflightChecker verifies that the user has the permissions needed to destroy a development environment
+type preflightChecker struct {
+	k8sClient kubernetes.Interface
+}
+
+func newPreflightChecker(k8sClient kubernetes.Interface) *preflightChecker {
+	return &preflightChecker{
+		k8sClient: k8sClient,
+	}
+}
+
+// check returns a single error listing every permission missing to destroy the development environment
+func (pc *preflightChecker) check(ctx context.Context, opts *Options) error {
+	permissions := pc.getRequiredPermissions(ctx, opts)
+
+	var missing []string
+	for _, p := range permissions {
+		allowed, err := pc.isAllowed(ctx, opts.Namespace, p)
+		if err != nil {
+			return fmt.Errorf("could not check permission to %s: %w", p.String(), err)
+		}
+		if !allowed {
+			missing = append(missing, p.String())
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+	return oktetoErrors.UserError{
+		E:    fmt.Errorf("missing permissions to destroy the development environment in namespace '%s':\n    - %s", opts.Namespace, strings.Join(missing, "\n    - ")),
+		Hint: "Ask your cluster administrator for these permissions or use the '--skip-preflight' flag to skip this check",
+	}
+}
+
+// getRequiredPermissions returns delete over each kind of resource deployed by the development environment, plus the permissions to update its configmap
+func (pc *preflightChecker) getRequiredPermissions(ctx context.Context, opts *Options) []preflightPermission {
+	permissions := []preflightPermission{
+		{verb: "update", resource: "configmaps"},
+		{verb: "delete", resource: "configmaps"},
+	}
+
+	resources := append([]preflightResource{}, preflightResources...)
+	if opts.DestroyVolumes {
+		resources = append(resources, pvcPreflightResource)
+	}
+
+	listOpts := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", model.DeployedByLabel, format.ResourceK8sMetaString(opts.Name)),
+	}
+	for _, r := range resources {
+		n, err := r.count(ctx, pc.k8sClient, opts.Namespace, listOpts)
+		if err != nil {
+			oktetoLog.Infof("could not list %s for the destroy preflight: %s", r.resource, err)
+			continue
+		}
+		if n == 0 {
+			continue
+		}
+		permissions = append(permissions, preflightPermission{verb: "delete", group: r.group, resource: r.resource})
+	}
+	return permissions
+}
+
+func (pc *preflightChecker) isAllowed(ctx context.Context, ns string, p preflightPermission) (bool, error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: ns,
+				Verb:      p.verb,
+				Group:     p.group,
+				Resource:  p.resource,
+			},
+		},
+	}
+	result, err := pc.k8sClient.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return result.Status.Allowed, nil
+}
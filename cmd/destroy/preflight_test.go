@@ -0,0 +1,126 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+func newFakeAuthorizationClient(denied map[string]bool, objs ...runtime.Object) *fake.Clientset {
+	c := fake.NewSimpleClientset(objs...)
+	c.Fake.PrependReactor("create", "selfsubjectaccessreviews", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8sTesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		attrs := review.Spec.ResourceAttributes
+		p := preflightPermission{verb: attrs.Verb, group: attrs.Group, resource: attrs.Resource}
+		review.Status.Allowed = !denied[p.String()]
+		return true, review, nil
+	})
+	return c
+}
+
+func TestPreflightCheck(t *testing.T) {
+	deployedBy := map[string]string{model.DeployedByLabel: "movies"}
+	objs := []runtime.Object{
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "test", Labels: deployedBy},
+		},
+		&apiv1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "test", Labels: deployedBy},
+		},
+		&apiv1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "test", Labels: deployedBy},
+		},
+		&apiv1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "test", Labels: map[string]string{model.DeployedByLabel: "other"}},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		opts            *Options
+		denied          map[string]bool
+		expectedMissing []string
+		notExpected     []string
+	}{
+		{
+			name: "all permissions granted",
+			opts: &Options{Name: "movies", Namespace: "test"},
+		},
+		{
+			name: "missing permissions are aggregated",
+			opts: &Options{Name: "movies", Namespace: "test"},
+			denied: map[string]bool{
+				"update configmaps":             true,
+				"delete deployments.apps":       true,
+				"delete services":               true,
+				"delete secrets":                true,
+				"delete persistentvolumeclaims": true,
+			},
+			expectedMissing: []string{"update configmaps", "delete deployments.apps", "delete services"},
+			notExpected:     []string{"delete secrets", "delete persistentvolumeclaims"},
+		},
+		{
+			name: "volumes only checked when destroying volumes",
+			opts: &Options{Name: "movies", Namespace: "test", DestroyVolumes: true},
+			denied: map[string]bool{
+				"delete persistentvolumeclaims": true,
+			},
+			expectedMissing: []string{"delete persistentvolumeclaims"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFakeAuthorizationClient(tt.denied, objs...)
+			err := newPreflightChecker(c).check(context.Background(), tt.opts)
+			if len(tt.expectedMissing) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			var userErr oktetoErrors.UserError
+			require.ErrorAs(t, err, &userErr)
+			for _, m := range tt.expectedMissing {
+				assert.Contains(t, err.Error(), m)
+			}
+			for _, m := range tt.notExpected {
+				assert.NotContains(t, err.Error(), m)
+			}
+		})
+	}
+}
+
+func TestPreflightCheckWithReviewError(t *testing.T) {
+	c := fake.NewSimpleClientset()
+	c.Fake.PrependReactor("create", "selfsubjectaccessreviews", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("unauthorized")
+	})
+
+	err := newPreflightChecker(c).check(context.Background(), &Options{Name: "movies", Namespace: "test"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unauthorized")
+}
@@ -0,0 +1,44 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"github.com/okteto/okteto/cmd/utils"
+	"github.com/okteto/okteto/pkg/constants"
+)
+
+// proxyEnvVars are the proxy variables forwarded to the remote destroy when it is enabled
+var proxyEnvVars = []string{
+	"HTTP_PROXY",
+	"HTTPS_PROXY",
+	"NO_PROXY",
+	"http_proxy",
+	"https_proxy",
+	"no_proxy",
+}
+
+// getProxyEnvVars returns the local proxy variables to set in the remote destroy. They are only forwarded
+// with '--forward-proxy' or OKTETO_FORWARD_PROXY because they might contain credentials
+func getProxyEnvVars(opts *Options, lookupEnv func(string) (string, bool)) map[string]string {
+	if !opts.ForwardProxy && !utils.LoadBoolean(constants.OktetoForwardProxyEnvVar) {
+		return nil
+	}
+	result := map[string]string{}
+	for _, name := range proxyEnvVars {
+		if value, ok := lookupEnv(name); ok && value != "" {
+			result[name] = value
+		}
+	}
+	return result
+}
@@ -0,0 +1,48 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"fmt"
+	"strings"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+)
+
+const (
+	// userReason is the reason of the destroys requested by a user
+	userReason = "user"
+	// ttlReason is the reason of the destroys triggered by the expiration of a development environment
+	ttlReason = "ttl"
+	// ciReason is the reason of the destroys run by a CI pipeline
+	ciReason = "ci"
+	// previewClosedReason is the reason of the destroys triggered by closing a preview environment
+	previewClosedReason = "preview-closed"
+)
+
+// destroyReasons are the accepted values of the flag '--reason'
+var destroyReasons = []string{userReason, ttlReason, ciReason, previewClosedReason}
+
+// validateReason returns an error if reason is not one of destroyReasons
+func validateReason(reason string) error {
+	for _, r := range destroyReasons {
+		if reason == r {
+			return nil
+		}
+	}
+	return oktetoErrors.UserError{
+		E:    fmt.Errorf("invalid value '%s' for flag '--reason'", reason),
+		Hint: fmt.Sprintf("Accepted values are %s", strings.Join(destroyReasons, ", ")),
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/okteto/okteto/internal/test"
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	"github.com/okteto/okteto/pkg/constants"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	filesystem "github.com/okteto/okteto/pkg/filesystem/fake"
+	"github.com/okteto/okteto/pkg/k8s/configmaps"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestValidateReason(t *testing.T) {
+	for _, reason := range destroyReasons {
+		assert.NoError(t, validateReason(reason))
+	}
+	err := validateReason("manual")
+	assert.ErrorAs(t, err, &oktetoErrors.UserError{})
+}
+
+func TestDestroyReasonIsExposedToLocalCommands(t *testing.T) {
+	ctx := context.Background()
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Namespace: "test",
+			},
+		},
+		CurrentContext: "test",
+	}
+	executor := &fakeExecutor{}
+	opts := &Options{
+		Name:      "test-app",
+		Reason:    ttlReason,
+		Variables: []string{"A=1"},
+	}
+	k8sClientProvider := test.NewFakeK8sProvider()
+	fakeClient, _, err := k8sClientProvider.Provide(api.NewConfig())
+	require.NoError(t, err)
+
+	ld := localDestroyCommand{
+		&localDestroyAllCommand{
+			ConfigMapHandler:  NewConfigmapHandler(fakeClient),
+			nsDestroyer:       &fakeDestroyer{errOnVolumes: assert.AnError},
+			executor:          executor,
+			k8sClientProvider: k8sClientProvider,
+		},
+		fakeManifest,
+	}
+
+	err = ld.runDestroy(ctx, opts)
+	assert.Error(t, err)
+
+	require.Len(t, executor.envs, 3)
+	for _, envs := range executor.envs {
+		assert.Equal(t, []string{"A=1", fmt.Sprintf("%s=%s", constants.OktetoDestroyReasonEnvVar, ttlReason)}, envs)
+	}
+	assert.Equal(t, []string{"A=1"}, opts.Variables)
+
+	cfg, err := configmaps.Get(ctx, pipeline.TranslatePipelineName(opts.Name), okteto.Context().Namespace, fakeClient)
+	require.NoError(t, err)
+	assert.Equal(t, ttlReason, cfg.Data["destroyReason"])
+}
+
+func TestDestroyReasonIsSetInRemoteDockerfile(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	rdc := remoteDestroyCommand{
+		fs:                   afero.NewMemMapFs(),
+		destroyImage:         "test-image",
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		registry:             newFakeRegistry(),
+	}
+
+	dockerfileName, err := rdc.createDockerfile("/test", &Options{Reason: previewClosedReason}, "")
+	require.NoError(t, err)
+	content, err := afero.ReadFile(rdc.fs, dockerfileName)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), fmt.Sprintf("ENV %s=\"preview-closed\"", constants.OktetoDestroyReasonEnvVar))
+	assert.Contains(t, string(content), "--reason preview-closed")
+}
+
+func TestDirtyWorkingTreeIsSetInRemoteDockerfile(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	rdc := remoteDestroyCommand{
+		fs:                   afero.NewMemMapFs(),
+		destroyImage:         "test-image",
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		registry:             newFakeRegistry(),
+	}
+
+	dockerfileName, err := rdc.createDockerfile("/test", &Options{Reason: userReason}, "")
+	require.NoError(t, err)
+	content, err := afero.ReadFile(rdc.fs, dockerfileName)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), constants.OktetoDirtyWorkingTreeEnvVar)
+
+	dockerfileName, err = rdc.createDockerfile("/test", &Options{Reason: userReason, dirtyWorkingTree: true}, "")
+	require.NoError(t, err)
+	content, err = afero.ReadFile(rdc.fs, dockerfileName)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), fmt.Sprintf("ENV %s=\"true\"", constants.OktetoDirtyWorkingTreeEnvVar))
+}
@@ -0,0 +1,125 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/okteto/okteto/pkg/types"
+)
+
+// defaultReconnectBudget is the number of times the status of a remote destroy is checked without progress
+// after the connection with the builder is lost
+const defaultReconnectBudget = 12
+
+// reconnectInterval is the wait between two checks of the status of a remote destroy
+var reconnectInterval = 5 * time.Second
+
+// errDestroyStatusUnknown is returned when the status of a remote destroy can't be known after losing the builder
+var errDestroyStatusUnknown = errors.New("lost the connection with the builder and couldn't get the status of the destroy")
+
+// streamDropErrors are the messages of the failures of the log stream of the builder
+var streamDropErrors = []string{
+	"transport is closing",
+	"error reading from server: eof",
+	"connection reset by peer",
+	"failed to receive status",
+}
+
+// isStreamDropError returns if the remote destroy build failed because the connection with the builder dropped
+func isStreamDropError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, dropped := range streamDropErrors {
+		if strings.Contains(msg, dropped) {
+			return true
+		}
+	}
+	return false
+}
+
+// build runs the remote destroy build. If the connection with the builder drops while the destroy keeps running
+// server-side, it waits for the destroy to finish instead of failing
+func (rd *remoteDestroyCommand) build(ctx context.Context, buildOptions *types.BuildOptions, opts *Options) error {
+	err := rd.builder.Build(ctx, copyBuildOptions(buildOptions))
+	if !isStreamDropError(err) || opts.Name == "" || rd.destroyStatus == nil {
+		return err
+	}
+	return rd.waitForDestroy(ctx, opts, err)
+}
+
+// waitForDestroy polls the status of the development environment until the destroy finishes. Only the checks
+// that don't show the destroy in progress consume the reconnect budget
+func (rd *remoteDestroyCommand) waitForDestroy(ctx context.Context, opts *Options, streamErr error) error {
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = okteto.Context().Namespace
+	}
+	oktetoLog.Warning("The connection with the builder was lost: %s. Waiting for the destroy to finish", streamErr)
+
+	lastStatus := ""
+	for budget := rd.reconnectBudget; budget > 0; {
+		status, err := rd.destroyStatus(ctx, opts.Name, namespace)
+		switch {
+		case oktetoErrors.IsNotFound(err):
+			oktetoLog.Information("Development environment '%s' destroyed", opts.Name)
+			return nil
+		case err != nil:
+			oktetoLog.Infof("could not get the status of development environment '%s': %s", opts.Name, err)
+			budget--
+		case status == pipeline.ErrorStatus:
+			return oktetoErrors.UserError{
+				E:    fmt.Errorf("the destroy of development environment '%s' failed", opts.Name),
+				Hint: "Check the logs of the development environment in the Okteto UI",
+			}
+		case status == pipeline.DestroyingStatus:
+			if status != lastStatus {
+				oktetoLog.Information("Development environment '%s' is still being destroyed", opts.Name)
+			}
+		default:
+			budget--
+		}
+		lastStatus = status
+
+		select {
+		case <-ctx.Done():
+			return streamErr
+		case <-time.After(reconnectInterval):
+		}
+	}
+	return fmt.Errorf("%w after %d checks: %s", errDestroyStatusUnknown, rd.reconnectBudget, streamErr)
+}
+
+// getEnvironmentStatus returns the status of a development environment from the okteto API
+func getEnvironmentStatus(ctx context.Context, name, namespace string) (string, error) {
+	c, err := okteto.NewOktetoClientProvider().Provide()
+	if err != nil {
+		return "", fmt.Errorf("failed to provide okteto client for checking the development environment: %w", err)
+	}
+	gitDeploy, err := c.Pipeline().GetByName(ctx, name, namespace)
+	if err != nil {
+		return "", err
+	}
+	return gitDeploy.Status, nil
+}
@@ -0,0 +1,195 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTransportClosing = errors.New("failed to receive status: rpc error: code = Unavailable desc = transport is closing")
+
+// droppingBuilder runs 'steps' steps and drops the stream after 'dropAt' of them. A negative 'dropAt' never drops
+type droppingBuilder struct {
+	steps  int
+	dropAt int
+	builds int
+	seen   []int
+}
+
+func (b *droppingBuilder) Build(_ context.Context, _ *types.BuildOptions) error {
+	b.builds++
+	for step := 0; step < b.steps; step++ {
+		if step == b.dropAt {
+			return errTransportClosing
+		}
+		b.seen = append(b.seen, step)
+	}
+	return nil
+}
+
+func (*droppingBuilder) IsV1() bool { return true }
+
+// statusSequence returns the statuses in order, one per check, and repeats the last one when they are exhausted
+type statusSequence struct {
+	statuses []string
+	errs     []error
+	checks   int
+}
+
+func (s *statusSequence) get(_ context.Context, _, _ string) (string, error) {
+	i := s.checks
+	s.checks++
+	if i >= len(s.statuses) {
+		i = len(s.statuses) - 1
+	}
+	return s.statuses[i], s.errs[i]
+}
+
+func TestIsStreamDropError(t *testing.T) {
+	var tests = []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name: "no error",
+		},
+		{
+			name:     "transport is closing",
+			err:      errTransportClosing,
+			expected: true,
+		},
+		{
+			name:     "server eof",
+			err:      errors.New("build failed: error reading from server: EOF"),
+			expected: true,
+		},
+		{
+			name: "builder unavailable",
+			err:  errors.New("rpc error: code = Unavailable desc = connection error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isStreamDropError(tt.err))
+		})
+	}
+}
+
+func TestBuildWhenTheStreamDrops(t *testing.T) {
+	interval := reconnectInterval
+	reconnectInterval = time.Millisecond
+	defer func() { reconnectInterval = interval }()
+	backoff := buildRetryBackoff
+	buildRetryBackoff = time.Millisecond
+	defer func() { buildRetryBackoff = backoff }()
+
+	var tests = []struct {
+		name           string
+		opts           *Options
+		dropAt         int
+		status         *statusSequence
+		expectedErr    error
+		userErr        bool
+		expectedBuilds int
+		expectedChecks int
+		expectedSeen   []int
+	}{
+		{
+			name:           "stream never drops",
+			opts:           &Options{Name: "test", Namespace: "ns", BuildRetries: 3},
+			dropAt:         -1,
+			status:         &statusSequence{},
+			expectedBuilds: 1,
+			expectedSeen:   []int{0, 1, 2, 3},
+		},
+		{
+			name:   "destroy finishes after the stream drops",
+			opts:   &Options{Name: "test", Namespace: "ns", BuildRetries: 3},
+			dropAt: 2,
+			status: &statusSequence{
+				statuses: []string{pipeline.DestroyingStatus, pipeline.DestroyingStatus, ""},
+				errs:     []error{nil, nil, oktetoErrors.ErrNotFound},
+			},
+			expectedBuilds: 1,
+			expectedChecks: 3,
+			expectedSeen:   []int{0, 1},
+		},
+		{
+			name:   "destroy fails after the stream drops",
+			opts:   &Options{Name: "test", Namespace: "ns", BuildRetries: 3},
+			dropAt: 1,
+			status: &statusSequence{
+				statuses: []string{pipeline.DestroyingStatus, pipeline.ErrorStatus},
+				errs:     []error{nil, nil},
+			},
+			userErr:        true,
+			expectedBuilds: 1,
+			expectedChecks: 2,
+			expectedSeen:   []int{0},
+		},
+		{
+			name:   "reconnect budget exhausted",
+			opts:   &Options{Name: "test", Namespace: "ns", BuildRetries: 3},
+			dropAt: 0,
+			status: &statusSequence{
+				statuses: []string{pipeline.DestroyingStatus, ""},
+				errs:     []error{nil, assert.AnError},
+			},
+			expectedErr:    errDestroyStatusUnknown,
+			expectedBuilds: 1,
+			expectedChecks: 4,
+		},
+		{
+			name:           "without name the build is retried",
+			opts:           &Options{Namespace: "ns", BuildRetries: 2},
+			dropAt:         0,
+			status:         &statusSequence{},
+			expectedErr:    errTransportClosing,
+			expectedBuilds: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &droppingBuilder{steps: 4, dropAt: tt.dropAt}
+			rd := remoteDestroyCommand{
+				builder:         b,
+				destroyStatus:   tt.status.get,
+				reconnectBudget: 3,
+			}
+
+			err := rd.buildWithRetries(context.Background(), &types.BuildOptions{}, tt.opts)
+			switch {
+			case tt.userErr:
+				require.ErrorAs(t, err, &oktetoErrors.UserError{})
+			case tt.expectedErr != nil:
+				require.ErrorIs(t, err, tt.expectedErr)
+			default:
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedBuilds, b.builds)
+			assert.Equal(t, tt.expectedChecks, tt.status.checks)
+			assert.Equal(t, tt.expectedSeen, b.seen)
+		})
+	}
+}
@@ -3,17 +3,22 @@ package destroy
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"math/big"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
-	"text/template"
 
+	"github.com/alessio/shellescape"
+	"github.com/google/uuid"
 	builder "github.com/okteto/okteto/cmd/build"
+	"github.com/okteto/okteto/cmd/utils"
 
 	remoteBuild "github.com/okteto/okteto/cmd/build/remote"
 	"github.com/okteto/okteto/pkg/config"
@@ -22,76 +27,35 @@ import (
 
 	"github.com/okteto/okteto/pkg/cmd/build"
 	"github.com/okteto/okteto/pkg/constants"
+	"github.com/okteto/okteto/pkg/discovery"
 	oktetoLog "github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/model"
 	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/okteto/okteto/pkg/registry"
+	"github.com/okteto/okteto/pkg/remote"
 	"github.com/okteto/okteto/pkg/types"
 	"github.com/spf13/afero"
 )
 
 const (
-	templateName           = "destroy-dockerfile"
-	dockerfileTemporalNane = "deploy"
-	oktetoDockerignoreName = ".oktetodeployignore"
-	dockerfileTemplate     = `
-FROM {{ .OktetoCLIImage }} as okteto-cli
-
-FROM {{ .InstallerImage }} as installer
-
-FROM alpine as certs
-RUN apk update && apk add ca-certificates
-
-FROM {{ .UserDestroyImage }} as deploy
-
-ENV PATH="${PATH}:/okteto/bin"
-COPY --from=certs /etc/ssl/certs /etc/ssl/certs
-COPY --from=installer /app/bin/* /okteto/bin/
-COPY --from=okteto-cli /usr/local/bin/* /okteto/bin/
-
-{{range $key, $val := .OktetoBuildEnvVars }}
-ENV {{$key}} {{$val}}
-{{end}}
-ENV {{ .NamespaceEnvVar }} {{ .NamespaceValue }}
-ENV {{ .ContextEnvVar }} {{ .ContextValue }}
-ENV {{ .TokenEnvVar }} {{ .TokenValue }}
-ENV {{ .RemoteDeployEnvVar }} true
-{{ if ne .ActionNameValue "" }}
-ENV {{ .ActionNameEnvVar }} {{ .ActionNameValue }}
-{{ end }}
-{{ if ne .GitCommitValue "" }}
-ENV {{ .GitCommitEnvVar }} {{ .GitCommitValue }}
-{{ end }}
-
-COPY . /okteto/src
-WORKDIR /okteto/src
-
-ENV OKTETO_INVALIDATE_CACHE {{ .RandomInt }}
-ARG OKTETO_TLS_CERT_BASE64
-ARG INTERNAL_SERVER_NAME=""
-RUN echo "$OKTETO_TLS_CERT_BASE64" | base64 -d > /etc/ssl/certs/okteto.crt
-RUN okteto destroy --log-output=json --server-name="$INTERNAL_SERVER_NAME" {{ .DestroyFlags }}
-`
+	dockerfileTemporalName = "destroy-dockerfile"
+	tokenSecretID          = "okteto-token"
+	tokenSecretFileName    = "okteto-token"
+	// destroyRunIDArg is set to a different value on every run so the destroy step is never
+	// taken from the cache, even when the rest of the layers are
+	destroyRunIDArg = "OKTETO_DESTROY_RUN_ID"
 )
 
-type dockerfileTemplateProperties struct {
-	OktetoCLIImage     string
-	UserDestroyImage   string
-	InstallerImage     string
-	OktetoBuildEnvVars map[string]string
-	ContextEnvVar      string
-	ContextValue       string
-	NamespaceEnvVar    string
-	NamespaceValue     string
-	TokenEnvVar        string
-	TokenValue         string
-	ActionNameEnvVar   string
-	ActionNameValue    string
-	GitCommitEnvVar    string
-	GitCommitValue     string
-	RemoteDeployEnvVar string
-	DeployFlags        string
-	RandomInt          int
-	DestroyFlags       string
+// RandomSource returns random numbers in [0, max)
+type RandomSource interface {
+	Int(max *big.Int) (*big.Int, error)
+}
+
+// cryptoRandomSource returns the random numbers of crypto/rand
+type cryptoRandomSource struct{}
+
+func (cryptoRandomSource) Int(max *big.Int) (*big.Int, error) {
+	return rand.Int(rand.Reader, max)
 }
 
 type remoteDestroyCommand struct {
@@ -103,31 +67,56 @@ type remoteDestroyCommand struct {
 	manifest             *model.Manifest
 	registry             remoteBuild.OktetoRegistryInterface
 	clusterMetadata      func(context.Context) (*types.ClusterMetadata, error)
+	environmentExists    func(ctx context.Context, name, namespace string) (bool, error)
+	imagePlatforms       func(image string) ([]string, error)
+	destroyStatus        func(ctx context.Context, name, namespace string) (string, error)
+	// buildContext is the folder shipped as build context, the working directory when empty
+	buildContext string
+	// reconnectBudget is how many checks of the destroy status without progress are done after losing the builder
+	reconnectBudget int
+	// random is used for the cache busting values and the name of the dockerfile, crypto/rand when nil
+	random RandomSource
+	// out is where the dry-run output is written
+	out io.Writer
 }
 
-func newRemoteDestroyer(manifest *model.Manifest) *remoteDestroyCommand {
+func newRemoteDestroyer(manifest *model.Manifest, destroyImage string) *remoteDestroyCommand {
 	fs := afero.NewOsFs()
 	builder := remoteBuild.NewBuilderFromScratch()
 	return &remoteDestroyCommand{
 		builder:              builder,
-		destroyImage:         manifest.Destroy.Image,
+		destroyImage:         destroyImage,
 		fs:                   fs,
 		workingDirectoryCtrl: filesystem.NewOsWorkingDirectoryCtrl(),
 		temporalCtrl:         filesystem.NewTemporalDirectoryCtrl(fs),
 		manifest:             manifest,
 		registry:             builder.Registry,
 		clusterMetadata:      fetchClusterMetadata,
+		environmentExists:    checkEnvironmentExists,
+		imagePlatforms:       registry.NewOktetoRegistry(okteto.Config{}).GetImagePlatforms,
+		destroyStatus:        getEnvironmentStatus,
+		reconnectBudget:      defaultReconnectBudget,
+		random:               cryptoRandomSource{},
+		out:                  os.Stdout,
 	}
 }
 
 func (rd *remoteDestroyCommand) destroy(ctx context.Context, opts *Options) error {
-	sc, err := rd.clusterMetadata(ctx)
+	if opts.InsecureSkipTLSVerify {
+		oktetoLog.Warning("Insecure mode enabled: the destroy run in remote won't verify the certificate of the okteto server. Use it only with trusted networks")
+	}
+	if opts.DestroyVolumes && !manifestHasVolumes(rd.manifest) {
+		oktetoLog.Warning("The flag '--volumes' is set but the okteto manifest doesn't define any persistent volume")
+	}
+	if err := validateExtraDockerfile(rd.getExtraDockerfile()); err != nil {
+		return err
+	}
+	buildArgs, err := parseBuildArgs(opts.BuildArgs)
 	if err != nil {
 		return err
 	}
-
-	if rd.destroyImage == "" {
-		rd.destroyImage = sc.PipelineRunnerImage
+	if opts.DestroyDependencies && (rd.manifest == nil || len(rd.manifest.Dependencies) == 0) {
+		oktetoLog.Warning("The flag '--dependencies' is set but the okteto manifest doesn't define any dependency")
 	}
 
 	cwd, err := rd.workingDirectoryCtrl.Get()
@@ -135,23 +124,119 @@ func (rd *remoteDestroyCommand) destroy(ctx context.Context, opts *Options) erro
 		return err
 	}
 
-	tmpDir, err := rd.temporalCtrl.Create()
+	dirty, err := utils.CheckWorkingTree(cwd, "destroy", opts.RequireClean)
 	if err != nil {
 		return err
 	}
+	opts.dirtyWorkingTree = dirty
 
-	dockerfile, err := rd.createDockerfile(tmpDir, opts, sc.PipelineInstallerImage)
+	// the extra CA certificates are read before anything else so missing files fail fast
+	caCerts, err := rd.readCACerts(cwd, rd.getCACertPaths(opts))
+	if err != nil {
+		return err
+	}
+
+	platform, err := rd.getPlatform(opts)
+	if err != nil {
+		return err
+	}
+
+	sshAgents, err := rd.getSSHAgents(os.LookupEnv)
+	if err != nil {
+		return err
+	}
+
+	contextDir, err := rd.getBuildContext(cwd, opts)
+	if err != nil {
+		return err
+	}
+	if contextDir != cwd {
+		manifestPath, err := getManifestPathInContext(cwd, contextDir, opts)
+		if err != nil {
+			return err
+		}
+		// the okteto destroy in remote runs from the root of the build context
+		opts.ManifestPathFlag = manifestPath
+	}
+	rd.buildContext = contextDir
+
+	if opts.Name != "" {
+		namespace := opts.Namespace
+		if namespace == "" {
+			namespace = okteto.Context().Namespace
+		}
+		exists, err := rd.environmentExists(ctx, opts.Name, namespace)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			if opts.IgnoreNotFound {
+				oktetoLog.Information("Development environment '%s' not found in namespace '%s'. Nothing to destroy", opts.Name, namespace)
+				return nil
+			}
+			return oktetoErrors.UserError{
+				E:    fmt.Errorf("development environment '%s' not found in namespace '%s'", opts.Name, namespace),
+				Hint: "Use the '--ignore-not-found' flag to not fail when the development environment doesn't exist",
+			}
+		}
+	}
+
+	// the images set by the user are checked before building, the okteto ones always exist
+	if rd.destroyImage != "" && !opts.DryRun {
+		if err := rd.checkDestroyImageExists(rd.destroyImage); err != nil {
+			return err
+		}
+	}
+
+	sc := opts.ClusterMetadataOverride
+	if sc == nil {
+		sc, err = rd.clusterMetadata(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	sc, err = utils.ResolveClusterMetadata(sc, rd.destroyImage, "destroy")
+	if err != nil {
+		return err
+	}
+
+	if rd.destroyImage == "" {
+		rd.destroyImage = sc.PipelineRunnerImage
+	}
+
+	tmpDir, err := rd.temporalCtrl.Create()
 	if err != nil {
 		return err
 	}
 
 	defer func() {
-		if err := rd.fs.Remove(dockerfile); err != nil {
-			oktetoLog.Infof("error removing dockerfile: %w", err)
+		if err := rd.fs.RemoveAll(tmpDir); err != nil {
+			oktetoLog.Debugf("error removing temporal directory '%s': %s", tmpDir, err)
 		}
 	}()
 
+	dockerfile, err := rd.createDockerfile(tmpDir, opts, sc.PipelineInstallerImage)
+	if err != nil {
+		return err
+	}
+
+	if opts.DryRun {
+		return remote.PrintDryRun(rd.out, rd.fs, dockerfile, tmpDir, okteto.Context().Token)
+	}
+
+	if platform != "" {
+		if err := rd.checkImagePlatform(platform); err != nil {
+			return err
+		}
+	}
+
+	tokenFile, err := rd.createTokenSecretFile(tmpDir)
+	if err != nil {
+		return err
+	}
+
 	buildInfo := &model.BuildInfo{
+		Context:    contextDir,
 		Dockerfile: dockerfile,
 	}
 
@@ -160,19 +245,57 @@ func (rd *remoteDestroyCommand) destroy(ctx context.Context, opts *Options) erro
 		return err
 	}
 
-	buildOptions := build.OptsFromBuildInfoForRemoteDeploy(buildInfo, &types.BuildOptions{Path: cwd, OutputMode: "destroy"})
+	outputMode := "destroy"
+	if getRemoteLogOutput(opts) == oktetoLog.PlainFormat {
+		outputMode = build.DestroyPlainOutputMode
+	}
+	buildOptions := build.OptsFromBuildInfoForRemoteDeploy(buildInfo, &types.BuildOptions{
+		Path:       contextDir,
+		OutputMode: outputMode,
+		Secrets:    []string{fmt.Sprintf("id=%s,src=%s", tokenSecretID, tokenFile)},
+	})
 	buildOptions.Manifest = rd.manifest
+	buildOptions.Platform = platform
+	buildOptions.SSH = sshAgents
+	buildOptions.ExpectedCLIVersion = remote.GetExpectedOktetoCLIVersion(remote.ResolveOktetoCLIImage(config.VersionString))
 	buildOptions.BuildArgs = append(
 		buildOptions.BuildArgs,
 		fmt.Sprintf("OKTETO_TLS_CERT_BASE64=%s", base64.StdEncoding.EncodeToString(sc.Certificate)),
 		fmt.Sprintf("INTERNAL_SERVER_NAME=%s", sc.ServerName),
+		fmt.Sprintf("%s=%s", destroyRunIDArg, uuid.NewString()),
 	)
+	if len(caCerts) > 0 {
+		buildOptions.BuildArgs = append(buildOptions.BuildArgs, fmt.Sprintf("%s=%s", extraCACertsArg, base64.StdEncoding.EncodeToString(caCerts)))
+	}
+	includedFiles, err := rd.getIncludedFiles(contextDir)
+	if err != nil {
+		return err
+	}
+	for _, f := range includedFiles {
+		buildOptions.BuildArgs = append(buildOptions.BuildArgs, f.BuildArg())
+	}
+	buildOptions.BuildArgs = append(buildOptions.BuildArgs, getImpersonationBuildArgs(opts)...)
+	for _, a := range buildArgs {
+		buildOptions.BuildArgs = append(buildOptions.BuildArgs, a.String())
+	}
 
 	// we need to call Build() method using a remote builder. This Builder will have
 	// the same behavior as the V1 builder but with a different output taking into
 	// account that we must not confuse the user with build messages since this logic is
 	// executed in the deploy command.
-	if err := rd.builder.Build(ctx, buildOptions); err != nil {
+	buildCtx := ctx
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		buildCtx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	if err := rd.buildWithRetries(buildCtx, buildOptions, opts); err != nil {
+		if errors.Is(buildCtx.Err(), context.DeadlineExceeded) {
+			return oktetoErrors.UserError{
+				E:    fmt.Errorf("the destroy of the development environment didn't finish after %s: %w", opts.Timeout, context.DeadlineExceeded),
+				Hint: "Increase the timeout with the '--timeout' flag or check that your okteto builder has enough capacity",
+			}
+		}
 		var cmdErr build.OktetoCommandErr
 		if errors.As(err, &cmdErr) {
 			oktetoLog.SetStage(cmdErr.Stage)
@@ -189,139 +312,292 @@ func (rd *remoteDestroyCommand) destroy(ctx context.Context, opts *Options) erro
 			E: fmt.Errorf("error during destroy of the development environment: %w", err),
 		}
 	}
-	oktetoLog.SetStage("done")
-	oktetoLog.AddToBuffer(oktetoLog.InfoLevel, "EOF")
+	rd.reportBaseImageDigest(opts.result)
+	oktetoLog.FinishBuffer()
 
 	return nil
 }
 
+// reportBaseImageDigest records the digest of the base image of the remote destroy in the result of the destroy.
+// The image built on top of it to run the destroy commands is never pushed, so it has no digest.
+// The destroy already succeeded, so failures are only logged
+func (rd *remoteDestroyCommand) reportBaseImageDigest(result *resultRecorder) {
+	ref, err := rd.registry.GetImageTagWithDigest(rd.destroyImage)
+	if err != nil {
+		oktetoLog.Debugf("could not resolve the digest of the destroy image '%s': %s", rd.destroyImage, err)
+		return
+	}
+	_, digest, found := strings.Cut(ref, "@")
+	if !found {
+		oktetoLog.Debugf("the destroy image '%s' has no digest", ref)
+		return
+	}
+	oktetoLog.Infof("the base image of the destroy has digest %s", digest)
+	result.setBaseImageDigest(digest)
+}
+
+// getIncludedFiles returns the files included by the manifest that are outside the build context
+func (rd *remoteDestroyCommand) getIncludedFiles(contextDir string) ([]remote.IncludedFile, error) {
+	if rd.manifest == nil {
+		return nil, nil
+	}
+	return remote.GetIncludedFilesOutsideContext(rd.fs, contextDir, rd.manifest.IncludedFiles)
+}
+
 func (rd *remoteDestroyCommand) createDockerfile(tempDir string, opts *Options, installerImage string) (string, error) {
 	cwd, err := rd.workingDirectoryCtrl.Get()
 	if err != nil {
 		return "", err
 	}
 
-	randomNumber, err := rand.Int(rand.Reader, big.NewInt(1000))
+	cacheKey, err := rd.getCacheKey(cwd, opts)
 	if err != nil {
 		return "", err
 	}
 
-	tmpl := template.Must(template.New(templateName).Parse(dockerfileTemplate))
-	dockerfileSyntax := dockerfileTemplateProperties{
-		OktetoCLIImage:     getOktetoCLIVersion(config.VersionString),
-		InstallerImage:     installerImage,
-		UserDestroyImage:   rd.destroyImage,
-		ContextEnvVar:      model.OktetoContextEnvVar,
-		ContextValue:       okteto.Context().Name,
-		NamespaceEnvVar:    model.OktetoNamespaceEnvVar,
-		NamespaceValue:     okteto.Context().Namespace,
-		TokenEnvVar:        model.OktetoTokenEnvVar,
-		TokenValue:         okteto.Context().Token,
-		ActionNameEnvVar:   model.OktetoActionNameEnvVar,
-		ActionNameValue:    os.Getenv(model.OktetoActionNameEnvVar),
-		GitCommitEnvVar:    constants.OktetoGitCommitEnvVar,
-		GitCommitValue:     os.Getenv(constants.OktetoGitCommitEnvVar),
-		RemoteDeployEnvVar: constants.OKtetoDeployRemote,
-		RandomInt:          int(randomNumber.Int64()),
-		DestroyFlags:       strings.Join(getDestroyFlags(opts), " "),
-	}
-
-	dockerfile, err := rd.fs.Create(filepath.Join(tempDir, "deploy"))
+	contextDir := cwd
+	if rd.buildContext != "" {
+		contextDir = rd.buildContext
+	}
+	includedFiles, err := rd.getIncludedFiles(contextDir)
 	if err != nil {
 		return "", err
 	}
+	buildArgs, err := parseBuildArgs(opts.BuildArgs)
+	if err != nil {
+		return "", err
+	}
+	installerPlatform, err := rd.getInstallerPlatform(opts, installerImage, os.LookupEnv)
+	if err != nil {
+		return "", err
+	}
+
+	params := remote.Params{
+		Command:           "destroy",
+		Flags:             getDestroyFlags(opts),
+		LogOutput:         getRemoteLogOutput(opts),
+		OktetoCLIImage:    remote.GetOktetoCLIImage(config.VersionString),
+		InstallerImage:    installerImage,
+		InstallerPlatform: installerPlatform,
+		RunImage:          rd.destroyImage,
+		PreferImageCLI:    opts.PreferImageCLI,
+		Namespace:         okteto.Context().Namespace,
+		Context:           okteto.Context().Name,
+		ActionName:        os.Getenv(model.OktetoActionNameEnvVar),
+		GitCommit:         os.Getenv(constants.OktetoGitCommitEnvVar),
+		TokenValue:        okteto.Context().Token,
+		TokenSecretID:     tokenSecretID,
+		CacheKey:          cacheKey,
+		IncludedFiles:     includedFiles,
+		RunIDArg:          destroyRunIDArg,
+		ProxyEnvVars:      getProxyEnvVars(opts, os.LookupEnv),
+		EnvVars:           getDestroyEnvVars(opts),
+		Args:              getImpersonationArgs(opts),
+		ExposedArgs:       getBuildArgNames(buildArgs),
+		SkipTLSVerify:     opts.InsecureSkipTLSVerify,
+		SSH:               rd.sshEnabled(),
+		CLICheckMarker:    build.OktetoCLICheckMarker,
+		ExtraDockerfile:   rd.getExtraDockerfile(),
+	}
+	if len(rd.getCACertPaths(opts)) > 0 {
+		params.ExtraCACertsArg = extraCACertsArg
+	}
 
-	err = rd.createDockerignoreIfNeeded(cwd, tempDir)
+	// the name is unique per invocation so concurrent destroys sharing a temp dir never overwrite each other
+	randomNumber, err := rd.randomInt(big.NewInt(math.MaxInt64))
 	if err != nil {
 		return "", err
 	}
+	dockerfilePath := filepath.Join(tempDir, fmt.Sprintf("%s-%s", dockerfileTemporalName, randomNumber.String()))
+	dockerfile, err := rd.fs.Create(dockerfilePath)
+	if err != nil {
+		return "", err
+	}
+	defer dockerfile.Close()
 
-	if err := tmpl.Execute(dockerfile, dockerfileSyntax); err != nil {
+	// the ignore files are the ones of the build context
+	if err := remote.WriteDockerignore(rd.fs, contextDir, tempDir); err != nil {
 		return "", err
 	}
-	return dockerfile.Name(), nil
 
+	if err := remote.RenderDockerfile(dockerfile, params); err != nil {
+		return "", err
+	}
+	return dockerfilePath, nil
 }
 
-func (rd *remoteDestroyCommand) createDockerignoreIfNeeded(cwd, tmpDir string) error {
-	dockerignoreFilePath := fmt.Sprintf("%s/%s", cwd, ".oktetodeployignore")
-	if _, err := rd.fs.Stat(dockerignoreFilePath); err != nil {
-		if !errors.Is(err, os.ErrNotExist) {
-			return err
-		}
-	} else {
-		dockerignoreContent, err := afero.ReadFile(rd.fs, dockerignoreFilePath)
-		if err != nil {
-			return err
-		}
+// getDestroyEnvVars returns the env vars the remote destroy image sets for the destroy commands
+func getDestroyEnvVars(opts *Options) []remote.EnvVar {
+	var envVars []remote.EnvVar
+	if opts.Reason != "" {
+		envVars = append(envVars, remote.EnvVar{Name: constants.OktetoDestroyReasonEnvVar, Value: opts.Reason})
+	}
+	if opts.dirtyWorkingTree {
+		envVars = append(envVars, remote.EnvVar{Name: constants.OktetoDirtyWorkingTreeEnvVar, Value: "true"})
+	}
+	return envVars
+}
 
-		err = afero.WriteFile(rd.fs, fmt.Sprintf("%s/%s", tmpDir, ".dockerignore"), dockerignoreContent, 0600)
-		if err != nil {
-			return err
-		}
+// getExtraDockerfile returns the Dockerfile instructions of the destroy section of the manifest
+func (rd *remoteDestroyCommand) getExtraDockerfile() string {
+	if rd.manifest == nil || rd.manifest.Destroy == nil {
+		return ""
 	}
+	return rd.manifest.Destroy.ExtraDockerfile
+}
 
+// validateExtraDockerfile checks that the fragment doesn't start a new stage, the destroy must run in the deploy stage
+func validateExtraDockerfile(fragment string) error {
+	for _, line := range strings.Split(fragment, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && strings.EqualFold(fields[0], "FROM") {
+			return oktetoErrors.UserError{
+				E:    fmt.Errorf("'destroy.extraDockerfile' can't contain 'FROM' instructions"),
+				Hint: "Use 'destroy.image' to change the image used to destroy in remote",
+			}
+		}
+	}
 	return nil
 }
 
-func getDestroyFlags(opts *Options) []string {
-	var deployFlags []string
-
-	if opts.Name != "" {
-		deployFlags = append(deployFlags, fmt.Sprintf("--name \"%s\"", opts.Name))
+// randomInt returns a random number in [0, max) from the random source of the command
+func (rd *remoteDestroyCommand) randomInt(max *big.Int) (*big.Int, error) {
+	if rd.random == nil {
+		return cryptoRandomSource{}.Int(max)
 	}
+	return rd.random.Int(max)
+}
 
-	if opts.Namespace != "" {
-		deployFlags = append(deployFlags, fmt.Sprintf("--namespace %s", opts.Namespace))
+// manifestHasVolumes returns true if the manifest defines persistent volumes, either in the compose volumes
+// or in the persistent volume of the dev containers
+func manifestHasVolumes(manifest *model.Manifest) bool {
+	if manifest == nil {
+		return false
+	}
+	if manifest.Deploy != nil && manifest.Deploy.ComposeSection != nil && manifest.Deploy.ComposeSection.Stack != nil {
+		stack := manifest.Deploy.ComposeSection.Stack
+		if len(stack.Volumes) > 0 {
+			return true
+		}
+		for _, svc := range stack.Services {
+			if len(svc.Volumes) > 0 {
+				return true
+			}
+		}
 	}
+	for _, dev := range manifest.Dev {
+		if dev.PersistentVolumeInfo != nil && dev.PersistentVolumeInfo.Enabled {
+			return true
+		}
+	}
+	return false
+}
 
-	if opts.ManifestPathFlag != "" {
-		deployFlags = append(deployFlags, fmt.Sprintf("--file %s", opts.ManifestPathFlag))
+// getCacheKey returns the value used to invalidate the cache of the destroy image. It is a hash of the
+// manifest and the destroy commands so repeated destroys of the same content reuse the cached layers.
+// When opts.NoCache is set a random value is returned instead
+func (rd *remoteDestroyCommand) getCacheKey(cwd string, opts *Options) (string, error) {
+	if opts.NoCache {
+		randomNumber, err := rd.randomInt(big.NewInt(1000))
+		if err != nil {
+			return "", err
+		}
+		return randomNumber.String(), nil
 	}
 
-	if opts.DestroyVolumes {
-		deployFlags = append(deployFlags, "--volumes")
+	h := sha256.New()
+	manifestPath := opts.ManifestPath
+	if manifestPath == "" {
+		if path, err := discovery.GetOktetoManifestPath(cwd); err == nil {
+			manifestPath = path
+		}
+	} else if !filepath.IsAbs(manifestPath) {
+		manifestPath = filepath.Join(cwd, manifestPath)
+	}
+	if manifestPath != "" {
+		content, err := afero.ReadFile(rd.fs, manifestPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		h.Write(content)
 	}
 
-	if opts.ForceDestroy {
-		deployFlags = append(deployFlags, "--force-destroy")
+	if rd.manifest != nil && rd.manifest.Destroy != nil {
+		fmt.Fprintf(h, "image:%s\n", rd.manifest.Destroy.Image)
+		fmt.Fprintf(h, "extraDockerfile:%s\n", rd.manifest.Destroy.ExtraDockerfile)
+		for _, cmd := range rd.manifest.Destroy.Commands {
+			fmt.Fprintf(h, "command:%s:%s\n", cmd.Name, cmd.Command)
+		}
 	}
+	fmt.Fprintf(h, "flags:%s\n", strings.Join(getDestroyFlags(opts), " "))
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
 
-	return deployFlags
+// createTokenSecretFile writes the okteto token into a file used as the source of the
+// build secret so the token is never stored in the image layers
+func (rd *remoteDestroyCommand) createTokenSecretFile(tmpDir string) (string, error) {
+	tokenFile := filepath.Join(tmpDir, tokenSecretFileName)
+	if err := afero.WriteFile(rd.fs, tokenFile, []byte(okteto.Context().Token), 0600); err != nil {
+		return "", err
+	}
+	return tokenFile, nil
 }
 
-func getOktetoCLIVersion(versionString string) string {
-	var version string
-	if match, _ := regexp.MatchString(`\d+\.\d+\.\d+`, versionString); match {
-		version = fmt.Sprintf(constants.OktetoCLIImageForRemoteTemplate, versionString)
-	} else {
-		remoteOktetoImage := os.Getenv(constants.OKtetoDeployRemoteImage)
-		if remoteOktetoImage != "" {
-			version = remoteOktetoImage
-		} else {
-			version = fmt.Sprintf(constants.OktetoCLIImageForRemoteTemplate, "latest")
-		}
+func getDestroyFlags(opts *Options) []string {
+	// only the options used by the okteto destroy run in remote are forwarded.
+	// The dependencies are destroyed by the okteto CLI in remote using the token and context of the Dockerfile
+	remoteOpts := &Options{
+		Name:                opts.Name,
+		Namespace:           opts.Namespace,
+		ManifestPathFlag:    opts.ManifestPathFlag,
+		Variables:           opts.Variables,
+		DestroyVolumes:      opts.DestroyVolumes,
+		DestroyDependencies: opts.DestroyDependencies,
+		ForceDestroy:        opts.ForceDestroy,
+		LogLevel:            opts.LogLevel,
+		BuildRetries:        defaultBuildRetries,
+		Reason:              opts.Reason,
 	}
+	return remoteOpts.ToFlags()
+}
 
-	return version
+// formatShellFlag returns a flag with its value as a single item. The flags of the remote destroy are rendered
+// in a shell interpreted RUN instruction, so the value is always shell quoted
+func formatShellFlag(name, value string) string {
+	return fmt.Sprintf("--%s %s", name, shellEscape(value))
 }
 
-func fetchClusterMetadata(ctx context.Context) (*types.ClusterMetadata, error) {
-	cp := okteto.NewOktetoClientProvider()
-	c, err := cp.Provide()
-	if err != nil {
-		return nil, fmt.Errorf("failed to provide okteto client for fetching certs: %s", err)
+// shellEscape quotes s so it is passed as a single word to the shell of the remote
+// destroy, escaping quotes, backslashes, spaces and dollar signs
+func shellEscape(s string) string {
+	return shellescape.Quote(s)
+}
+
+// getRemoteLogOutput returns the log output of the okteto destroy running in remote. It is json
+// unless plain output is requested, as json is the format parsed to display the remote logs.
+// Local builds show the raw output of docker, so they use plain output too
+func getRemoteLogOutput(opts *Options) string {
+	if opts.RemoteLogOutput == oktetoLog.PlainFormat || opts.LocalBuild {
+		return oktetoLog.PlainFormat
 	}
-	uc := c.User()
+	return oktetoLog.JSONFormat
+}
 
-	metadata, err := uc.GetClusterMetadata(ctx, okteto.Context().Namespace)
+// checkEnvironmentExists uses the okteto API to check if a development environment is deployed in the namespace
+func checkEnvironmentExists(ctx context.Context, name, namespace string) (bool, error) {
+	c, err := okteto.NewOktetoClientProvider().Provide()
 	if err != nil {
-		return nil, err
+		return false, fmt.Errorf("failed to provide okteto client for checking the development environment: %w", err)
 	}
-
-	if metadata.Certificate == nil {
-		metadata.Certificate, err = uc.GetClusterCertificate(ctx, okteto.Context().Name, okteto.Context().Namespace)
+	if _, err := c.Pipeline().GetByName(ctx, name, namespace); err != nil {
+		if oktetoErrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
 	}
+	return true, nil
+}
 
-	return &metadata, err
+func fetchClusterMetadata(ctx context.Context) (*types.ClusterMetadata, error) {
+	return utils.GetClusterMetadata(ctx, okteto.NewOktetoClientProvider(), okteto.Context().Name, okteto.Context().Namespace)
 }
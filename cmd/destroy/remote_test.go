@@ -14,25 +14,38 @@
 package destroy
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"flag"
 	"fmt"
+	"math/big"
+	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/okteto/okteto/cmd/utils"
+	"github.com/okteto/okteto/internal/test/client"
 	"github.com/okteto/okteto/pkg/cmd/build"
 	"github.com/okteto/okteto/pkg/constants"
 	oktetoErrors "github.com/okteto/okteto/pkg/errors"
 	filesystem "github.com/okteto/okteto/pkg/filesystem/fake"
 	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
 	"github.com/okteto/okteto/pkg/registry"
+	"github.com/okteto/okteto/pkg/remote"
 	"github.com/okteto/okteto/pkg/types"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+var updateGolden = flag.Bool("update", false, "update the golden files")
+
 type fakeBuilder struct {
 	err error
 }
@@ -110,6 +123,16 @@ func (fr fakeRegistry) GetRepoNameAndTag(repo string) (string, string)   { retur
 
 func TestRemoteTest(t *testing.T) {
 	ctx := context.Background()
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
 	wdCtrl := filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/"))
 	fs := afero.NewMemMapFs()
 	tempCreator := filesystem.NewTemporalDirectoryCtrl(fs)
@@ -119,6 +142,7 @@ func TestRemoteTest(t *testing.T) {
 		tempFsCreator error
 		options       *Options
 		builderErr    error
+		notFound      bool
 	}
 	var tests = []struct {
 		name     string
@@ -182,6 +206,31 @@ func TestRemoteTest(t *testing.T) {
 				options: &Options{},
 			},
 		},
+		{
+			name: "environment not found",
+			config: config{
+				options:  &Options{Name: "test"},
+				notFound: true,
+			},
+			expected: oktetoErrors.UserError{
+				E:    fmt.Errorf("development environment 'test' not found in namespace 'test'"),
+				Hint: "Use the '--ignore-not-found' flag to not fail when the development environment doesn't exist",
+			},
+		},
+		{
+			name: "environment not found with ignore not found",
+			config: config{
+				options:    &Options{Name: "test", IgnoreNotFound: true},
+				notFound:   true,
+				builderErr: assert.AnError,
+			},
+		},
+		{
+			name: "environment found",
+			config: config{
+				options: &Options{Name: "test"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -196,7 +245,10 @@ func TestRemoteTest(t *testing.T) {
 				destroyImage:         "",
 				registry:             newFakeRegistry(),
 				clusterMetadata: func(ctx context.Context) (*types.ClusterMetadata, error) {
-					return &types.ClusterMetadata{Certificate: []byte("cert")}, nil
+					return &types.ClusterMetadata{Certificate: []byte("cert"), PipelineInstallerImage: "okteto/installer:1.0", PipelineRunnerImage: "okteto/runner:1.0"}, nil
+				},
+				environmentExists: func(_ context.Context, _, _ string) (bool, error) {
+					return !tt.config.notFound, nil
 				},
 			}
 			err := rdc.destroy(ctx, tt.config.options)
@@ -227,7 +279,16 @@ func TestGetDestroyFlags(t *testing.T) {
 					Name: "test",
 				},
 			},
-			expected: []string{"--name \"test\""},
+			expected: []string{"--name test"},
+		},
+		{
+			name: "dependencies set",
+			config: config{
+				opts: &Options{
+					DestroyDependencies: true,
+				},
+			},
+			expected: []string{"--dependencies"},
 		},
 		{
 			name: "name multiple words",
@@ -236,7 +297,61 @@ func TestGetDestroyFlags(t *testing.T) {
 					Name: "this is a test",
 				},
 			},
-			expected: []string{"--name \"this is a test\""},
+			expected: []string{"--name 'this is a test'"},
+		},
+		{
+			name: "name with double quotes",
+			config: config{
+				opts: &Options{
+					Name: `my "app"`,
+				},
+			},
+			expected: []string{`--name 'my "app"'`},
+		},
+		{
+			name: "name with command substitution",
+			config: config{
+				opts: &Options{
+					Name: "a$(whoami)b",
+				},
+			},
+			expected: []string{"--name 'a$(whoami)b'"},
+		},
+		{
+			name: "name with backticks and single quotes",
+			config: config{
+				opts: &Options{
+					Name: "it's `whoami`",
+				},
+			},
+			expected: []string{`--name 'it'"'"'s ` + "`whoami`'"},
+		},
+		{
+			name: "name with backslashes",
+			config: config{
+				opts: &Options{
+					Name: `my\app`,
+				},
+			},
+			expected: []string{`--name 'my\app'`},
+		},
+		{
+			name: "namespace with spaces",
+			config: config{
+				opts: &Options{
+					Namespace: "my namespace",
+				},
+			},
+			expected: []string{"--namespace 'my namespace'"},
+		},
+		{
+			name: "manifest path with special characters",
+			config: config{
+				opts: &Options{
+					ManifestPathFlag: "/my dir/$HOME/okteto.yml",
+				},
+			},
+			expected: []string{"--file '/my dir/$HOME/okteto.yml'"},
 		},
 		{
 			name: "namespace set",
@@ -256,6 +371,15 @@ func TestGetDestroyFlags(t *testing.T) {
 			},
 			expected: []string{"--file /hello/this/is/a/test"},
 		},
+		{
+			name: "variables set",
+			config: config{
+				opts: &Options{
+					Variables: []string{"DB_NAME=db", "A=b c", "B=c=d"},
+				},
+			},
+			expected: []string{"--var DB_NAME=db", "--var 'A=b c'", "--var B=c=d"},
+		},
 		{
 			name: "destroy volumes set",
 			config: config{
@@ -274,6 +398,24 @@ func TestGetDestroyFlags(t *testing.T) {
 			},
 			expected: []string{"--force-destroy"},
 		},
+		{
+			name: "log level set",
+			config: config{
+				opts: &Options{
+					LogLevel: "debug",
+				},
+			},
+			expected: []string{"--log-level debug"},
+		},
+		{
+			name: "reason set",
+			config: config{
+				opts: &Options{
+					Reason: ttlReason,
+				},
+			},
+			expected: []string{"--reason ttl"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -284,7 +426,86 @@ func TestGetDestroyFlags(t *testing.T) {
 	}
 }
 
+func TestGetDestroyFlagsAreQuoted(t *testing.T) {
+	opts := &Options{
+		Name:             "my app",
+		Namespace:        "a$(whoami)b",
+		ManifestPathFlag: "/my dir/okteto.yml",
+		Variables:        []string{"A=b c"},
+		DestroyVolumes:   true,
+		Reason:           ttlReason,
+	}
+	assert.Equal(t, []string{
+		"--name 'my app'",
+		"--namespace 'a$(whoami)b'",
+		"--file '/my dir/okteto.yml'",
+		"--var 'A=b c'",
+		"--volumes",
+		"--reason ttl",
+	}, getDestroyFlags(opts))
+}
+
+func TestShellEscape(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "empty",
+			input:    "",
+			expected: "''",
+		},
+		{
+			name:     "safe characters",
+			input:    "my-app_1.0",
+			expected: "my-app_1.0",
+		},
+		{
+			name:     "spaces",
+			input:    "my app",
+			expected: "'my app'",
+		},
+		{
+			name:     "double quotes",
+			input:    `my"app`,
+			expected: `'my"app'`,
+		},
+		{
+			name:     "single quotes",
+			input:    "my'app",
+			expected: `'my'"'"'app'`,
+		},
+		{
+			name:     "backslashes",
+			input:    `my\app`,
+			expected: `'my\app'`,
+		},
+		{
+			name:     "dollar signs",
+			input:    "$HOME",
+			expected: "'$HOME'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, shellEscape(tt.input))
+		})
+	}
+}
+
 func TestCreateDockerfile(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
 	wdCtrl := filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/"))
 	fs := afero.NewMemMapFs()
 	type config struct {
@@ -319,7 +540,7 @@ func TestCreateDockerfile(t *testing.T) {
 				opts: &Options{},
 			},
 			expected: expected{
-				dockerfileName: filepath.Clean("/test/deploy"),
+				dockerfileName: filepath.Join(filepath.Clean("/test"), dockerfileTemporalName),
 			},
 			actionNameValue: "test",
 		},
@@ -337,12 +558,12 @@ func TestCreateDockerfile(t *testing.T) {
 			t.Setenv(model.OktetoActionNameEnvVar, tt.actionNameValue)
 			dockerfileName, err := rdc.createDockerfile("/test", tt.config.opts, "")
 			assert.ErrorIs(t, err, tt.expected.err)
-			assert.Equal(t, tt.expected.dockerfileName, dockerfileName)
+			assert.True(t, strings.HasPrefix(dockerfileName, tt.expected.dockerfileName))
 
 			if tt.expected.err == nil {
-				_, err = rdc.fs.Stat(filepath.Join("/test", dockerfileTemporalNane))
+				_, err = rdc.fs.Stat(dockerfileName)
 				assert.NoError(t, err)
-				content, _ := afero.ReadFile(rdc.fs, filepath.Join("/test", dockerfileTemporalNane))
+				content, _ := afero.ReadFile(rdc.fs, dockerfileName)
 				assert.True(t, strings.Contains(string(content), fmt.Sprintf("ENV %s %s", model.OktetoActionNameEnvVar, tt.actionNameValue)))
 			}
 
@@ -350,80 +571,1002 @@ func TestCreateDockerfile(t *testing.T) {
 	}
 }
 
-func TestCreateDockerignoreIfNeeded(t *testing.T) {
+func TestCreateDockerfileDoesNotContainToken(t *testing.T) {
+	token := "my-secret-okteto-token"
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     token,
+			},
+		},
+		CurrentContext: "test",
+	}
 	fs := afero.NewMemMapFs()
+	rdc := remoteDestroyCommand{
+		fs:                   fs,
+		destroyImage:         "test-image",
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		registry:             newFakeRegistry(),
+	}
 
-	dockerignoreWd := "/test/"
-	assert.NoError(t, fs.MkdirAll(dockerignoreWd, 0755))
-	assert.NoError(t, afero.WriteFile(fs, "/test/.oktetodeployignore", []byte("FROM alpine"), 0644))
-	type config struct {
-		wd string
+	dockerfileName, err := rdc.createDockerfile("/test", &Options{Name: "test"}, "installer")
+	require.NoError(t, err)
+
+	content, err := afero.ReadFile(fs, dockerfileName)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), token)
+	assert.Contains(t, string(content), fmt.Sprintf("RUN --mount=type=secret,id=%s", tokenSecretID))
+
+	tokenFile, err := rdc.createTokenSecretFile("/test")
+	require.NoError(t, err)
+	tokenContent, err := afero.ReadFile(fs, tokenFile)
+	require.NoError(t, err)
+	assert.Equal(t, token, string(tokenContent))
+}
+
+func TestCreateDockerfileWithoutToken(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+			},
+		},
+		CurrentContext: "test",
+	}
+	rdc := remoteDestroyCommand{
+		fs:                   afero.NewMemMapFs(),
+		destroyImage:         "test-image",
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		registry:             newFakeRegistry(),
+	}
+
+	_, err := rdc.createDockerfile("/test", &Options{}, "installer")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "OKTETO_TOKEN must be set")
+}
+
+func TestGetCacheKey(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	manifestPath := filepath.Clean("/test/okteto.yml")
+	require.NoError(t, afero.WriteFile(fs, manifestPath, []byte("destroy:\n- echo hello"), 0600))
+
+	rdc := remoteDestroyCommand{
+		fs: fs,
+		manifest: &model.Manifest{
+			Destroy: &model.DestroyInfo{
+				Commands: []model.DeployCommand{{Name: "hello", Command: "echo hello"}},
+			},
+		},
 	}
+	opts := &Options{Name: "test", ManifestPath: manifestPath}
+
+	key, err := rdc.getCacheKey("/test", opts)
+	require.NoError(t, err)
+	sameKey, err := rdc.getCacheKey("/test", opts)
+	require.NoError(t, err)
+	assert.Equal(t, key, sameKey)
+
+	require.NoError(t, afero.WriteFile(fs, manifestPath, []byte("destroy:\n- echo bye"), 0600))
+	manifestChangedKey, err := rdc.getCacheKey("/test", opts)
+	require.NoError(t, err)
+	assert.NotEqual(t, key, manifestChangedKey)
+
+	rdc.manifest.Destroy.Commands = []model.DeployCommand{{Name: "bye", Command: "echo bye"}}
+	commandsChangedKey, err := rdc.getCacheKey("/test", opts)
+	require.NoError(t, err)
+	assert.NotEqual(t, manifestChangedKey, commandsChangedKey)
+
+	noCacheKey, err := rdc.getCacheKey("/test", &Options{Name: "test", ManifestPath: manifestPath, NoCache: true})
+	require.NoError(t, err)
+	assert.NotEqual(t, commandsChangedKey, noCacheKey)
+}
+
+func TestCreateDockerfileIsDeterministic(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	fs := afero.NewMemMapFs()
+	rdc := remoteDestroyCommand{
+		fs:                   fs,
+		destroyImage:         "test-image",
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		registry:             newFakeRegistry(),
+	}
+
+	dockerfileName, err := rdc.createDockerfile("/test", &Options{Name: "test"}, "installer")
+	require.NoError(t, err)
+	first, err := afero.ReadFile(fs, dockerfileName)
+	require.NoError(t, err)
+
+	dockerfileName, err = rdc.createDockerfile("/test", &Options{Name: "test"}, "installer")
+	require.NoError(t, err)
+	second, err := afero.ReadFile(fs, dockerfileName)
+	require.NoError(t, err)
+
+	assert.Equal(t, string(first), string(second))
+	assert.Contains(t, string(first), fmt.Sprintf("ARG %s", destroyRunIDArg))
+}
+
+func TestRemoteDestroyRemovesTemporalDirectory(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+
 	var tests = []struct {
-		name   string
-		config config
+		name       string
+		builderErr error
 	}{
 		{
-			name: "dockerignore present",
-			config: config{
-				wd: dockerignoreWd,
-			},
+			name: "destroy completes",
 		},
 		{
-			name:   "without dockerignore",
-			config: config{},
+			name:       "destroy fails midway",
+			builderErr: assert.AnError,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(fs, filepath.Join("/", remote.DockerignoreName), []byte("node_modules"), 0600))
 			rdc := remoteDestroyCommand{
-				fs:       fs,
-				registry: newFakeRegistry(),
+				builder:              fakeBuilder{tt.builderErr},
+				fs:                   fs,
+				workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+				temporalCtrl:         filesystem.NewTemporalDirectoryCtrl(fs),
+				registry:             newFakeRegistry(),
+				clusterMetadata: func(ctx context.Context) (*types.ClusterMetadata, error) {
+					return &types.ClusterMetadata{Certificate: []byte("cert"), PipelineInstallerImage: "okteto/installer:1.0", PipelineRunnerImage: "okteto/runner:1.0"}, nil
+				},
+			}
+			err := rdc.destroy(context.Background(), &Options{})
+			if tt.builderErr != nil {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
 			}
-			err := rdc.createDockerignoreIfNeeded(tt.config.wd, "/temp")
+
+			var leftovers []string
+			err = afero.Walk(fs, os.TempDir(), func(path string, _ os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				if path != os.TempDir() {
+					leftovers = append(leftovers, path)
+				}
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Empty(t, leftovers)
+
+			_, err = fs.Stat(filepath.Join("/", remote.DockerignoreName))
 			assert.NoError(t, err)
 		})
 	}
 }
 
-func Test_getOktetoCLIVersion(t *testing.T) {
+// blockingBuilder blocks until the build context is done
+type blockingBuilder struct{}
+
+func (blockingBuilder) Build(ctx context.Context, _ *types.BuildOptions) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (blockingBuilder) IsV1() bool { return true }
+
+func TestRemoteDestroyTimeout(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	fs := afero.NewMemMapFs()
+	rdc := remoteDestroyCommand{
+		builder:              blockingBuilder{},
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		temporalCtrl:         filesystem.NewTemporalDirectoryCtrl(fs),
+		registry:             newFakeRegistry(),
+		clusterMetadata: func(ctx context.Context) (*types.ClusterMetadata, error) {
+			return &types.ClusterMetadata{Certificate: []byte("cert"), PipelineInstallerImage: "okteto/installer:1.0", PipelineRunnerImage: "okteto/runner:1.0"}, nil
+		},
+	}
+
+	timeout := 100 * time.Millisecond
+	start := time.Now()
+	err := rdc.destroy(context.Background(), &Options{Timeout: timeout})
+	elapsed := time.Since(start)
+
+	var userErr oktetoErrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, userErr.Hint, "--timeout")
+	assert.GreaterOrEqual(t, elapsed, timeout)
+	assert.Less(t, elapsed, 5*time.Second)
+}
+
+func TestCreateDockerfileWithWindowsWorkingDirectory(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	wd := `C:\Users\okteto\project`
+	tmpDir := `C:\Users\okteto\AppData\Local\Temp\okteto`
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(wd, remote.OktetoDockerignoreName), []byte("node_modules"), 0600))
+	rdc := remoteDestroyCommand{
+		fs:                   fs,
+		destroyImage:         "test-image",
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(wd),
+		registry:             newFakeRegistry(),
+	}
+
+	_, err := rdc.createDockerfile(tmpDir, &Options{Name: "test"}, "installer")
+	require.NoError(t, err)
+
+	content, err := afero.ReadFile(fs, filepath.Join(tmpDir, remote.DockerignoreName))
+	require.NoError(t, err)
+	assert.Equal(t, "node_modules", string(content))
+}
+
+func TestRemoteDestroyDryRun(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "secret-token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	t.Setenv(model.OktetoActionNameEnvVar, "")
+	t.Setenv(constants.OktetoGitCommitEnvVar, "")
+	t.Setenv(constants.OKtetoDeployRemoteImage, "")
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, filepath.Join("/", remote.DockerignoreName), []byte("node_modules"), 0600))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join("/", remote.OktetoDockerignoreName), []byte("!node_modules/config"), 0600))
+
+	out := &bytes.Buffer{}
+	rdc := remoteDestroyCommand{
+		// the builder fails so the test catches any call to it
+		builder:              fakeBuilder{assert.AnError},
+		destroyImage:         "okteto/destroy:1.0",
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		temporalCtrl:         filesystem.NewTemporalDirectoryCtrl(fs),
+		registry:             newFakeRegistry(),
+		clusterMetadata: func(ctx context.Context) (*types.ClusterMetadata, error) {
+			return &types.ClusterMetadata{PipelineInstallerImage: "okteto/installer:1.0"}, nil
+		},
+		environmentExists: func(context.Context, string, string) (bool, error) {
+			return true, nil
+		},
+		out: out,
+	}
+
+	err := rdc.destroy(context.Background(), &Options{Name: "movies", DryRun: true})
+	require.NoError(t, err)
+	assert.NotContains(t, out.String(), "secret-token")
+
+	golden := filepath.Join("testdata", "remote-destroy-dry-run.golden")
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(golden, out.Bytes(), 0600))
+	}
+	expected, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), out.String())
+}
+
+func TestRemoteDestroyWithPartialClusterMetadata(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	t.Setenv(constants.OKtetoDeployRemoteImage, "")
+
+	fs := afero.NewMemMapFs()
+	out := &bytes.Buffer{}
+	rdc := remoteDestroyCommand{
+		builder:              fakeBuilder{assert.AnError},
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		temporalCtrl:         filesystem.NewTemporalDirectoryCtrl(fs),
+		registry:             newFakeRegistry(),
+		clusterMetadata: func(ctx context.Context) (*types.ClusterMetadata, error) {
+			return &types.ClusterMetadata{Certificate: []byte("cert")}, nil
+		},
+		environmentExists: func(context.Context, string, string) (bool, error) {
+			return true, nil
+		},
+		out: out,
+	}
+
+	err := rdc.destroy(context.Background(), &Options{Name: "movies", DryRun: true})
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), fmt.Sprintf("FROM %s as installer", constants.OktetoPipelineInstallerImage))
+	assert.Contains(t, out.String(), fmt.Sprintf("FROM %s as deploy", constants.OktetoPipelineRunnerImage))
+}
+
+// dockerfileRecordingBuilder keeps the options and the Dockerfile of the last build
+type dockerfileRecordingBuilder struct {
+	fs         afero.Fs
+	opts       *types.BuildOptions
+	dockerfile string
+}
+
+func (b *dockerfileRecordingBuilder) Build(_ context.Context, opts *types.BuildOptions) error {
+	b.opts = opts
+	dockerfile, err := afero.ReadFile(b.fs, opts.File)
+	if err != nil {
+		return err
+	}
+	b.dockerfile = string(dockerfile)
+	return nil
+}
+
+func (*dockerfileRecordingBuilder) IsV1() bool { return true }
+
+func TestRemoteDestroyWithDependencies(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"https://okteto.example.com": {
+				Name:      "https://okteto.example.com",
+				Namespace: "test",
+				Token:     "secret-token",
+				IsOkteto:  true,
+			},
+		},
+		CurrentContext: "https://okteto.example.com",
+	}
+
+	fs := afero.NewMemMapFs()
+	b := &dockerfileRecordingBuilder{fs: fs}
+	rdc := remoteDestroyCommand{
+		builder: b,
+		manifest: &model.Manifest{
+			Dependencies: model.ManifestDependencies{
+				"api": &model.Dependency{
+					Repository: "https://github.com/okteto/api",
+				},
+			},
+		},
+		destroyImage:         "okteto/destroy:1.0",
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		temporalCtrl:         filesystem.NewTemporalDirectoryCtrl(fs),
+		registry:             newFakeRegistry(),
+		clusterMetadata: func(ctx context.Context) (*types.ClusterMetadata, error) {
+			return &types.ClusterMetadata{Certificate: []byte("cert"), PipelineInstallerImage: "okteto/installer:1.0"}, nil
+		},
+		environmentExists: func(context.Context, string, string) (bool, error) {
+			return true, nil
+		},
+	}
+
+	err := rdc.destroy(context.Background(), &Options{Name: "movies", DestroyDependencies: true})
+	require.NoError(t, err)
+	require.NotNil(t, b.opts)
+
+	assert.Contains(t, b.dockerfile, `--server-name="$INTERNAL_SERVER_NAME" --name movies --dependencies`)
+	assert.Contains(t, b.dockerfile, fmt.Sprintf("ENV %s https://okteto.example.com", model.OktetoContextEnvVar))
+	assert.Contains(t, b.dockerfile, fmt.Sprintf("ENV %s test", model.OktetoNamespaceEnvVar))
+	assert.Contains(t, b.dockerfile, fmt.Sprintf("export %s=\"$(cat /run/secrets/%s)\"", model.OktetoTokenEnvVar, tokenSecretID))
+	assert.Contains(t, b.opts.Secrets, fmt.Sprintf("id=%s,src=%s", tokenSecretID, filepath.Join(filepath.Dir(b.opts.File), tokenSecretFileName)))
+	assert.NotContains(t, b.dockerfile, "secret-token")
+}
+
+func TestRemoteDestroyWithClusterMetadataOverride(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+
+	fs := afero.NewMemMapFs()
+	b := &recordingBuilder{}
+	rdc := remoteDestroyCommand{
+		builder:              b,
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		temporalCtrl:         filesystem.NewTemporalDirectoryCtrl(fs),
+		registry:             newFakeRegistry(),
+		// the api is never called when the cluster metadata is overridden
+		clusterMetadata: func(context.Context) (*types.ClusterMetadata, error) {
+			return nil, assert.AnError
+		},
+		environmentExists: func(context.Context, string, string) (bool, error) {
+			return true, nil
+		},
+	}
+
+	opts := &Options{
+		Name: "test",
+		ClusterMetadataOverride: &types.ClusterMetadata{
+			Certificate:            []byte("cert"),
+			ServerName:             "1.1.1.1",
+			PipelineInstallerImage: "okteto/installer:1.0",
+			PipelineRunnerImage:    "okteto/runner:1.0",
+		},
+	}
+	require.NoError(t, rdc.destroy(context.Background(), opts))
+	require.NotNil(t, b.opts)
+	assert.Contains(t, b.opts.BuildArgs, "INTERNAL_SERVER_NAME=1.1.1.1")
+	assert.Contains(t, b.opts.BuildArgs, fmt.Sprintf("OKTETO_TLS_CERT_BASE64=%s", base64.StdEncoding.EncodeToString([]byte("cert"))))
+}
+
+func TestRemoteDestroyWithPartialClusterMetadataFromAPI(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	t.Setenv(constants.OKtetoDeployRemoteImage, "")
+
+	// the metadata goes through the same client call as the api, not through ClusterMetadataOverride
+	provider := client.NewFakeOktetoClientProvider(&client.FakeOktetoClient{
+		Users: client.NewFakeUsersClientWithClusterMetadata(types.ClusterMetadata{
+			Certificate:            []byte("cert"),
+			PipelineInstallerImage: "okteto/installer:1.0",
+		}, nil),
+	})
+
+	fs := afero.NewMemMapFs()
+	b := &dockerfileRecordingBuilder{fs: fs}
+	rdc := remoteDestroyCommand{
+		builder:              b,
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		temporalCtrl:         filesystem.NewTemporalDirectoryCtrl(fs),
+		registry:             newFakeRegistry(),
+		clusterMetadata: func(ctx context.Context) (*types.ClusterMetadata, error) {
+			return utils.GetClusterMetadata(ctx, provider, "test", "test")
+		},
+		environmentExists: func(context.Context, string, string) (bool, error) {
+			return true, nil
+		},
+	}
+
+	require.NoError(t, rdc.destroy(context.Background(), &Options{Name: "test"}))
+	require.NotNil(t, b.opts)
+	assert.Contains(t, b.dockerfile, "okteto/installer:1.0 as installer")
+	assert.Contains(t, b.dockerfile, fmt.Sprintf("FROM %s as deploy", constants.OktetoPipelineRunnerImage))
+}
+
+func TestRemoteDestroyWithInvalidClusterMetadataImage(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	b := &recordingBuilder{}
+	rdc := remoteDestroyCommand{
+		builder:              b,
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		temporalCtrl:         filesystem.NewTemporalDirectoryCtrl(fs),
+		registry:             newFakeRegistry(),
+		clusterMetadata: func(context.Context) (*types.ClusterMetadata, error) {
+			return &types.ClusterMetadata{PipelineInstallerImage: "okteto/installer:not a tag"}, nil
+		},
+		environmentExists: func(context.Context, string, string) (bool, error) {
+			return true, nil
+		},
+	}
+
+	err := rdc.destroy(context.Background(), &Options{Name: "test"})
+	assert.ErrorAs(t, err, &oktetoErrors.UserError{})
+	assert.ErrorContains(t, err, "pipelineInstallerImage")
+	assert.Nil(t, b.opts)
+}
+
+// digestRegistry resolves every image to a fixed digest
+type digestRegistry struct {
+	fakeRegistry
+	digest string
+}
+
+func (r digestRegistry) GetImageTagWithDigest(imageTag string) (string, error) {
+	return fmt.Sprintf("%s@%s", imageTag, r.digest), nil
+}
+
+func TestRemoteDestroyReportsBaseImageDigest(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	fs := afero.NewMemMapFs()
+	rdc := remoteDestroyCommand{
+		builder:              fakeBuilder{},
+		destroyImage:         "okteto/destroy:1.0",
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		temporalCtrl:         filesystem.NewTemporalDirectoryCtrl(fs),
+		registry:             digestRegistry{fakeRegistry: newFakeRegistry(), digest: "sha256:1234"},
+		clusterMetadata: func(context.Context) (*types.ClusterMetadata, error) {
+			return &types.ClusterMetadata{Certificate: []byte("cert"), PipelineInstallerImage: "okteto/installer:1.0"}, nil
+		},
+		environmentExists: func(context.Context, string, string) (bool, error) {
+			return true, nil
+		},
+	}
+	opts := &Options{Name: "test", result: newResultRecorder(time.Now)}
+	require.NoError(t, rdc.destroy(context.Background(), opts))
+	assert.Equal(t, "sha256:1234", opts.result.artifact().BaseImageDigest)
+	assert.Equal(t, "sha256:1234", opts.result.result(opts, nil, nil).BaseImageDigest)
+}
+
+func TestRemoteDestroyWithoutImageDigest(t *testing.T) {
+	rdc := remoteDestroyCommand{
+		destroyImage: "okteto/destroy:1.0",
+		registry:     newFakeRegistry(),
+	}
+	result := newResultRecorder(time.Now)
+	rdc.reportBaseImageDigest(result)
+	assert.Empty(t, result.artifact().BaseImageDigest)
+
+	// the result is only recorded with '--output' or '--output-file'
+	rdc.reportBaseImageDigest(nil)
+}
+
+func TestManifestHasVolumes(t *testing.T) {
 	var tests = []struct {
-		name                                 string
-		versionString, expected, cliImageEnv string
+		manifest *model.Manifest
+		name     string
+		expected bool
 	}{
 		{
-			name:          "no version string and no env return latest",
-			versionString: "",
-			expected:      "okteto/okteto:latest",
+			name: "nil manifest",
 		},
 		{
-			name:          "no version string return env value",
-			versionString: "",
-			cliImageEnv:   "okteto/remote:test",
-			expected:      "okteto/remote:test",
+			name:     "no volumes",
+			manifest: &model.Manifest{Destroy: &model.DestroyInfo{}},
 		},
 		{
-			name:          "found version string",
-			versionString: "2.2.2",
-			expected:      "okteto/okteto:2.2.2",
+			name: "compose volumes",
+			manifest: &model.Manifest{
+				Deploy: &model.DeployInfo{
+					ComposeSection: &model.ComposeSectionInfo{
+						Stack: &model.Stack{
+							Volumes: map[string]*model.VolumeSpec{"data": {}},
+						},
+					},
+				},
+			},
+			expected: true,
 		},
 		{
-			name:          "found incorrect version string return latest ",
-			versionString: "2.a.2",
-			expected:      "okteto/okteto:latest",
+			name: "compose service volumes",
+			manifest: &model.Manifest{
+				Deploy: &model.DeployInfo{
+					ComposeSection: &model.ComposeSectionInfo{
+						Stack: &model.Stack{
+							Services: model.ComposeServices{
+								"db": {Volumes: []model.StackVolume{{RemotePath: "/data"}}},
+							},
+						},
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "dev persistent volume",
+			manifest: &model.Manifest{
+				Dev: model.ManifestDevs{
+					"api": {PersistentVolumeInfo: &model.PersistentVolumeInfo{Enabled: true}},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "dev persistent volume disabled",
+			manifest: &model.Manifest{
+				Dev: model.ManifestDevs{
+					"api": {PersistentVolumeInfo: &model.PersistentVolumeInfo{Enabled: false}},
+				},
+			},
 		},
 	}
-
 	for _, tt := range tests {
-		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			if tt.cliImageEnv != "" {
-				t.Setenv(constants.OKtetoDeployRemoteImage, tt.cliImageEnv)
-			}
+			assert.Equal(t, tt.expected, manifestHasVolumes(tt.manifest))
+		})
+	}
+}
 
-			version := getOktetoCLIVersion(tt.versionString)
-			require.Equal(t, version, tt.expected)
+func TestGetRemoteLogOutput(t *testing.T) {
+	var tests = []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{
+			name:     "default",
+			expected: "json",
+		},
+		{
+			name:     "json",
+			output:   "json",
+			expected: "json",
+		},
+		{
+			name:     "plain",
+			output:   "plain",
+			expected: "plain",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, getRemoteLogOutput(&Options{RemoteLogOutput: tt.output}))
 		})
 	}
+	assert.Equal(t, "plain", getRemoteLogOutput(&Options{RemoteLogOutput: "json", LocalBuild: true}))
+}
+
+func TestCreateDockerfileWithIncludedFiles(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	fs := afero.NewMemMapFs()
+	included := filepath.FromSlash("/repo/shared/okteto-destroy.yml")
+	require.NoError(t, afero.WriteFile(fs, included, []byte("- helm uninstall app"), 0600))
+	rdc := remoteDestroyCommand{
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.FromSlash("/repo/app")),
+		manifest:             &model.Manifest{IncludedFiles: []string{included}},
+	}
+
+	dockerfileName, err := rdc.createDockerfile("/test", &Options{Name: "test"}, "installer")
+	require.NoError(t, err)
+	content, err := afero.ReadFile(fs, dockerfileName)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "WORKDIR /okteto/src\nARG OKTETO_INCLUDED_FILE_0\nRUN mkdir -p /okteto/shared && echo \"$OKTETO_INCLUDED_FILE_0\" | base64 -d > /okteto/shared/okteto-destroy.yml\n")
+}
+
+func TestCreateDockerfileConcurrently(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	fs := afero.NewMemMapFs()
+	rdc := remoteDestroyCommand{
+		fs:                   fs,
+		destroyImage:         "test-image",
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		registry:             newFakeRegistry(),
+	}
+
+	names := make([]string, 2)
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+	for i := range names {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			names[i], errs[i] = rdc.createDockerfile("/test", &Options{}, "")
+		}(i)
+	}
+	wg.Wait()
+
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+	assert.NotEqual(t, names[0], names[1])
+	for _, name := range names {
+		content, err := afero.ReadFile(fs, name)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "FROM test-image")
+	}
+}
+
+// fakeRandomSource always returns the same number
+type fakeRandomSource struct {
+	value int64
+	err   error
+}
+
+func (f fakeRandomSource) Int(_ *big.Int) (*big.Int, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return big.NewInt(f.value), nil
+}
+
+func TestCreateDockerfileWithRandomSource(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	rdc := remoteDestroyCommand{
+		fs:                   afero.NewMemMapFs(),
+		destroyImage:         "test-image",
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		registry:             newFakeRegistry(),
+		random:               fakeRandomSource{value: 42},
+	}
+	opts := &Options{NoCache: true}
+
+	dockerfileName, err := rdc.createDockerfile("/test", opts, "")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(filepath.Clean("/test"), "destroy-dockerfile-42"), dockerfileName)
+	content, err := afero.ReadFile(rdc.fs, dockerfileName)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "ENV OKTETO_INVALIDATE_CACHE 42")
+
+	sameDockerfileName, err := rdc.createDockerfile("/test", opts, "")
+	require.NoError(t, err)
+	assert.Equal(t, dockerfileName, sameDockerfileName)
+	sameContent, err := afero.ReadFile(rdc.fs, sameDockerfileName)
+	require.NoError(t, err)
+	assert.Equal(t, string(content), string(sameContent))
+
+	rdc.random = fakeRandomSource{err: assert.AnError}
+	_, err = rdc.createDockerfile("/test", opts, "")
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+const maxDockerfileRenderDuration = time.Millisecond
+
+func TestCreateDockerfileWithInsecureSkipTLSVerify(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	fs := afero.NewMemMapFs()
+	rdc := remoteDestroyCommand{
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+	}
+
+	dockerfileName, err := rdc.createDockerfile("/test", &Options{Name: "test"}, "installer")
+	require.NoError(t, err)
+	content, err := afero.ReadFile(fs, dockerfileName)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), constants.OktetoTLSVerifyEnvVar)
+
+	dockerfileName, err = rdc.createDockerfile("/test", &Options{Name: "test", InsecureSkipTLSVerify: true}, "installer")
+	require.NoError(t, err)
+	content, err = afero.ReadFile(fs, dockerfileName)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "ENV OKTETO_TLS_VERIFY=false\nRUN --mount=type=secret")
+}
+
+func TestCreateDockerfileWithImpersonation(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	fs := afero.NewMemMapFs()
+	rdc := remoteDestroyCommand{
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+	}
+
+	dockerfileName, err := rdc.createDockerfile("/test", &Options{Name: "test"}, "installer")
+	require.NoError(t, err)
+	content, err := afero.ReadFile(fs, dockerfileName)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), impersonateUserArg)
+
+	dockerfileName, err = rdc.createDockerfile("/test", &Options{Name: "test", ImpersonateUser: "alice"}, "installer")
+	require.NoError(t, err)
+	content, err = afero.ReadFile(fs, dockerfileName)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "ARG KUBE_IMPERSONATE_USER\nARG KUBE_IMPERSONATE_GROUP\nRUN --mount=type=secret")
+	assert.NotContains(t, string(content), "alice")
+}
+
+func TestCreateDockerfileWithBuildArgs(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	fs := afero.NewMemMapFs()
+	rdc := remoteDestroyCommand{
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+	}
+
+	dockerfileName, err := rdc.createDockerfile("/test", &Options{Name: "test", BuildArgs: []string{"QUERY=a=b c", "REGION=eu"}}, "installer")
+	require.NoError(t, err)
+	content, err := afero.ReadFile(fs, dockerfileName)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "ARG QUERY\nENV QUERY=\"$QUERY\"\nARG REGION\nENV REGION=\"$REGION\"\nRUN --mount=type=secret")
+	assert.NotContains(t, string(content), "a=b c")
+
+	_, err = rdc.createDockerfile("/test", &Options{Name: "test", BuildArgs: []string{"INTERNAL_SERVER_NAME=evil"}}, "installer")
+	require.ErrorAs(t, err, &oktetoErrors.UserError{})
+}
+
+func TestRemoteDestroyWithBuildArgs(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+
+	fs := afero.NewMemMapFs()
+	b := &recordingBuilder{}
+	rdc := remoteDestroyCommand{
+		builder:              b,
+		destroyImage:         "okteto/destroy:1.0",
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		temporalCtrl:         filesystem.NewTemporalDirectoryCtrl(fs),
+		registry:             newFakeRegistry(),
+		clusterMetadata: func(context.Context) (*types.ClusterMetadata, error) {
+			return &types.ClusterMetadata{Certificate: []byte("cert"), ServerName: "server", PipelineInstallerImage: "okteto/installer:1.0"}, nil
+		},
+	}
+
+	err := rdc.destroy(context.Background(), &Options{BuildArgs: []string{"QUERY=a=b c"}})
+	require.NoError(t, err)
+	require.NotNil(t, b.opts)
+	assert.Contains(t, b.opts.BuildArgs, "QUERY=a=b c")
+	assert.Contains(t, b.opts.BuildArgs, "INTERNAL_SERVER_NAME=server")
+}
+
+func TestCreateDockerfileWithPreferImageCLI(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	fs := afero.NewMemMapFs()
+	rdc := remoteDestroyCommand{
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+	}
+	cliCheck := `echo "okteto-cli-check: $(command -v okteto) $(okteto version)" && \` + "\n  okteto destroy"
+
+	dockerfileName, err := rdc.createDockerfile("/test", &Options{Name: "test"}, "installer")
+	require.NoError(t, err)
+	content, err := afero.ReadFile(fs, dockerfileName)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "COPY --from=okteto-cli /usr/local/bin/* /okteto/bin/")
+	assert.Contains(t, string(content), cliCheck)
+
+	dockerfileName, err = rdc.createDockerfile("/test", &Options{Name: "test", PreferImageCLI: true}, "installer")
+	require.NoError(t, err)
+	content, err = afero.ReadFile(fs, dockerfileName)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "COPY --from=okteto-cli")
+	assert.Contains(t, string(content), "COPY --from=installer /app/bin/* /okteto/bin/\n\n")
+	assert.Contains(t, string(content), cliCheck)
+}
+
+func TestCreateDockerfileWithExtraDockerfile(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	fs := afero.NewMemMapFs()
+	rdc := remoteDestroyCommand{
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+		destroyImage:         "okteto/destroy:1.0",
+		manifest:             &model.Manifest{Destroy: &model.DestroyInfo{}},
+	}
+
+	dockerfileName, err := rdc.createDockerfile("/test", &Options{Name: "test"}, "installer")
+	require.NoError(t, err)
+	content, err := afero.ReadFile(fs, dockerfileName)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "FROM okteto/destroy:1.0 as deploy\n\nENV PATH")
+
+	// the fragment is inserted verbatim, template actions are not evaluated
+	rdc.manifest.Destroy.ExtraDockerfile = "RUN apt-get update && apt-get install -y awscli\nRUN echo '{{ .TokenValue }}'"
+	dockerfileName, err = rdc.createDockerfile("/test", &Options{Name: "test"}, "installer")
+	require.NoError(t, err)
+	content, err = afero.ReadFile(fs, dockerfileName)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "FROM okteto/destroy:1.0 as deploy\n\nRUN apt-get update && apt-get install -y awscli\nRUN echo '{{ .TokenValue }}'\n\nENV PATH")
+	assert.NotContains(t, string(content), "RUN echo 'token'")
+}
+
+func TestValidateExtraDockerfile(t *testing.T) {
+	assert.NoError(t, validateExtraDockerfile(""))
+	assert.NoError(t, validateExtraDockerfile("RUN apt-get install -y awscli\nENV PLATFORM=aws"))
+	assert.Error(t, validateExtraDockerfile("RUN apt-get install -y awscli\n  from alpine"))
 }
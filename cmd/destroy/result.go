@@ -0,0 +1,227 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/spf13/afero"
+)
+
+const (
+	// jsonOutput is the value of the '--output' flag that prints the result document
+	jsonOutput = "json"
+
+	// resultSchemaVersion is the version of the result document. Bump it on breaking changes
+	resultSchemaVersion = "v1"
+
+	localMode  = "local"
+	remoteMode = "remote"
+
+	volumeKind = "PersistentVolumeClaim"
+)
+
+// Error codes of the result document
+const (
+	resultErrorTimeout  = "timeout"
+	resultErrorCanceled = "canceled"
+	resultErrorUser     = "user_error"
+	resultErrorInternal = "internal_error"
+)
+
+// Result is the document printed to stdout when destroy runs with '--output json'.
+// ResourcesDeleted and VolumesDeleted are only known when destroying locally.
+// BaseImageDigest is the digest of the base image of the remote destroy: the image built on top of it is never pushed
+type Result struct {
+	SchemaVersion    string         `json:"schemaVersion"`
+	Name             string         `json:"name"`
+	Namespace        string         `json:"namespace"`
+	Mode             string         `json:"mode"`
+	DurationSeconds  float64        `json:"durationSeconds"`
+	ResourcesDeleted map[string]int `json:"resourcesDeleted"`
+	VolumesDeleted   []string       `json:"volumesDeleted"`
+	Warnings         []string       `json:"warnings"`
+	BaseImageDigest  string         `json:"baseImageDigest,omitempty"`
+	Error            *ResultError   `json:"error,omitempty"`
+}
+
+// DestroyArtifact is the document written to the file of the '--output-file' flag when destroy succeeds.
+// ResourcesDeleted and PVCsDeleted are only known when destroying locally, BaseImageDigest when destroying in remote.
+// Duration is in nanoseconds
+type DestroyArtifact struct {
+	ResourcesDeleted int           `json:"resourcesDeleted"`
+	PVCsDeleted      int           `json:"pvcsDeleted"`
+	ImagesDeleted    []string      `json:"imagesDeleted"`
+	BaseImageDigest  string        `json:"baseImageDigest,omitempty"`
+	Duration         time.Duration `json:"duration"`
+}
+
+// ResultError describes why destroy failed
+type ResultError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// resultRecorder collects the result of a destroy. Its methods are safe to call on a nil recorder
+type resultRecorder struct {
+	start   time.Time
+	now     func() time.Time
+	deleted map[string]int
+	mode    string
+	volumes []string
+	digest  string
+	mu      sync.Mutex
+}
+
+func newResultRecorder(now func() time.Time) *resultRecorder {
+	return &resultRecorder{
+		start:   now(),
+		now:     now,
+		deleted: map[string]int{},
+	}
+}
+
+func (r *resultRecorder) setMode(mode string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mode = mode
+}
+
+// setBaseImageDigest records the digest of the base image of the remote destroy
+func (r *resultRecorder) setBaseImageDigest(digest string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.digest = digest
+}
+
+// onDeleted records a deleted resource. It is called concurrently by the namespace destroyer
+func (r *resultRecorder) onDeleted(kind, name string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deleted[kind]++
+	if kind == volumeKind {
+		r.volumes = append(r.volumes, name)
+	}
+}
+
+func (r *resultRecorder) result(opts *Options, warnings []string, err error) Result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := make(map[string]int, len(r.deleted))
+	for kind, count := range r.deleted {
+		deleted[kind] = count
+	}
+	volumes := append([]string{}, r.volumes...)
+	sort.Strings(volumes)
+	if warnings == nil {
+		warnings = []string{}
+	}
+
+	return Result{
+		SchemaVersion:    resultSchemaVersion,
+		Name:             opts.Name,
+		Namespace:        opts.Namespace,
+		Mode:             r.mode,
+		DurationSeconds:  r.now().Sub(r.start).Round(time.Millisecond).Seconds(),
+		ResourcesDeleted: deleted,
+		VolumesDeleted:   volumes,
+		Warnings:         warnings,
+		BaseImageDigest:  r.digest,
+		Error:            newResultError(err),
+	}
+}
+
+// write prints the result document of the destroy to w
+func (r *resultRecorder) write(w io.Writer, opts *Options, err error) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if errEncode := encoder.Encode(r.result(opts, oktetoLog.GetWarnings(), err)); errEncode != nil {
+		return fmt.Errorf("failed to print the destroy result: %w", errEncode)
+	}
+	return nil
+}
+
+func (r *resultRecorder) artifact() DestroyArtifact {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	total := 0
+	for _, count := range r.deleted {
+		total += count
+	}
+	return DestroyArtifact{
+		ResourcesDeleted: total,
+		PVCsDeleted:      r.deleted[volumeKind],
+		// destroy never deletes images
+		ImagesDeleted:   []string{},
+		BaseImageDigest: r.digest,
+		Duration:        r.now().Sub(r.start),
+	}
+}
+
+// writeArtifact writes the artifact of the destroy to path
+func (r *resultRecorder) writeArtifact(fs afero.Fs, path string) error {
+	content, err := json.MarshalIndent(r.artifact(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the destroy artifact: %w", err)
+	}
+	if err := afero.WriteFile(fs, path, append(content, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write the destroy artifact to '%s': %w", path, err)
+	}
+	return nil
+}
+
+func newResultError(err error) *ResultError {
+	if err == nil {
+		return nil
+	}
+	result := &ResultError{
+		Code:    resultErrorInternal,
+		Message: err.Error(),
+	}
+	var userErr oktetoErrors.UserError
+	isUserErr := errors.As(err, &userErr)
+	if isUserErr {
+		result.Hint = userErr.Hint
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(err, oktetoErrors.ErrTimeout):
+		result.Code = resultErrorTimeout
+	case errors.Is(err, context.Canceled):
+		result.Code = resultErrorCanceled
+	case isUserErr:
+		result.Code = resultErrorUser
+	}
+	return result
+}
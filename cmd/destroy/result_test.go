@@ -0,0 +1,214 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/okteto/okteto/internal/test"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/k8s/namespaces"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+type deletedResource struct {
+	kind string
+	name string
+}
+
+// recordingNsDestroyer reports the resources it deletes through the OnDeleted callback
+type recordingNsDestroyer struct {
+	resources []deletedResource
+	volumes   []deletedResource
+}
+
+func (d *recordingNsDestroyer) DestroyWithLabel(_ context.Context, _ string, opts namespaces.DeleteAllOptions) error {
+	for _, r := range d.resources {
+		opts.OnDeleted(r.kind, r.name)
+	}
+	return nil
+}
+
+func (d *recordingNsDestroyer) DestroySFSVolumes(_ context.Context, _ string, opts namespaces.DeleteAllOptions) error {
+	for _, r := range d.volumes {
+		opts.OnDeleted(r.kind, r.name)
+	}
+	return nil
+}
+
+// newFakeClock returns a clock that advances one and a half seconds on every call
+func newFakeClock() func() time.Time {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		current := now
+		now = now.Add(1500 * time.Millisecond)
+		return current
+	}
+}
+
+func TestDestroyResultDocument(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Namespace: "test",
+			},
+		},
+		CurrentContext: "test",
+	}
+	nsDestroyer := &recordingNsDestroyer{
+		resources: []deletedResource{
+			{kind: "Deployment", name: "api"},
+			{kind: "Deployment", name: "frontend"},
+			{kind: "Service", name: "api"},
+			{kind: "PersistentVolumeClaim", name: "data-db-0"},
+		},
+		volumes: []deletedResource{
+			{kind: "PersistentVolumeClaim", name: "cache-redis-0"},
+		},
+	}
+
+	var tests = []struct {
+		executorErr error
+		name        string
+		golden      string
+	}{
+		{
+			name:   "success",
+			golden: "destroy-result-success.golden",
+		},
+		{
+			name:        "failure",
+			executorErr: errors.New("exit status 1"),
+			golden:      "destroy-result-failure.golden",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClientProvider := test.NewFakeK8sProvider()
+			fakeClient, _, err := k8sClientProvider.Provide(api.NewConfig())
+			require.NoError(t, err)
+
+			opts := &Options{
+				Name:           "test-app",
+				Namespace:      "test",
+				DestroyVolumes: true,
+				result:         newResultRecorder(newFakeClock()),
+			}
+			opts.result.setMode(localMode)
+			ld := localDestroyCommand{
+				&localDestroyAllCommand{
+					ConfigMapHandler:  NewConfigmapHandler(fakeClient),
+					nsDestroyer:       nsDestroyer,
+					executor:          &fakeExecutor{err: tt.executorErr},
+					k8sClientProvider: k8sClientProvider,
+					secrets:           &fakeSecretHandler{secrets: []v1.Secret{}},
+				},
+				fakeManifest,
+			}
+
+			oktetoLog.RecordWarnings()
+			oktetoLog.Warning("volume '%s' is still mounted", "shared")
+			err = ld.runDestroy(context.Background(), opts)
+			if tt.executorErr != nil {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			out := &bytes.Buffer{}
+			require.NoError(t, opts.result.write(out, opts, err))
+
+			golden := filepath.Join("testdata", tt.golden)
+			if *updateGolden {
+				require.NoError(t, os.WriteFile(golden, out.Bytes(), 0600))
+			}
+			expected, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), out.String())
+		})
+	}
+}
+
+func TestWriteDestroyArtifact(t *testing.T) {
+	r := newResultRecorder(newFakeClock())
+	r.onDeleted("Deployment", "api")
+	r.onDeleted("Service", "api")
+	r.onDeleted(volumeKind, "data-db-0")
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, r.writeArtifact(fs, "/artifacts/destroy.json"))
+
+	content, err := afero.ReadFile(fs, "/artifacts/destroy.json")
+	require.NoError(t, err)
+	expected := `{
+  "resourcesDeleted": 3,
+  "pvcsDeleted": 1,
+  "imagesDeleted": [],
+  "duration": 1500000000
+}
+`
+	assert.Equal(t, expected, string(content))
+}
+
+func TestNewResultError(t *testing.T) {
+	var tests = []struct {
+		err      error
+		expected *ResultError
+		name     string
+	}{
+		{
+			name: "no error",
+		},
+		{
+			name:     "timeout",
+			err:      oktetoErrors.UserError{E: fmt.Errorf("didn't finish after 1m: %w", context.DeadlineExceeded), Hint: "Increase the timeout"},
+			expected: &ResultError{Code: resultErrorTimeout, Message: "didn't finish after 1m: context deadline exceeded", Hint: "Increase the timeout"},
+		},
+		{
+			name:     "canceled",
+			err:      context.Canceled,
+			expected: &ResultError{Code: resultErrorCanceled, Message: "context canceled"},
+		},
+		{
+			name:     "user error",
+			err:      oktetoErrors.UserError{E: fmt.Errorf("invalid manifest"), Hint: "Fix it"},
+			expected: &ResultError{Code: resultErrorUser, Message: "invalid manifest", Hint: "Fix it"},
+		},
+		{
+			name:     "internal error",
+			err:      assert.AnError,
+			expected: &ResultError{Code: resultErrorInternal, Message: assert.AnError.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, newResultError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/okteto/okteto/pkg/cmd/build"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/types"
+)
+
+// defaultBuildRetries is the number of times a remote destroy build is retried after a transient failure
+const defaultBuildRetries = 3
+
+// buildRetryBackoff is the wait before the first retry of a remote destroy build, doubled on every retry
+var buildRetryBackoff = 2 * time.Second
+
+// retryableBuildErrors are the messages of the transient failures of the builder and the registry
+var retryableBuildErrors = []string{
+	"code = unavailable",
+	"connection reset by peer",
+	"transport is closing",
+	"error reading from server: eof",
+	"buildkit service is not available",
+	"500 internal server error",
+	"502 bad gateway",
+	"503 service unavailable",
+	"504 gateway timeout",
+}
+
+// isRetryableBuildError returns if the remote destroy build failed because of a transient failure of the
+// builder or the registry. The failures of the destroy commands are never retried
+func isRetryableBuildError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var cmdErr build.OktetoCommandErr
+	if errors.As(err, &cmdErr) {
+		return false
+	}
+	if errors.Is(err, errDestroyStatusUnknown) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, retryable := range retryableBuildErrors {
+		if strings.Contains(msg, retryable) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildWithRetries runs the remote destroy build and runs it again up to opts.BuildRetries times, with an exponential
+// backoff, while it fails because of transient errors. The stage of the failed attempt is kept in the warnings
+func (rd *remoteDestroyCommand) buildWithRetries(ctx context.Context, buildOptions *types.BuildOptions, opts *Options) error {
+	retries := opts.BuildRetries
+	err := rd.build(ctx, buildOptions, opts)
+	backoff := buildRetryBackoff
+	for attempt := 1; attempt <= retries && ctx.Err() == nil && isRetryableBuildError(err); attempt++ {
+		oktetoLog.Warning("The destroy build failed with a transient error: %s. Retrying in %s (retry %d/%d)", err, backoff, attempt, retries)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		err = rd.build(ctx, buildOptions, opts)
+	}
+	return err
+}
+
+// copyBuildOptions returns a copy of the options for a build attempt, as builders rewrite some of them
+func copyBuildOptions(o *types.BuildOptions) *types.BuildOptions {
+	c := *o
+	c.BuildArgs = append([]string{}, o.BuildArgs...)
+	c.CacheFrom = append([]string{}, o.CacheFrom...)
+	c.Secrets = append([]string{}, o.Secrets...)
+	c.ExportCache = append([]string{}, o.ExportCache...)
+	c.CommandArgs = append([]string{}, o.CommandArgs...)
+	return &c
+}
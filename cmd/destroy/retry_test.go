@@ -0,0 +1,176 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/okteto/okteto/pkg/cmd/build"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsRetryableBuildError(t *testing.T) {
+	var tests = []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name: "no error",
+		},
+		{
+			name:     "buildkit unavailable",
+			err:      errors.New("failed to solve: rpc error: code = Unavailable desc = connection error"),
+			expected: true,
+		},
+		{
+			name:     "connection reset",
+			err:      errors.New("read tcp 10.0.0.1:5000: read: connection reset by peer"),
+			expected: true,
+		},
+		{
+			name:     "registry bad gateway",
+			err:      oktetoErrors.UserError{E: errors.New("failed to push: unexpected status: 502 Bad Gateway")},
+			expected: true,
+		},
+		{
+			name:     "builder unavailable user error",
+			err:      oktetoErrors.UserError{E: errors.New("buildkit service is not available at the moment")},
+			expected: true,
+		},
+		{
+			name: "destroy command failure",
+			err: build.OktetoCommandErr{
+				Stage: "destroy",
+				Err:   errors.New("502 Bad Gateway"),
+			},
+		},
+		{
+			name: "invalid manifest",
+			err:  oktetoErrors.UserError{E: errors.New("your okteto manifest is not valid")},
+		},
+		{
+			name: "timeout",
+			err:  fmt.Errorf("code = Unavailable: %w", context.DeadlineExceeded),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isRetryableBuildError(tt.err))
+		})
+	}
+}
+
+// sequenceBuilder returns the errors in order, one per build, and nil when they are exhausted
+type sequenceBuilder struct {
+	errs    []error
+	secrets [][]string
+}
+
+func (b *sequenceBuilder) Build(_ context.Context, opts *types.BuildOptions) error {
+	b.secrets = append(b.secrets, append([]string{}, opts.Secrets...))
+	// builders rewrite the secrets with the path of their temporal copies
+	opts.Secrets[0] = "id=rewritten"
+	attempt := len(b.secrets) - 1
+	if attempt < len(b.errs) {
+		return b.errs[attempt]
+	}
+	return nil
+}
+
+func (*sequenceBuilder) IsV1() bool { return true }
+
+func TestBuildWithRetries(t *testing.T) {
+	backoff := buildRetryBackoff
+	buildRetryBackoff = time.Millisecond
+	defer func() { buildRetryBackoff = backoff }()
+
+	unavailable := errors.New("rpc error: code = Unavailable")
+	cmdErr := build.OktetoCommandErr{Stage: "destroy", Err: errors.New("exit status 1")}
+	var tests = []struct {
+		name             string
+		errs             []error
+		retries          int
+		expectedErr      error
+		expectedAttempts int
+	}{
+		{
+			name:             "succeeds after transient failures",
+			errs:             []error{unavailable, unavailable},
+			retries:          3,
+			expectedAttempts: 3,
+		},
+		{
+			name:             "gives up after the retries",
+			errs:             []error{unavailable, unavailable, unavailable},
+			retries:          2,
+			expectedErr:      unavailable,
+			expectedAttempts: 3,
+		},
+		{
+			name:             "destroy command failures are not retried",
+			errs:             []error{cmdErr},
+			retries:          3,
+			expectedErr:      cmdErr,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "no retries",
+			errs:             []error{unavailable},
+			expectedErr:      unavailable,
+			expectedAttempts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &sequenceBuilder{errs: tt.errs}
+			rd := remoteDestroyCommand{builder: b}
+			opts := &types.BuildOptions{Secrets: []string{"id=okteto-token,src=/tmp/token"}}
+
+			err := rd.buildWithRetries(context.Background(), opts, &Options{BuildRetries: tt.retries})
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Len(t, b.secrets, tt.expectedAttempts)
+			for _, secrets := range b.secrets {
+				assert.Equal(t, []string{"id=okteto-token,src=/tmp/token"}, secrets)
+			}
+		})
+	}
+}
+
+func TestBuildWithRetriesStopsWhenContextIsDone(t *testing.T) {
+	backoff := buildRetryBackoff
+	buildRetryBackoff = time.Hour
+	defer func() { buildRetryBackoff = backoff }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	unavailable := errors.New("rpc error: code = Unavailable")
+	b := &sequenceBuilder{errs: []error{unavailable, unavailable}}
+	rd := remoteDestroyCommand{builder: b}
+
+	err := rd.buildWithRetries(ctx, &types.BuildOptions{Secrets: []string{"id=okteto-token"}}, &Options{BuildRetries: 3})
+	require.ErrorIs(t, err, unavailable)
+	assert.Len(t, b.secrets, 1)
+}
@@ -0,0 +1,48 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"errors"
+	"fmt"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+)
+
+const (
+	sshAuthSockEnvVar = "SSH_AUTH_SOCK"
+	// defaultSSHAgentID is the id used by 'RUN --mount=type=ssh' when no id is set
+	defaultSSHAgentID = "default"
+)
+
+// sshEnabled returns if the manifest asks to forward the local ssh agent to the remote destroy
+func (rd *remoteDestroyCommand) sshEnabled() bool {
+	return rd.manifest != nil && rd.manifest.Destroy != nil && rd.manifest.Destroy.SSH
+}
+
+// getSSHAgents returns the ssh agents exposed to the remote destroy build. It fails when the forwarding
+// is enabled and there is no local ssh agent running
+func (rd *remoteDestroyCommand) getSSHAgents(lookupEnv func(string) (string, bool)) ([]string, error) {
+	if !rd.sshEnabled() {
+		return nil, nil
+	}
+	socket, ok := lookupEnv(sshAuthSockEnvVar)
+	if !ok || socket == "" {
+		return nil, oktetoErrors.UserError{
+			E:    errors.New("'destroy.ssh' is enabled but no ssh agent was detected"),
+			Hint: fmt.Sprintf("Start an ssh agent with 'eval $(ssh-agent)', add your keys with 'ssh-add' and check that %s is set", sshAuthSockEnvVar),
+		}
+	}
+	return []string{fmt.Sprintf("%s=%s", defaultSSHAgentID, socket)}, nil
+}
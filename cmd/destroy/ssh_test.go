@@ -0,0 +1,105 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destroy
+
+import (
+	"path/filepath"
+	"testing"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	filesystem "github.com/okteto/okteto/pkg/filesystem/fake"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSSHAgents(t *testing.T) {
+	withSocket := func(string) (string, bool) { return "/tmp/agent.sock", true }
+	withoutSocket := func(string) (string, bool) { return "", false }
+
+	tests := []struct {
+		name      string
+		manifest  *model.Manifest
+		lookupEnv func(string) (string, bool)
+		expected  []string
+		expectErr bool
+	}{
+		{
+			name:      "no manifest",
+			lookupEnv: withSocket,
+		},
+		{
+			name:      "disabled",
+			manifest:  &model.Manifest{Destroy: &model.DestroyInfo{}},
+			lookupEnv: withSocket,
+		},
+		{
+			name:      "enabled",
+			manifest:  &model.Manifest{Destroy: &model.DestroyInfo{SSH: true}},
+			lookupEnv: withSocket,
+			expected:  []string{"default=/tmp/agent.sock"},
+		},
+		{
+			name:      "enabled without agent",
+			manifest:  &model.Manifest{Destroy: &model.DestroyInfo{SSH: true}},
+			lookupEnv: withoutSocket,
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rd := &remoteDestroyCommand{manifest: tt.manifest}
+			agents, err := rd.getSSHAgents(tt.lookupEnv)
+			if tt.expectErr {
+				assert.ErrorAs(t, err, &oktetoErrors.UserError{})
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, agents)
+		})
+	}
+}
+
+func TestCreateDockerfileWithSSH(t *testing.T) {
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Name:      "test",
+				Namespace: "test",
+				Token:     "token",
+			},
+		},
+		CurrentContext: "test",
+	}
+	fs := afero.NewMemMapFs()
+	rdc := remoteDestroyCommand{
+		fs:                   fs,
+		workingDirectoryCtrl: filesystem.NewFakeWorkingDirectoryCtrl(filepath.Clean("/")),
+	}
+
+	dockerfileName, err := rdc.createDockerfile("/test", &Options{Name: "test"}, "installer")
+	require.NoError(t, err)
+	content, err := afero.ReadFile(fs, dockerfileName)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "type=ssh")
+
+	rdc.manifest = &model.Manifest{Destroy: &model.DestroyInfo{SSH: true}}
+	dockerfileName, err = rdc.createDockerfile("/test", &Options{Name: "test"}, "installer")
+	require.NoError(t, err)
+	content, err = afero.ReadFile(fs, dockerfileName)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "RUN --mount=type=secret,id=okteto-token --mount=type=ssh \\\n")
+}
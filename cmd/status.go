@@ -16,20 +16,30 @@ package cmd
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"text/tabwriter"
 	"time"
 
 	contextCMD "github.com/okteto/okteto/cmd/context"
 	"github.com/okteto/okteto/cmd/utils"
 	"github.com/okteto/okteto/pkg/analytics"
+	"github.com/okteto/okteto/pkg/cmd/artifacts"
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
 	"github.com/okteto/okteto/pkg/cmd/status"
 	"github.com/okteto/okteto/pkg/config"
+	"github.com/okteto/okteto/pkg/devenvironment"
 	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/k8s/configmaps"
 	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
 	"github.com/okteto/okteto/pkg/okteto"
 	"github.com/okteto/okteto/pkg/syncthing"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
 )
 
 // Status returns the status of the synchronization process
@@ -39,6 +49,8 @@ func Status() *cobra.Command {
 	var k8sContext string
 	var showInfo bool
 	var watch bool
+	var downloadArtifacts string
+	var showTrail bool
 	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Status of the synchronization process",
@@ -57,6 +69,14 @@ func Status() *cobra.Command {
 				return err
 			}
 
+			if downloadArtifacts != "" {
+				return downloadDeployArtifacts(ctx, manifest, devPath, downloadArtifacts)
+			}
+
+			if showTrail {
+				return printDeployTrail(ctx, manifest, devPath)
+			}
+
 			devName := ""
 			if len(args) == 1 {
 				devName = args[0]
@@ -105,9 +125,124 @@ func Status() *cobra.Command {
 	cmd.Flags().StringVarP(&k8sContext, "context", "c", "", "context where the up command is executing")
 	cmd.Flags().BoolVarP(&showInfo, "info", "i", false, "show syncthing links for troubleshooting the synchronization service")
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "watch for changes")
+	cmd.Flags().BoolVarP(&showTrail, "trail", "", false, "show the changes made to the cluster by the commands of the last deploy of the development environment")
+	cmd.Flags().StringVarP(&downloadArtifacts, "download-artifacts", "", "", "download the artifacts uploaded by the last deploy of the development environment to the given directory")
 	return cmd
 }
 
+// getDevEnvironmentName returns the name of the development environment of the manifest
+func getDevEnvironmentName(ctx context.Context, manifest *model.Manifest, manifestPath string, c kubernetes.Interface) (string, error) {
+	if manifest.Name != "" {
+		return manifest.Name, nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get the current working directory: %w", err)
+	}
+	return devenvironment.NewNameInferer(c).InferName(ctx, cwd, okteto.Context().Namespace, manifestPath), nil
+}
+
+func downloadDeployArtifacts(ctx context.Context, manifest *model.Manifest, manifestPath, dir string) error {
+	if !okteto.IsOkteto() {
+		return oktetoErrors.ErrContextIsNotOktetoCluster
+	}
+
+	c, _, err := okteto.NewK8sClientProvider().Provide(okteto.Context().Cfg)
+	if err != nil {
+		return err
+	}
+	name, err := getDevEnvironmentName(ctx, manifest, manifestPath, c)
+	if err != nil {
+		return err
+	}
+
+	client, err := okteto.NewArtifactsClient(okteto.Context().Name, okteto.Context().Token, okteto.Context().Namespace)
+	if err != nil {
+		return err
+	}
+	content, err := client.Download(name)
+	if err != nil {
+		return err
+	}
+	if err := artifacts.Extract(afero.NewOsFs(), content, dir); err != nil {
+		return err
+	}
+	oktetoLog.Success("Artifacts of development environment '%s' downloaded to '%s'", name, dir)
+	return nil
+}
+
+func printDeployTrail(ctx context.Context, manifest *model.Manifest, manifestPath string) error {
+	c, _, err := okteto.NewK8sClientProvider().Provide(okteto.Context().Cfg)
+	if err != nil {
+		return err
+	}
+	name, err := getDevEnvironmentName(ctx, manifest, manifestPath, c)
+	if err != nil {
+		return err
+	}
+	cmap, err := configmaps.Get(ctx, pipeline.TranslatePipelineName(name), okteto.Context().Namespace, c)
+	if err != nil {
+		if oktetoErrors.IsNotFound(err) {
+			return oktetoErrors.UserError{
+				E:    fmt.Errorf("development environment '%s' not found in namespace '%s'", name, okteto.Context().Namespace),
+				Hint: "Deploy the development environment with 'okteto deploy' to record its trail",
+			}
+		}
+		return err
+	}
+	trail, err := pipeline.GetTrail(cmap)
+	if err != nil {
+		return err
+	}
+	if len(trail) == 0 {
+		oktetoLog.Information("There is no trail for development environment '%s'. It is recorded by 'okteto deploy' unless '--no-trail' is set", name)
+		return nil
+	}
+	return writeTrail(os.Stdout, trail, manifest)
+}
+
+// writeTrail writes the trail as a table, showing the name of the deploy command that sent each request
+func writeTrail(out io.Writer, trail []pipeline.TrailEntry, manifest *model.Manifest) error {
+	w := tabwriter.NewWriter(out, 1, 1, 2, ' ', 0)
+	fmt.Fprintf(w, "Time\tCommand\tVerb\tResource\tNamespace\tName\tBody SHA256\n")
+	for _, entry := range trail {
+		resource := entry.Resource
+		if entry.Group != "" {
+			resource = fmt.Sprintf("%s.%s", resource, entry.Group)
+		}
+		if entry.Kind != "" {
+			resource = fmt.Sprintf("%s (%s)", resource, entry.Kind)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			entry.Time.Format(time.RFC3339),
+			getTrailCommandName(entry.Command, manifest),
+			entry.Verb,
+			resource,
+			valueOrDash(entry.Namespace),
+			valueOrDash(entry.Name),
+			valueOrDash(entry.BodySHA256),
+		)
+	}
+	return w.Flush()
+}
+
+func getTrailCommandName(i int, manifest *model.Manifest) string {
+	if i < 0 {
+		return "-"
+	}
+	if manifest != nil && manifest.Deploy != nil && i < len(manifest.Deploy.Commands) {
+		return fmt.Sprintf("%d: %s", i, manifest.Deploy.Commands[i].Name)
+	}
+	return fmt.Sprintf("%d", i)
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 func runWithWatch(ctx context.Context, sy *syncthing.Syncthing) error {
 	textSpinner := "Synchronizing your files..."
 	oktetoLog.Spinner(textSpinner)
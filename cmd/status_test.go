@@ -0,0 +1,75 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTrail(t *testing.T) {
+	manifest := &model.Manifest{
+		Deploy: &model.DeployInfo{
+			Commands: []model.DeployCommand{
+				{Name: "helm upgrade", Command: "helm upgrade --install api chart"},
+			},
+		},
+	}
+	trail := []pipeline.TrailEntry{
+		{
+			Time:       time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC),
+			Verb:       "create",
+			Group:      "apps",
+			Version:    "v1",
+			Kind:       "Deployment",
+			Resource:   "deployments",
+			Namespace:  "test",
+			Name:       "api",
+			BodySHA256: "abc",
+		},
+		{
+			Time:     time.Date(2023, 5, 1, 10, 0, 1, 0, time.UTC),
+			Verb:     "delete",
+			Version:  "v1",
+			Resource: "namespaces",
+			Name:     "old",
+			Command:  -1,
+		},
+		{
+			Time:     time.Date(2023, 5, 1, 10, 0, 2, 0, time.UTC),
+			Verb:     "patch",
+			Version:  "v1",
+			Resource: "configmaps",
+			Name:     "cfg",
+			Command:  3,
+		},
+	}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, writeTrail(out, trail, manifest))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Contains(t, lines[1], "0: helm upgrade")
+	assert.Contains(t, lines[1], "deployments.apps (Deployment)")
+	assert.Contains(t, lines[1], "abc")
+	assert.Regexp(t, `2023-05-01T10:00:01Z\s+-\s+delete\s+namespaces\s+-\s+old\s+-`, lines[2])
+	assert.Regexp(t, `\s3\s+patch`, lines[3])
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/okteto/okteto/pkg/config"
+	"github.com/okteto/okteto/pkg/constants"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/types"
+)
+
+// GetClusterMetadata returns the metadata of the okteto instance used to deploy and destroy in remote.
+// Its failures are returned as user errors with hints on how to fix them
+func GetClusterMetadata(ctx context.Context, cp types.OktetoClientProvider, contextName, namespace string) (*types.ClusterMetadata, error) {
+	c, err := cp.Provide()
+	if err != nil {
+		return nil, clusterMetadataError(err, contextName, namespace)
+	}
+	uc := c.User()
+
+	metadata, err := uc.GetClusterMetadata(ctx, namespace)
+	if err != nil {
+		return nil, clusterMetadataError(err, contextName, namespace)
+	}
+
+	if metadata.Certificate == nil {
+		metadata.Certificate, err = uc.GetClusterCertificate(ctx, contextName, namespace)
+		if err != nil {
+			return nil, clusterMetadataError(err, contextName, namespace)
+		}
+	}
+
+	return &metadata, nil
+}
+
+// clusterMetadataError classifies the errors of the okteto client fetching the cluster metadata
+func clusterMetadataError(err error, contextName, namespace string) error {
+	switch {
+	case contextName == "" || errors.Is(err, oktetoErrors.ErrCtxNotSet):
+		return oktetoErrors.UserError{
+			E:    fmt.Errorf("there is no okteto context configured: %w", err),
+			Hint: "Run 'okteto context use <url>' to select the Okteto instance",
+		}
+	case oktetoErrors.IsForbidden(err) || strings.Contains(err.Error(), "token is invalid"):
+		return oktetoErrors.UserError{
+			E:    fmt.Errorf("your okteto token is invalid or has expired: %w", err),
+			Hint: fmt.Sprintf("Run 'okteto context use %s' to log in again", contextName),
+		}
+	case oktetoErrors.IsNotFound(err):
+		return oktetoErrors.UserError{
+			E:    fmt.Errorf(oktetoErrors.ErrNamespaceNotFound, namespace),
+			Hint: "Run 'okteto namespace use <namespace>' to select an existing namespace or 'okteto context update-kubeconfig' to refresh your credentials",
+		}
+	case oktetoErrors.IsTransient(err) || strings.Contains(err.Error(), "no such host"):
+		return oktetoErrors.UserError{
+			E:    fmt.Errorf("could not reach the okteto instance '%s': %w", contextName, err),
+			Hint: "Check your network connection. If you use a VPN or a proxy, verify that they allow the connection to your Okteto instance",
+		}
+	default:
+		return fmt.Errorf("failed to fetch the cluster metadata: %w", err)
+	}
+}
+
+// ResolveClusterMetadata checks that the cluster provides the images needed to render the dockerfile of a command run in remote.
+// Older Okteto instances don't define all of them, so the missing ones fall back to the default images of the CLI.
+// The runner image is only required when the image of the command is not set by the user
+func ResolveClusterMetadata(sc *types.ClusterMetadata, image, command string) (*types.ClusterMetadata, error) {
+	if sc == nil {
+		return nil, oktetoErrors.UserError{
+			E:    errors.New("the Okteto instance didn't return the cluster metadata"),
+			Hint: fmt.Sprintf("Your Okteto instance is too old to run the %s in remote. Upgrade it or run 'okteto %s --local' to %s your development environment locally", command, command, command),
+		}
+	}
+	resolved := *sc
+
+	installerImage, err := resolveClusterMetadataImage("pipelineInstallerImage", sc.PipelineInstallerImage, constants.OktetoPipelineInstallerImage, command)
+	if err != nil {
+		return nil, err
+	}
+	resolved.PipelineInstallerImage = installerImage
+
+	if image == "" {
+		runnerImage, err := resolveClusterMetadataImage("pipelineRunnerImage", sc.PipelineRunnerImage, constants.OktetoPipelineRunnerImage, command)
+		if err != nil {
+			return nil, err
+		}
+		resolved.PipelineRunnerImage = runnerImage
+	}
+	return &resolved, nil
+}
+
+// resolveClusterMetadataImage returns the image of a field of the cluster metadata, or the fallback when it's missing.
+// Invalid images fail instead of falling back, as they are set by the administrator of the Okteto instance
+func resolveClusterMetadataImage(field, image, fallback, command string) (string, error) {
+	if image == "" {
+		oktetoLog.Warning("Your Okteto instance doesn't define '%s' in its cluster metadata, using the default image '%s'. %s", field, fallback, upgradeOktetoInstanceMessage())
+		return fallback, nil
+	}
+	if _, err := name.ParseReference(image); err != nil {
+		return "", oktetoErrors.UserError{
+			E:    fmt.Errorf("the cluster metadata defines an invalid '%s': '%s' is not a valid image reference", field, image),
+			Hint: fmt.Sprintf("Ask the administrator of your Okteto instance to fix the image in its configuration or run 'okteto %s --local' to %s your development environment locally", command, command),
+		}
+	}
+	return image, nil
+}
+
+// upgradeOktetoInstanceMessage names the version of the Okteto instance that defines every field of the cluster metadata
+func upgradeOktetoInstanceMessage() string {
+	if config.VersionString == "" {
+		return "Upgrade your Okteto instance to the version matching your okteto CLI"
+	}
+	return fmt.Sprintf("Upgrade your Okteto instance to the version matching okteto CLI %s", config.VersionString)
+}
@@ -0,0 +1,226 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/okteto/okteto/internal/test/client"
+	"github.com/okteto/okteto/pkg/constants"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetClusterMetadata(t *testing.T) {
+	tests := []struct {
+		name         string
+		provider     types.OktetoClientProvider
+		contextName  string
+		expectedHint string
+		expectedErr  string
+	}{
+		{
+			name:         "no context configured",
+			provider:     client.NewFakeOktetoClientProviderWithError(oktetoErrors.ErrCtxNotSet),
+			expectedHint: "Run 'okteto context use <url>' to select the Okteto instance",
+		},
+		{
+			name:         "invalid token",
+			provider:     client.NewFakeOktetoClientProviderWithError(fmt.Errorf(oktetoErrors.ErrNotLogged, "https://okteto.dev")),
+			contextName:  "https://okteto.dev",
+			expectedHint: "Run 'okteto context use https://okteto.dev' to log in again",
+		},
+		{
+			name: "expired token",
+			provider: client.NewFakeOktetoClientProvider(&client.FakeOktetoClient{
+				Users: client.NewFakeUsersClientWithClusterMetadata(types.ClusterMetadata{}, errors.New("unauthorized")),
+			}),
+			contextName:  "https://okteto.dev",
+			expectedHint: "Run 'okteto context use https://okteto.dev' to log in again",
+		},
+		{
+			name: "namespace not found",
+			provider: client.NewFakeOktetoClientProvider(&client.FakeOktetoClient{
+				Users: client.NewFakeUsersClientWithClusterMetadata(types.ClusterMetadata{}, errors.New("namespace not found")),
+			}),
+			contextName:  "https://okteto.dev",
+			expectedHint: "Run 'okteto namespace use <namespace>' to select an existing namespace or 'okteto context update-kubeconfig' to refresh your credentials",
+		},
+		{
+			name: "network unreachable",
+			provider: client.NewFakeOktetoClientProvider(&client.FakeOktetoClient{
+				Users: client.NewFakeUsersClientWithClusterMetadata(types.ClusterMetadata{}, errors.New("dial tcp: connect: network is unreachable")),
+			}),
+			contextName:  "https://okteto.dev",
+			expectedHint: "Check your network connection. If you use a VPN or a proxy, verify that they allow the connection to your Okteto instance",
+		},
+		{
+			name: "unknown error",
+			provider: client.NewFakeOktetoClientProvider(&client.FakeOktetoClient{
+				Users: client.NewFakeUsersClientWithClusterMetadata(types.ClusterMetadata{}, errors.New("internal server error")),
+			}),
+			contextName: "https://okteto.dev",
+			expectedErr: "failed to fetch the cluster metadata: internal server error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GetClusterMetadata(context.Background(), tt.provider, tt.contextName, "test")
+			require.Error(t, err)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			var userErr oktetoErrors.UserError
+			require.ErrorAs(t, err, &userErr)
+			assert.Equal(t, tt.expectedHint, userErr.Hint)
+		})
+	}
+}
+
+func TestGetClusterMetadataCertificate(t *testing.T) {
+	provider := client.NewFakeOktetoClientProvider(&client.FakeOktetoClient{
+		Users: client.NewFakeUsersClientWithClusterMetadata(types.ClusterMetadata{Certificate: []byte("cert")}, nil),
+	})
+	metadata, err := GetClusterMetadata(context.Background(), provider, "https://okteto.dev", "test")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("cert"), metadata.Certificate)
+}
+
+func TestGetClusterMetadataPartialFallsBackToDefaultImages(t *testing.T) {
+	provider := client.NewFakeOktetoClientProvider(&client.FakeOktetoClient{
+		Users: client.NewFakeUsersClientWithClusterMetadata(types.ClusterMetadata{PipelineInstallerImage: "okteto/installer:1.0"}, nil),
+	})
+	metadata, err := GetClusterMetadata(context.Background(), provider, "https://okteto.dev", "test")
+	require.NoError(t, err)
+
+	resolved, err := ResolveClusterMetadata(metadata, "", "deploy")
+	require.NoError(t, err)
+	assert.Equal(t, "okteto/installer:1.0", resolved.PipelineInstallerImage)
+	assert.Equal(t, constants.OktetoPipelineRunnerImage, resolved.PipelineRunnerImage)
+}
+
+func TestResolveClusterMetadata(t *testing.T) {
+	tests := []struct {
+		name             string
+		metadata         *types.ClusterMetadata
+		image            string
+		expected         *types.ClusterMetadata
+		expectedWarnings []string
+		expectErr        bool
+	}{
+		{
+			name:      "no metadata",
+			expectErr: true,
+		},
+		{
+			name: "empty metadata",
+			metadata: &types.ClusterMetadata{
+				Certificate: []byte("cert"),
+			},
+			expected: &types.ClusterMetadata{
+				Certificate:            []byte("cert"),
+				PipelineInstallerImage: constants.OktetoPipelineInstallerImage,
+				PipelineRunnerImage:    constants.OktetoPipelineRunnerImage,
+			},
+			expectedWarnings: []string{"pipelineInstallerImage", "pipelineRunnerImage"},
+		},
+		{
+			name:     "empty installer image",
+			metadata: &types.ClusterMetadata{PipelineRunnerImage: "okteto/runner:1.0"},
+			expected: &types.ClusterMetadata{
+				PipelineInstallerImage: constants.OktetoPipelineInstallerImage,
+				PipelineRunnerImage:    "okteto/runner:1.0",
+			},
+			expectedWarnings: []string{"pipelineInstallerImage"},
+		},
+		{
+			name:     "empty runner image without manifest image",
+			metadata: &types.ClusterMetadata{PipelineInstallerImage: "okteto/installer:1.0"},
+			expected: &types.ClusterMetadata{
+				PipelineInstallerImage: "okteto/installer:1.0",
+				PipelineRunnerImage:    constants.OktetoPipelineRunnerImage,
+			},
+			expectedWarnings: []string{"pipelineRunnerImage"},
+		},
+		{
+			name:     "empty runner image with manifest image",
+			metadata: &types.ClusterMetadata{PipelineInstallerImage: "okteto/installer:1.0"},
+			image:    "okteto/destroy:1.0",
+			expected: &types.ClusterMetadata{PipelineInstallerImage: "okteto/installer:1.0"},
+		},
+		{
+			name: "both present",
+			metadata: &types.ClusterMetadata{
+				PipelineInstallerImage: "okteto/installer:1.0",
+				PipelineRunnerImage:    "okteto/runner:1.0",
+				ServerName:             "1.1.1.1",
+			},
+			expected: &types.ClusterMetadata{
+				PipelineInstallerImage: "okteto/installer:1.0",
+				PipelineRunnerImage:    "okteto/runner:1.0",
+				ServerName:             "1.1.1.1",
+			},
+		},
+		{
+			name: "invalid installer image",
+			metadata: &types.ClusterMetadata{
+				PipelineInstallerImage: "okteto/installer:not a tag",
+				PipelineRunnerImage:    "okteto/runner:1.0",
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid runner image",
+			metadata: &types.ClusterMetadata{
+				PipelineInstallerImage: "okteto/installer:1.0",
+				PipelineRunnerImage:    "okteto/RUNNER",
+			},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oktetoLog.RecordWarnings()
+			resolved, err := ResolveClusterMetadata(tt.metadata, tt.image, "destroy")
+			if tt.expectErr {
+				assert.ErrorAs(t, err, &oktetoErrors.UserError{})
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resolved)
+
+			warnings := oktetoLog.GetWarnings()
+			require.Len(t, warnings, len(tt.expectedWarnings))
+			for i, field := range tt.expectedWarnings {
+				assert.Contains(t, warnings[i], field)
+				assert.Contains(t, warnings[i], "Upgrade your Okteto instance")
+			}
+		})
+	}
+}
+
+func TestResolveClusterMetadataDoesNotModifyMetadata(t *testing.T) {
+	metadata := &types.ClusterMetadata{}
+	_, err := ResolveClusterMetadata(metadata, "", "destroy")
+	require.NoError(t, err)
+	assert.Empty(t, metadata.PipelineInstallerImage)
+	assert.Empty(t, metadata.PipelineRunnerImage)
+}
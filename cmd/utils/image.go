@@ -0,0 +1,32 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+)
+
+// ValidateImageReference returns a user error if the image is not a valid image reference
+func ValidateImageReference(flag, image string) error {
+	if _, err := name.ParseReference(image); err != nil {
+		return oktetoErrors.UserError{
+			E:    fmt.Errorf("invalid value for '--%s': '%s' is not a valid image reference", flag, image),
+			Hint: "Use a reference like 'okteto/pipeline-runner:1.0.0' or 'registry.example.com/runner@sha256:<digest>'",
+		}
+	}
+	return nil
+}
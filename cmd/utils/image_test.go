@@ -0,0 +1,46 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateImageReference(t *testing.T) {
+	tests := []struct {
+		name      string
+		image     string
+		expectErr bool
+	}{
+		{name: "image with tag", image: "okteto/pipeline-runner:1.0.0"},
+		{name: "image with registry", image: "registry.example.com:5000/okteto/runner"},
+		{name: "image with digest", image: "okteto/runner@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"},
+		{name: "spaces", image: "okteto/runner 1.0", expectErr: true},
+		{name: "invalid tag", image: "okteto/runner:v1!", expectErr: true},
+		{name: "uppercase", image: "Okteto/Runner", expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateImageReference("remote-run-image", tt.image)
+			if tt.expectErr {
+				assert.ErrorAs(t, err, &oktetoErrors.UserError{})
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
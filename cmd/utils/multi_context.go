@@ -0,0 +1,273 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// ContextsFlag is the flag that runs a command against several contexts
+	ContextsFlag = "contexts"
+	// ParallelContextsFlag is the flag that runs the contexts of ContextsFlag in parallel
+	ParallelContextsFlag = "parallel-contexts"
+)
+
+// ContextResult is the result of running a command against a context
+type ContextResult struct {
+	Context  string
+	Err      error
+	Duration time.Duration
+}
+
+// ContextRunFunc runs a command against a context, writing its output to out
+type ContextRunFunc func(ctx context.Context, kubeContext string, out io.Writer) error
+
+// ParseContexts returns the contexts of a comma separated list, failing on empty or repeated contexts
+func ParseContexts(value string) ([]string, error) {
+	var contexts []string
+	seen := map[string]bool{}
+	for _, c := range strings.Split(value, ",") {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			return nil, oktetoErrors.UserError{
+				E:    fmt.Errorf("invalid value '%s' for flag '--%s': empty context", value, ContextsFlag),
+				Hint: fmt.Sprintf("Use a comma separated list of contexts, e.g. '--%s ctx1,ctx2'", ContextsFlag),
+			}
+		}
+		if seen[c] {
+			return nil, oktetoErrors.UserError{
+				E:    fmt.Errorf("invalid value '%s' for flag '--%s': context '%s' is repeated", value, ContextsFlag, c),
+				Hint: fmt.Sprintf("Use a comma separated list of contexts, e.g. '--%s ctx1,ctx2'", ContextsFlag),
+			}
+		}
+		seen[c] = true
+		contexts = append(contexts, c)
+	}
+	return contexts, nil
+}
+
+// ValidateContextsFlags returns an error if the flags selecting the contexts are combined in an invalid way
+func ValidateContextsFlags(cmd, contexts, kubeContext string, parallel bool) error {
+	if contexts != "" && kubeContext != "" {
+		return oktetoErrors.UserError{
+			E:    fmt.Errorf("flags '--context' and '--%s' can't be used together", ContextsFlag),
+			Hint: fmt.Sprintf("Use 'okteto %s --%s ctx1,ctx2' to run against several contexts", cmd, ContextsFlag),
+		}
+	}
+	if parallel && contexts == "" {
+		return oktetoErrors.UserError{
+			E:    fmt.Errorf("flag '--%s' can only be used with '--%s'", ParallelContextsFlag, ContextsFlag),
+			Hint: fmt.Sprintf("Run 'okteto %s --%s ctx1,ctx2 --%s'", cmd, ContextsFlag, ParallelContextsFlag),
+		}
+	}
+	return nil
+}
+
+// RunCommandInContexts runs cmd against every context of the comma separated list, prints a summary
+// and fails if it failed in any of them
+func RunCommandInContexts(ctx context.Context, cmd *cobra.Command, args, variables []string, contexts string, parallel bool) error {
+	kubeContexts, err := ParseContexts(contexts)
+	if err != nil {
+		return err
+	}
+	run, err := NewContextCommandRun(cmd, args, variables, kubeContexts)
+	if err != nil {
+		return err
+	}
+	results := RunInContexts(ctx, kubeContexts, parallel, os.Stdout, run)
+	PrintContextsSummary(os.Stdout, results)
+	return ContextsError(cmd.Name(), results)
+}
+
+// SplitContextVariables splits the variables set with '--var' into the ones shared by every context and
+// the ones prefixed by a context ('ctx:KEY=VALUE'), which only apply to that context
+func SplitContextVariables(variables, contexts []string) ([]string, map[string][]string) {
+	var shared []string
+	perContext := map[string][]string{}
+	for _, v := range variables {
+		// contexts can contain ':' (e.g. urls), so the prefix is matched against the known contexts
+		key := strings.SplitN(v, "=", 2)[0]
+		matched := false
+		for _, c := range contexts {
+			prefix := c + ":"
+			if strings.HasPrefix(key, prefix) && len(key) > len(prefix) {
+				perContext[c] = append(perContext[c], strings.TrimPrefix(v, prefix))
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			shared = append(shared, v)
+		}
+	}
+	return shared, perContext
+}
+
+// RunInContexts runs the command against each context, sequentially or in parallel. The output of each context
+// is prefixed with its name and the results are returned in the order of the contexts
+func RunInContexts(ctx context.Context, contexts []string, parallel bool, out io.Writer, run ContextRunFunc) []ContextResult {
+	results := make([]ContextResult, len(contexts))
+	var mu sync.Mutex
+	runOne := func(i int) {
+		w := &prefixWriter{prefix: fmt.Sprintf("[%s] ", contexts[i]), out: out, mu: &mu}
+		start := time.Now()
+		err := run(ctx, contexts[i], w)
+		w.flush()
+		results[i] = ContextResult{Context: contexts[i], Err: err, Duration: time.Since(start)}
+	}
+
+	if !parallel {
+		for i := range contexts {
+			runOne(i)
+		}
+		return results
+	}
+
+	var wg sync.WaitGroup
+	for i := range contexts {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			runOne(i)
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// PrintContextsSummary writes the status of every context
+func PrintContextsSummary(w io.Writer, results []ContextResult) {
+	fmt.Fprintln(w, "Summary:")
+	for _, r := range results {
+		status := "succeeded"
+		if r.Err != nil {
+			status = fmt.Sprintf("failed: %s", r.Err.Error())
+		}
+		fmt.Fprintf(w, "  %s: %s (%s)\n", r.Context, status, r.Duration.Round(time.Second))
+	}
+}
+
+// ContextsError returns an error when the command failed in any of the contexts
+func ContextsError(cmd string, results []ContextResult) error {
+	var failed []string
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, r.Context)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return oktetoErrors.UserError{
+		E:    fmt.Errorf("%s failed in %d of %d contexts: %s", cmd, len(failed), len(results), strings.Join(failed, ", ")),
+		Hint: "Check the output of the failed contexts above",
+	}
+}
+
+// NewContextCommandRun returns a ContextRunFunc that runs the current okteto command in a new process
+// for each context, with the same args and flags plus '--context' and the variables of that context
+func NewContextCommandRun(cmd *cobra.Command, args []string, variables []string, contexts []string) (ContextRunFunc, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the okteto binary: %w", err)
+	}
+	shared, perContext := SplitContextVariables(variables, contexts)
+	return func(ctx context.Context, kubeContext string, out io.Writer) error {
+		contextVariables := append(append([]string{}, shared...), perContext[kubeContext]...)
+		c := exec.CommandContext(ctx, executable, GetContextCommandArgs(cmd, args, kubeContext, contextVariables)...)
+		c.Stdout = out
+		c.Stderr = out
+		c.Env = append(os.Environ(), fmt.Sprintf("%s=true", oktetoLog.OktetoDisableSpinnerEnvVar))
+		return c.Run()
+	}, nil
+}
+
+// GetContextCommandArgs returns the args to run cmd against kubeContext: the changed flags are kept except
+// the ones selecting the contexts and the variables, which are replaced by the ones of that context
+func GetContextCommandArgs(cmd *cobra.Command, args []string, kubeContext string, variables []string) []string {
+	// the command path includes the root command, which is the binary itself
+	result := strings.Fields(cmd.CommandPath())[1:]
+	logOutputSet := false
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		switch f.Name {
+		case ContextsFlag, ParallelContextsFlag, "context", "var":
+			return
+		case "log-output":
+			logOutputSet = true
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			for _, v := range sv.GetSlice() {
+				result = append(result, fmt.Sprintf("--%s=%s", f.Name, v))
+			}
+			return
+		}
+		result = append(result, fmt.Sprintf("--%s=%s", f.Name, f.Value.String()))
+	})
+	// the output of every context is prefixed with its name, so the interactive output is disabled
+	if !logOutputSet {
+		result = append(result, fmt.Sprintf("--log-output=%s", oktetoLog.PlainFormat))
+	}
+	result = append(result, fmt.Sprintf("--context=%s", kubeContext))
+	for _, v := range variables {
+		result = append(result, fmt.Sprintf("--var=%s", v))
+	}
+	return append(result, args...)
+}
+
+// prefixWriter writes each complete line to out with a prefix. The mutex is shared by the writers of every context
+type prefixWriter struct {
+	out    io.Writer
+	mu     *sync.Mutex
+	prefix string
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		w.writeLine(w.buf[:i+1])
+		w.buf = w.buf[i+1:]
+	}
+}
+
+func (w *prefixWriter) flush() {
+	if len(w.buf) > 0 {
+		w.writeLine(append(w.buf, '\n'))
+		w.buf = nil
+	}
+}
+
+func (w *prefixWriter) writeLine(line []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fmt.Fprintf(w.out, "%s%s", w.prefix, line)
+}
@@ -0,0 +1,184 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseContexts(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  []string
+		expectErr bool
+	}{
+		{
+			name:     "single context",
+			value:    "east",
+			expected: []string{"east"},
+		},
+		{
+			name:     "several contexts with spaces",
+			value:    "east, https://west.okteto.dev",
+			expected: []string{"east", "https://west.okteto.dev"},
+		},
+		{
+			name:      "empty context",
+			value:     "east,,west",
+			expectErr: true,
+		},
+		{
+			name:      "repeated context",
+			value:     "east,west,east",
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contexts, err := ParseContexts(tt.value)
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, contexts)
+		})
+	}
+}
+
+func TestValidateContextsFlags(t *testing.T) {
+	assert.NoError(t, ValidateContextsFlags("deploy", "", "", false))
+	assert.NoError(t, ValidateContextsFlags("deploy", "east,west", "", true))
+	assert.Error(t, ValidateContextsFlags("deploy", "east,west", "east", false))
+	assert.Error(t, ValidateContextsFlags("deploy", "", "", true))
+}
+
+func TestSplitContextVariables(t *testing.T) {
+	contexts := []string{"east", "https://west.okteto.dev"}
+	shared, perContext := SplitContextVariables([]string{
+		"A=1",
+		"east:B=2",
+		"https://west.okteto.dev:B=3",
+		"URL=https://east:8080",
+		"north:C=4",
+	}, contexts)
+	assert.Equal(t, []string{"A=1", "URL=https://east:8080", "north:C=4"}, shared)
+	assert.Equal(t, map[string][]string{
+		"east":                    {"B=2"},
+		"https://west.okteto.dev": {"B=3"},
+	}, perContext)
+}
+
+func TestRunInContexts(t *testing.T) {
+	contexts := []string{"east", "west", "north"}
+	fakeRun := func(_ context.Context, kubeContext string, out io.Writer) error {
+		fmt.Fprintf(out, "deploying\ndone")
+		if kubeContext == "west" {
+			return errors.New("west is down")
+		}
+		return nil
+	}
+
+	for _, parallel := range []bool{false, true} {
+		t.Run(fmt.Sprintf("parallel=%t", parallel), func(t *testing.T) {
+			out := &bytes.Buffer{}
+			results := RunInContexts(context.Background(), contexts, parallel, out, fakeRun)
+
+			require.Len(t, results, 3)
+			for i, r := range results {
+				assert.Equal(t, contexts[i], r.Context)
+			}
+			assert.NoError(t, results[0].Err)
+			assert.EqualError(t, results[1].Err, "west is down")
+			assert.NoError(t, results[2].Err)
+
+			lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			sort.Strings(lines)
+			assert.Equal(t, []string{
+				"[east] deploying",
+				"[east] done",
+				"[north] deploying",
+				"[north] done",
+				"[west] deploying",
+				"[west] done",
+			}, lines)
+
+			err := ContextsError("deploy", results)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "deploy failed in 1 of 3 contexts: west")
+		})
+	}
+}
+
+func TestRunInContextsParallelRunsAtTheSameTime(t *testing.T) {
+	contexts := []string{"east", "west"}
+	var wg sync.WaitGroup
+	wg.Add(len(contexts))
+	// every run waits for the others, so it only finishes if they run at the same time
+	fakeRun := func(_ context.Context, _ string, _ io.Writer) error {
+		wg.Done()
+		wg.Wait()
+		return nil
+	}
+	results := RunInContexts(context.Background(), contexts, true, io.Discard, fakeRun)
+	assert.NoError(t, ContextsError("deploy", results))
+}
+
+func TestPrintContextsSummary(t *testing.T) {
+	out := &bytes.Buffer{}
+	PrintContextsSummary(out, []ContextResult{
+		{Context: "east"},
+		{Context: "west", Err: errors.New("exit status 1")},
+	})
+	assert.Equal(t, "Summary:\n  east: succeeded (0s)\n  west: failed: exit status 1 (0s)\n", out.String())
+}
+
+func TestGetContextCommandArgs(t *testing.T) {
+	root := &cobra.Command{Use: "okteto"}
+	root.PersistentFlags().String("log-output", "tty", "")
+	var contexts, name string
+	var parallel bool
+	var variables []string
+	cmd := &cobra.Command{Use: "deploy", RunE: func(*cobra.Command, []string) error { return nil }}
+	cmd.Flags().StringVar(&name, "name", "", "")
+	cmd.Flags().StringVar(&contexts, ContextsFlag, "", "")
+	cmd.Flags().BoolVar(&parallel, ParallelContextsFlag, false, "")
+	cmd.Flags().StringArrayVarP(&variables, "var", "v", nil, "")
+	root.AddCommand(cmd)
+	root.SetArgs([]string{"deploy", "api", "--name", "env", "--contexts", "east,west", "--parallel-contexts", "-v", "A=1"})
+	require.NoError(t, root.Execute())
+
+	assert.Equal(t, []string{
+		"deploy",
+		"--name=env",
+		"--log-output=plain",
+		"--context=east",
+		"--var=A=1",
+		"--var=B=2",
+		"api",
+	}, GetContextCommandArgs(cmd, []string{"api"}, "east", []string{"A=1", "B=2"}))
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/okteto/okteto/pkg/constants"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+)
+
+// RunModePrecedenceHelp documents how the commands decide between running locally or in remote
+const RunModePrecedenceHelp = `The commands run in remote or locally following this precedence:
+  1. the flags '--remote' and '--local'
+  2. the environment variable OKTETO_FORCE_REMOTE
+  3. the image defined in the okteto manifest, which runs them in remote`
+
+// ValidateRunModeFlags returns an error if the flags force running both locally and in remote
+func ValidateRunModeFlags(cmd string, remote, local bool) error {
+	if remote && local {
+		return oktetoErrors.UserError{
+			E:    fmt.Errorf("flags '--remote' and '--local' can't be used together"),
+			Hint: fmt.Sprintf("Use 'okteto %s --remote' to run in remote or 'okteto %s --local' to run locally", cmd, cmd),
+		}
+	}
+	return nil
+}
+
+// ShouldRunInRemote returns if the commands must run in remote. The flags take precedence over OKTETO_FORCE_REMOTE
+// and the variable takes precedence over the image of the manifest
+func ShouldRunInRemote(remote, local bool, manifestImage string, lookupEnv func(string) (string, bool)) bool {
+	if remote {
+		return true
+	}
+	if local {
+		return false
+	}
+	if value, ok := lookupEnv(constants.OktetoForceRemoteEnvVar); ok && value != "" {
+		forceRemote, err := strconv.ParseBool(value)
+		if err == nil {
+			return forceRemote
+		}
+		oktetoLog.Yellow("'%s' is not a valid value for environment variable %s", value, constants.OktetoForceRemoteEnvVar)
+	}
+	return manifestImage != ""
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/okteto/okteto/pkg/constants"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRunModeFlags(t *testing.T) {
+	assert.NoError(t, ValidateRunModeFlags("destroy", true, false))
+	assert.NoError(t, ValidateRunModeFlags("destroy", false, true))
+	assert.NoError(t, ValidateRunModeFlags("destroy", false, false))
+	assert.ErrorAs(t, ValidateRunModeFlags("destroy", true, true), &oktetoErrors.UserError{})
+}
+
+func TestShouldRunInRemote(t *testing.T) {
+	var tests = []struct {
+		name          string
+		remote        bool
+		local         bool
+		env           map[string]string
+		manifestImage string
+		expected      bool
+	}{
+		{
+			name: "nothing set",
+		},
+		{
+			name:          "manifest image",
+			manifestImage: "okteto/destroy",
+			expected:      true,
+		},
+		{
+			name:     "remote flag",
+			remote:   true,
+			expected: true,
+		},
+		{
+			name:     "env var forces remote",
+			env:      map[string]string{constants.OktetoForceRemoteEnvVar: "true"},
+			expected: true,
+		},
+		{
+			name:          "env var overrides manifest image",
+			env:           map[string]string{constants.OktetoForceRemoteEnvVar: "false"},
+			manifestImage: "okteto/destroy",
+		},
+		{
+			name:  "local flag overrides env var",
+			local: true,
+			env:   map[string]string{constants.OktetoForceRemoteEnvVar: "true"},
+		},
+		{
+			name:          "local flag overrides manifest image",
+			local:         true,
+			manifestImage: "okteto/destroy",
+		},
+		{
+			name:     "remote flag overrides env var",
+			remote:   true,
+			env:      map[string]string{constants.OktetoForceRemoteEnvVar: "false"},
+			expected: true,
+		},
+		{
+			name:          "invalid env var falls back to manifest image",
+			env:           map[string]string{constants.OktetoForceRemoteEnvVar: "sometimes"},
+			manifestImage: "okteto/destroy",
+			expected:      true,
+		},
+		{
+			name: "empty env var is ignored",
+			env:  map[string]string{constants.OktetoForceRemoteEnvVar: ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookupEnv := func(k string) (string, bool) {
+				v, ok := tt.env[k]
+				return v, ok
+			}
+			assert.Equal(t, tt.expected, ShouldRunInRemote(tt.remote, tt.local, tt.manifestImage, lookupEnv))
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/types"
+)
+
+// CheckTokenPermissions checks that the okteto token can run the command in the namespace.
+// A read-only token is allowed in plan mode: it returns true so the caller skips the steps that mutate resources.
+// When the role can't be fetched the command runs and the okteto server enforces the permissions of the token
+func CheckTokenPermissions(ctx context.Context, cp types.OktetoClientProvider, command, namespace string, planMode bool) (bool, error) {
+	c, err := cp.Provide()
+	if err != nil {
+		return false, err
+	}
+
+	role, err := c.User().GetTokenRole(ctx, namespace)
+	if err != nil {
+		oktetoLog.Infof("failed to check the permissions of your okteto token: %s", err)
+		return false, nil
+	}
+	if role.CanMutate() {
+		switch role {
+		case types.TokenRoleOwner, types.TokenRoleDeveloper:
+		default:
+			oktetoLog.Infof("unknown role '%s' of your okteto token in namespace '%s'", role, namespace)
+		}
+		return false, nil
+	}
+
+	if planMode {
+		oktetoLog.Information("Your okteto token is read-only in namespace '%s'. 'okteto %s' runs in plan-only mode", namespace, command)
+		return true, nil
+	}
+	return false, oktetoErrors.UserError{
+		E:    fmt.Errorf("your okteto token is read-only in namespace '%s' and can't run 'okteto %s'", namespace, command),
+		Hint: fmt.Sprintf("Use a token with developer permissions or run 'okteto %s --remote --dry-run' to see the plan without applying it", command),
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/okteto/okteto/internal/test/client"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckTokenPermissions(t *testing.T) {
+	tests := []struct {
+		name             string
+		role             types.TokenRole
+		roleErr          error
+		planMode         bool
+		expectedReadOnly bool
+		expectedErr      bool
+		expectedUserErr  bool
+	}{
+		{
+			name: "owner",
+			role: types.TokenRoleOwner,
+		},
+		{
+			name: "developer",
+			role: types.TokenRoleDeveloper,
+		},
+		{
+			name:     "developer in plan mode",
+			role:     types.TokenRoleDeveloper,
+			planMode: true,
+		},
+		{
+			name:             "viewer in plan mode",
+			role:             types.TokenRoleViewer,
+			planMode:         true,
+			expectedReadOnly: true,
+		},
+		{
+			name:            "viewer mutating",
+			role:            types.TokenRoleViewer,
+			expectedErr:     true,
+			expectedUserErr: true,
+		},
+		{
+			name:    "role can't be fetched",
+			roleErr: assert.AnError,
+		},
+		{
+			name: "unknown role",
+			role: types.TokenRole("admin"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cp := client.NewFakeOktetoClientProvider(&client.FakeOktetoClient{
+				Users: client.NewFakeUsersClientWithTokenRole(tt.role, tt.roleErr),
+			})
+
+			readOnly, err := CheckTokenPermissions(context.Background(), cp, "destroy", "test", tt.planMode)
+			assert.Equal(t, tt.expectedReadOnly, readOnly)
+			if !tt.expectedErr {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			var userErr oktetoErrors.UserError
+			assert.Equal(t, tt.expectedUserErr, errors.As(err, &userErr))
+		})
+	}
+}
+
+func TestCheckTokenPermissionsProviderError(t *testing.T) {
+	cp := client.NewFakeOktetoClientProviderWithError(assert.AnError)
+	_, err := CheckTokenPermissions(context.Background(), cp, "deploy", "test", true)
+	assert.ErrorIs(t, err, assert.AnError)
+}
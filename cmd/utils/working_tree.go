@@ -0,0 +1,78 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/repository"
+)
+
+const (
+	// RequireCleanFlag fails the remote commands when the working tree has uncommitted changes
+	RequireCleanFlag = "require-clean"
+
+	// maxDirtyFilesListed is the number of files with uncommitted changes listed in the warning
+	maxDirtyFilesListed = 10
+)
+
+// CheckWorkingTree warns if the git repo containing dir has uncommitted changes, because the remote command
+// runs with the working tree as it is. It fails instead when requireClean is set. It returns true if the tree is dirty
+func CheckWorkingTree(dir, command string, requireClean bool) (bool, error) {
+	return checkWorkingTree(dir, command, requireClean, repository.GetDirtyFiles)
+}
+
+func checkWorkingTree(dir, command string, requireClean bool, getDirtyFiles func(string) ([]string, error)) (bool, error) {
+	files, err := getDirtyFiles(dir)
+	if err != nil {
+		if errors.Is(err, git.ErrRepositoryNotExists) {
+			return false, nil
+		}
+		if requireClean {
+			return false, fmt.Errorf("failed to check if the working tree has uncommitted changes: %w", err)
+		}
+		oktetoLog.Infof("failed to check if the working tree has uncommitted changes: %s", err)
+		return false, nil
+	}
+	if len(files) == 0 {
+		return false, nil
+	}
+
+	if requireClean {
+		return true, oktetoErrors.UserError{
+			E:    fmt.Errorf("the working tree has uncommitted changes and '--%s' is set:\n%s", RequireCleanFlag, listDirtyFiles(files)),
+			Hint: fmt.Sprintf("Commit or stash your changes, or run 'okteto %s' without '--%s'", command, RequireCleanFlag),
+		}
+	}
+	oktetoLog.Warning("The working tree has uncommitted changes that are included in the remote %s:\n%s", command, listDirtyFiles(files))
+	return true, nil
+}
+
+// listDirtyFiles returns a line per file, up to maxDirtyFilesListed
+func listDirtyFiles(files []string) string {
+	lines := make([]string, 0, maxDirtyFilesListed+1)
+	for i, f := range files {
+		if i == maxDirtyFilesListed {
+			lines = append(lines, fmt.Sprintf("    ... and %d more", len(files)-maxDirtyFilesListed))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("    - %s", f))
+	}
+	return strings.Join(lines, "\n")
+}
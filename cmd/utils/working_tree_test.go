@@ -0,0 +1,99 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckWorkingTree(t *testing.T) {
+	tests := []struct {
+		name          string
+		files         []string
+		err           error
+		requireClean  bool
+		expectedDirty bool
+		expectedErr   bool
+		userErr       bool
+	}{
+		{
+			name: "clean",
+		},
+		{
+			name:         "not a git repo",
+			err:          git.ErrRepositoryNotExists,
+			requireClean: true,
+		},
+		{
+			name: "status error warns",
+			err:  errors.New("corrupt index"),
+		},
+		{
+			name:         "status error with require clean",
+			err:          errors.New("corrupt index"),
+			requireClean: true,
+			expectedErr:  true,
+		},
+		{
+			name:          "dirty",
+			files:         []string{"okteto.yml"},
+			expectedDirty: true,
+		},
+		{
+			name:          "dirty with require clean",
+			files:         []string{"okteto.yml"},
+			requireClean:  true,
+			expectedDirty: true,
+			expectedErr:   true,
+			userErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getDirtyFiles := func(string) ([]string, error) {
+				return tt.files, tt.err
+			}
+			dirty, err := checkWorkingTree("/app", "destroy", tt.requireClean, getDirtyFiles)
+			assert.Equal(t, tt.expectedDirty, dirty)
+			if !tt.expectedErr {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			if tt.userErr {
+				require.ErrorAs(t, err, &oktetoErrors.UserError{})
+			}
+		})
+	}
+}
+
+func TestListDirtyFiles(t *testing.T) {
+	files := make([]string, 0, 12)
+	for i := 0; i < 12; i++ {
+		files = append(files, fmt.Sprintf("file-%02d", i))
+	}
+
+	assert.Equal(t, "    - file-00\n    - file-01", listDirtyFiles(files[:2]))
+
+	listed := listDirtyFiles(files)
+	assert.Contains(t, listed, "    - file-09\n    ... and 2 more")
+	assert.NotContains(t, listed, "file-10")
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vars
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/okteto/okteto/cmd/utils"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/varprofiles"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// Delete deletes a saved variables profile
+func Delete() *cobra.Command {
+	filter := varprofiles.Key{}
+	cmd := &cobra.Command{
+		Use:   "delete <profile>",
+		Short: "Delete a variables profile saved with 'okteto deploy --save-vars'",
+		Args:  utils.ExactArgsAccepted(1, ""),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filter.Profile = args[0]
+			return deleteProfiles(varprofiles.NewStore(afero.NewOsFs()), filter)
+		},
+	}
+	cmd.Flags().StringVarP(&filter.Environment, "name", "", "", "only delete the profile of this development environment")
+	cmd.Flags().StringVarP(&filter.Namespace, "namespace", "n", "", "only delete the profile of this namespace")
+	cmd.Flags().StringVarP(&filter.Context, "context", "c", "", "only delete the profile of this context")
+	return cmd
+}
+
+func deleteProfiles(store profileStore, filter varprofiles.Key) error {
+	deleted, err := store.Delete(filter)
+	if err != nil {
+		if errors.Is(err, varprofiles.ErrProfileNotFound) {
+			return oktetoErrors.UserError{
+				E:    fmt.Errorf("variables profile '%s' not found", filter.Profile),
+				Hint: "Run 'okteto vars list' to see the saved profiles",
+			}
+		}
+		return err
+	}
+	for _, p := range deleted {
+		oktetoLog.Success("Variables profile '%s' of '%s' in namespace '%s' deleted", p.Profile, p.Environment, p.Namespace)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vars
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/okteto/okteto/cmd/utils"
+	"github.com/okteto/okteto/pkg/varprofiles"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// List lists the saved variables profiles
+func List() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Short:   "List the variables profiles saved with 'okteto deploy --save-vars'",
+		Aliases: []string{"ls"},
+		Args:    utils.NoArgsAccepted(""),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listProfiles(os.Stdout, varprofiles.NewStore(afero.NewOsFs()))
+		},
+	}
+}
+
+func listProfiles(out io.Writer, store profileStore) error {
+	profiles, err := store.List()
+	if err != nil {
+		return err
+	}
+	if len(profiles) == 0 {
+		_, err := fmt.Fprintln(out, "There are no saved variables profiles")
+		return err
+	}
+	w := tabwriter.NewWriter(out, 1, 1, 2, ' ', 0)
+	fmt.Fprintf(w, "Profile\tDevelopment environment\tNamespace\tContext\tVariables\n")
+	for _, p := range profiles {
+		names := make([]string, 0, len(p.Variables))
+		for _, v := range p.Variables {
+			names = append(names, v.Name)
+		}
+		variables := strings.Join(names, ", ")
+		if variables == "" {
+			variables = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", p.Profile, p.Environment, p.Namespace, p.Context, variables)
+	}
+	return w.Flush()
+}
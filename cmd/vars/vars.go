@@ -0,0 +1,36 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vars
+
+import (
+	"github.com/okteto/okteto/pkg/varprofiles"
+	"github.com/spf13/cobra"
+)
+
+// profileStore manages the variables profiles saved with 'okteto deploy --save-vars'
+type profileStore interface {
+	List() ([]varprofiles.Profile, error)
+	Delete(filter varprofiles.Key) ([]varprofiles.Profile, error)
+}
+
+// Vars manages the variables profiles of okteto deploy
+func Vars() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vars",
+		Short: "Manage the variables profiles saved with 'okteto deploy --save-vars'",
+	}
+	cmd.AddCommand(List())
+	cmd.AddCommand(Delete())
+	return cmd
+}
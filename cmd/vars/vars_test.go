@@ -0,0 +1,87 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vars
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/varprofiles"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProfileStore struct {
+	profiles []varprofiles.Profile
+	err      error
+	filter   varprofiles.Key
+}
+
+func (s *fakeProfileStore) List() ([]varprofiles.Profile, error) {
+	return s.profiles, s.err
+}
+
+func (s *fakeProfileStore) Delete(filter varprofiles.Key) ([]varprofiles.Profile, error) {
+	s.filter = filter
+	return s.profiles, s.err
+}
+
+func TestListProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		profiles []varprofiles.Profile
+		expected string
+	}{
+		{
+			name:     "empty",
+			expected: "There are no saved variables profiles\n",
+		},
+		{
+			name: "profiles",
+			profiles: []varprofiles.Profile{
+				{
+					Key:       varprofiles.Key{Context: "ctx", Namespace: "ns", Environment: "app", Profile: "staging"},
+					Variables: []varprofiles.Variable{{Name: "A", Value: "1"}, {Name: "TOKEN", Secret: true}},
+				},
+				{
+					Key: varprofiles.Key{Context: "ctx", Namespace: "ns", Environment: "app", Profile: "empty"},
+				},
+			},
+			expected: "Profile  Development environment  Namespace  Context  Variables\nstaging  app                      ns         ctx      A, TOKEN\nempty    app                      ns         ctx      -\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			require.NoError(t, listProfiles(out, &fakeProfileStore{profiles: tt.profiles}))
+			assert.Equal(t, tt.expected, out.String())
+		})
+	}
+}
+
+func TestDeleteProfiles(t *testing.T) {
+	store := &fakeProfileStore{profiles: []varprofiles.Profile{{Key: varprofiles.Key{Profile: "staging"}}}}
+	filter := varprofiles.Key{Namespace: "ns", Profile: "staging"}
+	require.NoError(t, deleteProfiles(store, filter))
+	assert.Equal(t, filter, store.filter)
+
+	err := deleteProfiles(&fakeProfileStore{err: varprofiles.ErrProfileNotFound}, filter)
+	assert.ErrorAs(t, err, &oktetoErrors.UserError{})
+
+	expectedErr := errors.New("read error")
+	err = deleteProfiles(&fakeProfileStore{err: expectedErr}, filter)
+	assert.ErrorIs(t, err, expectedErr)
+}
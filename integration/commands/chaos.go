@@ -0,0 +1,82 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/okteto/okteto/pkg/chaos"
+	"github.com/okteto/okteto/pkg/config"
+)
+
+// IsInjectedFailure returns if err is the exit of an okteto command stopped at the stage of its FailAtStage option.
+// Failures are only injected by okteto binaries built with the 'chaos' build tag
+func IsInjectedFailure(err error) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == chaos.ExitCode
+}
+
+// AssertOktetoHomeUnlocked returns an error if an okteto process still holds the lock over the okteto home
+func AssertOktetoHomeUnlocked(oktetoHome string) error {
+	folder := filepath.Join(oktetoHome, ".okteto")
+	if _, err := os.Stat(folder); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	locked, err := config.IsOktetoHomeLocked(folder)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return fmt.Errorf("the okteto home '%s' is still locked", folder)
+	}
+	return nil
+}
+
+// AssertNoTempFilesLeft returns an error listing the files left in tempDir.
+// Commands must run with TMPDIR set to tempDir for their temporary files to be created there
+func AssertNoTempFilesLeft(tempDir string) error {
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		return fmt.Errorf("could not read temporary folder '%s': %w", tempDir, err)
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	left := make([]string, 0, len(entries))
+	for _, e := range entries {
+		left = append(left, e.Name())
+	}
+	return fmt.Errorf("found %d temporary files left in '%s': %s", len(left), tempDir, strings.Join(left, ", "))
+}
+
+// AssertEnvironmentStatus returns an error if the status stored in the configmap of the development environment
+// is not one of statuses
+func AssertEnvironmentStatus(kubeconfig, ns, envName string, statuses ...string) error {
+	cmap, err := GetEnvironmentConfigmap(kubeconfig, ns, envName)
+	if err != nil {
+		return err
+	}
+	status := cmap.Data["status"]
+	for _, s := range statuses {
+		if status == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("development environment '%s' has status '%s', expected one of '%s'", envName, status, strings.Join(statuses, "', '"))
+}
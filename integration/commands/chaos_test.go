@@ -0,0 +1,124 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/chaos"
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	"github.com/okteto/okteto/pkg/config"
+	"github.com/okteto/okteto/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeployCmdWithFailAtStage(t *testing.T) {
+	cmd := getDeployCmd("okteto", &DeployOptions{FailAtStage: chaos.DeployImagesBuiltStage})
+	assert.Contains(t, cmd.Env, fmt.Sprintf("%s=%s", chaos.FailAtStageEnvVar, chaos.DeployImagesBuiltStage))
+}
+
+func TestDestroyCmdWithFailAtStage(t *testing.T) {
+	cmd := getDestroyCmd("okteto", &DestroyOptions{FailAtStage: chaos.DestroyResourcesDeletedStage})
+	assert.Contains(t, cmd.Env, fmt.Sprintf("%s=%s", chaos.FailAtStageEnvVar, chaos.DestroyResourcesDeletedStage))
+}
+
+func TestIsInjectedFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		expected bool
+	}{
+		{
+			name:     "injected failure",
+			script:   fmt.Sprintf("exit %d\n", chaos.ExitCode),
+			expected: true,
+		},
+		{
+			name:     "other failure",
+			script:   "exit 1\n",
+			expected: false,
+		},
+		{
+			name:     "success",
+			script:   "exit 0\n",
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oktetoPath := writeFakeOkteto(t, tt.script)
+			err := RunOktetoDestroy(oktetoPath, &DestroyOptions{})
+			assert.Equal(t, tt.expected, IsInjectedFailure(err))
+		})
+	}
+}
+
+func TestAssertOktetoHomeUnlocked(t *testing.T) {
+	home := t.TempDir()
+	require.NoError(t, AssertOktetoHomeUnlocked(home))
+
+	folder := filepath.Join(home, ".okteto")
+	require.NoError(t, os.MkdirAll(folder, 0700))
+	t.Setenv(constants.OktetoFolderEnvVar, folder)
+
+	acquired := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- config.WithOktetoHomeLock(func() error {
+			close(acquired)
+			<-release
+			return nil
+		})
+	}()
+	<-acquired
+	err := AssertOktetoHomeUnlocked(home)
+	close(release)
+	require.NoError(t, <-done)
+	require.Error(t, err)
+
+	require.NoError(t, AssertOktetoHomeUnlocked(home))
+}
+
+func TestAssertNoTempFilesLeft(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, AssertNoTempFilesLeft(dir))
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "okteto-123"), 0700))
+	err := AssertNoTempFilesLeft(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "okteto-123")
+
+	require.Error(t, AssertNoTempFilesLeft(filepath.Join(dir, "not-found")))
+}
+
+func TestAssertEnvironmentStatus(t *testing.T) {
+	kubeconfig := setFakeClientset(t, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pipeline.TranslatePipelineName("movies"),
+			Namespace: "test",
+		},
+		Data: map[string]string{"status": pipeline.ProgressingStatus},
+	})
+
+	assert.NoError(t, AssertEnvironmentStatus(kubeconfig, "test", "movies", pipeline.ErrorStatus, pipeline.ProgressingStatus))
+	assert.Error(t, AssertEnvironmentStatus(kubeconfig, "test", "movies", pipeline.DeployedStatus))
+	assert.Error(t, AssertEnvironmentStatus(kubeconfig, "test", "not-found", pipeline.DeployedStatus))
+}
@@ -15,14 +15,44 @@ package commands
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"os/exec"
-	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
 
+	"github.com/okteto/okteto/pkg/chaos"
 	"github.com/okteto/okteto/pkg/constants"
+	"github.com/okteto/okteto/pkg/format"
 	"github.com/okteto/okteto/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	replicasTimeout = 60 * time.Second
+
+	podCountStablePeriod = 10 * time.Second
+	podCountTimeout      = 2 * time.Minute
+
+	serviceAccountTimeout = 30 * time.Second
+
+	externalIPTimeout = 5 * time.Minute
+
+	// killGracePeriod is how long to wait for the output of a killed command to be flushed
+	killGracePeriod = 5 * time.Second
 )
 
 // DeployOptions defines the options that can be added to a deploy command
@@ -38,6 +68,21 @@ type DeployOptions struct {
 	Token            string
 	Name             string
 	Variables        string
+	// FailAtStage makes a chaos build of okteto exit at the stage, see IsInjectedFailure
+	FailAtStage string
+	// WaitForStable makes RunOktetoDeployAndGetPodCount wait until the pod count doesn't change for a while
+	WaitForStable bool
+}
+
+// ComposeDeployOptions defines the options to deploy a compose file
+type ComposeDeployOptions struct {
+	Workdir     string
+	ComposeFile string
+	Namespace   string
+	OktetoHome  string
+	Token       string
+	// Services filters the compose services to deploy
+	Services []string
 }
 
 // DestroyOptions defines the options that can be added to a deploy command
@@ -48,6 +93,8 @@ type DestroyOptions struct {
 	OktetoHome   string
 	Token        string
 	Name         string
+	// FailAtStage makes a chaos build of okteto exit at the stage, see IsInjectedFailure
+	FailAtStage string
 }
 
 // RunOktetoDeploy runs an okteto deploy command
@@ -75,6 +122,22 @@ func RunOktetoDeploy(oktetoPath string, deployOptions *DeployOptions) error {
 	return err
 }
 
+// RunOktetoDeployWithComposeFile runs an okteto deploy command using a compose file as manifest
+func RunOktetoDeployWithComposeFile(oktetoPath string, composeOptions *ComposeDeployOptions) error {
+	return RunOktetoDeploy(oktetoPath, composeOptions.toDeployOptions())
+}
+
+func (o *ComposeDeployOptions) toDeployOptions() *DeployOptions {
+	return &DeployOptions{
+		Workdir:          o.Workdir,
+		ManifestPath:     o.ComposeFile,
+		ServicesToDeploy: o.Services,
+		Namespace:        o.Namespace,
+		OktetoHome:       o.OktetoHome,
+		Token:            o.Token,
+	}
+}
+
 // RunOktetoDeployAndGetOutput runs an okteto deploy command and returns the output
 func RunOktetoDeployAndGetOutput(oktetoPath string, deployOptions *DeployOptions) (string, error) {
 	cmd := getDeployCmd(oktetoPath, deployOptions)
@@ -87,13 +150,489 @@ func RunOktetoDeployAndGetOutput(oktetoPath string, deployOptions *DeployOptions
 	return string(o), nil
 }
 
+// RunOktetoDeployAndVerifyNoWarnings runs an okteto deploy command and reports an error for every warning in its output.
+// The warnings are written with the rest of the logs to stdout, so both stdout and stderr are captured.
+// The plain log output is used by default as the warnings of the tty output only have a symbol
+func RunOktetoDeployAndVerifyNoWarnings(t *testing.T, oktetoPath string, deployOptions *DeployOptions) {
+	t.Helper()
+	opts := *deployOptions
+	if opts.LogOutput == "" {
+		opts.LogOutput = "plain"
+	}
+
+	output, err := RunOktetoDeployAndGetOutput(oktetoPath, &opts)
+	if err != nil {
+		t.Fatalf("okteto deploy failed: %s", err)
+	}
+	for _, line := range getWarnings(output) {
+		t.Errorf("okteto deploy emitted a warning: %s", line)
+	}
+}
+
+// getWarnings returns the lines of the output that contain "warn", case insensitive
+func getWarnings(output string) []string {
+	var warnings []string
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(strings.ToLower(line), "warn") {
+			warnings = append(warnings, strings.TrimRight(line, "\r"))
+		}
+	}
+	return warnings
+}
+
+// RunOktetoDeployWithTimeout runs an okteto deploy command and returns the output. If the deploy doesn't
+// finish before the timeout, the process is killed and the output collected so far is returned
+func RunOktetoDeployWithTimeout(oktetoPath string, deployOptions *DeployOptions, timeout time.Duration) (string, error) {
+	cmd := getDeployCmd(oktetoPath, deployOptions)
+	log.Printf("Running '%s'", cmd.String())
+
+	output := &syncBuffer{}
+	cmd.Stdout = output
+	cmd.Stderr = output
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			return output.String(), fmt.Errorf("okteto deploy failed: %s - %w", output.String(), err)
+		}
+		log.Printf("okteto deploy success")
+		return output.String(), nil
+	case <-ctx.Done():
+		if err := cmd.Process.Kill(); err != nil {
+			log.Printf("error killing okteto deploy: %s", err)
+		}
+		select {
+		case <-done:
+		case <-time.After(killGracePeriod):
+		}
+		return output.String(), fmt.Errorf("okteto deploy didn't finish after %s: %w", timeout, ctx.Err())
+	}
+}
+
+// syncBuffer is a buffer safe to read while a command is writing to it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// RunOktetoDeployAndMeasureTime runs an okteto deploy command and returns how long it took
+func RunOktetoDeployAndMeasureTime(oktetoPath string, deployOptions *DeployOptions) (time.Duration, error) {
+	start := time.Now()
+	err := RunOktetoDeploy(oktetoPath, deployOptions)
+	duration := time.Since(start)
+	log.Printf("Deploy took %v", duration)
+	return duration, err
+}
+
+// RunOktetoDeployAndAssertReplicas runs an okteto deploy command and waits until the deployment
+// named serviceName has the expected number of ready replicas
+func RunOktetoDeployAndAssertReplicas(oktetoPath string, k8sClient kubernetes.Interface, deployOptions *DeployOptions, serviceName string, expectedReplicas int32) error {
+	if err := RunOktetoDeploy(oktetoPath, deployOptions); err != nil {
+		return err
+	}
+
+	var readyReplicas int32
+	ready := poll(time.Second, replicasTimeout, func() bool {
+		d, err := k8sClient.AppsV1().Deployments(deployOptions.Namespace).Get(context.Background(), serviceName, metav1.GetOptions{})
+		if err != nil {
+			log.Printf("error getting deployment '%s': %s", serviceName, err)
+			return false
+		}
+		readyReplicas = d.Status.ReadyReplicas
+		return readyReplicas == expectedReplicas
+	})
+	if !ready {
+		return fmt.Errorf("deployment '%s' has %d ready replicas after %s, expected %d", serviceName, readyReplicas, replicasTimeout.String(), expectedReplicas)
+	}
+	return nil
+}
+
+// RunOktetoDeployAndGetDeploymentYAML runs an okteto deploy command and returns the YAML of the deployment
+// named deploymentName. The fields set by the cluster are cleared so the YAML can be compared with golden files
+func RunOktetoDeployAndGetDeploymentYAML(oktetoPath string, k8sClient kubernetes.Interface, deployOptions *DeployOptions, deploymentName string) (string, error) {
+	if err := RunOktetoDeploy(oktetoPath, deployOptions); err != nil {
+		return "", err
+	}
+	return getDeploymentYAML(k8sClient, deployOptions.Namespace, deploymentName)
+}
+
+func getDeploymentYAML(k8sClient kubernetes.Interface, ns, name string) (string, error) {
+	d, err := k8sClient.AppsV1().Deployments(ns).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("could not get deployment '%s' in namespace '%s': %w", name, ns, err)
+	}
+	d.TypeMeta = metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}
+	d.ObjectMeta.UID = ""
+	d.ObjectMeta.ResourceVersion = ""
+	d.ObjectMeta.Generation = 0
+	d.ObjectMeta.CreationTimestamp = metav1.Time{}
+	d.ObjectMeta.ManagedFields = nil
+	d.Status = appsv1.DeploymentStatus{}
+
+	out, err := yaml.Marshal(d)
+	if err != nil {
+		return "", fmt.Errorf("could not serialize deployment '%s': %w", name, err)
+	}
+	return string(out), nil
+}
+
+// RunOktetoDeployAndCheckEnvVar runs an okteto deploy command and checks with 'okteto exec -- env' that
+// the variable varName is set to expectedValue in the deployed service
+func RunOktetoDeployAndCheckEnvVar(oktetoPath string, deployOptions *DeployOptions, varName, expectedValue string) error {
+	if err := RunOktetoDeploy(oktetoPath, deployOptions); err != nil {
+		return err
+	}
+	output, err := RunExecCommand(oktetoPath, &ExecOptions{
+		Workdir:      deployOptions.Workdir,
+		Namespace:    deployOptions.Namespace,
+		ManifestPath: deployOptions.ManifestPath,
+		Command:      "env",
+		OktetoHome:   deployOptions.OktetoHome,
+		Token:        deployOptions.Token,
+	})
+	if err != nil {
+		return err
+	}
+	return checkEnvVar(output, varName, expectedValue)
+}
+
+// checkEnvVar checks that the output of 'env' sets varName to expectedValue. Only the names of the
+// other variables are included in the error, their values might be secrets
+func checkEnvVar(envOutput, varName, expectedValue string) error {
+	names := []string{}
+	for _, line := range strings.Split(envOutput, "\n") {
+		name, value, found := strings.Cut(strings.TrimRight(line, "\r"), "=")
+		if !found {
+			continue
+		}
+		if name == varName {
+			if value != expectedValue {
+				return fmt.Errorf("env var '%s' is '%s' in the deployed service, expected '%s'", varName, value, expectedValue)
+			}
+			return nil
+		}
+		names = append(names, name)
+	}
+	return fmt.Errorf("env var '%s' is not set in the deployed service, found: %s", varName, strings.Join(names, ", "))
+}
+
+// RunOktetoDeployAndGetPodCount runs an okteto deploy command and returns the number of pods matching
+// the selector in the namespace of the development environment
+func RunOktetoDeployAndGetPodCount(oktetoPath string, k8sClient kubernetes.Interface, deployOptions *DeployOptions, selector string) (int, error) {
+	if err := RunOktetoDeploy(oktetoPath, deployOptions); err != nil {
+		return 0, err
+	}
+	if !deployOptions.WaitForStable {
+		return countPods(k8sClient, deployOptions.Namespace, selector)
+	}
+	return waitForStablePodCount(k8sClient, deployOptions.Namespace, selector, podCountStablePeriod, time.Second, podCountTimeout)
+}
+
+func countPods(k8sClient kubernetes.Interface, ns, selector string) (int, error) {
+	pods, err := k8sClient.CoreV1().Pods(ns).List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return 0, fmt.Errorf("could not list pods with selector '%s' in namespace '%s': %w", selector, ns, err)
+	}
+	return len(pods.Items), nil
+}
+
+// waitForStablePodCount returns the number of pods matching the selector once it hasn't changed for stablePeriod
+func waitForStablePodCount(k8sClient kubernetes.Interface, ns, selector string, stablePeriod, interval, timeout time.Duration) (int, error) {
+	count, err := countPods(k8sClient, ns, selector)
+	if err != nil {
+		return 0, err
+	}
+	stableSince := time.Now()
+	stable := poll(interval, timeout, func() bool {
+		current, err := countPods(k8sClient, ns, selector)
+		if err != nil {
+			log.Printf("error counting pods: %s", err)
+			return false
+		}
+		if current != count {
+			count = current
+			stableSince = time.Now()
+			return false
+		}
+		return time.Since(stableSince) >= stablePeriod
+	})
+	if !stable {
+		return count, fmt.Errorf("pod count with selector '%s' is not stable after %s, last count was %d", selector, timeout.String(), count)
+	}
+	return count, nil
+}
+
+// RunOktetoDeployAndVerifyServiceAccount runs an okteto deploy command and returns the service account
+// named saName from the namespace of the development environment, failing if it doesn't exist within 30 seconds
+func RunOktetoDeployAndVerifyServiceAccount(oktetoPath string, k8sClient kubernetes.Interface, deployOptions *DeployOptions, saName string) (*corev1.ServiceAccount, error) {
+	if err := RunOktetoDeploy(oktetoPath, deployOptions); err != nil {
+		return nil, err
+	}
+	return waitForServiceAccount(k8sClient, deployOptions.Namespace, saName, time.Second, serviceAccountTimeout)
+}
+
+func waitForServiceAccount(k8sClient kubernetes.Interface, ns, name string, interval, timeout time.Duration) (*corev1.ServiceAccount, error) {
+	var sa *corev1.ServiceAccount
+	var err error
+	found := poll(interval, timeout, func() bool {
+		sa, err = k8sClient.CoreV1().ServiceAccounts(ns).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			log.Printf("error getting service account '%s': %s", name, err)
+			return false
+		}
+		return true
+	})
+	if !found {
+		return nil, fmt.Errorf("service account '%s' not found in namespace '%s' after %s: %w", name, ns, timeout.String(), err)
+	}
+	return sa, nil
+}
+
+// RunOktetoDeployAndGetServiceExternalIP runs an okteto deploy command and returns the ip or hostname assigned
+// to the LoadBalancer service svcName, failing if it isn't assigned within 5 minutes
+func RunOktetoDeployAndGetServiceExternalIP(oktetoPath string, k8sClient kubernetes.Interface, deployOptions *DeployOptions, svcName string) (string, error) {
+	if err := RunOktetoDeploy(oktetoPath, deployOptions); err != nil {
+		return "", err
+	}
+	return waitForServiceExternalIP(k8sClient, deployOptions.Namespace, svcName, 5*time.Second, externalIPTimeout)
+}
+
+func waitForServiceExternalIP(k8sClient kubernetes.Interface, ns, name string, interval, timeout time.Duration) (string, error) {
+	var externalIP string
+	assigned := poll(interval, timeout, func() bool {
+		svc, err := k8sClient.CoreV1().Services(ns).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			log.Printf("error getting service '%s': %s", name, err)
+			return false
+		}
+		if ingress := svc.Status.LoadBalancer.Ingress; len(ingress) > 0 {
+			externalIP = ingress[0].IP
+			if externalIP == "" {
+				externalIP = ingress[0].Hostname
+			}
+		}
+		return externalIP != ""
+	})
+	if !assigned {
+		return "", fmt.Errorf("service '%s' in namespace '%s' has no external ip after %s", name, ns, timeout.String())
+	}
+	return externalIP, nil
+}
+
+// RunOktetoDeployAndGetVolumes runs an okteto deploy command and returns the persistent volume claims deployed by
+// the development environment in its namespace. The name of the development environment is required
+func RunOktetoDeployAndGetVolumes(oktetoPath string, k8sClient kubernetes.Interface, deployOptions *DeployOptions) ([]corev1.PersistentVolumeClaim, error) {
+	if deployOptions.Name == "" {
+		return nil, fmt.Errorf("the name of the development environment is required to get its volumes")
+	}
+	if err := RunOktetoDeploy(oktetoPath, deployOptions); err != nil {
+		return nil, err
+	}
+	return listVolumes(k8sClient, deployOptions.Namespace, deployOptions.Name)
+}
+
+func listVolumes(k8sClient kubernetes.Interface, ns, name string) ([]corev1.PersistentVolumeClaim, error) {
+	selector := fmt.Sprintf("%s=%s", model.DeployedByLabel, format.ResourceK8sMetaString(name))
+	pvcs, err := k8sClient.CoreV1().PersistentVolumeClaims(ns).List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("could not list volumes of '%s' in namespace '%s': %w", name, ns, err)
+	}
+	return pvcs.Items, nil
+}
+
+// RunOktetoDeployAndAssertConfigMapData runs an okteto deploy command and returns an error with the differences
+// if the data of the configmap cmName in the namespace of the development environment is not expectedData
+func RunOktetoDeployAndAssertConfigMapData(oktetoPath string, k8sClient kubernetes.Interface, deployOptions *DeployOptions, cmName string, expectedData map[string]string) error {
+	if err := RunOktetoDeploy(oktetoPath, deployOptions); err != nil {
+		return err
+	}
+	return assertConfigMapData(k8sClient, deployOptions.Namespace, cmName, expectedData)
+}
+
+func assertConfigMapData(k8sClient kubernetes.Interface, ns, cmName string, expectedData map[string]string) error {
+	cmap, err := k8sClient.CoreV1().ConfigMaps(ns).Get(context.Background(), cmName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("could not get configmap '%s' in namespace '%s': %w", cmName, ns, err)
+	}
+	data := cmap.Data
+	if data == nil {
+		data = map[string]string{}
+	}
+	expected := expectedData
+	if expected == nil {
+		expected = map[string]string{}
+	}
+	if reflect.DeepEqual(expected, data) {
+		return nil
+	}
+	return fmt.Errorf("data of configmap '%s' in namespace '%s' doesn't match:\n%s", cmName, ns, diffConfigMapData(expected, data))
+}
+
+// diffConfigMapData returns a line per key whose value differs, sorted by key
+func diffConfigMapData(expected, actual map[string]string) string {
+	keys := map[string]bool{}
+	for k := range expected {
+		keys[k] = true
+	}
+	for k := range actual {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var lines []string
+	for _, k := range sorted {
+		e, inExpected := expected[k]
+		a, inActual := actual[k]
+		switch {
+		case !inActual:
+			lines = append(lines, fmt.Sprintf("- %s: %q", k, e))
+		case !inExpected:
+			lines = append(lines, fmt.Sprintf("+ %s: %q", k, a))
+		case e != a:
+			lines = append(lines, fmt.Sprintf("- %s: %q", k, e), fmt.Sprintf("+ %s: %q", k, a))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RunOktetoDeployAndVerifyResourceQuota runs an okteto deploy command and returns an error if the CPU or memory
+// used by any resource quota of the namespace of the development environment exceeds maxCPU or maxMemory
+func RunOktetoDeployAndVerifyResourceQuota(oktetoPath string, k8sClient kubernetes.Interface, deployOptions *DeployOptions, maxCPU, maxMemory string) error {
+	if err := RunOktetoDeploy(oktetoPath, deployOptions); err != nil {
+		return err
+	}
+	return verifyResourceQuota(k8sClient, deployOptions.Namespace, maxCPU, maxMemory)
+}
+
+var (
+	// quotaCPUResources are the resources of a quota that account for CPU
+	quotaCPUResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceRequestsCPU, corev1.ResourceLimitsCPU}
+	// quotaMemoryResources are the resources of a quota that account for memory
+	quotaMemoryResources = []corev1.ResourceName{corev1.ResourceMemory, corev1.ResourceRequestsMemory, corev1.ResourceLimitsMemory}
+)
+
+func verifyResourceQuota(k8sClient kubernetes.Interface, ns, maxCPU, maxMemory string) error {
+	cpu, err := resource.ParseQuantity(maxCPU)
+	if err != nil {
+		return fmt.Errorf("invalid max cpu '%s': %w", maxCPU, err)
+	}
+	memory, err := resource.ParseQuantity(maxMemory)
+	if err != nil {
+		return fmt.Errorf("invalid max memory '%s': %w", maxMemory, err)
+	}
+
+	quotas, err := k8sClient.CoreV1().ResourceQuotas(ns).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("could not list resource quotas in namespace '%s': %w", ns, err)
+	}
+	if len(quotas.Items) == 0 {
+		return fmt.Errorf("no resource quota found in namespace '%s'", ns)
+	}
+
+	var exceeded []string
+	for _, q := range quotas.Items {
+		exceeded = append(exceeded, exceededQuotaResources(q, quotaCPUResources, cpu)...)
+		exceeded = append(exceeded, exceededQuotaResources(q, quotaMemoryResources, memory)...)
+	}
+	if len(exceeded) > 0 {
+		sort.Strings(exceeded)
+		return fmt.Errorf("resource quota usage in namespace '%s' exceeds the expected bounds:\n%s", ns, strings.Join(exceeded, "\n"))
+	}
+	return nil
+}
+
+// exceededQuotaResources returns a line per resource of names whose usage in the quota is over limit
+func exceededQuotaResources(q corev1.ResourceQuota, names []corev1.ResourceName, limit resource.Quantity) []string {
+	var result []string
+	for _, name := range names {
+		used, ok := q.Status.Used[name]
+		if !ok || used.Cmp(limit) <= 0 {
+			continue
+		}
+		result = append(result, fmt.Sprintf("%s/%s: used %s, max %s", q.Name, name, used.String(), limit.String()))
+	}
+	return result
+}
+
+// RunOktetoDeployAndVerifyNetworkPolicy runs an okteto deploy command and returns an error if the network policy
+// doesn't exist in the namespace of the development environment or if it doesn't isolate its ingress and egress traffic
+func RunOktetoDeployAndVerifyNetworkPolicy(oktetoPath string, k8sClient kubernetes.Interface, deployOptions *DeployOptions, policyName string) error {
+	if err := RunOktetoDeploy(oktetoPath, deployOptions); err != nil {
+		return err
+	}
+	return verifyNetworkPolicy(k8sClient, deployOptions.Namespace, policyName)
+}
+
+func verifyNetworkPolicy(k8sClient kubernetes.Interface, ns, name string) error {
+	policy, err := k8sClient.NetworkingV1().NetworkPolicies(ns).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("could not get network policy '%s' in namespace '%s': %w", name, ns, err)
+	}
+
+	var problems []string
+	for _, policyType := range []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress} {
+		if !hasPolicyType(policy.Spec.PolicyTypes, policyType) {
+			problems = append(problems, fmt.Sprintf("policy type '%s' is not set, its traffic is not restricted", policyType))
+		}
+	}
+	for i, rule := range policy.Spec.Ingress {
+		if len(rule.From) == 0 && len(rule.Ports) == 0 {
+			problems = append(problems, fmt.Sprintf("ingress rule %d allows traffic from any source", i))
+		}
+	}
+	for i, rule := range policy.Spec.Egress {
+		if len(rule.To) == 0 && len(rule.Ports) == 0 {
+			problems = append(problems, fmt.Sprintf("egress rule %d allows traffic to any destination", i))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("network policy '%s' in namespace '%s' doesn't isolate the development environment:\n%s", name, ns, strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+func hasPolicyType(policyTypes []networkingv1.PolicyType, policyType networkingv1.PolicyType) bool {
+	for _, t := range policyTypes {
+		if t == policyType {
+			return true
+		}
+	}
+	return false
+}
+
 // RunOktetoDestroy runs an okteto destroy command
 func RunOktetoDestroy(oktetoPath string, destroyOptions *DestroyOptions) error {
 	log.Printf("okteto destroy %s", oktetoPath)
 	cmd := getDestroyCmd(oktetoPath, destroyOptions)
 	o, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("okteto deploy failed: %s - %s", string(o), err)
+		return fmt.Errorf("okteto deploy failed: %s - %w", string(o), err)
 	}
 	log.Printf("okteto destroy success")
 	return nil
@@ -150,6 +689,9 @@ func getDestroyCmd(oktetoPath string, destroyOptions *DestroyOptions) *exec.Cmd
 	if destroyOptions.Token != "" {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", model.OktetoTokenEnvVar, destroyOptions.Token))
 	}
+	if destroyOptions.FailAtStage != "" {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", chaos.FailAtStageEnvVar, destroyOptions.FailAtStage))
+	}
 	return cmd
 }
 
@@ -189,11 +731,14 @@ func getDeployCmd(oktetoPath string, deployOptions *DeployOptions) *exec.Cmd {
 
 	if deployOptions.OktetoHome != "" {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", constants.OktetoHomeEnvVar, deployOptions.OktetoHome))
-		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", constants.KubeConfigEnvVar, filepath.Join(deployOptions.OktetoHome, ".kube", "config")))
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", constants.KubeConfigEnvVar, GetKubeconfigPath(deployOptions.OktetoHome)))
 	}
 	if deployOptions.Token != "" {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", model.OktetoTokenEnvVar, deployOptions.Token))
 	}
+	if deployOptions.FailAtStage != "" {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", chaos.FailAtStageEnvVar, deployOptions.FailAtStage))
+	}
 
 	return cmd
 }
@@ -0,0 +1,406 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newPod(name string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test",
+			Labels:    labels,
+		},
+	}
+}
+
+func TestCountPods(t *testing.T) {
+	c := fake.NewSimpleClientset(
+		newPod("a", map[string]string{"app": "a"}),
+		newPod("b", map[string]string{"app": "a"}),
+		newPod("c", map[string]string{"app": "c"}),
+	)
+	count, err := countPods(c, "test", "app=a")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestWaitForStablePodCount(t *testing.T) {
+	c := fake.NewSimpleClientset(newPod("a", map[string]string{"app": "a"}))
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_, err := c.CoreV1().Pods("test").Create(context.Background(), newPod("b", map[string]string{"app": "a"}), metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}()
+
+	count, err := waitForStablePodCount(c, "test", "app=a", 100*time.Millisecond, 10*time.Millisecond, 5*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestWaitForStablePodCountTimeout(t *testing.T) {
+	c := fake.NewSimpleClientset(newPod("a", map[string]string{"app": "a"}))
+	_, err := waitForStablePodCount(c, "test", "app=a", time.Second, 10*time.Millisecond, 50*time.Millisecond)
+	assert.Error(t, err)
+}
+
+func TestWaitForServiceAccount(t *testing.T) {
+	c := fake.NewSimpleClientset()
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		sa := &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "api",
+				Namespace:   "test",
+				Annotations: map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123:role/api"},
+			},
+		}
+		_, err := c.CoreV1().ServiceAccounts("test").Create(context.Background(), sa, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}()
+
+	sa, err := waitForServiceAccount(c, "test", "api", 10*time.Millisecond, 5*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "arn:aws:iam::123:role/api", sa.Annotations["eks.amazonaws.com/role-arn"])
+}
+
+func TestWaitForServiceAccountTimeout(t *testing.T) {
+	c := fake.NewSimpleClientset()
+	_, err := waitForServiceAccount(c, "test", "api", 10*time.Millisecond, 50*time.Millisecond)
+	assert.Error(t, err)
+}
+
+func TestWaitForServiceExternalIP(t *testing.T) {
+	tests := []struct {
+		name     string
+		ingress  corev1.LoadBalancerIngress
+		expected string
+	}{
+		{
+			name:     "ip",
+			ingress:  corev1.LoadBalancerIngress{IP: "10.0.0.1"},
+			expected: "10.0.0.1",
+		},
+		{
+			name:     "hostname",
+			ingress:  corev1.LoadBalancerIngress{Hostname: "api.elb.amazonaws.com"},
+			expected: "api.elb.amazonaws.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "test"},
+				Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+			}
+			c := fake.NewSimpleClientset(svc)
+			go func() {
+				time.Sleep(30 * time.Millisecond)
+				svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{tt.ingress}
+				_, err := c.CoreV1().Services("test").UpdateStatus(context.Background(), svc, metav1.UpdateOptions{})
+				assert.NoError(t, err)
+			}()
+
+			ip, err := waitForServiceExternalIP(c, "test", "api", 10*time.Millisecond, 5*time.Second)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ip)
+		})
+	}
+}
+
+func TestWaitForServiceExternalIPTimeout(t *testing.T) {
+	c := fake.NewSimpleClientset(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "test"}})
+	_, err := waitForServiceExternalIP(c, "test", "api", 10*time.Millisecond, 50*time.Millisecond)
+	assert.ErrorContains(t, err, "has no external ip")
+}
+
+func TestComposeDeployCmd(t *testing.T) {
+	opts := &ComposeDeployOptions{
+		Workdir:     "/tmp/app",
+		ComposeFile: "docker-compose.yml",
+		Namespace:   "test",
+		Services:    []string{"api", "db"},
+	}
+	cmd := getDeployCmd("okteto", opts.toDeployOptions())
+	assert.Equal(t, "/tmp/app", cmd.Dir)
+	assert.Equal(t, []string{"okteto", "deploy", "api", "db", "-f", "docker-compose.yml", "--namespace", "test"}, cmd.Args)
+}
+
+func writeFakeOkteto(t *testing.T, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake okteto binary is a shell script")
+	}
+	p := filepath.Join(t.TempDir(), "okteto")
+	require.NoError(t, os.WriteFile(p, []byte("#!/bin/sh\n"+script), 0700))
+	return p
+}
+
+func TestRunOktetoDeployWithTimeout(t *testing.T) {
+	oktetoPath := writeFakeOkteto(t, "echo deployed\n")
+
+	output, err := RunOktetoDeployWithTimeout(oktetoPath, &DeployOptions{}, 5*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "deployed\n", output)
+}
+
+func TestRunOktetoDeployWithTimeoutCapturesOutput(t *testing.T) {
+	oktetoPath := writeFakeOkteto(t, "echo deploying\necho failing >&2\nexec sleep 10\n")
+
+	output, err := RunOktetoDeployWithTimeout(oktetoPath, &DeployOptions{}, 500*time.Millisecond)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, output, "deploying\n")
+	assert.Contains(t, output, "failing\n")
+}
+
+func TestRunOktetoDeployAndGetDeploymentYAML(t *testing.T) {
+	oktetoPath := writeFakeOkteto(t, "echo deployed\n")
+	c := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "api",
+			Namespace:         "test",
+			Labels:            map[string]string{"app": "api"},
+			UID:               "1234",
+			ResourceVersion:   "42",
+			Generation:        3,
+			CreationTimestamp: metav1.Now(),
+		},
+		Status: appsv1.DeploymentStatus{ReadyReplicas: 1},
+	})
+
+	out, err := RunOktetoDeployAndGetDeploymentYAML(oktetoPath, c, &DeployOptions{Namespace: "test"}, "api")
+	require.NoError(t, err)
+	expected := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  creationTimestamp: null
+  labels:
+    app: api
+  name: api
+  namespace: test
+spec:
+  selector: null
+  strategy: {}
+  template:
+    metadata:
+      creationTimestamp: null
+    spec:
+      containers: null
+status: {}
+`
+	assert.Equal(t, expected, out)
+}
+
+func TestRunOktetoDeployAndGetDeploymentYAMLNotFound(t *testing.T) {
+	oktetoPath := writeFakeOkteto(t, "echo deployed\n")
+
+	_, err := RunOktetoDeployAndGetDeploymentYAML(oktetoPath, fake.NewSimpleClientset(), &DeployOptions{Namespace: "test"}, "api")
+	assert.ErrorContains(t, err, "could not get deployment 'api' in namespace 'test'")
+}
+
+func TestRunOktetoDeployAndGetVolumes(t *testing.T) {
+	oktetoPath := writeFakeOkteto(t, "echo deployed\n")
+	newPVC := func(name, ns string, labels map[string]string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ns,
+				Labels:    labels,
+			},
+		}
+	}
+	c := fake.NewSimpleClientset(
+		newPVC("data", "test", map[string]string{model.DeployedByLabel: "my-app"}),
+		newPVC("other", "test", map[string]string{model.DeployedByLabel: "other-app"}),
+		newPVC("data", "other-ns", map[string]string{model.DeployedByLabel: "my-app"}),
+	)
+
+	pvcs, err := RunOktetoDeployAndGetVolumes(oktetoPath, c, &DeployOptions{Namespace: "test", Name: "My App"})
+	require.NoError(t, err)
+	require.Len(t, pvcs, 1)
+	assert.Equal(t, "data", pvcs[0].Name)
+	assert.Equal(t, "test", pvcs[0].Namespace)
+}
+
+func TestRunOktetoDeployAndGetVolumesWithoutName(t *testing.T) {
+	oktetoPath := writeFakeOkteto(t, "echo deployed\n")
+
+	_, err := RunOktetoDeployAndGetVolumes(oktetoPath, fake.NewSimpleClientset(), &DeployOptions{Namespace: "test"})
+	assert.ErrorContains(t, err, "the name of the development environment is required")
+}
+
+func TestAssertConfigMapData(t *testing.T) {
+	c := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "test"},
+		Data: map[string]string{
+			"env":   "prod",
+			"level": "debug",
+			"extra": "1",
+		},
+	})
+
+	require.NoError(t, assertConfigMapData(c, "test", "settings", map[string]string{"env": "prod", "level": "debug", "extra": "1"}))
+
+	err := assertConfigMapData(c, "test", "settings", map[string]string{"env": "prod", "level": "info", "missing": "x"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "+ extra: \"1\"\n- level: \"info\"\n+ level: \"debug\"\n- missing: \"x\"")
+
+	require.Error(t, assertConfigMapData(c, "test", "not-found", nil))
+}
+
+func TestAssertConfigMapDataEmpty(t *testing.T) {
+	c := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "test"},
+	})
+	require.NoError(t, assertConfigMapData(c, "test", "settings", map[string]string{}))
+}
+
+func TestRunOktetoDeployAndAssertConfigMapData(t *testing.T) {
+	oktetoPath := writeFakeOkteto(t, "exit 0\n")
+	c := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "test"},
+		Data:       map[string]string{"env": "prod"},
+	})
+
+	err := RunOktetoDeployAndAssertConfigMapData(oktetoPath, c, &DeployOptions{Namespace: "test"}, "settings", map[string]string{"env": "prod"})
+	require.NoError(t, err)
+}
+
+func newResourceQuota(name string, used corev1.ResourceList) *corev1.ResourceQuota {
+	return &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Status:     corev1.ResourceQuotaStatus{Used: used},
+	}
+}
+
+func TestVerifyResourceQuota(t *testing.T) {
+	c := fake.NewSimpleClientset(
+		newResourceQuota("compute", corev1.ResourceList{
+			corev1.ResourceRequestsCPU:    resource.MustParse("500m"),
+			corev1.ResourceRequestsMemory: resource.MustParse("256Mi"),
+		}),
+		newResourceQuota("limits", corev1.ResourceList{
+			corev1.ResourceLimitsCPU:    resource.MustParse("1"),
+			corev1.ResourceLimitsMemory: resource.MustParse("1Gi"),
+		}),
+	)
+
+	require.NoError(t, verifyResourceQuota(c, "test", "1", "1Gi"))
+
+	err := verifyResourceQuota(c, "test", "750m", "512Mi")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "limits/limits.cpu: used 1, max 750m\nlimits/limits.memory: used 1Gi, max 512Mi")
+	assert.NotContains(t, err.Error(), "compute/")
+
+	require.Error(t, verifyResourceQuota(c, "test", "not-a-quantity", "1Gi"))
+	require.Error(t, verifyResourceQuota(c, "empty", "1", "1Gi"))
+}
+
+func TestRunOktetoDeployAndVerifyResourceQuota(t *testing.T) {
+	oktetoPath := writeFakeOkteto(t, "exit 0\n")
+	c := fake.NewSimpleClientset(newResourceQuota("compute", corev1.ResourceList{
+		corev1.ResourceRequestsCPU: resource.MustParse("100m"),
+	}))
+
+	err := RunOktetoDeployAndVerifyResourceQuota(oktetoPath, c, &DeployOptions{Namespace: "test"}, "200m", "128Mi")
+	require.NoError(t, err)
+}
+
+func newNetworkPolicy(name string, spec networkingv1.NetworkPolicySpec) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Spec:       spec,
+	}
+}
+
+func TestVerifyNetworkPolicy(t *testing.T) {
+	sameNamespace := []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}}
+	c := fake.NewSimpleClientset(
+		newNetworkPolicy("isolated", networkingv1.NetworkPolicySpec{
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			Ingress:     []networkingv1.NetworkPolicyIngressRule{{From: sameNamespace}},
+			Egress:      []networkingv1.NetworkPolicyEgressRule{{To: sameNamespace}},
+		}),
+		newNetworkPolicy("open", networkingv1.NetworkPolicySpec{
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     []networkingv1.NetworkPolicyIngressRule{{From: sameNamespace}, {}},
+		}),
+	)
+
+	require.NoError(t, verifyNetworkPolicy(c, "test", "isolated"))
+
+	err := verifyNetworkPolicy(c, "test", "open")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "policy type 'Egress' is not set, its traffic is not restricted\ningress rule 1 allows traffic from any source")
+
+	err = verifyNetworkPolicy(c, "test", "missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "could not get network policy 'missing' in namespace 'test'")
+}
+
+func TestRunOktetoDeployAndVerifyNetworkPolicy(t *testing.T) {
+	oktetoPath := writeFakeOkteto(t, "exit 0\n")
+	c := fake.NewSimpleClientset(newNetworkPolicy("deny-all", networkingv1.NetworkPolicySpec{
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+	}))
+
+	err := RunOktetoDeployAndVerifyNetworkPolicy(oktetoPath, c, &DeployOptions{Namespace: "test"}, "deny-all")
+	require.NoError(t, err)
+}
+
+func TestGetWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected []string
+	}{
+		{
+			name:   "no warnings",
+			output: "Running 'kubectl apply'\nDevelopment environment 'app' successfully deployed\n",
+		},
+		{
+			name:     "plain warning",
+			output:   "Running 'kubectl apply'\r\nWARNING: The flag '--volumes' is set\r\ndone\r\n",
+			expected: []string{"WARNING: The flag '--volumes' is set"},
+		},
+		{
+			name:     "json warnings",
+			output:   `{"level":"warn","message":"first"}` + "\n" + `{"level":"info","message":"ok"}` + "\n" + `{"level":"warn","message":"second"}`,
+			expected: []string{`{"level":"warn","message":"first"}`, `{"level":"warn","message":"second"}`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, getWarnings(tt.output))
+		})
+	}
+}
@@ -25,6 +25,7 @@ import (
 
 // ExecOptions are the options to add to an exec command
 type ExecOptions struct {
+	Workdir      string
 	Namespace    string
 	ManifestPath string
 	Command      string
@@ -36,6 +37,9 @@ type ExecOptions struct {
 func RunExecCommand(oktetoPath string, execOptions *ExecOptions) (string, error) {
 	cmd := exec.Command(oktetoPath, "exec")
 	cmd.Env = os.Environ()
+	if execOptions.Workdir != "" {
+		cmd.Dir = execOptions.Workdir
+	}
 	if execOptions.Namespace != "" {
 		cmd.Args = append(cmd.Args, "-n", execOptions.Namespace)
 	}
@@ -0,0 +1,179 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+var (
+	clientsetsMu sync.Mutex
+	clientsets   = map[string]kubernetes.Interface{}
+
+	// newClientset builds the clientset for a kubeconfig path. It is overridden in tests
+	newClientset = func(kubeconfig string) (kubernetes.Interface, error) {
+		cfg, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+		if err != nil {
+			return nil, err
+		}
+		return kubernetes.NewForConfig(cfg)
+	}
+)
+
+// GetKubeconfigPath returns the kubeconfig used by the okteto commands run with the given okteto home
+func GetKubeconfigPath(oktetoHome string) string {
+	return filepath.Join(oktetoHome, ".kube", "config")
+}
+
+// getClientset returns the clientset for a kubeconfig, creating it the first time it is requested
+func getClientset(kubeconfig string) (kubernetes.Interface, error) {
+	clientsetsMu.Lock()
+	defer clientsetsMu.Unlock()
+	if c, ok := clientsets[kubeconfig]; ok {
+		return c, nil
+	}
+	c, err := newClientset(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not create kubernetes client from '%s': %w", kubeconfig, err)
+	}
+	clientsets[kubeconfig] = c
+	return c, nil
+}
+
+// WaitForDeploymentReady waits until all the replicas of a deployment are ready
+func WaitForDeploymentReady(kubeconfig, ns, name string, timeout time.Duration) error {
+	c, err := getClientset(kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	lastStatus := "not found"
+	ready := poll(time.Second, timeout, func() bool {
+		d, err := c.AppsV1().Deployments(ns).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			lastStatus = err.Error()
+			return false
+		}
+		desired := int32(1)
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		if d.Status.ObservedGeneration >= d.Generation && d.Status.ReadyReplicas == desired && d.Status.UpdatedReplicas == desired {
+			return true
+		}
+		lastStatus = fmt.Sprintf("%d/%d replicas ready, %d updated", d.Status.ReadyReplicas, desired, d.Status.UpdatedReplicas)
+		return false
+	})
+	if !ready {
+		return fmt.Errorf("deployment '%s/%s' is not ready after %s: %s", ns, name, timeout.String(), lastStatus)
+	}
+	return nil
+}
+
+// AssertNoResourcesWithLabel returns an error listing every resource found in the namespace matching the selector
+func AssertNoResourcesWithLabel(kubeconfig, ns, selector string) error {
+	c, err := getClientset(kubeconfig)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	opts := metav1.ListOptions{LabelSelector: selector}
+	var found []string
+
+	deployments, err := c.AppsV1().Deployments(ns).List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, i := range deployments.Items {
+		found = append(found, fmt.Sprintf("deployment/%s", i.Name))
+	}
+
+	statefulsets, err := c.AppsV1().StatefulSets(ns).List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, i := range statefulsets.Items {
+		found = append(found, fmt.Sprintf("statefulset/%s", i.Name))
+	}
+
+	jobs, err := c.BatchV1().Jobs(ns).List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, i := range jobs.Items {
+		found = append(found, fmt.Sprintf("job/%s", i.Name))
+	}
+
+	services, err := c.CoreV1().Services(ns).List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, i := range services.Items {
+		found = append(found, fmt.Sprintf("service/%s", i.Name))
+	}
+
+	configmaps, err := c.CoreV1().ConfigMaps(ns).List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, i := range configmaps.Items {
+		found = append(found, fmt.Sprintf("configmap/%s", i.Name))
+	}
+
+	secrets, err := c.CoreV1().Secrets(ns).List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, i := range secrets.Items {
+		found = append(found, fmt.Sprintf("secret/%s", i.Name))
+	}
+
+	pvcs, err := c.CoreV1().PersistentVolumeClaims(ns).List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, i := range pvcs.Items {
+		found = append(found, fmt.Sprintf("persistentvolumeclaim/%s", i.Name))
+	}
+
+	if len(found) > 0 {
+		return fmt.Errorf("found %d resources with label '%s' in namespace '%s': %s", len(found), selector, ns, strings.Join(found, ", "))
+	}
+	return nil
+}
+
+// GetEnvironmentConfigmap returns the configmap that stores the status of a development environment
+func GetEnvironmentConfigmap(kubeconfig, ns, envName string) (*corev1.ConfigMap, error) {
+	c, err := getClientset(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	name := pipeline.TranslatePipelineName(envName)
+	cmap, err := c.CoreV1().ConfigMaps(ns).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("could not get configmap '%s' of development environment '%s' in namespace '%s': %w", name, envName, ns, err)
+	}
+	return cmap, nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func setFakeClientset(t *testing.T, objects ...runtime.Object) string {
+	t.Helper()
+	kubeconfig := t.Name()
+	c := fake.NewSimpleClientset(objects...)
+	clientsetsMu.Lock()
+	clientsets[kubeconfig] = c
+	clientsetsMu.Unlock()
+	t.Cleanup(func() {
+		clientsetsMu.Lock()
+		delete(clientsets, kubeconfig)
+		clientsetsMu.Unlock()
+	})
+	return kubeconfig
+}
+
+func TestGetClientsetIsLazy(t *testing.T) {
+	calls := 0
+	original := newClientset
+	newClientset = func(string) (kubernetes.Interface, error) {
+		calls++
+		return fake.NewSimpleClientset(), nil
+	}
+	t.Cleanup(func() {
+		newClientset = original
+		clientsetsMu.Lock()
+		delete(clientsets, "lazy")
+		clientsetsMu.Unlock()
+	})
+
+	_, err := getClientset("lazy")
+	require.NoError(t, err)
+	_, err = getClientset("lazy")
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestWaitForDeploymentReady(t *testing.T) {
+	replicas := int32(2)
+	tests := []struct {
+		name      string
+		objects   []runtime.Object
+		expectErr string
+	}{
+		{
+			name: "ready",
+			objects: []runtime.Object{
+				&appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "ns"},
+					Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+					Status:     appsv1.DeploymentStatus{ReadyReplicas: 2, UpdatedReplicas: 2},
+				},
+			},
+		},
+		{
+			name: "not ready",
+			objects: []runtime.Object{
+				&appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "ns"},
+					Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+					Status:     appsv1.DeploymentStatus{ReadyReplicas: 1, UpdatedReplicas: 2},
+				},
+			},
+			expectErr: "1/2 replicas ready, 2 updated",
+		},
+		{
+			name:      "not found",
+			expectErr: "not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeconfig := setFakeClientset(t, tt.objects...)
+			err := WaitForDeploymentReady(kubeconfig, "ns", "api", 10*time.Millisecond)
+			if tt.expectErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectErr)
+		})
+	}
+}
+
+func TestAssertNoResourcesWithLabel(t *testing.T) {
+	labels := map[string]string{"dev.okteto.com/deployed-by": "app"}
+	kubeconfig := setFakeClientset(t,
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "ns", Labels: labels}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "ns", Labels: labels}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "ns", Labels: labels}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ns"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "other-ns", Labels: labels}},
+	)
+
+	err := AssertNoResourcesWithLabel(kubeconfig, "ns", "dev.okteto.com/deployed-by=app")
+	require.Error(t, err)
+	assert.Equal(t, "found 3 resources with label 'dev.okteto.com/deployed-by=app' in namespace 'ns': deployment/api, service/api, persistentvolumeclaim/data", err.Error())
+
+	assert.NoError(t, AssertNoResourcesWithLabel(kubeconfig, "empty-ns", "dev.okteto.com/deployed-by=app"))
+}
+
+func TestGetEnvironmentConfigmap(t *testing.T) {
+	kubeconfig := setFakeClientset(t,
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "okteto-git-my-app", Namespace: "ns"}, Data: map[string]string{"status": "deployed"}},
+	)
+
+	cmap, err := GetEnvironmentConfigmap(kubeconfig, "ns", "my app")
+	require.NoError(t, err)
+	assert.Equal(t, "deployed", cmap.Data["status"])
+
+	_, err = GetEnvironmentConfigmap(kubeconfig, "ns", "missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "okteto-git-missing")
+}
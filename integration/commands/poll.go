@@ -0,0 +1,36 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import "time"
+
+// poll calls condition right away and then every interval until it returns true.
+// It returns false if the condition isn't met before the timeout expires
+func poll(interval, timeout time.Duration, condition func() bool) bool {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	to := time.NewTimer(timeout)
+	defer to.Stop()
+	for {
+		if condition() {
+			return true
+		}
+
+		select {
+		case <-to.C:
+			return false
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoll(t *testing.T) {
+	calls := 0
+	ok := poll(time.Millisecond, 5*time.Second, func() bool {
+		calls++
+		return calls == 3
+	})
+	assert.True(t, ok)
+	assert.Equal(t, 3, calls)
+}
+
+func TestPollChecksConditionRightAway(t *testing.T) {
+	calls := 0
+	ok := poll(time.Hour, time.Hour, func() bool {
+		calls++
+		return true
+	})
+	assert.True(t, ok)
+	assert.Equal(t, 1, calls)
+}
+
+func TestPollTimeout(t *testing.T) {
+	ok := poll(10*time.Millisecond, 50*time.Millisecond, func() bool {
+		return false
+	})
+	assert.False(t, ok)
+}
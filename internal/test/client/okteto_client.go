@@ -28,6 +28,13 @@ func NewFakeOktetoClientProvider(f *FakeOktetoClient) *FakeOktetoClientProvider
 	}
 }
 
+// NewFakeOktetoClientProviderWithError returns a provider that fails with err
+func NewFakeOktetoClientProviderWithError(err error) *FakeOktetoClientProvider {
+	return &FakeOktetoClientProvider{
+		err: err,
+	}
+}
+
 func (p *FakeOktetoClientProvider) Provide() (types.OktetoInterface, error) {
 	return p.c, p.err
 }
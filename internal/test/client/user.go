@@ -22,12 +22,28 @@ type FakeUserClient struct {
 	userCtx     *types.UserContext
 	userSecrets []types.Secret
 	err         []error
+
+	clusterMetadata    types.ClusterMetadata
+	clusterMetadataErr error
+
+	tokenRole    types.TokenRole
+	tokenRoleErr error
 }
 
 func NewFakeUsersClient(user *types.User, err ...error) *FakeUserClient {
 	return &FakeUserClient{userCtx: &types.UserContext{User: *user}, err: err}
 }
 
+// NewFakeUsersClientWithClusterMetadata returns a fake whose GetClusterMetadata returns metadata and err
+func NewFakeUsersClientWithClusterMetadata(metadata types.ClusterMetadata, err error) *FakeUserClient {
+	return &FakeUserClient{userCtx: &types.UserContext{}, clusterMetadata: metadata, clusterMetadataErr: err}
+}
+
+// NewFakeUsersClientWithTokenRole returns a fake whose GetTokenRole returns role and err
+func NewFakeUsersClientWithTokenRole(role types.TokenRole, err error) *FakeUserClient {
+	return &FakeUserClient{userCtx: &types.UserContext{}, tokenRole: role, tokenRoleErr: err}
+}
+
 func (c *FakeUserClient) GetContext(_ context.Context, ns string) (*types.UserContext, error) {
 	if c.err != nil && len(c.err) > 0 {
 		err := c.err[0]
@@ -47,5 +63,12 @@ func (c *FakeUserClient) GetClusterCertificate(_ context.Context, cluster, ns st
 }
 
 func (c *FakeUserClient) GetClusterMetadata(ctx context.Context, ns string) (types.ClusterMetadata, error) {
-	return types.ClusterMetadata{}, nil
+	return c.clusterMetadata, c.clusterMetadataErr
+}
+
+func (c *FakeUserClient) GetTokenRole(_ context.Context, _ string) (types.TokenRole, error) {
+	if c.tokenRole == "" && c.tokenRoleErr == nil {
+		return types.TokenRoleDeveloper, nil
+	}
+	return c.tokenRole, c.tokenRoleErr
 }
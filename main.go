@@ -17,6 +17,7 @@ import (
 	"context"
 	cryptoRand "crypto/rand"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math/rand"
 	"os"
@@ -25,6 +26,7 @@ import (
 	"unicode"
 
 	"github.com/okteto/okteto/cmd"
+	"github.com/okteto/okteto/cmd/admin"
 	"github.com/okteto/okteto/cmd/build"
 	contextCMD "github.com/okteto/okteto/cmd/context"
 	"github.com/okteto/okteto/cmd/deploy"
@@ -36,6 +38,7 @@ import (
 	"github.com/okteto/okteto/cmd/preview"
 	"github.com/okteto/okteto/cmd/stack"
 	"github.com/okteto/okteto/cmd/up"
+	"github.com/okteto/okteto/cmd/vars"
 	"github.com/okteto/okteto/pkg/analytics"
 	"github.com/okteto/okteto/pkg/config"
 	oktetoErrors "github.com/okteto/okteto/pkg/errors"
@@ -105,6 +108,7 @@ func main() {
 			oktetoLog.SetLevel(logLevel)
 			oktetoLog.SetOutputFormat(outputMode)
 			okteto.SetServerNameOverride(serverNameOverride)
+			okteto.SetInsecureSkipTLSVerifyPolicyFromEnv(os.LookupEnv)
 			oktetoLog.Infof("started %s", strings.Join(os.Args, " "))
 		},
 		PersistentPostRun: func(ccmd *cobra.Command, args []string) {
@@ -138,6 +142,8 @@ func main() {
 	root.AddCommand(cmd.UpdateDeprecated())
 	root.AddCommand(deploy.Deploy(ctx))
 	root.AddCommand(destroy.Destroy(ctx))
+	root.AddCommand(vars.Vars())
+	root.AddCommand(admin.Admin(ctx))
 	root.AddCommand(deploy.Endpoints(ctx))
 	root.AddCommand(logs.Logs(ctx))
 	root.AddCommand(generateFigSpec.NewCmdGenFigSpec())
@@ -159,7 +165,12 @@ func main() {
 			tmp[0] = unicode.ToUpper(tmp[0])
 			message = string(tmp)
 		}
-		oktetoLog.Fail(message)
+		var validationErrs oktetoErrors.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			oktetoLog.FailWithErrors(validationErrs, "%s", message)
+		} else {
+			oktetoLog.Fail(message)
+		}
 		if uErr, ok := err.(oktetoErrors.UserError); ok {
 			if len(uErr.Hint) > 0 {
 				oktetoLog.Hint("    %s", uErr.Hint)
@@ -20,6 +20,7 @@ import (
 
 	"github.com/denisbrodbeck/machineid"
 	"github.com/okteto/okteto/pkg/config"
+	"github.com/okteto/okteto/pkg/filesystem"
 	oktetoLog "github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/okteto"
 )
@@ -136,8 +137,11 @@ func (a *Analytics) save() error {
 		}
 	}
 
-	if err := os.WriteFile(analyticsPath, marshalled, 0600); err != nil {
-		return fmt.Errorf("couldn't save analytics: %s", err)
+	err = config.WithOktetoHomeLock(func() error {
+		return filesystem.WriteFileAtomic(analyticsPath, marshalled, 0600)
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't save analytics: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,154 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dukex/mixpanel"
+	"github.com/okteto/okteto/pkg/config"
+	"github.com/okteto/okteto/pkg/constants"
+)
+
+const (
+	// analyticsModeOff doesn't record any event
+	analyticsModeOff = "off"
+	// analyticsModeLocal writes the events to a local file instead of sending them
+	analyticsModeLocal = "local"
+	// analyticsModeOn sends the events to mixpanel
+	analyticsModeOn = "on"
+
+	localAnalyticsDir      = "analytics"
+	localAnalyticsFile     = "events.jsonl"
+	localAnalyticsMaxBytes = 10 << 20
+)
+
+// eventSink is the destination of the analytics events
+type eventSink interface {
+	Track(distinctID, event string, props map[string]interface{}) error
+}
+
+// getAnalyticsMode returns the mode set by OKTETO_ANALYTICS, defaulting to 'on'
+func getAnalyticsMode() string {
+	switch mode := strings.ToLower(strings.TrimSpace(os.Getenv(constants.OktetoAnalyticsEnvVar))); mode {
+	case analyticsModeOff, analyticsModeLocal:
+		return mode
+	default:
+		return analyticsModeOn
+	}
+}
+
+// getSink returns the sink for the analytics mode
+func getSink(mode string) eventSink {
+	switch mode {
+	case analyticsModeOff:
+		return noopSink{}
+	case analyticsModeLocal:
+		return newLocalSink(filepath.Join(config.GetOktetoHome(), localAnalyticsDir, localAnalyticsFile), localAnalyticsMaxBytes)
+	default:
+		return mixpanelSink{client: mixpanelClient}
+	}
+}
+
+type noopSink struct{}
+
+func (noopSink) Track(string, string, map[string]interface{}) error {
+	return nil
+}
+
+type mixpanelSink struct {
+	client mixpanel.Mixpanel
+}
+
+func (s mixpanelSink) Track(distinctID, event string, props map[string]interface{}) error {
+	return s.client.Track(distinctID, event, &mixpanel.Event{Properties: props})
+}
+
+// localEvent is each of the lines of the local analytics file
+type localEvent struct {
+	Time       time.Time              `json:"time"`
+	Event      string                 `json:"event"`
+	DistinctID string                 `json:"distinctId"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// localSink appends the events to a JSONL file, rotating it when it reaches maxBytes
+type localSink struct {
+	path     string
+	maxBytes int64
+	now      func() time.Time
+}
+
+// localSinkMu serializes the writes of concurrent events to the local file
+var localSinkMu sync.Mutex
+
+func newLocalSink(path string, maxBytes int64) *localSink {
+	return &localSink{
+		path:     path,
+		maxBytes: maxBytes,
+		now:      time.Now,
+	}
+}
+
+func (s *localSink) Track(distinctID, event string, props map[string]interface{}) error {
+	line, err := json.Marshal(localEvent{
+		Time:       s.now().UTC(),
+		Event:      event,
+		DistinctID: distinctID,
+		Properties: props,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode analytics event: %w", err)
+	}
+	line = append(line, '\n')
+
+	localSinkMu.Lock()
+	defer localSinkMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	if err := s.rotateIfNeeded(int64(len(line))); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(line)
+	return err
+}
+
+// rotateIfNeeded moves the current file to '<path>.1' when appending size bytes would exceed maxBytes
+func (s *localSink) rotateIfNeeded(size int64) error {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if info.Size()+size <= s.maxBytes {
+		return nil
+	}
+	return os.Rename(s.path, s.path+".1")
+}
@@ -0,0 +1,113 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_getAnalyticsMode(t *testing.T) {
+	var tests = []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "unset", value: "", expected: analyticsModeOn},
+		{name: "off", value: "off", expected: analyticsModeOff},
+		{name: "local", value: "LOCAL", expected: analyticsModeLocal},
+		{name: "on", value: "on", expected: analyticsModeOn},
+		{name: "unknown", value: "whatever", expected: analyticsModeOn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(constants.OktetoAnalyticsEnvVar, tt.value)
+			assert.Equal(t, tt.expected, getAnalyticsMode())
+		})
+	}
+}
+
+func Test_getSink(t *testing.T) {
+	t.Setenv(constants.OktetoFolderEnvVar, t.TempDir())
+
+	assert.IsType(t, noopSink{}, getSink(analyticsModeOff))
+	assert.IsType(t, &localSink{}, getSink(analyticsModeLocal))
+	assert.IsType(t, mixpanelSink{}, getSink(analyticsModeOn))
+}
+
+func readLocalEvents(t *testing.T, path string) []localEvent {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var events []localEvent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e localEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		events = append(events, e)
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
+
+func Test_trackLocal(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(constants.OktetoFolderEnvVar, home)
+	t.Setenv(constants.OktetoAnalyticsEnvVar, analyticsModeLocal)
+
+	track(upEvent, true, map[string]interface{}{"duration": 1.5})
+
+	events := readLocalEvents(t, filepath.Join(home, localAnalyticsDir, localAnalyticsFile))
+	require.Len(t, events, 1)
+	assert.Equal(t, upEvent, events[0].Event)
+	assert.Equal(t, true, events[0].Properties["success"])
+	assert.Equal(t, 1.5, events[0].Properties["duration"])
+}
+
+func Test_trackOff(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(constants.OktetoFolderEnvVar, home)
+	t.Setenv(constants.OktetoAnalyticsEnvVar, analyticsModeOff)
+
+	track(upEvent, true, nil)
+
+	_, err := os.Stat(filepath.Join(home, localAnalyticsDir))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func Test_localSinkRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), localAnalyticsDir, localAnalyticsFile)
+	s := newLocalSink(path, 200)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, s.Track("id", "event", map[string]interface{}{"i": i}))
+	}
+
+	current, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, current.Size(), int64(200))
+
+	rotated := readLocalEvents(t, path+".1")
+	assert.NotEmpty(t, rotated)
+	assert.NotEmpty(t, readLocalEvents(t, path))
+}
@@ -353,8 +353,10 @@ func TrackLogin(success bool) {
 
 // TrackSignup sends a tracking event to mixpanel when the user signs up
 func TrackSignup(success bool, userID string) {
-	if err := mixpanelClient.Alias(get().MachineID, userID); err != nil {
-		oktetoLog.Errorf("failed to alias %s to %s", get().MachineID, userID)
+	if getAnalyticsMode() == analyticsModeOn {
+		if err := mixpanelClient.Alias(get().MachineID, userID); err != nil {
+			oktetoLog.Errorf("failed to alias %s to %s", get().MachineID, userID)
+		}
 	}
 
 	track(signupEvent, success, nil)
@@ -396,7 +398,13 @@ func TrackBuildPullError(oktetoBuilkitURL string, success bool) {
 }
 
 func track(event string, success bool, props map[string]interface{}) {
-	if !get().Enabled {
+	mode := getAnalyticsMode()
+	if mode == analyticsModeOff {
+		return
+	}
+
+	// local events never leave the machine, so they don't depend on the analytics settings
+	if mode == analyticsModeOn && !get().Enabled {
 		return
 	}
 
@@ -405,7 +413,7 @@ func track(event string, success bool, props map[string]interface{}) {
 		return
 	}
 
-	if disabledByOktetoAdmin() {
+	if mode == analyticsModeOn && disabledByOktetoAdmin() {
 		return
 	}
 
@@ -449,8 +457,7 @@ func track(event string, success bool, props map[string]interface{}) {
 		props["term-type"] = termType
 	}
 
-	e := &mixpanel.Event{Properties: props}
-	if err := mixpanelClient.Track(getTrackID(), event, e); err != nil {
+	if err := getSink(mode).Track(getTrackID(), event, props); err != nil {
 		oktetoLog.Infof("Failed to send analytics: %s", err)
 	}
 }
@@ -0,0 +1,48 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package chaos injects failures into okteto commands for the end-to-end tests of their recovery.
+// Failures are only injected by binaries built with the 'chaos' build tag
+package chaos
+
+const (
+	// FailAtStageEnvVar is the stage where a chaos build of okteto exits
+	FailAtStageEnvVar = "OKTETO_FAIL_AT_STAGE"
+
+	// ExitCode is the exit code of the process when a failure is injected
+	ExitCode = 113
+
+	// DeployConfigMapCreatedStage is reached once the deploy marked the development environment as progressing
+	DeployConfigMapCreatedStage = "deploy-configmap-created"
+
+	// DeployImagesBuiltStage is reached once the deploy built the images of the manifest
+	DeployImagesBuiltStage = "deploy-images-built"
+
+	// DeployCommandsExecutedStage is reached once the deploy commands succeeded, before the status is updated
+	DeployCommandsExecutedStage = "deploy-commands-executed"
+
+	// DestroyConfigMapUpdatedStage is reached once the destroy marked the development environment as destroying
+	DestroyConfigMapUpdatedStage = "destroy-configmap-updated"
+
+	// DestroyCommandsExecutedStage is reached once the destroy commands finished
+	DestroyCommandsExecutedStage = "destroy-commands-executed"
+
+	// DestroyResourcesDeletedStage is reached once the resources were deleted, before the configmap is deleted
+	DestroyResourcesDeletedStage = "destroy-resources-deleted"
+)
+
+// FailAt exits the process when it is a chaos build and OKTETO_FAIL_AT_STAGE is stage.
+// The process exits without running deferred functions, as if it was killed
+func FailAt(stage string) {
+	failAt(stage)
+}
@@ -0,0 +1,33 @@
+//go:build chaos
+// +build chaos
+
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaos
+
+import (
+	"fmt"
+	"os"
+)
+
+// exit is overridden in tests
+var exit = os.Exit
+
+func failAt(stage string) {
+	if os.Getenv(FailAtStageEnvVar) != stage {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "okteto: failure injected at stage '%s'\n", stage)
+	exit(ExitCode)
+}
@@ -0,0 +1,66 @@
+//go:build chaos
+// +build chaos
+
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chaos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		stage    string
+		expected []int
+	}{
+		{
+			name:     "stage not set",
+			stage:    DeployImagesBuiltStage,
+			expected: nil,
+		},
+		{
+			name:     "other stage",
+			env:      DeployConfigMapCreatedStage,
+			stage:    DeployImagesBuiltStage,
+			expected: nil,
+		},
+		{
+			name:     "same stage",
+			env:      DeployImagesBuiltStage,
+			stage:    DeployImagesBuiltStage,
+			expected: []int{ExitCode},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(FailAtStageEnvVar, tt.env)
+			var codes []int
+			previousExit := exit
+			exit = func(code int) {
+				codes = append(codes, code)
+			}
+			defer func() {
+				exit = previousExit
+			}()
+
+			FailAt(tt.stage)
+			assert.Equal(t, tt.expected, codes)
+		})
+	}
+}
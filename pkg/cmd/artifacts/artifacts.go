@@ -0,0 +1,224 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifacts
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+const (
+	// MaxSize is the maximum size of a compressed artifacts bundle
+	MaxSize = 10 << 20
+
+	maskedValue = "***"
+
+	lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// Bundle is a set of files generated by a deploy. All the content added to a bundle is scrubbed
+type Bundle struct {
+	files    map[string][]byte
+	replacer *strings.Replacer
+}
+
+// NewBundle returns a bundle that redacts the given secret words from every file added to it
+func NewBundle(secrets []string) *Bundle {
+	words := []string{}
+	for _, s := range secrets {
+		if strings.TrimSpace(s) != "" {
+			words = append(words, s)
+		}
+	}
+	sort.Slice(words, func(i, j int) bool {
+		return len(words[i]) > len(words[j])
+	})
+	oldnew := []string{}
+	for _, w := range words {
+		oldnew = append(oldnew, w, maskedValue)
+	}
+	return &Bundle{
+		files:    map[string][]byte{},
+		replacer: strings.NewReplacer(oldnew...),
+	}
+}
+
+// Add adds a file to the bundle after redacting its secrets
+func (b *Bundle) Add(name string, content []byte) {
+	b.files[filepath.ToSlash(name)] = []byte(b.replacer.Replace(string(content)))
+}
+
+// AddObjects adds the kubernetes objects applied by a deploy as a single json file. The data of secrets is always redacted
+func (b *Bundle) AddObjects(name string, objects [][]byte) error {
+	result := make([]json.RawMessage, 0, len(objects))
+	for _, o := range objects {
+		scrubbed, err := scrubSecretObject(o)
+		if err != nil {
+			return err
+		}
+		result = append(result, scrubbed)
+	}
+	content, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	b.Add(name, content)
+	return nil
+}
+
+// Len returns the number of files in the bundle
+func (b *Bundle) Len() int {
+	return len(b.files)
+}
+
+// Compress returns the bundle as a gzipped tarball, failing if it exceeds maxSize bytes
+func (b *Bundle) Compress(maxSize int) ([]byte, error) {
+	names := make([]string, 0, len(b.files))
+	for name := range b.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	for _, name := range names {
+		content := b.files[name]
+		header := &tar.Header{
+			Name: name,
+			Mode: 0600,
+			Size: int64(len(content)),
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(content); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	if buf.Len() > maxSize {
+		return nil, fmt.Errorf("artifacts bundle is %d bytes, which exceeds the limit of %d bytes", buf.Len(), maxSize)
+	}
+	return buf.Bytes(), nil
+}
+
+// Extract writes the files of a compressed bundle into dir
+func Extract(fs afero.Fs, content []byte, dir string) error {
+	gz, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return fmt.Errorf("invalid artifacts bundle: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid artifacts bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(header.Name))
+		rel, err := filepath.Rel(dir, target)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("invalid file '%s' in artifacts bundle", header.Name)
+		}
+		if err := fs.MkdirAll(filepath.Dir(target), 0700); err != nil {
+			return err
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, MaxSize))
+		if err != nil {
+			return err
+		}
+		if err := afero.WriteFile(fs, target, data, 0600); err != nil {
+			return err
+		}
+	}
+}
+
+func scrubSecretObject(o []byte) ([]byte, error) {
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(o, &body); err != nil {
+		return nil, fmt.Errorf("invalid kubernetes object: %w", err)
+	}
+	var kind string
+	if err := json.Unmarshal(body["kind"], &kind); err != nil || kind != "Secret" {
+		return o, nil
+	}
+
+	for _, field := range []string{"data", "stringData"} {
+		raw, ok := body[field]
+		if !ok {
+			continue
+		}
+		var values map[string]string
+		if err := json.Unmarshal(raw, &values); err != nil {
+			return nil, fmt.Errorf("invalid secret %s: %w", field, err)
+		}
+		for k := range values {
+			values[k] = maskedValue
+		}
+		scrubbed, err := json.Marshal(values)
+		if err != nil {
+			return nil, err
+		}
+		body[field] = scrubbed
+	}
+
+	// kubectl apply keeps a copy of the whole secret in an annotation
+	if raw, ok := body["metadata"]; ok {
+		var metadata map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &metadata); err != nil {
+			return nil, fmt.Errorf("invalid secret metadata: %w", err)
+		}
+		var annotations map[string]string
+		if err := json.Unmarshal(metadata["annotations"], &annotations); err == nil {
+			if _, ok := annotations[lastAppliedConfigAnnotation]; ok {
+				annotations[lastAppliedConfigAnnotation] = maskedValue
+				scrubbed, err := json.Marshal(annotations)
+				if err != nil {
+					return nil, err
+				}
+				metadata["annotations"] = scrubbed
+				scrubbedMetadata, err := json.Marshal(metadata)
+				if err != nil {
+					return nil, err
+				}
+				body["metadata"] = scrubbedMetadata
+			}
+		}
+	}
+	return json.Marshal(body)
+}
@@ -0,0 +1,141 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifacts
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readBundle(t *testing.T, content []byte) map[string]string {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(content))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	files := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[header.Name] = string(data)
+	}
+}
+
+func TestBundleScrubsSecrets(t *testing.T) {
+	secrets := []string{"s3cr3t-token", "hunter2", "hunter2-long", " "}
+	b := NewBundle(secrets)
+	b.Add("okteto.yml", []byte("deploy:\n  - helm upgrade --set token=s3cr3t-token --set pass=hunter2-long\n"))
+	b.Add(filepath.Join("values", "values.yml"), []byte("password: hunter2\n"))
+	require.NoError(t, b.AddObjects("applied-objects.json", [][]byte{
+		[]byte(`{"kind":"ConfigMap","metadata":{"name":"cfg"},"data":{"token":"s3cr3t-token"}}`),
+	}))
+
+	content, err := b.Compress(MaxSize)
+	require.NoError(t, err)
+
+	files := readBundle(t, content)
+	require.Len(t, files, 3)
+	for name, data := range files {
+		for _, s := range []string{"s3cr3t-token", "hunter2"} {
+			assert.NotContains(t, data, s, "secret leaked in %s", name)
+		}
+	}
+	assert.Equal(t, "deploy:\n  - helm upgrade --set token=*** --set pass=***\n", files["okteto.yml"])
+	assert.Equal(t, "password: ***\n", files["values/values.yml"])
+}
+
+func TestBundleScrubsSecretObjects(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString([]byte("not-a-masked-word"))
+	b := NewBundle(nil)
+	err := b.AddObjects("applied-objects.json", [][]byte{
+		[]byte(`{"kind":"Secret","metadata":{"name":"db","annotations":{"kubectl.kubernetes.io/last-applied-configuration":"{\"data\":{\"password\":\"` + encoded + `\"}}","team":"api"}},"data":{"password":"` + encoded + `"},"stringData":{"user":"admin"}}`),
+		[]byte(`{"kind":"Deployment","metadata":{"name":"api"}}`),
+	})
+	require.NoError(t, err)
+
+	content, err := b.Compress(MaxSize)
+	require.NoError(t, err)
+
+	objects := readBundle(t, content)["applied-objects.json"]
+	assert.NotContains(t, objects, encoded)
+	assert.NotContains(t, objects, "admin")
+	assert.Contains(t, objects, `"team": "api"`)
+	assert.Contains(t, objects, `"name": "api"`)
+}
+
+func TestBundleAddObjectsWithInvalidObject(t *testing.T) {
+	b := NewBundle(nil)
+	err := b.AddObjects("applied-objects.json", [][]byte{[]byte("not json")})
+	require.Error(t, err)
+	assert.Equal(t, 0, b.Len())
+}
+
+func TestBundleCompressExceedsMaxSize(t *testing.T) {
+	b := NewBundle(nil)
+	b.Add("okteto.yml", []byte("name: test"))
+
+	_, err := b.Compress(10)
+	require.Error(t, err)
+}
+
+func TestExtract(t *testing.T) {
+	b := NewBundle([]string{"password"})
+	b.Add("okteto.yml", []byte("name: password"))
+	b.Add("values/values.yml", []byte("replicas: 1"))
+	content, err := b.Compress(MaxSize)
+	require.NoError(t, err)
+
+	fs := afero.NewMemMapFs()
+	dir := filepath.Join("/", "artifacts")
+	require.NoError(t, Extract(fs, content, dir))
+
+	manifest, err := afero.ReadFile(fs, filepath.Join(dir, "okteto.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, "name: ***", string(manifest))
+
+	values, err := afero.ReadFile(fs, filepath.Join(dir, "values", "values.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, "replicas: 1", string(values))
+}
+
+func TestExtractRejectsPathTraversal(t *testing.T) {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "../evil", Mode: 0600, Size: 4, Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte("evil"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	fs := afero.NewMemMapFs()
+	err = Extract(fs, buf.Bytes(), filepath.Join("/", "artifacts"))
+	require.Error(t, err)
+
+	_, err = fs.Stat(filepath.Join("/", "evil"))
+	assert.Error(t, err)
+}
@@ -138,16 +138,19 @@ func buildWithOkteto(ctx context.Context, buildOptions *types.BuildOptions) erro
 		return errors.Wrap(err, "failed to create build solver")
 	}
 
-	err = solveBuild(ctx, buildkitClient, opt, buildOptions.OutputMode)
+	err = solveBuild(ctx, buildkitClient, opt, buildOptions)
 	if err != nil {
 		oktetoLog.Infof("Failed to build image: %s", err.Error())
 	}
+	if buildOptions.Tag != "" {
+		registry.InvalidateDigest(buildOptions.Tag)
+	}
 	if isTransientError(err) {
 		oktetoLog.Yellow(`Failed to push '%s' to the registry:
   %s,
   Retrying ...`, buildOptions.Tag, err.Error())
 		success := true
-		err := solveBuild(ctx, buildkitClient, opt, buildOptions.OutputMode)
+		err := solveBuild(ctx, buildkitClient, opt, buildOptions)
 		if err != nil {
 			success = false
 			oktetoLog.Infof("Failed to build image: %s", err.Error())
@@ -163,7 +166,7 @@ func buildWithOkteto(ctx context.Context, buildOptions *types.BuildOptions) erro
 	  %s,
 	  Retrying ...`, buildOptions.Tag, err.Error())
 			success := true
-			err := solveBuild(ctx, buildkitClient, opt, buildOptions.OutputMode)
+			err := solveBuild(ctx, buildkitClient, opt, buildOptions)
 			if err != nil {
 				success = false
 				oktetoLog.Infof("Failed to build image: %s", err.Error())
@@ -204,6 +207,7 @@ func buildWithDocker(ctx context.Context, buildOptions *types.BuildOptions) erro
 		}
 	}
 	if buildOptions.Tag != "" {
+		defer registry.InvalidateDigest(buildOptions.Tag)
 		return pushImage(ctx, buildOptions.Tag, cli)
 	}
 	return nil
@@ -337,6 +341,10 @@ func OptsFromBuildInfo(manifestName, svcName string, b *model.BuildInfo, o *type
 		outputMode = o.OutputMode
 	}
 	opts.OutputMode = setOutputMode(outputMode)
+	if o.Renderer != nil {
+		opts.Renderer = o.Renderer
+		opts.ServiceName = svcName
+	}
 
 	return opts
 }
@@ -348,6 +356,8 @@ func OptsFromBuildInfoForRemoteDeploy(b *model.BuildInfo, o *types.BuildOptions)
 		OutputMode: o.OutputMode,
 		File:       b.Dockerfile,
 		Platform:   o.Platform,
+		Secrets:    o.Secrets,
+		SSH:        o.SSH,
 	}
 	return opts
 }
@@ -359,12 +369,12 @@ func extractFromContextAndDockerfile(context, dockerfile, svcName string) string
 
 	joinPath := filepath.Join(context, dockerfile)
 	if !filesystem.FileExistsAndNotDir(joinPath) {
-		oktetoLog.Warning(fmt.Sprintf(warningDockerfilePath, svcName, dockerfile, context))
+		oktetoLog.Warning(warningDockerfilePath, svcName, dockerfile, context)
 		return dockerfile
 	}
 
 	if joinPath != filepath.Clean(dockerfile) && filesystem.FileExistsAndNotDir(dockerfile) {
-		oktetoLog.Warning(fmt.Sprintf(doubleDockerfileWarning, svcName, context, dockerfile))
+		oktetoLog.Warning(doubleDockerfileWarning, svcName, context, dockerfile)
 	}
 
 	return joinPath
@@ -27,8 +27,10 @@ import (
 	"github.com/moby/buildkit/cmd/buildctl/build"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/session/auth/authprovider"
+	"github.com/moby/buildkit/session/sshforward/sshprovider"
 	"github.com/moby/buildkit/util/progress/progressui"
 	"github.com/okteto/okteto/pkg/config"
+	"github.com/okteto/okteto/pkg/filesystem"
 	oktetoLog "github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/okteto"
 	"github.com/okteto/okteto/pkg/types"
@@ -100,6 +102,18 @@ func getSolveOpt(buildOptions *types.BuildOptions) (*client.SolveOpt, error) {
 		}
 		attachable = append(attachable, secretProvider)
 	}
+
+	if len(buildOptions.SSH) > 0 {
+		sshConfigs, err := build.ParseSSH(buildOptions.SSH)
+		if err != nil {
+			return nil, err
+		}
+		sshProvider, err := sshprovider.NewSSHAgentProvider(sshConfigs)
+		if err != nil {
+			return nil, err
+		}
+		attachable = append(attachable, sshProvider)
+	}
 	opt := &client.SolveOpt{
 		LocalDirs:     localDirs,
 		Frontend:      frontend,
@@ -171,7 +185,10 @@ func getBuildkitClient(ctx context.Context) (*client.Client, error) {
 			return nil, fmt.Errorf("certificate decoding error: %w", err)
 		}
 
-		if err := os.WriteFile(config.GetCertificatePath(), certBytes, 0600); err != nil {
+		err = config.WithOktetoHomeLock(func() error {
+			return filesystem.WriteFileAtomic(config.GetCertificatePath(), certBytes, 0600)
+		})
+		if err != nil {
 			return nil, err
 		}
 
@@ -213,7 +230,8 @@ func getClientForOktetoCluster(ctx context.Context) (*client.Client, error) {
 	return c, nil
 }
 
-func solveBuild(ctx context.Context, c *client.Client, opt *client.SolveOpt, progress string) error {
+func solveBuild(ctx context.Context, c *client.Client, opt *client.SolveOpt, buildOptions *types.BuildOptions) error {
+	progress := buildOptions.OutputMode
 	logFilterRules := []Rule{
 		{
 			condition:   BuildKitMissingCacheCondition,
@@ -264,6 +282,14 @@ func solveBuild(ctx context.Context, c *client.Client, opt *client.SolveOpt, pro
 	eg.Go(func() error {
 
 		w := &buildWriter{}
+		if buildOptions.Renderer != nil {
+			// the builds of the services of a manifest are displayed by the renderer with one line per service
+			logs := buildOptions.Renderer.Writer(buildOptions.ServiceName)
+			if progress == oktetoLog.TTYFormat {
+				go trackBuildSteps(buildOptions.Renderer, buildOptions.ServiceName, ttyChannel)
+			}
+			return progressui.DisplaySolveStatus(context.TODO(), "", nil, logs, plainChannel)
+		}
 		switch progress {
 		case oktetoLog.TTYFormat:
 			var c console.Console
@@ -285,7 +311,11 @@ func solveBuild(ctx context.Context, c *client.Client, opt *client.SolveOpt, pro
 			commandFailChannel <- err
 			return err
 		case "destroy":
-			err := deployDisplayer(context.TODO(), plainChannel, &types.BuildOptions{OutputMode: "destroy"})
+			err := deployDisplayer(context.TODO(), plainChannel, &types.BuildOptions{OutputMode: "destroy", ExpectedCLIVersion: buildOptions.ExpectedCLIVersion})
+			commandFailChannel <- err
+			return err
+		case DestroyPlainOutputMode:
+			err := deployDisplayer(context.TODO(), plainChannel, &types.BuildOptions{OutputMode: DestroyPlainOutputMode, ExpectedCLIVersion: buildOptions.ExpectedCLIVersion})
 			commandFailChannel <- err
 			return err
 		default:
@@ -310,6 +340,17 @@ func solveBuild(ctx context.Context, c *client.Client, opt *client.SolveOpt, pro
 	return nil
 }
 
+// trackBuildSteps sets the step of the service being built as the vertexes of the build start
+func trackBuildSteps(renderer *oktetoLog.BuildRenderer, svc string, ch chan *client.SolveStatus) {
+	for ss := range ch {
+		for _, v := range ss.Vertexes {
+			if v.Started != nil && v.Completed == nil {
+				renderer.Step(svc, v.Name)
+			}
+		}
+	}
+}
+
 func (*buildWriter) Write(p []byte) (int, error) {
 	msg := strings.TrimSpace(string(p))
 	oktetoLog.AddToBuffer(oktetoLog.InfoLevel, msg)
@@ -29,8 +29,25 @@ import (
 const (
 	// largeContextThreshold is the threshold (in bytes) by which a context is catalogued as large or not (50MB)
 	largeContextThreshold = 50000000
+
+	// DestroyPlainOutputMode displays the logs of a remote destroy running with '--log-output=plain' as they are
+	DestroyPlainOutputMode = "destroy-plain"
+
+	// SynchronizingContextStage is the stage of the remote destroy while the build context is uploaded
+	SynchronizingContextStage = "Synchronizing context"
+	// PullingImagesStage is the stage of the remote destroy while the base images are pulled
+	PullingImagesStage = "Pulling images"
+	// BuildingRunnerStage is the stage of the remote destroy while the runner image is built
+	BuildingRunnerStage = "Building runner image"
+	// RunningCommandsStage is the stage of the remote destroy while the okteto destroy runs in the runner image
+	RunningCommandsStage = "Running remote destroy"
+	// ExportingImageStage is the stage of the remote destroy while the runner image is exported
+	ExportingImageStage = "Exporting image"
 )
 
+// OktetoCLICheckMarker prefixes the line logged by the remote destroy with the path and version of its okteto binary
+const OktetoCLICheckMarker = "okteto-cli-check:"
+
 func deployDisplayer(ctx context.Context, ch chan *client.SolveStatus, o *types.BuildOptions) error {
 	// TODO: import build timeout
 	timeout := time.NewTicker(10 * time.Minute)
@@ -41,13 +58,20 @@ func deployDisplayer(ctx context.Context, ch chan *client.SolveStatus, o *types.
 	defer oktetoLog.StopSpinner()
 
 	t := newTrace()
+	t.expectedCLIVersion = o.ExpectedCLIVersion
 
 	var done bool
 	var outputMode string
 
-	if o.OutputMode == "destroy" {
+	switch o.OutputMode {
+	case "destroy":
 		outputMode = "destroy"
-	} else {
+		t.reportStages = true
+	case DestroyPlainOutputMode:
+		outputMode = "destroy"
+		t.plainLogs = true
+		t.reportStages = true
+	default:
 		outputMode = "deploy"
 	}
 	for {
@@ -82,6 +106,14 @@ type trace struct {
 	ongoing       map[string]*vertexInfo
 	stages        map[string]bool
 	showCtxAdvice bool
+	// plainLogs is set when the remote command logs plain text instead of json
+	plainLogs bool
+	// reportStages logs the stages of the builder as they start
+	reportStages bool
+	// builderStages are the stages of the builder already reported
+	builderStages map[string]bool
+	// expectedCLIVersion is the okteto version the remote destroy should run
+	expectedCLIVersion string
 
 	err error
 }
@@ -99,6 +131,7 @@ func newTrace() *trace {
 	return &trace{
 		ongoing:       map[string]*vertexInfo{},
 		stages:        map[string]bool{},
+		builderStages: map[string]bool{},
 		showCtxAdvice: true,
 	}
 }
@@ -111,6 +144,9 @@ func (t *trace) update(ss *client.SolveStatus) error {
 				name: rawVertex.Name,
 			}
 			t.ongoing[rawVertex.Digest.Encoded()] = v
+			if t.reportStages {
+				t.reportBuilderStage(rawVertex.Name)
+			}
 		}
 		if rawVertex.Error != "" {
 			return fmt.Errorf("error on stage %s: %s", rawVertex.Name, rawVertex.Error)
@@ -159,6 +195,16 @@ func (t *trace) display(progress string) {
 				oktetoLog.Spinner("Destroying your development environment...")
 			}
 			for _, log := range v.logs {
+				if strings.HasPrefix(log, OktetoCLICheckMarker) {
+					t.checkOktetoCLI(log)
+					continue
+				}
+				if t.plainLogs {
+					if log != "" {
+						oktetoLog.Println(log)
+					}
+					continue
+				}
 				var text oktetoLog.JSONLogFormat
 				if err := json.Unmarshal([]byte(log), &text); err != nil {
 					oktetoLog.Infof("could not parse %s: %w", log, err)
@@ -197,6 +243,58 @@ func (t *trace) display(progress string) {
 	}
 }
 
+// checkOktetoCLI warns when the okteto binary found by the remote destroy isn't the expected version,
+// which happens when the destroy image has its own okteto binary on the PATH
+func (t *trace) checkOktetoCLI(line string) {
+	path, version := parseOktetoCLICheck(line)
+	oktetoLog.Infof("the remote destroy runs okteto %s from '%s'", version, path)
+	if t.expectedCLIVersion == "" || version == t.expectedCLIVersion {
+		return
+	}
+	oktetoLog.Warning("The remote destroy runs okteto %s from '%s', but %s was expected. Your destroy image might contain its own okteto binary", version, path, t.expectedCLIVersion)
+}
+
+// parseOktetoCLICheck returns the path and version of the line '<marker> <path> okteto version <version>'
+func parseOktetoCLICheck(line string) (string, string) {
+	fields := strings.Fields(strings.TrimPrefix(line, OktetoCLICheckMarker))
+	if len(fields) < 2 {
+		return "", ""
+	}
+	return fields[0], fields[len(fields)-1]
+}
+
+// reportBuilderStage logs the stage of the builder a vertex belongs to the first time one of its vertexes starts
+func (t *trace) reportBuilderStage(vertexName string) {
+	stage := getBuilderStage(vertexName)
+	if stage == "" || t.builderStages[stage] {
+		return
+	}
+	t.builderStages[stage] = true
+	oktetoLog.SetStage(stage)
+	oktetoLog.AddToBuffer(oktetoLog.InfoLevel, "Running stage '%s'", stage)
+	oktetoLog.SetStage("")
+}
+
+// getBuilderStage returns the stage of the builder of a vertex, or an empty string for the vertexes of the
+// buildkit frontend
+func getBuilderStage(vertexName string) string {
+	switch {
+	case strings.HasPrefix(vertexName, "[internal] load build context"):
+		return SynchronizingContextStage
+	case strings.HasPrefix(vertexName, "[internal] load metadata for"), strings.Contains(vertexName, "] FROM "):
+		return PullingImagesStage
+	case strings.HasPrefix(vertexName, "[internal]"):
+		return ""
+	case strings.Contains(vertexName, "okteto destroy"):
+		return RunningCommandsStage
+	case strings.HasPrefix(vertexName, "exporting"), strings.HasPrefix(vertexName, "pushing"):
+		return ExportingImageStage
+	case strings.HasPrefix(vertexName, "["):
+		return BuildingRunnerStage
+	}
+	return ""
+}
+
 func (t trace) isTransferringContext(name string) bool {
 	isInternal := strings.HasPrefix(name, "[internal]")
 	isLoadingCtx := strings.Contains(name, "load build")
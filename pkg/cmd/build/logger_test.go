@@ -0,0 +1,190 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/client"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/types"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStatusBuilder sends synthetic buildkit status events for the vertexes, in order
+type fakeStatusBuilder struct {
+	vertexes []string
+	// logs are the lines logged by each vertex
+	logs map[string][]string
+}
+
+func (b fakeStatusBuilder) run(ch chan *client.SolveStatus) {
+	defer close(ch)
+	for _, name := range b.vertexes {
+		d := digest.FromString(name)
+		now := time.Now()
+		ch <- &client.SolveStatus{Vertexes: []*client.Vertex{{Digest: d, Name: name, Started: &now}}}
+		for _, l := range b.logs[name] {
+			ch <- &client.SolveStatus{Logs: []*client.VertexLog{{Vertex: d, Data: []byte(l)}}}
+		}
+		ch <- &client.SolveStatus{Vertexes: []*client.Vertex{{Digest: d, Name: name, Started: &now, Completed: &now}}}
+	}
+}
+
+func TestGetBuilderStage(t *testing.T) {
+	var tests = []struct {
+		vertex   string
+		expected string
+	}{
+		{vertex: "[internal] load build definition from deploy", expected: ""},
+		{vertex: "[internal] load metadata for docker.io/library/alpine:latest", expected: PullingImagesStage},
+		{vertex: "[internal] load build context", expected: SynchronizingContextStage},
+		{vertex: "[deploy 1/12] FROM docker.io/okteto/pipeline-runner:1.0", expected: PullingImagesStage},
+		{vertex: "[certs 2/2] RUN apk update && apk add ca-certificates", expected: BuildingRunnerStage},
+		{vertex: "[deploy 12/12] RUN --mount=type=secret,id=okteto-token okteto destroy --log-output=json", expected: RunningCommandsStage},
+		{vertex: "exporting to image", expected: ExportingImageStage},
+		{vertex: "resolve image config for docker.io/docker/dockerfile:1.4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.vertex, func(t *testing.T) {
+			assert.Equal(t, tt.expected, getBuilderStage(tt.vertex))
+		})
+	}
+}
+
+func TestDeployDisplayerReportsDestroyStages(t *testing.T) {
+	var buf bytes.Buffer
+	oktetoLog.SetOutputFormat(oktetoLog.JSONFormat)
+	oktetoLog.SetOutput(&buf)
+	defer func() {
+		oktetoLog.SetOutputFormat(oktetoLog.TTYFormat)
+		oktetoLog.SetOutput(os.Stderr)
+	}()
+
+	runVertex := "[deploy 12/12] RUN okteto destroy --log-output=json"
+	b := fakeStatusBuilder{
+		vertexes: []string{
+			"[internal] load build definition from deploy",
+			"[internal] load metadata for docker.io/library/alpine:latest",
+			"[internal] load build context",
+			"[deploy 1/12] FROM docker.io/okteto/pipeline-runner:1.0",
+			"[certs 1/2] FROM docker.io/library/alpine:latest",
+			"[certs 2/2] RUN apk update",
+			"[deploy 2/12] COPY . /okteto/src",
+			runVertex,
+		},
+		logs: map[string][]string{
+			runVertex: {
+				`{"level":"info","stage":"remove db","message":"db removed"}`,
+			},
+		},
+	}
+	ch := make(chan *client.SolveStatus)
+	go b.run(ch)
+
+	err := deployDisplayer(context.Background(), ch, &types.BuildOptions{OutputMode: "destroy"})
+	require.NoError(t, err)
+
+	var stages []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var msg oktetoLog.JSONLogFormat
+		require.NoError(t, json.Unmarshal([]byte(line), &msg))
+		if len(stages) == 0 || stages[len(stages)-1] != msg.Stage {
+			stages = append(stages, msg.Stage)
+		}
+	}
+	assert.Equal(t, []string{
+		PullingImagesStage,
+		SynchronizingContextStage,
+		BuildingRunnerStage,
+		RunningCommandsStage,
+		"remove db",
+	}, stages)
+}
+
+func TestDeployDisplayerDoesNotReportDeployStages(t *testing.T) {
+	var buf bytes.Buffer
+	oktetoLog.SetOutputFormat(oktetoLog.JSONFormat)
+	oktetoLog.SetOutput(&buf)
+	defer func() {
+		oktetoLog.SetOutputFormat(oktetoLog.TTYFormat)
+		oktetoLog.SetOutput(os.Stderr)
+	}()
+
+	b := fakeStatusBuilder{vertexes: []string{"[internal] load build context", "[deploy 1/12] FROM docker.io/okteto/pipeline-runner:1.0"}}
+	ch := make(chan *client.SolveStatus)
+	go b.run(ch)
+
+	require.NoError(t, deployDisplayer(context.Background(), ch, &types.BuildOptions{OutputMode: "deploy"}))
+	assert.Empty(t, buf.String())
+}
+
+func TestParseOktetoCLICheck(t *testing.T) {
+	path, version := parseOktetoCLICheck("okteto-cli-check: /usr/local/bin/okteto okteto version 2.21.0 ")
+	assert.Equal(t, "/usr/local/bin/okteto", path)
+	assert.Equal(t, "2.21.0", version)
+
+	path, version = parseOktetoCLICheck("okteto-cli-check: ")
+	assert.Empty(t, path)
+	assert.Empty(t, version)
+}
+
+func TestDeployDisplayerWarnsOnOktetoCLIMismatch(t *testing.T) {
+	var tests = []struct {
+		name     string
+		expected string
+		warns    bool
+	}{
+		{name: "mismatch", expected: "2.22.0", warns: true},
+		{name: "match", expected: "2.21.0"},
+		{name: "unknown expected version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			oktetoLog.SetOutputFormat(oktetoLog.PlainFormat)
+			oktetoLog.SetOutput(&buf)
+			defer func() {
+				oktetoLog.SetOutputFormat(oktetoLog.TTYFormat)
+				oktetoLog.SetOutput(os.Stderr)
+			}()
+
+			runVertex := "[deploy 12/12] RUN okteto destroy --log-output=json"
+			b := fakeStatusBuilder{
+				vertexes: []string{runVertex},
+				logs: map[string][]string{
+					runVertex: {"okteto-cli-check: /usr/local/bin/okteto okteto version 2.21.0 "},
+				},
+			}
+			ch := make(chan *client.SolveStatus)
+			go b.run(ch)
+
+			err := deployDisplayer(context.Background(), ch, &types.BuildOptions{OutputMode: "destroy", ExpectedCLIVersion: tt.expected})
+			require.NoError(t, err)
+			if tt.warns {
+				assert.Contains(t, buf.String(), "The remote destroy runs okteto 2.21.0 from '/usr/local/bin/okteto', but 2.22.0 was expected")
+			} else {
+				assert.NotContains(t, buf.String(), "was expected")
+			}
+		})
+	}
+}
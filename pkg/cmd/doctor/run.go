@@ -72,7 +72,7 @@ func Run(ctx context.Context, dev *model.Dev, devPath string, c *kubernetes.Clie
 	podPath, err := generatePodFile(ctx, dev, c)
 	if err != nil {
 		oktetoLog.Infof("failed to get information about the remote dev container: %s", err)
-		oktetoLog.Warning(oktetoErrors.ErrNotInDevMode.Error())
+		oktetoLog.Warning("%s", oktetoErrors.ErrNotInDevMode.Error())
 	} else {
 		defer os.RemoveAll(podPath)
 	}
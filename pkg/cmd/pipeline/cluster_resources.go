@@ -0,0 +1,152 @@
+// Copyright 2021 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/okteto/okteto/pkg/k8s/configmaps"
+	"github.com/okteto/okteto/pkg/model"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+)
+
+const clusterResourcesField = "clusterResources"
+
+// ClusterResource is a cluster-scoped object applied by the deploy of a development environment
+type ClusterResource struct {
+	Group    string `json:"group,omitempty"`
+	Version  string `json:"version"`
+	Resource string `json:"resource"`
+	Kind     string `json:"kind,omitempty"`
+	Name     string `json:"name"`
+}
+
+// GroupVersionResource returns the group version resource of the object
+func (r ClusterResource) GroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Resource}
+}
+
+func (r ClusterResource) String() string {
+	kind := r.Kind
+	if kind == "" {
+		kind = r.Resource
+	}
+	return fmt.Sprintf("%s '%s'", kind, r.Name)
+}
+
+// key identifies the object regardless of the version used to apply it
+func (r ClusterResource) key() string {
+	return fmt.Sprintf("%s/%s/%s", r.Group, r.Resource, r.Name)
+}
+
+// GetClusterResources returns the cluster-scoped objects recorded in the configmap of a pipeline
+func GetClusterResources(cmap *apiv1.ConfigMap) ([]ClusterResource, error) {
+	if cmap == nil {
+		return nil, nil
+	}
+	encoded, ok := cmap.Data[clusterResourcesField]
+	if !ok || encoded == "" {
+		return nil, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cluster resources: %w", err)
+	}
+	var resources []ClusterResource
+	if err := json.Unmarshal(decoded, &resources); err != nil {
+		return nil, fmt.Errorf("invalid cluster resources: %w", err)
+	}
+	return resources, nil
+}
+
+// AddClusterResources records the cluster-scoped objects applied by the deploy of a pipeline. The objects
+// recorded by previous deploys are kept so destroy removes everything the pipeline ever created
+func AddClusterResources(ctx context.Context, name, namespace string, resources []ClusterResource, c kubernetes.Interface) error {
+	if len(resources) == 0 {
+		return nil
+	}
+	cmap, err := configmaps.Get(ctx, TranslatePipelineName(name), namespace, c)
+	if err != nil {
+		return err
+	}
+	current, err := GetClusterResources(cmap)
+	if err != nil {
+		return err
+	}
+
+	byKey := map[string]ClusterResource{}
+	for _, r := range append(current, resources...) {
+		byKey[r.key()] = r
+	}
+	merged := make([]ClusterResource, 0, len(byKey))
+	for _, r := range byKey {
+		merged = append(merged, r)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].key() < merged[j].key()
+	})
+
+	encoded, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	if cmap.Data == nil {
+		cmap.Data = map[string]string{}
+	}
+	cmap.Data[clusterResourcesField] = base64.StdEncoding.EncodeToString(encoded)
+	return configmaps.Deploy(ctx, cmap, cmap.Namespace, c)
+}
+
+// GetUnreferencedClusterResources returns the cluster-scoped objects recorded in the configmap
+// that are not recorded by the configmap of any other pipeline, in any namespace
+func GetUnreferencedClusterResources(ctx context.Context, cmap *apiv1.ConfigMap, c kubernetes.Interface) ([]ClusterResource, error) {
+	resources, err := GetClusterResources(cmap)
+	if err != nil || len(resources) == 0 {
+		return nil, err
+	}
+
+	cmaps, err := configmaps.List(ctx, metav1.NamespaceAll, fmt.Sprintf("%s=true", model.GitDeployLabel), c)
+	if err != nil {
+		return nil, fmt.Errorf("could not check if the cluster resources are used by other development environments: %w", err)
+	}
+	referenced := map[string]bool{}
+	for i := range cmaps {
+		other := &cmaps[i]
+		if other.Name == cmap.Name && other.Namespace == cmap.Namespace {
+			continue
+		}
+		otherResources, err := GetClusterResources(other)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the cluster resources of '%s/%s': %w", other.Namespace, other.Name, err)
+		}
+		for _, r := range otherResources {
+			referenced[r.key()] = true
+		}
+	}
+
+	var result []ClusterResource
+	for _, r := range resources {
+		if !referenced[r.key()] {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
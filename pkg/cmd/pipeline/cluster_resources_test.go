@@ -0,0 +1,132 @@
+// Copyright 2021 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var (
+	clusterRole = ClusterResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles", Kind: "ClusterRole", Name: "reader"}
+	crd         = ClusterResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions", Kind: "CustomResourceDefinition", Name: "foos.example.com"}
+)
+
+func newPipelineConfigMap(t *testing.T, name, namespace string, resources ...ClusterResource) *apiv1.ConfigMap {
+	t.Helper()
+	cmap := &apiv1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TranslatePipelineName(name),
+			Namespace: namespace,
+			Labels:    map[string]string{model.GitDeployLabel: "true"},
+		},
+	}
+	if len(resources) > 0 {
+		c := fake.NewSimpleClientset(cmap)
+		require.NoError(t, AddClusterResources(context.Background(), name, namespace, resources, c))
+		updated, err := c.CoreV1().ConfigMaps(namespace).Get(context.Background(), cmap.Name, metav1.GetOptions{})
+		require.NoError(t, err)
+		return updated
+	}
+	return cmap
+}
+
+func TestAddClusterResources(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewSimpleClientset(newPipelineConfigMap(t, "movies", "ns"))
+
+	require.NoError(t, AddClusterResources(ctx, "movies", "ns", []ClusterResource{clusterRole}, c))
+	// applying the same object with another version doesn't duplicate it
+	v1beta1Role := clusterRole
+	v1beta1Role.Version = "v1beta1"
+	require.NoError(t, AddClusterResources(ctx, "movies", "ns", []ClusterResource{crd, v1beta1Role}, c))
+
+	cmap, err := c.CoreV1().ConfigMaps("ns").Get(ctx, TranslatePipelineName("movies"), metav1.GetOptions{})
+	require.NoError(t, err)
+	resources, err := GetClusterResources(cmap)
+	require.NoError(t, err)
+	assert.Equal(t, []ClusterResource{crd, v1beta1Role}, resources)
+}
+
+func TestAddClusterResourcesWithoutResources(t *testing.T) {
+	c := fake.NewSimpleClientset()
+	assert.NoError(t, AddClusterResources(context.Background(), "movies", "ns", nil, c))
+}
+
+func TestGetClusterResourcesInvalid(t *testing.T) {
+	_, err := GetClusterResources(&apiv1.ConfigMap{Data: map[string]string{clusterResourcesField: "not-base64"}})
+	assert.Error(t, err)
+}
+
+func TestGetUnreferencedClusterResources(t *testing.T) {
+	var tests = []struct {
+		name     string
+		others   []*apiv1.ConfigMap
+		expected []ClusterResource
+	}{
+		{
+			name:     "no other environments",
+			expected: []ClusterResource{crd, clusterRole},
+		},
+		{
+			name: "other environment without cluster resources",
+			others: []*apiv1.ConfigMap{
+				newPipelineConfigMap(t, "frontend", "ns"),
+			},
+			expected: []ClusterResource{crd, clusterRole},
+		},
+		{
+			name: "resource referenced by an environment in the same namespace",
+			others: []*apiv1.ConfigMap{
+				newPipelineConfigMap(t, "frontend", "ns", clusterRole),
+			},
+			expected: []ClusterResource{crd},
+		},
+		{
+			name: "resource referenced by the same environment name in another namespace",
+			others: []*apiv1.ConfigMap{
+				newPipelineConfigMap(t, "movies", "other", crd),
+			},
+			expected: []ClusterResource{clusterRole},
+		},
+		{
+			name: "all resources referenced by several environments",
+			others: []*apiv1.ConfigMap{
+				newPipelineConfigMap(t, "frontend", "ns", clusterRole),
+				newPipelineConfigMap(t, "api", "other", crd, clusterRole),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmap := newPipelineConfigMap(t, "movies", "ns", clusterRole, crd)
+			c := fake.NewSimpleClientset(cmap)
+			for _, other := range tt.others {
+				_, err := c.CoreV1().ConfigMaps(other.Namespace).Create(context.Background(), other, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			result, err := GetUnreferencedClusterResources(context.Background(), cmap, c)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/okteto/okteto/pkg/constants"
+	"github.com/okteto/okteto/pkg/k8s/configmaps"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/types"
+	apiv1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/client-go/kubernetes"
+)
+
+// maxRotateCredentialsAttempts is the number of times a record is re-read and updated when
+// another operation modifies it at the same time
+const maxRotateCredentialsAttempts = 5
+
+// credentialEnvVars are the variables with credential material that okteto resolves again
+// on every deploy and destroy, so they are safe to strip from the environment records
+var credentialEnvVars = map[string]bool{
+	model.OktetoTokenEnvVar:  true,
+	"OKTETO_TLS_CERT_BASE64": true,
+}
+
+// RotateCredentialsResult summarizes the environment records processed by RotateCredentials
+type RotateCredentialsResult struct {
+	// Updated is the number of records that had credential material
+	Updated int
+	// Skipped is the number of records that were already clean
+	Skipped int
+	// Failed is the name of the records that could not be updated
+	Failed []string
+}
+
+// RotateCredentials strips the credential material recorded in the environment records of a namespace.
+// Records without credentials are left untouched, so running it again after a failure resumes the rotation
+func RotateCredentials(ctx context.Context, namespace string, c kubernetes.Interface) (*RotateCredentialsResult, error) {
+	cmaps, err := configmaps.List(ctx, namespace, fmt.Sprintf("%s=true", model.GitDeployLabel), c)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RotateCredentialsResult{}
+	for i := range cmaps {
+		updated, err := rotateConfigMapCredentials(ctx, &cmaps[i], c)
+		if err != nil {
+			oktetoLog.Infof("could not rotate the credentials of '%s': %s", cmaps[i].Name, err)
+			result.Failed = append(result.Failed, cmaps[i].Name)
+			continue
+		}
+		if updated {
+			result.Updated++
+		} else {
+			result.Skipped++
+		}
+	}
+	return result, nil
+}
+
+// rotateConfigMapCredentials updates the record using its resource version, reading it again when there is a conflict
+func rotateConfigMapCredentials(ctx context.Context, cmap *apiv1.ConfigMap, c kubernetes.Interface) (bool, error) {
+	for attempt := 1; ; attempt++ {
+		changed, err := stripCredentials(cmap)
+		if err != nil {
+			return false, err
+		}
+		if !changed {
+			return false, nil
+		}
+
+		if cmap.Annotations == nil {
+			cmap.Annotations = map[string]string{}
+		}
+		cmap.Annotations[constants.LastUpdatedAnnotation] = time.Now().UTC().Format(constants.TimeFormat)
+		_, err = c.CoreV1().ConfigMaps(cmap.Namespace).Update(ctx, cmap, metav1.UpdateOptions{})
+		if err == nil {
+			return true, nil
+		}
+		if !k8sErrors.IsConflict(err) || attempt == maxRotateCredentialsAttempts {
+			return false, err
+		}
+
+		oktetoLog.Infof("'%s' was modified while rotating its credentials, retrying", cmap.Name)
+		cmap, err = configmaps.Get(ctx, cmap.Name, cmap.Namespace, c)
+		if err != nil {
+			return false, err
+		}
+	}
+}
+
+// stripCredentials removes the credential material of every field of the record and the deploy stages,
+// so the next deploy runs every command and resolves the token and certificates again.
+// It returns true when the record was modified
+func stripCredentials(cmap *apiv1.ConfigMap) (bool, error) {
+	if cmap.Data == nil {
+		return false, nil
+	}
+	changed := false
+
+	if repo, ok := cmap.Data[repoField]; ok {
+		sanitized := removeSensitiveDataFromGitURL(repo)
+		if sanitized != repo {
+			cmap.Data[repoField] = sanitized
+			changed = true
+		}
+	}
+
+	if encoded, ok := cmap.Data[variablesField]; ok && encoded != "" {
+		variables := types.DecodeStringToDeployVariable(encoded)
+		if variables == nil {
+			return false, fmt.Errorf("invalid variables")
+		}
+		var kept []types.DeployVariable
+		for _, v := range variables {
+			if credentialEnvVars[v.Name] {
+				continue
+			}
+			kept = append(kept, v)
+		}
+		if len(kept) != len(variables) {
+			changed = true
+			if len(kept) == 0 {
+				delete(cmap.Data, variablesField)
+			} else {
+				encodedVars, err := json.Marshal(kept)
+				if err != nil {
+					return false, err
+				}
+				cmap.Data[variablesField] = base64.StdEncoding.EncodeToString(encodedVars)
+			}
+		}
+	}
+
+	if encoded, ok := cmap.Data[constants.OktetoDependencyEnvsKey]; ok && encoded != "" {
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return false, fmt.Errorf("invalid dependency envs: %w", err)
+		}
+		envs := map[string]string{}
+		if err := json.Unmarshal(decoded, &envs); err != nil {
+			return false, fmt.Errorf("invalid dependency envs: %w", err)
+		}
+		stripped := false
+		for name := range envs {
+			if credentialEnvVars[name] {
+				delete(envs, name)
+				stripped = true
+			}
+		}
+		if stripped {
+			changed = true
+			if len(envs) == 0 {
+				delete(cmap.Data, constants.OktetoDependencyEnvsKey)
+			} else {
+				encodedEnvs, err := json.Marshal(envs)
+				if err != nil {
+					return false, err
+				}
+				cmap.Data[constants.OktetoDependencyEnvsKey] = base64.StdEncoding.EncodeToString(encodedEnvs)
+			}
+		}
+	}
+
+	// the stages keep the content of $OKTETO_ENV, and resuming from them would skip the commands that resolve the credentials
+	if _, ok := cmap.Data[stagesField]; ok {
+		delete(cmap.Data, stagesField)
+		changed = true
+	}
+
+	return changed, nil
+}
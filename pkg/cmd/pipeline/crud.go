@@ -20,6 +20,7 @@ import (
 	"github.com/okteto/okteto/pkg/format"
 	"github.com/okteto/okteto/pkg/k8s/configmaps"
 	"github.com/okteto/okteto/pkg/k8s/deployments"
+	"github.com/okteto/okteto/pkg/k8s/jobs"
 	"github.com/okteto/okteto/pkg/k8s/statefulsets"
 	"github.com/okteto/okteto/pkg/model"
 	v1 "k8s.io/api/apps/v1"
@@ -75,5 +76,28 @@ func HasDeployedSomething(ctx context.Context, name, ns string, c kubernetes.Int
 		return true, nil
 	}
 
-	return false, nil
+	jobList, err := jobs.List(ctx, ns, labels, c)
+	if err != nil {
+		return false, err
+	}
+	return len(jobList) > 0, nil
+}
+
+// ListNamesByLabel returns the names of the pipelines deployed in the namespace that match the label selector
+func ListNamesByLabel(ctx context.Context, ns, labelSelector string, c kubernetes.Interface) ([]string, error) {
+	selector := fmt.Sprintf("%s=true", model.GitDeployLabel)
+	if labelSelector != "" {
+		selector = fmt.Sprintf("%s,%s", selector, labelSelector)
+	}
+	cmaps, err := configmaps.List(ctx, ns, selector, c)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(cmaps))
+	for _, cmap := range cmaps {
+		if name := cmap.Data[nameField]; name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
 }
@@ -17,6 +17,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/okteto/okteto/pkg/model"
 	"github.com/stretchr/testify/assert"
 	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -81,3 +82,52 @@ func Test_IsDeployedPipeline(t *testing.T) {
 		})
 	}
 }
+
+func Test_ListNamesByLabel(t *testing.T) {
+	ctx := context.Background()
+	newCmap := func(name string, labels map[string]string) *apiv1.ConfigMap {
+		return &apiv1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      TranslatePipelineName(name),
+				Namespace: "test",
+				Labels:    labels,
+			},
+			Data: map[string]string{nameField: name},
+		}
+	}
+	c := fake.NewSimpleClientset(
+		newCmap("app-a", map[string]string{model.GitDeployLabel: "true", "team": "a"}),
+		newCmap("app-b", map[string]string{model.GitDeployLabel: "true", "team": "b"}),
+		newCmap("app-c", map[string]string{model.GitDeployLabel: "true", "team": "a"}),
+		newCmap("not-a-pipeline", map[string]string{"team": "a"}),
+	)
+
+	var tests = []struct {
+		name     string
+		selector string
+		expected []string
+	}{
+		{
+			name:     "all pipelines",
+			expected: []string{"app-a", "app-b", "app-c"},
+		},
+		{
+			name:     "matching label",
+			selector: "team=a",
+			expected: []string{"app-a", "app-c"},
+		},
+		{
+			name:     "no matches",
+			selector: "team=c",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names, err := ListNamesByLabel(ctx, "test", tt.selector, c)
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, tt.expected, names)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2021 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/okteto/okteto/pkg/k8s/configmaps"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	trailField = "trail"
+
+	// maxTrailEntries keeps the trail within the size limits of a configmap
+	maxTrailEntries = 1000
+)
+
+// TrailEntry is a mutating request sent to the cluster by the deploy commands of a pipeline
+type TrailEntry struct {
+	Time      time.Time `json:"time"`
+	Verb      string    `json:"verb"`
+	Group     string    `json:"group,omitempty"`
+	Version   string    `json:"version"`
+	Kind      string    `json:"kind,omitempty"`
+	Resource  string    `json:"resource"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	// Command is the index of the deploy command that sent the request, -1 if no command was running
+	Command int `json:"command"`
+	// BodySHA256 is the hash of the request body. Bodies aren't stored to keep the trail small and free of secrets
+	BodySHA256 string `json:"bodySha256,omitempty"`
+}
+
+// GetTrail returns the trail recorded in the configmap of a pipeline
+func GetTrail(cmap *apiv1.ConfigMap) ([]TrailEntry, error) {
+	if cmap == nil {
+		return nil, nil
+	}
+	encoded, ok := cmap.Data[trailField]
+	if !ok || encoded == "" {
+		return nil, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trail: %w", err)
+	}
+	var trail []TrailEntry
+	if err := json.Unmarshal(decoded, &trail); err != nil {
+		return nil, fmt.Errorf("invalid trail: %w", err)
+	}
+	return trail, nil
+}
+
+// SetTrail replaces the trail recorded in the configmap of a pipeline with the one of the last deploy.
+// Only the last entries are kept when the trail is too long
+func SetTrail(ctx context.Context, name, namespace string, trail []TrailEntry, c kubernetes.Interface) error {
+	cmap, err := configmaps.Get(ctx, TranslatePipelineName(name), namespace, c)
+	if err != nil {
+		return err
+	}
+	if len(trail) == 0 {
+		if _, ok := cmap.Data[trailField]; !ok {
+			return nil
+		}
+		delete(cmap.Data, trailField)
+		return configmaps.Deploy(ctx, cmap, cmap.Namespace, c)
+	}
+
+	if len(trail) > maxTrailEntries {
+		trail = trail[len(trail)-maxTrailEntries:]
+	}
+	encoded, err := json.Marshal(trail)
+	if err != nil {
+		return err
+	}
+	if cmap.Data == nil {
+		cmap.Data = map[string]string{}
+	}
+	cmap.Data[trailField] = base64.StdEncoding.EncodeToString(encoded)
+	return configmaps.Deploy(ctx, cmap, cmap.Namespace, c)
+}
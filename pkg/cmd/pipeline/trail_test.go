@@ -0,0 +1,93 @@
+// Copyright 2021 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func getPipelineTrail(t *testing.T, c *fake.Clientset) []TrailEntry {
+	t.Helper()
+	cmap, err := c.CoreV1().ConfigMaps("ns").Get(context.Background(), TranslatePipelineName("movies"), metav1.GetOptions{})
+	require.NoError(t, err)
+	trail, err := GetTrail(cmap)
+	require.NoError(t, err)
+	return trail
+}
+
+func TestSetTrail(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewSimpleClientset(newPipelineConfigMap(t, "movies", "ns"))
+	first := []TrailEntry{
+		{
+			Time:       time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC),
+			Verb:       "create",
+			Version:    "v1",
+			Kind:       "ConfigMap",
+			Resource:   "configmaps",
+			Namespace:  "ns",
+			Name:       "api",
+			BodySHA256: "abc",
+		},
+	}
+	second := []TrailEntry{
+		{
+			Time:      time.Date(2023, 5, 1, 11, 0, 0, 0, time.UTC),
+			Verb:      "delete",
+			Group:     "apps",
+			Version:   "v1",
+			Resource:  "deployments",
+			Namespace: "ns",
+			Name:      "api",
+			Command:   1,
+		},
+	}
+
+	require.NoError(t, SetTrail(ctx, "movies", "ns", first, c))
+	assert.Equal(t, first, getPipelineTrail(t, c))
+
+	// each deploy replaces the trail of the previous one
+	require.NoError(t, SetTrail(ctx, "movies", "ns", second, c))
+	assert.Equal(t, second, getPipelineTrail(t, c))
+
+	require.NoError(t, SetTrail(ctx, "movies", "ns", nil, c))
+	assert.Empty(t, getPipelineTrail(t, c))
+}
+
+func TestSetTrailKeepsLastEntries(t *testing.T) {
+	c := fake.NewSimpleClientset(newPipelineConfigMap(t, "movies", "ns"))
+	var trail []TrailEntry
+	for i := 0; i < maxTrailEntries+10; i++ {
+		trail = append(trail, TrailEntry{Verb: "patch", Version: "v1", Resource: "configmaps", Name: fmt.Sprintf("cmap-%d", i)})
+	}
+
+	require.NoError(t, SetTrail(context.Background(), "movies", "ns", trail, c))
+	stored := getPipelineTrail(t, c)
+	require.Len(t, stored, maxTrailEntries)
+	assert.Equal(t, "cmap-10", stored[0].Name)
+}
+
+func TestGetTrailInvalid(t *testing.T) {
+	_, err := GetTrail(&apiv1.ConfigMap{Data: map[string]string{trailField: "not-base64"}})
+	assert.Error(t, err)
+}
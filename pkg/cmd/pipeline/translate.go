@@ -53,6 +53,13 @@ const (
 	actionLockField = "actionLock"
 	actionNameField = "actionName"
 	variablesField  = "variables"
+	stagesField     = "stages"
+	// dependencyRevisionsField is the SHA each dependency was deployed from, used by 'okteto deploy --locked'
+	dependencyRevisionsField = "dependencyRevisions"
+	// destroyReasonField is why the running or failed destroy was triggered
+	destroyReasonField = "destroyReason"
+	// dirtyField is set when the remote command ran with uncommitted changes in the working tree
+	dirtyField = "dirty"
 
 	actionDefaultName = "cli"
 
@@ -90,6 +97,10 @@ type CfgData struct {
 	Manifest   []byte
 	Icon       string
 	Variables  []string
+	// DestroyReason is why the destroy was triggered, empty when deploying
+	DestroyReason string
+	// Dirty is set when the remote command ran with uncommitted changes in the working tree
+	Dirty bool
 }
 
 // GetConfigmapVariablesEncoded returns Data["variables"] content from Configmap
@@ -185,6 +196,105 @@ func UpdateEnvs(ctx context.Context, name, namespace string, envs []string, c ku
 	return nil
 }
 
+// DeployStage represents a deploy command successfully executed
+type DeployStage struct {
+	Name string `json:"name"`
+	// Hash identifies the inputs of the command: the command itself, the files it references and the variables
+	Hash string `json:"hash"`
+	// Envs is the content of $OKTETO_ENV after the command was executed
+	Envs map[string]string `json:"envs,omitempty"`
+}
+
+// GetDeployStages returns the deploy stages completed by the last deploy of the pipeline
+func GetDeployStages(ctx context.Context, name, namespace string, c kubernetes.Interface) ([]DeployStage, error) {
+	cmap, err := configmaps.Get(ctx, TranslatePipelineName(name), namespace, c)
+	if err != nil {
+		if oktetoErrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	encoded, ok := cmap.Data[stagesField]
+	if !ok || encoded == "" {
+		return nil, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deploy stages: %w", err)
+	}
+	var stages []DeployStage
+	if err := json.Unmarshal(decoded, &stages); err != nil {
+		return nil, fmt.Errorf("invalid deploy stages: %w", err)
+	}
+	return stages, nil
+}
+
+// UpdateDeployStages stores the deploy stages completed by the current deploy of the pipeline
+func UpdateDeployStages(ctx context.Context, name, namespace string, stages []DeployStage, c kubernetes.Interface) error {
+	cmap, err := configmaps.Get(ctx, TranslatePipelineName(name), namespace, c)
+	if err != nil {
+		return err
+	}
+	if cmap.Data == nil {
+		cmap.Data = map[string]string{}
+	}
+	if len(stages) == 0 {
+		delete(cmap.Data, stagesField)
+	} else {
+		encoded, err := json.Marshal(stages)
+		if err != nil {
+			return err
+		}
+		cmap.Data[stagesField] = base64.StdEncoding.EncodeToString(encoded)
+	}
+	return configmaps.Deploy(ctx, cmap, cmap.Namespace, c)
+}
+
+// GetDependencyRevisions returns the SHA each dependency of the pipeline was deployed from
+func GetDependencyRevisions(ctx context.Context, name, namespace string, c kubernetes.Interface) (map[string]string, error) {
+	cmap, err := configmaps.Get(ctx, TranslatePipelineName(name), namespace, c)
+	if err != nil {
+		if oktetoErrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	encoded, ok := cmap.Data[dependencyRevisionsField]
+	if !ok || encoded == "" {
+		return nil, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dependency revisions: %w", err)
+	}
+	revisions := map[string]string{}
+	if err := json.Unmarshal(decoded, &revisions); err != nil {
+		return nil, fmt.Errorf("invalid dependency revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+// UpdateDependencyRevisions stores the SHA each dependency of the pipeline was deployed from
+func UpdateDependencyRevisions(ctx context.Context, name, namespace string, revisions map[string]string, c kubernetes.Interface) error {
+	cmap, err := configmaps.Get(ctx, TranslatePipelineName(name), namespace, c)
+	if err != nil {
+		return err
+	}
+	if cmap.Data == nil {
+		cmap.Data = map[string]string{}
+	}
+	if len(revisions) == 0 {
+		delete(cmap.Data, dependencyRevisionsField)
+	} else {
+		encoded, err := json.Marshal(revisions)
+		if err != nil {
+			return err
+		}
+		cmap.Data[dependencyRevisionsField] = base64.StdEncoding.EncodeToString(encoded)
+	}
+	return configmaps.Deploy(ctx, cmap, cmap.Namespace, c)
+}
+
 // TranslatePipelineName translate the name into the configmap name
 func TranslatePipelineName(name string) string {
 	return fmt.Sprintf("okteto-git-%s", format.ResourceK8sMetaString(name))
@@ -280,6 +390,16 @@ func updateCmap(cmap *apiv1.ConfigMap, data *CfgData) error {
 	cmap.Data[yamlField] = base64.StdEncoding.EncodeToString(data.Manifest)
 	cmap.Data[iconField] = data.Icon
 	cmap.Data[actionNameField] = actionName
+	if data.DestroyReason != "" {
+		cmap.Data[destroyReasonField] = data.DestroyReason
+	} else {
+		delete(cmap.Data, destroyReasonField)
+	}
+	if data.Dirty {
+		cmap.Data[dirtyField] = "true"
+	} else {
+		delete(cmap.Data, dirtyField)
+	}
 	if data.Repository != "" {
 		// the filename at the cfgmap is used by the installer to re-deploy the app from the ui
 		// this parameter is just saved if a repository is being detected
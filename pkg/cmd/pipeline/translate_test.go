@@ -80,6 +80,48 @@ func Test_translateConfigMap(t *testing.T) {
 	}
 }
 
+func Test_translateConfigMapDestroyReason(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewSimpleClientset()
+	data := &CfgData{
+		Name:          "test",
+		Namespace:     "test",
+		Status:        DestroyingStatus,
+		DestroyReason: "ttl",
+	}
+	cfg, err := TranslateConfigMapAndDeploy(ctx, data, fakeClient)
+	assert.NoError(t, err)
+	assert.Equal(t, "ttl", cfg.Data[destroyReasonField])
+
+	data = &CfgData{
+		Name:      "test",
+		Namespace: "test",
+		Status:    ProgressingStatus,
+	}
+	cfg, err = TranslateConfigMapAndDeploy(ctx, data, fakeClient)
+	assert.NoError(t, err)
+	assert.NotContains(t, cfg.Data, destroyReasonField)
+}
+
+func Test_translateConfigMapDirty(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewSimpleClientset()
+	data := &CfgData{
+		Name:      "test",
+		Namespace: "test",
+		Status:    ProgressingStatus,
+		Dirty:     true,
+	}
+	cfg, err := TranslateConfigMapAndDeploy(ctx, data, fakeClient)
+	assert.NoError(t, err)
+	assert.Equal(t, "true", cfg.Data[dirtyField])
+
+	data.Dirty = false
+	cfg, err = TranslateConfigMapAndDeploy(ctx, data, fakeClient)
+	assert.NoError(t, err)
+	assert.NotContains(t, cfg.Data, dirtyField)
+}
+
 func Test_updateEnvsWithoutError(t *testing.T) {
 	ctx := context.Background()
 	namespace := "test"
@@ -103,6 +145,82 @@ func Test_updateEnvsWithoutError(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func Test_DeployStages(t *testing.T) {
+	ctx := context.Background()
+	namespace := "test"
+	cmap := &apiv1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TranslatePipelineName("test"),
+			Namespace: namespace,
+			Labels:    map[string]string{},
+		},
+		Data: map[string]string{
+			statusField: DeployedStatus,
+		},
+	}
+	fakeClient := fake.NewSimpleClientset(cmap)
+
+	stages, err := GetDeployStages(ctx, "test", namespace, fakeClient)
+	assert.NoError(t, err)
+	assert.Empty(t, stages)
+
+	expected := []DeployStage{
+		{Name: "build", Hash: "a", Envs: map[string]string{"IMAGE": "okteto/test"}},
+		{Name: "deploy", Hash: "b"},
+	}
+	assert.NoError(t, UpdateDeployStages(ctx, "test", namespace, expected, fakeClient))
+	stages, err = GetDeployStages(ctx, "test", namespace, fakeClient)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, stages)
+
+	assert.NoError(t, UpdateDeployStages(ctx, "test", namespace, nil, fakeClient))
+	stages, err = GetDeployStages(ctx, "test", namespace, fakeClient)
+	assert.NoError(t, err)
+	assert.Empty(t, stages)
+
+	stages, err = GetDeployStages(ctx, "not-found", namespace, fakeClient)
+	assert.NoError(t, err)
+	assert.Empty(t, stages)
+}
+
+func Test_DependencyRevisions(t *testing.T) {
+	ctx := context.Background()
+	namespace := "test"
+	cmap := &apiv1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TranslatePipelineName("test"),
+			Namespace: namespace,
+			Labels:    map[string]string{},
+		},
+		Data: map[string]string{
+			statusField: DeployedStatus,
+		},
+	}
+	fakeClient := fake.NewSimpleClientset(cmap)
+
+	revisions, err := GetDependencyRevisions(ctx, "test", namespace, fakeClient)
+	assert.NoError(t, err)
+	assert.Empty(t, revisions)
+
+	expected := map[string]string{
+		"api":      "1111111111111111111111111111111111111111",
+		"frontend": "2222222222222222222222222222222222222222",
+	}
+	assert.NoError(t, UpdateDependencyRevisions(ctx, "test", namespace, expected, fakeClient))
+	revisions, err = GetDependencyRevisions(ctx, "test", namespace, fakeClient)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, revisions)
+
+	assert.NoError(t, UpdateDependencyRevisions(ctx, "test", namespace, nil, fakeClient))
+	revisions, err = GetDependencyRevisions(ctx, "test", namespace, fakeClient)
+	assert.NoError(t, err)
+	assert.Empty(t, revisions)
+
+	revisions, err = GetDependencyRevisions(ctx, "not-found", namespace, fakeClient)
+	assert.NoError(t, err)
+	assert.Empty(t, revisions)
+}
+
 func Test_updateEnvsWithError(t *testing.T) {
 	ctx := context.Background()
 	namespace := "test"
@@ -177,28 +177,7 @@ func deploy(ctx context.Context, s *model.Stack, c kubernetes.Interface, config
 		// each endpoint gets an ingress when using the endpoints spec at compose
 		// the endpoint would have paths for services as defined at the spec
 		for _, endpointName := range getEndpointsToDeployFromServicesToDeploy(s.Endpoints, servicesToDeploySet) {
-			endpoint := s.Endpoints[endpointName]
-			// initialize the maps for Labels and Annotations if nil
-			if endpoint.Labels == nil {
-				endpoint.Labels = map[string]string{}
-			}
-			if endpoint.Annotations == nil {
-				endpoint.Annotations = map[string]string{}
-			}
-
-			// add specific stack labels
-			if _, ok := endpoint.Labels[model.StackNameLabel]; !ok {
-				endpoint.Labels[model.StackNameLabel] = format.ResourceK8sMetaString(s.Name)
-			}
-			if _, ok := endpoint.Labels[model.StackEndpointNameLabel]; !ok {
-				endpoint.Labels[model.StackEndpointNameLabel] = endpointName
-			}
-
-			translateOptions := &ingresses.TranslateOptions{
-				Name:      format.ResourceK8sMetaString(s.Name),
-				Namespace: s.Namespace,
-			}
-			ingress := ingresses.Translate(endpointName, endpoint, translateOptions)
+			ingress := translateEndpoint(endpointName, s)
 			// check for labels collision in the case of a compose - before creation or update (deploy)
 			if skipIngressDeployForStackNameLabel(ctx, iClient, ingress) {
 				continue
@@ -241,7 +220,7 @@ func skipIngressDeployForStackNameLabel(ctx context.Context, iClient *ingresses.
 	// err is not checked here, we just want to check if the ingress already exists for this labels
 	if old, _ := iClient.Get(ctx, ingress.GetName(), ingress.GetNamespace()); old != nil {
 		if old.GetLabels()[model.StackNameLabel] == "" {
-			oktetoLog.Warning("skipping deploy of %s due to name collision: the ingress '%s' was running before deploying your compose", old.GetName())
+			oktetoLog.Warning("skipping deploy of %s due to name collision: the ingress '%s' was running before deploying your compose", ingress.GetName(), old.GetName())
 			return true
 		}
 		if old.GetLabels()[model.StackNameLabel] != ingress.GetLabels()[model.StackNameLabel] {
@@ -352,7 +331,7 @@ func deploySvc(ctx context.Context, stack *model.Stack, svcName string, client k
 
 	if err != nil {
 		if strings.Contains(err.Error(), "skipping ") {
-			oktetoLog.Warning(err.Error())
+			oktetoLog.Warning("%s", err.Error())
 			return nil
 		}
 		return err
@@ -367,7 +346,17 @@ func deploySvc(ctx context.Context, stack *model.Stack, svcName string, client k
 }
 
 func deployK8sEndpoint(ctx context.Context, ingressName, svcName string, port model.Port, s *model.Stack, c *ingresses.Client) error {
+	ingress := translatePortEndpoint(ingressName, svcName, port, s)
 
+	// check for labels collision in the case of a compose - before creation or update (deploy)
+	if skipIngressDeployForStackNameLabel(ctx, c, ingress) {
+		return nil
+	}
+	return c.Deploy(ctx, ingress)
+}
+
+// translatePortEndpoint returns the ingress of a public port of a compose service
+func translatePortEndpoint(ingressName, svcName string, port model.Port, s *model.Stack) *ingresses.Ingress {
 	// create a new endpoint for this port ingress deployment
 	endpoint := model.Endpoint{
 		Labels:      translateLabels(svcName, s),
@@ -392,17 +381,41 @@ func deployK8sEndpoint(ctx context.Context, ingressName, svcName string, port mo
 		Name:      format.ResourceK8sMetaString(s.Name),
 		Namespace: s.Namespace,
 	}
-	ingress := ingresses.Translate(ingressName, endpoint, translateOptions)
+	return ingresses.Translate(ingressName, endpoint, translateOptions)
+}
 
-	// check for labels collision in the case of a compose - before creation or update (deploy)
-	if skipIngressDeployForStackNameLabel(ctx, c, ingress) {
-		return nil
+// translateEndpoint returns the ingress of an endpoint of the compose endpoints spec
+func translateEndpoint(endpointName string, s *model.Stack) *ingresses.Ingress {
+	endpoint := s.Endpoints[endpointName]
+	// initialize the maps for Labels and Annotations if nil
+	if endpoint.Labels == nil {
+		endpoint.Labels = map[string]string{}
 	}
-	return c.Deploy(ctx, ingress)
+	if endpoint.Annotations == nil {
+		endpoint.Annotations = map[string]string{}
+	}
+
+	// add specific stack labels
+	if _, ok := endpoint.Labels[model.StackNameLabel]; !ok {
+		endpoint.Labels[model.StackNameLabel] = format.ResourceK8sMetaString(s.Name)
+	}
+	if _, ok := endpoint.Labels[model.StackEndpointNameLabel]; !ok {
+		endpoint.Labels[model.StackEndpointNameLabel] = endpointName
+	}
+
+	translateOptions := &ingresses.TranslateOptions{
+		Name:      format.ResourceK8sMetaString(s.Name),
+		Namespace: s.Namespace,
+	}
+	return ingresses.Translate(endpointName, endpoint, translateOptions)
 }
 
 func canSvcBeDeployed(ctx context.Context, stack *model.Stack, svcName string, client kubernetes.Interface, config *rest.Config) bool {
 	for dependentSvc, condition := range stack.Services[svcName].DependsOn {
+		if _, ok := getDependsOnInitContainerPort(dependentSvc, condition, stack); ok {
+			// the init containers of the service wait for this dependency
+			continue
+		}
 		if !isSvcReady(ctx, stack, dependentSvc, condition, client, config) {
 			oktetoLog.Infof("Service %s can not be deployed due to %s", svcName, dependentSvc)
 			return false
@@ -804,7 +817,7 @@ func DisplayNotSupportedFieldsWarnings(warnings []string) {
 
 func DisplayVolumeMountWarnings(warnings []string) {
 	for _, warning := range warnings {
-		oktetoLog.Warning(warning)
+		oktetoLog.Warning("%s", warning)
 	}
 }
 
@@ -1052,3 +1052,44 @@ func TestDeployK8sEndpoint(t *testing.T) {
 		})
 	}
 }
+
+func TestCanSvcBeDeployedWithDependsOnStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy model.DependsOnStrategy
+		expected bool
+	}{
+		{
+			name:     "wait strategy waits for the healthy dependency",
+			strategy: model.DependsOnWaitStrategy,
+			expected: false,
+		},
+		{
+			name:     "init containers strategy leaves the healthy dependency to the init containers",
+			strategy: model.DependsOnInitContainersStrategy,
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stack := &model.Stack{
+				Name:              "test",
+				Namespace:         "test",
+				DependsOnStrategy: tt.strategy,
+				Services: map[string]*model.Service{
+					"api": {
+						DependsOn: model.DependsOn{
+							"db": model.DependsOnConditionSpec{Condition: model.DependsOnServiceHealthy},
+						},
+					},
+					"db": {
+						Ports:      []model.Port{{ContainerPort: 5432, Protocol: apiv1.ProtocolTCP}},
+						Healtcheck: &model.HealthCheck{Test: model.HealtcheckTest{"pg_isready"}},
+					},
+				},
+			}
+			client := fake.NewSimpleClientset()
+			assert.Equal(t, tt.expected, canSvcBeDeployed(context.Background(), stack, "api", client, nil))
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/okteto/okteto/pkg/k8s/ingresses"
+	"github.com/okteto/okteto/pkg/model"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Render returns the kubernetes resources created by the deploy of the compose, without connecting to the cluster.
+// The ingresses use networking.k8s.io/v1 when ingressV1 is true and networking.k8s.io/v1beta1 otherwise
+func Render(s *model.Stack, ingressV1 bool) []runtime.Object {
+	objects := []runtime.Object{translateConfigMap(s)}
+
+	svcNames := make([]string, 0, len(s.Services))
+	for name := range s.Services {
+		svcNames = append(svcNames, name)
+	}
+	sort.Strings(svcNames)
+
+	for _, svcName := range svcNames {
+		if len(s.Services[svcName].Ports) == 0 {
+			continue
+		}
+		objects = append(objects, translateService(svcName, s))
+		ports := getSvcPublicPorts(svcName, s)
+		for _, port := range ports {
+			ingressName := svcName
+			if len(ports) > 1 {
+				ingressName = fmt.Sprintf("%s-%d", svcName, port.ContainerPort)
+			}
+			objects = append(objects, renderIngress(translatePortEndpoint(ingressName, svcName, port, s), ingressV1))
+		}
+	}
+
+	servicesToDeploy := map[string]bool{}
+	for _, svcName := range svcNames {
+		servicesToDeploy[svcName] = true
+	}
+	volumeNames := getVolumesToDeployFromServicesToDeploy(s, servicesToDeploy)
+	sort.Strings(volumeNames)
+	for _, volumeName := range volumeNames {
+		pvc := translatePersistentVolumeClaim(volumeName, s)
+		objects = append(objects, &pvc)
+	}
+
+	for _, svcName := range svcNames {
+		switch {
+		case s.Services[svcName].IsJob():
+			objects = append(objects, translateJob(svcName, s))
+		case len(s.Services[svcName].Volumes) == 0:
+			objects = append(objects, translateDeployment(svcName, s))
+		default:
+			objects = append(objects, translateStatefulSet(svcName, s))
+		}
+	}
+
+	endpointNames := getEndpointsToDeployFromServicesToDeploy(s.Endpoints, servicesToDeploy)
+	sort.Strings(endpointNames)
+	for _, endpointName := range endpointNames {
+		objects = append(objects, renderIngress(translateEndpoint(endpointName, s), ingressV1))
+	}
+	return objects
+}
+
+// renderIngress returns the version of the ingress supported by the cluster
+func renderIngress(ingress *ingresses.Ingress, ingressV1 bool) runtime.Object {
+	if ingressV1 {
+		return ingress.V1
+	}
+	return ingress.V1Beta1
+}
@@ -140,6 +140,7 @@ func translateDeployment(svcName string, s *model.Stack) *appsv1.Deployment {
 				Spec: apiv1.PodSpec{
 					TerminationGracePeriodSeconds: pointer.Int64Ptr(svc.StopGracePeriod),
 					NodeSelector:                  svc.NodeSelector,
+					InitContainers:                getDependsOnInitContainers(svcName, s),
 					Containers: []apiv1.Container{
 						{
 							Name:            svcName,
@@ -301,10 +302,54 @@ func getInitContainers(svcName string, s *model.Stack) []apiv1.Container {
 	if initializationContainer != nil {
 		initContainers = append(initContainers, *initializationContainer)
 	}
+	initContainers = append(initContainers, getDependsOnInitContainers(svcName, s)...)
 
 	return initContainers
 }
 
+// getDependsOnInitContainers returns the init containers that wait until the endpoints of the healthy dependencies
+// of a service are reachable. The kubernetes service only routes to ready pods, so this waits for their readiness
+func getDependsOnInitContainers(svcName string, s *model.Stack) []apiv1.Container {
+	dependsOn := s.Services[svcName].DependsOn
+	dependencies := make([]string, 0, len(dependsOn))
+	for dependency := range dependsOn {
+		dependencies = append(dependencies, dependency)
+	}
+	sort.Strings(dependencies)
+
+	var initContainers []apiv1.Container
+	for _, dependency := range dependencies {
+		port, ok := getDependsOnInitContainerPort(dependency, dependsOn[dependency], s)
+		if !ok {
+			continue
+		}
+		initContainers = append(initContainers, apiv1.Container{
+			Name:    fmt.Sprintf("wait-for-%s", dependency),
+			Image:   "busybox",
+			Command: []string{"sh", "-c", fmt.Sprintf("until nc -z %s %d; do echo waiting for %s; sleep 2; done", dependency, port, dependency)},
+		})
+	}
+	return initContainers
+}
+
+// getDependsOnInitContainerPort returns the port checked by the init container of a dependency.
+// It returns false when the dependency is not enforced by an init container
+func getDependsOnInitContainerPort(dependency string, condition model.DependsOnConditionSpec, s *model.Stack) (int32, bool) {
+	if s.DependsOnStrategy != model.DependsOnInitContainersStrategy || condition.Condition != model.DependsOnServiceHealthy {
+		return 0, false
+	}
+	svc, ok := s.Services[dependency]
+	if !ok || svc.IsJob() {
+		return 0, false
+	}
+	for _, p := range translateServicePorts(*svc) {
+		if p.Protocol == "" || p.Protocol == apiv1.ProtocolTCP {
+			return p.Port, true
+		}
+	}
+	return 0, false
+}
+
 func getAddPermissionsInitContainer(svcName string, svc *model.Service) apiv1.Container {
 	initContainerCommand, initContainerVolumeMounts := getInitContainerCommandAndVolumeMounts(*svc)
 	initContainer := apiv1.Container{
@@ -15,21 +15,30 @@ package stack
 
 import (
 	"encoding/base64"
+	"flag"
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/okteto/okteto/pkg/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
+	"sigs.k8s.io/yaml"
 )
 
+var updateGolden = flag.Bool("update", false, "update the golden files")
+
 func Test_translateConfigMap(t *testing.T) {
 	s := &model.Stack{
 		Manifest: []byte("manifest"),
@@ -1845,3 +1854,68 @@ func TestGetStrategyStrategy(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslateDependsOnGolden(t *testing.T) {
+	tests := []string{
+		"depends-on-init-containers",
+		"depends-on-wait",
+	}
+	for _, tt := range tests {
+		t.Run(tt, func(t *testing.T) {
+			manifest, err := os.ReadFile(filepath.Join("testdata", fmt.Sprintf("%s.yml", tt)))
+			require.NoError(t, err)
+			s, err := model.ReadStack(manifest, true)
+			require.NoError(t, err)
+			s.Name = "movies"
+			s.Namespace = "test"
+			require.NoError(t, s.Validate())
+
+			out := translateStackToYAML(t, s)
+			golden := filepath.Join("testdata", fmt.Sprintf("%s.golden", tt))
+			if *updateGolden {
+				require.NoError(t, os.WriteFile(golden, []byte(out), 0600))
+			}
+			expected, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), out)
+		})
+	}
+}
+
+// translateStackToYAML translates every service of the stack the same way deploySvc does
+func translateStackToYAML(t *testing.T, s *model.Stack) string {
+	t.Helper()
+	svcNames := make([]string, 0, len(s.Services))
+	for svcName := range s.Services {
+		svcNames = append(svcNames, svcName)
+	}
+	sort.Strings(svcNames)
+
+	docs := []string{}
+	for _, svcName := range svcNames {
+		var obj metav1.Object
+		switch {
+		case s.Services[svcName].IsJob():
+			job := translateJob(svcName, s)
+			delete(job.Spec.Template.Annotations, model.OktetoSampleAnnotation)
+			obj = job
+		case len(s.Services[svcName].Volumes) == 0:
+			d := translateDeployment(svcName, s)
+			delete(d.Spec.Template.Annotations, model.OktetoSampleAnnotation)
+			obj = d
+		default:
+			sfs := translateStatefulSet(svcName, s)
+			delete(sfs.Spec.Template.Annotations, model.OktetoSampleAnnotation)
+			obj = sfs
+		}
+		// the sample annotation depends on the repository running the tests
+		annotations := obj.GetAnnotations()
+		delete(annotations, model.OktetoSampleAnnotation)
+		obj.SetAnnotations(annotations)
+
+		b, err := yaml.Marshal(obj)
+		require.NoError(t, err)
+		docs = append(docs, string(b))
+	}
+	return strings.Join(docs, "---\n")
+}
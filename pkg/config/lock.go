@@ -0,0 +1,97 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+)
+
+const (
+	homeLockFile    = ".okteto.lock"
+	homeLockPIDFile = ".okteto.lock.pid"
+
+	homeLockRetryDelay = 100 * time.Millisecond
+)
+
+// homeLockTimeout is how long a command waits for another okteto process to release OKTETO_HOME
+var homeLockTimeout = 30 * time.Second
+
+// WithOktetoHomeLock runs fn holding an exclusive lock over the okteto home folder,
+// so concurrent okteto commands don't interleave writes to the files stored in it
+func WithOktetoHomeLock(fn func() error) error {
+	home := GetOktetoHome()
+	lock := flock.New(filepath.Join(home, homeLockFile))
+
+	ctx, cancel := context.WithTimeout(context.Background(), homeLockTimeout)
+	defer cancel()
+
+	locked, err := lock.TryLockContext(ctx, homeLockRetryDelay)
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to lock %s: %w", home, err)
+	}
+	if !locked {
+		return newHomeLockedError(home)
+	}
+	defer func() {
+		if err := lock.Unlock(); err != nil {
+			oktetoLog.Infof("failed to unlock %s: %s", lock.Path(), err)
+		}
+	}()
+
+	pidPath := filepath.Join(home, homeLockPIDFile)
+	if err := os.WriteFile(pidPath, []byte(strconv.Itoa(os.Getpid())), 0600); err != nil {
+		oktetoLog.Infof("failed to write %s: %s", pidPath, err)
+	}
+
+	return fn()
+}
+
+// IsOktetoHomeLocked returns if an okteto process holds the lock over the okteto home folder
+func IsOktetoHomeLocked(home string) (bool, error) {
+	lock := flock.New(filepath.Join(home, homeLockFile))
+	locked, err := lock.TryLock()
+	if err != nil {
+		return false, fmt.Errorf("failed to lock %s: %w", home, err)
+	}
+	if !locked {
+		return true, nil
+	}
+	if err := lock.Unlock(); err != nil {
+		return false, fmt.Errorf("failed to unlock %s: %w", lock.Path(), err)
+	}
+	return false, nil
+}
+
+func newHomeLockedError(home string) error {
+	holder := "another okteto process"
+	if content, err := os.ReadFile(filepath.Join(home, homeLockPIDFile)); err == nil {
+		if pid := strings.TrimSpace(string(content)); pid != "" {
+			holder = fmt.Sprintf("another okteto process (pid %s)", pid)
+		}
+	}
+	return oktetoErrors.UserError{
+		E:    fmt.Errorf("timed out after %s waiting for %s to release '%s'", homeLockTimeout, holder, home),
+		Hint: "Wait for the other okteto command to finish and try again, or set OKTETO_HOME to a different folder for each concurrent job",
+	}
+}
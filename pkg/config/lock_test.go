@@ -0,0 +1,190 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/okteto/okteto/pkg/constants"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/filesystem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	lockWriterEnvVar   = "OKTETO_TEST_HOME_LOCK_WRITER"
+	lockWriterIncrease = 20
+	lockWriters        = 6
+)
+
+// increaseCounter reads, increases and writes back the counter stored in the okteto home
+func increaseCounter() error {
+	return WithOktetoHomeLock(func() error {
+		p := filepath.Join(GetOktetoHome(), "counter")
+		n := 0
+		if content, err := os.ReadFile(p); err == nil {
+			n, err = strconv.Atoi(string(content))
+			if err != nil {
+				return err
+			}
+		}
+		return filesystem.WriteFileAtomic(p, []byte(strconv.Itoa(n+1)), 0600)
+	})
+}
+
+func TestHomeLockWriterProcess(t *testing.T) {
+	if os.Getenv(lockWriterEnvVar) != "true" {
+		t.Skip("only runs as a child process of TestWithOktetoHomeLockConcurrentWriters")
+	}
+	for i := 0; i < lockWriterIncrease; i++ {
+		require.NoError(t, increaseCounter())
+	}
+}
+
+func TestWithOktetoHomeLockConcurrentWriters(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(constants.OktetoFolderEnvVar, dir)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, lockWriters)
+	for i := 0; i < lockWriters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cmd := exec.Command(os.Args[0], "-test.run=^TestHomeLockWriterProcess$")
+			cmd.Env = append(os.Environ(), fmt.Sprintf("%s=true", lockWriterEnvVar))
+			if out, err := cmd.CombinedOutput(); err != nil {
+				errs <- fmt.Errorf("writer failed: %w: %s", err, out)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "counter"))
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(lockWriters*lockWriterIncrease), string(content))
+}
+
+func TestWithOktetoHomeLockTimeout(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(constants.OktetoFolderEnvVar, dir)
+
+	previousTimeout := homeLockTimeout
+	homeLockTimeout = 300 * time.Millisecond
+	defer func() {
+		homeLockTimeout = previousTimeout
+	}()
+
+	acquired := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- WithOktetoHomeLock(func() error {
+			close(acquired)
+			<-release
+			return nil
+		})
+	}()
+	<-acquired
+
+	err := WithOktetoHomeLock(func() error {
+		return nil
+	})
+	close(release)
+	require.NoError(t, <-done)
+
+	var userErr oktetoErrors.UserError
+	require.ErrorAs(t, err, &userErr)
+	assert.Contains(t, err.Error(), fmt.Sprintf("pid %d", os.Getpid()))
+
+	assert.NoError(t, WithOktetoHomeLock(func() error {
+		return nil
+	}))
+}
+
+func TestIsOktetoHomeLocked(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(constants.OktetoFolderEnvVar, dir)
+
+	locked, err := IsOktetoHomeLocked(dir)
+	require.NoError(t, err)
+	assert.False(t, locked)
+
+	acquired := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- WithOktetoHomeLock(func() error {
+			close(acquired)
+			<-release
+			return nil
+		})
+	}()
+	<-acquired
+
+	locked, err = IsOktetoHomeLocked(dir)
+	close(release)
+	require.NoError(t, <-done)
+	require.NoError(t, err)
+	assert.True(t, locked)
+
+	locked, err = IsOktetoHomeLocked(dir)
+	require.NoError(t, err)
+	assert.False(t, locked)
+}
+
+func TestUpdateStateFileHoldsHomeLock(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(constants.OktetoFolderEnvVar, dir)
+
+	previousTimeout := homeLockTimeout
+	homeLockTimeout = 300 * time.Millisecond
+	defer func() {
+		homeLockTimeout = previousTimeout
+	}()
+
+	acquired := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- WithOktetoHomeLock(func() error {
+			close(acquired)
+			<-release
+			return nil
+		})
+	}()
+	<-acquired
+
+	err := UpdateStateFile("dev", "ns", Ready)
+	close(release)
+	require.NoError(t, <-done)
+	require.Error(t, err)
+
+	require.NoError(t, UpdateStateFile("dev", "ns", Ready))
+	state, err := GetState("dev", "ns")
+	require.NoError(t, err)
+	assert.Equal(t, UpState(Ready), state)
+}
@@ -32,6 +32,9 @@ const (
 	// OktetoHomeEnvVar defines the path of okteto folder
 	OktetoHomeEnvVar = "OKTETO_HOME"
 
+	// OktetoAnalyticsEnvVar defines where the analytics events are sent: off, local or on
+	OktetoAnalyticsEnvVar = "OKTETO_ANALYTICS"
+
 	// KubeConfigEnvVar defines the path where kubeconfig is stored
 	KubeConfigEnvVar = "KUBECONFIG"
 
@@ -56,9 +59,33 @@ const (
 	// OKtetoDeployRemoteImage defines okteto cli image used for deploy an environment remotely
 	OKtetoDeployRemoteImage = "OKTETO_REMOTE_CLI_IMAGE"
 
+	// OktetoForwardProxyEnvVar forwards the local proxy variables to the remote destroy when it is true
+	OktetoForwardProxyEnvVar = "OKTETO_FORWARD_PROXY"
+
+	// OktetoDestroyReasonEnvVar tells the destroy commands why the destroy was triggered
+	OktetoDestroyReasonEnvVar = "OKTETO_DESTROY_REASON"
+
+	// OktetoDirtyWorkingTreeEnvVar is true when the remote destroy runs with uncommitted changes in the working tree
+	OktetoDirtyWorkingTreeEnvVar = "OKTETO_DIRTY_WORKING_TREE"
+
+	// OktetoDigestCacheTTLEnvVar is how long the image digests resolved from the registry are reused, 0 disables it
+	OktetoDigestCacheTTLEnvVar = "OKTETO_DIGEST_CACHE_TTL"
+
+	// OktetoForceRemoteEnvVar runs the deploy and destroy commands in remote when true, and locally when false
+	OktetoForceRemoteEnvVar = "OKTETO_FORCE_REMOTE"
+
+	// OktetoTLSVerifyEnvVar skips the verification of the okteto server certificate when false. It is set in the remote deploy and destroy run with '--insecure-skip-tls-verify'
+	OktetoTLSVerifyEnvVar = "OKTETO_TLS_VERIFY"
+
+	// OktetoBuildPlatformEnvVar is the platform of the installer image used by the remote destroy, e.g. linux/arm64
+	OktetoBuildPlatformEnvVar = "OKTETO_BUILD_PLATFORM"
+
 	// OktetoCLIImageForRemoteTemplate defines okteto CLI image template to use for remote deployments
 	OktetoCLIImageForRemoteTemplate = "okteto/okteto:%s"
 
+	// OktetoCLIImageForRemoteByDigestTemplate defines okteto CLI image template to use for remote deployments pinned by digest
+	OktetoCLIImageForRemoteByDigestTemplate = "okteto/okteto@%s"
+
 	// OktetoPipelineRunnerImage defines image to use for remote deployments if empty
 	OktetoPipelineRunnerImage = "okteto/pipeline-runner:1.0.0"
 
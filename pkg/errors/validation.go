@@ -0,0 +1,87 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ValidationError is a problem found validating the okteto manifest
+type ValidationError struct {
+	Message string `json:"message"`
+	// Line is the line of the manifest with the problem, zero when it is unknown
+	Line int `json:"line,omitempty"`
+}
+
+// Error returns the problem, prefixed by its line when it is known
+func (e ValidationError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return e.Message
+}
+
+// ValidationErrors are all the problems found validating the okteto manifest, so they can be fixed at once
+type ValidationErrors []ValidationError
+
+// Error returns the problems as a numbered list
+func (e ValidationErrors) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s, found %d problems:", ErrInvalidManifest, len(e))
+	for i, v := range e {
+		fmt.Fprintf(&sb, "\n  %d. %s", i+1, v.Error())
+	}
+	return sb.String()
+}
+
+// Is makes errors.Is(err, ErrInvalidManifest) true for the validation errors
+func (ValidationErrors) Is(target error) bool {
+	return target == ErrInvalidManifest
+}
+
+// Add appends err to the problems, flattening the validation errors it contains
+func (e *ValidationErrors) Add(err error) {
+	if err == nil {
+		return
+	}
+	var errs ValidationErrors
+	if errors.As(err, &errs) {
+		*e = append(*e, errs...)
+		return
+	}
+	var v ValidationError
+	if errors.As(err, &v) {
+		*e = append(*e, v)
+		return
+	}
+	*e = append(*e, ValidationError{Message: err.Error()})
+}
+
+// ErrorOrNil returns nil if there are no problems and the problem itself if there is only one,
+// so single problems keep being reported as plain errors
+func (e ValidationErrors) ErrorOrNil() error {
+	switch len(e) {
+	case 0:
+		return nil
+	case 1:
+		if e[0].Line > 0 {
+			return e[0]
+		}
+		return errors.New(e[0].Message)
+	default:
+		return e
+	}
+}
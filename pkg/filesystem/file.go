@@ -14,6 +14,7 @@
 package filesystem
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -67,6 +68,37 @@ func CopyFile(from, to string) error {
 	return nil
 }
 
+// WriteFileAtomic writes data to a temporary file in the same folder and renames it to name,
+// so readers never see a partially written file
+func WriteFileAtomic(name string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), fmt.Sprintf(".%s-*", filepath.Base(name)))
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer func() {
+		if err := os.Remove(tmpName); err != nil && !os.IsNotExist(err) {
+			oktetoLog.Debugf("Error removing temporary file %s: %s", tmpName, err)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, name)
+}
+
 // FileExistsAndNotDir checks if the file exists and its not a dir
 func FileExistsAndNotDir(filename string) bool {
 	info, err := os.Stat(filename)
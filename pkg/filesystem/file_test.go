@@ -70,3 +70,31 @@ func TestFileExists(t *testing.T) {
 		t.Errorf("fail to detect existing file")
 	}
 }
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "state.json")
+
+	if err := WriteFileAtomic(p, []byte("first"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFileAtomic(p, []byte("second"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "second" {
+		t.Fatalf("got %s, expected second", string(content))
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("temporary files were left in %s: %v", dir, entries)
+	}
+}
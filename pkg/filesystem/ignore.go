@@ -0,0 +1,44 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filesystem
+
+import (
+	"bytes"
+	"errors"
+	"os"
+
+	"github.com/spf13/afero"
+)
+
+// ReadIgnoreFiles returns the content of the ignore files that exist, concatenated in the given order
+// so the patterns of the latter files take precedence. It returns false if none of the files exist
+func ReadIgnoreFiles(fs afero.Fs, paths ...string) ([]byte, bool, error) {
+	var content bytes.Buffer
+	found := false
+	for _, path := range paths {
+		b, err := afero.ReadFile(fs, path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, false, err
+		}
+		found = true
+		if content.Len() > 0 && !bytes.HasSuffix(content.Bytes(), []byte("\n")) {
+			content.WriteByte('\n')
+		}
+		content.Write(b)
+	}
+	return content.Bytes(), found, nil
+}
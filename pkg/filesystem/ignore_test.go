@@ -0,0 +1,37 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filesystem
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadIgnoreFiles(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "/a", []byte("a"), 0600))
+	assert.NoError(t, afero.WriteFile(fs, "/b", []byte("b\n"), 0600))
+
+	content, found, err := ReadIgnoreFiles(fs, "/a", "/missing", "/b")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "a\nb\n", string(content))
+
+	content, found, err = ReadIgnoreFiles(fs, "/missing")
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.Empty(t, content)
+}
@@ -14,8 +14,13 @@
 package kubeconfig
 
 import (
+	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 
+	"github.com/okteto/okteto/pkg/config"
+	"github.com/okteto/okteto/pkg/filesystem"
 	"k8s.io/client-go/rest"
 
 	"k8s.io/client-go/tools/clientcmd"
@@ -45,9 +50,20 @@ func Get(kubeconfigPaths []string) *clientcmdapi.Config {
 	return mergedConfig
 }
 
-// Write stores a kubeconfig file
+// Write stores a kubeconfig file holding the okteto home lock, so it doesn't interleave with the writes of other okteto commands
 func Write(cfg *clientcmdapi.Config, kubeconfigPath string) error {
-	return clientcmd.WriteToFile(*cfg, kubeconfigPath)
+	content, err := clientcmd.Write(*cfg)
+	if err != nil {
+		return fmt.Errorf("failed to serialize kubeconfig: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(kubeconfigPath), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(kubeconfigPath), err)
+	}
+
+	return config.WithOktetoHomeLock(func() error {
+		return filesystem.WriteFileAtomic(kubeconfigPath, content, 0600)
+	})
 }
 
 // CurrentContext returns the name of the current context
@@ -15,9 +15,12 @@ package kubeconfig
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/okteto/okteto/pkg/constants"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
@@ -149,3 +152,23 @@ func createKubeconfig(kubeconfigFields kubeconfigFields) (string, error) {
 	}
 	return dir.Name(), nil
 }
+
+func TestWrite(t *testing.T) {
+	t.Setenv(constants.OktetoFolderEnvVar, t.TempDir())
+	kubeconfigPath := filepath.Join(t.TempDir(), "nested", "config")
+
+	cfg := &clientcmdapi.Config{
+		Contexts: map[string]*clientcmdapi.Context{
+			"okteto": {Namespace: "ns"},
+		},
+		CurrentContext: "okteto",
+	}
+	require.NoError(t, Write(cfg, kubeconfigPath))
+
+	info, err := os.Stat(kubeconfigPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	assert.Equal(t, "okteto", CurrentContext([]string{kubeconfigPath}))
+	assert.Equal(t, "ns", CurrentNamespace([]string{kubeconfigPath}))
+}
@@ -65,6 +65,8 @@ type DeleteAllOptions struct {
 	LabelSelector string
 	// IncludeVolumes flag to indicate if volumes have to be deleted or not
 	IncludeVolumes bool
+	// OnDeleted is called after each resource is deleted. It might be called concurrently
+	OnDeleted func(kind, name string)
 }
 
 // Namespaces struct to interact with namespaces in k8s
@@ -180,6 +182,9 @@ func (n *Namespaces) DestroyWithLabel(ctx context.Context, ns string, opts Delet
 		}
 
 		oktetoLog.Debugf("successfully deleted '%s' '%s'", gvk.Kind, m.GetName())
+		if opts.OnDeleted != nil {
+			opts.OnDeleted(gvk.Kind, m.GetName())
+		}
 		return nil
 	}))
 }
@@ -232,6 +237,9 @@ func (n *Namespaces) DestroySFSVolumes(ctx context.Context, ns string, opts Dele
 				if err := volumes.DestroyWithoutTimeout(ctx, v.Name, ns, n.k8sClient); err != nil {
 					return err
 				}
+				if opts.OnDeleted != nil {
+					opts.OnDeleted("PersistentVolumeClaim", v.Name)
+				}
 				break
 			}
 		}
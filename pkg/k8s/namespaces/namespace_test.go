@@ -316,9 +316,14 @@ func TestDestroySFSVolumesIfNeeded(t *testing.T) {
 			ctx := context.Background()
 			c := fake.NewSimpleClientset(tt.k8Resources...)
 
+			deleted := []string{}
 			opts := DeleteAllOptions{
 				IncludeVolumes: tt.includeVolumes,
 				LabelSelector:  fmt.Sprintf("%s=%s", model.DeployedByLabel, appName),
+				OnDeleted: func(kind, name string) {
+					assert.Equal(t, "PersistentVolumeClaim", kind)
+					deleted = append(deleted, name)
+				},
 			}
 
 			n := &Namespaces{
@@ -332,6 +337,14 @@ func TestDestroySFSVolumesIfNeeded(t *testing.T) {
 
 			assert.NoError(t, err)
 			assert.ElementsMatch(t, tt.expectedPVCs, pvcList.Items)
+
+			initialPVCs := 0
+			for _, r := range tt.k8Resources {
+				if _, ok := r.(*apiv1.PersistentVolumeClaim); ok {
+					initialPVCs++
+				}
+			}
+			assert.Len(t, deleted, initialPVCs-len(pvcList.Items))
 		})
 	}
 }
@@ -75,10 +75,12 @@ func CreateForDev(ctx context.Context, dev *model.Dev, c kubernetes.Interface, d
 				return fmt.Errorf("error updating kubernetes volume claim: %w", err)
 			}
 			oktetoLog.Debug("could not update pvc in namespace %s: %w", dev.Namespace, err)
+			currentSize := k8Volume.Spec.Resources.Requests[apiv1.ResourceStorage]
+			requestedSize := pvcForDev.Spec.Resources.Requests[apiv1.ResourceStorage]
 			oktetoLog.Warning(`Could not increase the size of the dev volume from %s to %s:
 try running 'okteto down -v' and 'okteto up', or talk to your administrator
 (the PVC's storage class must support 'allowVolumeExpansion' to be able to upscale dev volumes).`,
-				k8Volume.Spec.Resources.Requests[apiv1.ResourceStorage], pvcForDev.Spec.Resources.Requests[apiv1.ResourceStorage])
+				currentSize.String(), requestedSize.String())
 		}
 	}
 	return nil
@@ -0,0 +1,244 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	buildRunningStatus = "building"
+	buildDoneStatus    = "done"
+	buildFailedStatus  = "failed"
+
+	buildRunningSymbol = " - "
+
+	// buildLogTailSize is how many log lines of a service are shown when its build fails without being followed
+	buildLogTailSize = 10
+
+	// eraseLinesFormat moves the cursor up n lines and clears the screen from there
+	eraseLinesFormat = "\033[%dA\033[J"
+)
+
+// BuildRenderer displays the builds of several services. In a tty each service has a line with its
+// status, current step and elapsed time, and only the logs of the followed service are printed.
+// Otherwise, every line is printed prefixed with the name of its service
+type BuildRenderer struct {
+	out    io.Writer
+	tty    bool
+	follow string
+	now    func() time.Time
+
+	mu       sync.Mutex
+	services []*serviceBuild
+	// drawn is the number of lines of the status block currently in the terminal
+	drawn int
+}
+
+type serviceBuild struct {
+	name   string
+	status string
+	step   string
+	start  time.Time
+	end    time.Time
+	tail   []string
+}
+
+// NewBuildRenderer returns a renderer for the builds. The logs of the service follow are printed
+// as they come in a tty
+func NewBuildRenderer(out io.Writer, tty bool, follow string) *BuildRenderer {
+	return &BuildRenderer{
+		out:    out,
+		tty:    tty,
+		follow: follow,
+		now:    time.Now,
+	}
+}
+
+// Start marks the build of a service as running
+func (r *BuildRenderer) Start(svc string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.get(svc)
+	s.status = buildRunningStatus
+	s.step = ""
+	s.start = r.now()
+	s.tail = nil
+	if !r.tty {
+		r.print(fmt.Sprintf("[%s] Building image...", svc))
+		return
+	}
+	r.redraw(nil)
+}
+
+// Step sets the step being built for a service
+func (r *BuildRenderer) Step(svc, step string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.get(svc)
+	if s.step == step {
+		return
+	}
+	s.step = step
+	if !r.tty {
+		return
+	}
+	r.redraw(nil)
+}
+
+// Log adds a line to the logs of a service
+func (r *BuildRenderer) Log(svc, line string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	line = redactMessage(line)
+	if !r.tty {
+		r.print(fmt.Sprintf("[%s] %s", svc, line))
+		return
+	}
+	s := r.get(svc)
+	s.tail = append(s.tail, line)
+	if len(s.tail) > buildLogTailSize {
+		s.tail = s.tail[len(s.tail)-buildLogTailSize:]
+	}
+	if svc == r.follow {
+		r.redraw([]string{fmt.Sprintf("%s | %s", svc, line)})
+	}
+}
+
+// Finish marks the build of a service as done, or failed if err is not nil. The last lines of the
+// logs of a failed service are printed in a tty, unless they were already printed
+func (r *BuildRenderer) Finish(svc string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.get(svc)
+	s.end = r.now()
+	s.status = buildDoneStatus
+	if err != nil {
+		s.status = buildFailedStatus
+	}
+	if !r.tty {
+		if err != nil {
+			r.print(fmt.Sprintf("[%s] Build failed after %s: %s", svc, s.elapsed(s.end), err))
+			return
+		}
+		r.print(fmt.Sprintf("[%s] Build done in %s", svc, s.elapsed(s.end)))
+		return
+	}
+	var above []string
+	if err != nil && svc != r.follow {
+		for _, line := range s.tail {
+			above = append(above, fmt.Sprintf("%s | %s", svc, line))
+		}
+	}
+	r.redraw(above)
+}
+
+// Writer returns a writer that adds each line written to the logs of a service
+func (r *BuildRenderer) Writer(svc string) io.Writer {
+	return &buildLogWriter{renderer: r, svc: svc}
+}
+
+func (r *BuildRenderer) get(svc string) *serviceBuild {
+	for _, s := range r.services {
+		if s.name == svc {
+			return s
+		}
+	}
+	s := &serviceBuild{name: svc}
+	r.services = append(r.services, s)
+	return s
+}
+
+func (r *BuildRenderer) print(line string) {
+	fmt.Fprintln(r.out, line)
+}
+
+// redraw erases the status block, prints the lines above it and draws the status block again
+func (r *BuildRenderer) redraw(above []string) {
+	if log.spinner != nil {
+		log.spinner.hold()
+		defer log.spinner.unhold()
+	}
+	buf := &bytes.Buffer{}
+	if r.drawn > 0 {
+		fmt.Fprintf(buf, eraseLinesFormat, r.drawn)
+	}
+	for _, line := range above {
+		fmt.Fprintln(buf, line)
+	}
+	width := 0
+	for _, s := range r.services {
+		if len(s.name) > width {
+			width = len(s.name)
+		}
+	}
+	now := r.now()
+	for _, s := range r.services {
+		fmt.Fprintln(buf, s.line(width, now))
+	}
+	r.drawn = len(r.services)
+	r.out.Write(buf.Bytes())
+}
+
+// line returns the line of a service in the status block
+func (s *serviceBuild) line(width int, now time.Time) string {
+	symbol := buildRunningSymbol
+	switch s.status {
+	case buildDoneStatus:
+		symbol = successSymbol
+	case buildFailedStatus:
+		symbol = errorSymbol
+	}
+	end := now
+	if !s.end.IsZero() {
+		end = s.end
+	}
+	fields := []string{fmt.Sprintf("%s %-*s", symbol, width, s.name), fmt.Sprintf("%-*s", len(buildRunningStatus), s.status), s.elapsed(end)}
+	if s.status == buildRunningStatus && s.step != "" {
+		fields = append(fields, s.step)
+	}
+	return strings.Join(fields, "  ")
+}
+
+func (s *serviceBuild) elapsed(end time.Time) string {
+	return end.Sub(s.start).Round(time.Second).String()
+}
+
+// buildLogWriter splits what is written into lines for the logs of a service
+type buildLogWriter struct {
+	renderer *BuildRenderer
+	svc      string
+	pending  []byte
+}
+
+func (w *buildLogWriter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i < 0 {
+			break
+		}
+		line := strings.TrimRight(string(w.pending[:i]), "\r")
+		w.pending = w.pending[i+1:]
+		if line != "" {
+			w.renderer.Log(w.svc, line)
+		}
+	}
+	return len(p), nil
+}
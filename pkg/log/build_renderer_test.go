@@ -0,0 +1,119 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files")
+
+// renderInterleavedBuilds builds api and web at the same time, one second per event, and fails web
+func renderInterleavedBuilds(r *BuildRenderer) {
+	clock := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	api := r.Writer("api")
+	web := r.Writer("web")
+
+	r.Start("api")
+	r.Start("web")
+	r.Step("api", "[1/2] FROM golang")
+	fmt.Fprint(api, "#1 [1/2] FROM golang\n#1 DONE 0.1s\n")
+	r.Step("web", "[1/3] FROM node")
+	fmt.Fprint(web, "#2 [1/3] FROM node\n")
+	r.Step("api", "[2/2] RUN go build")
+	fmt.Fprint(api, "#3 [2/2] RUN go build\n#3 0.5 go: downloading")
+	fmt.Fprint(web, "#2 DONE 0.3s\n#4 [2/3] RUN npm ci\n")
+	fmt.Fprint(api, " modules\n")
+	r.Step("web", "[2/3] RUN npm ci")
+	fmt.Fprint(web, "#4 1.2 npm ERR! missing script\n")
+	r.Finish("web", errors.New("exit code 1"))
+	fmt.Fprint(api, "#3 DONE 2.0s\n")
+	r.Finish("api", nil)
+}
+
+func TestBuildRendererSnapshots(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the success symbol is different on windows")
+	}
+	tests := []struct {
+		name   string
+		tty    bool
+		follow string
+	}{
+		{
+			name: "build-renderer-tty",
+			tty:  true,
+		},
+		{
+			name:   "build-renderer-tty-follow",
+			tty:    true,
+			follow: "api",
+		},
+		{
+			name: "build-renderer-plain",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			renderInterleavedBuilds(NewBuildRenderer(out, tt.tty, tt.follow))
+
+			// the escape sequences are replaced to keep the golden files readable
+			result := strings.ReplaceAll(out.String(), "\033", `\e`)
+			golden := filepath.Join("testdata", fmt.Sprintf("%s.golden", tt.name))
+			if *updateGolden {
+				require.NoError(t, os.MkdirAll("testdata", 0700))
+				require.NoError(t, os.WriteFile(golden, []byte(result), 0600))
+			}
+			expected, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), result)
+		})
+	}
+}
+
+func TestBuildRendererPlainPrefixesEveryLine(t *testing.T) {
+	out := &bytes.Buffer{}
+	r := NewBuildRenderer(out, false, "")
+	renderInterleavedBuilds(r)
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		assert.True(t, strings.HasPrefix(line, "[api] ") || strings.HasPrefix(line, "[web] "), line)
+	}
+}
+
+func TestBuildRendererTailSize(t *testing.T) {
+	r := NewBuildRenderer(&bytes.Buffer{}, true, "")
+	for i := 0; i < buildLogTailSize+5; i++ {
+		r.Log("api", fmt.Sprintf("line %d", i))
+	}
+	s := r.get("api")
+	require.Len(t, s.tail, buildLogTailSize)
+	assert.Equal(t, "line 5", s.tail[0])
+}
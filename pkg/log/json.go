@@ -35,6 +35,8 @@ type jsonMessage struct {
 	Stage     string `json:"stage"`
 	Message   string `json:"message"`
 	Timestamp int64  `json:"timestamp"`
+	// Errors are the problems behind an error message, as a machine-readable list
+	Errors interface{} `json:"errors,omitempty"`
 }
 
 // JSONLogFormat formats the messages into json struct
@@ -191,13 +193,18 @@ func (w *JSONWriter) Hint(format string, args ...interface{}) {
 
 // Fail prints a message with the error symbol first, and the text in red
 func (w *JSONWriter) Fail(format string, args ...interface{}) {
+	w.failWithErrors(nil, format, args...)
+}
+
+// failWithErrors prints a failure whose json message includes the list of problems behind it
+func (w *JSONWriter) failWithErrors(errs interface{}, format string, args ...interface{}) {
 	log.out.Infof(format, args...)
 	msg := fmt.Sprintf("%s %s", errorSymbol, fmt.Sprintf(format, args...))
 	if msg != "" {
 		if log.stage == "" {
 			log.stage = "Internal server error"
 		}
-		msg = convertToJSON(ErrorLevel, log.stage, msg)
+		msg = convertToJSONWithErrors(ErrorLevel, log.stage, msg, errs)
 		if msg != "" {
 			log.buf.WriteString(msg)
 			log.buf.WriteString("\n")
@@ -265,6 +272,10 @@ func (*JSONWriter) IsInteractive() bool {
 }
 
 func convertToJSON(level, stage, message string) string {
+	return convertToJSONWithErrors(level, stage, message, nil)
+}
+
+func convertToJSONWithErrors(level, stage, message string, errs interface{}) string {
 	message = strings.TrimRightFunc(message, unicode.IsSpace)
 	if stage == "" || message == "" {
 		return ""
@@ -274,6 +285,7 @@ func convertToJSON(level, stage, message string) string {
 		Message:   ansiRegex.ReplaceAllString(message, ""),
 		Stage:     stage,
 		Timestamp: time.Now().Unix(),
+		Errors:    errs,
 	}
 	messageJSON, _ := json.Marshal(messageStruct)
 	return string(messageJSON)
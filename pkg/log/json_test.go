@@ -97,3 +97,19 @@ func Test_ConvertToJson(t *testing.T) {
 		})
 	}
 }
+
+func Test_ConvertToJsonWithErrors(t *testing.T) {
+	type problem struct {
+		Message string `json:"message"`
+		Line    int    `json:"line"`
+	}
+	result := convertToJSONWithErrors("error", "Load manifest", "invalid manifest", []problem{{Message: "field foo not found", Line: 3}})
+
+	var msg map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(result), &msg))
+	assert.Equal(t, []interface{}{map[string]interface{}{"message": "field foo not found", "line": float64(3)}}, msg["errors"])
+
+	var withoutErrors map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(convertToJSON("error", "Load manifest", "invalid manifest")), &withoutErrors))
+	assert.NotContains(t, withoutErrors, "errors")
+}
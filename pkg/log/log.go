@@ -23,6 +23,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/fatih/color"
 	"github.com/google/uuid"
@@ -64,6 +65,13 @@ var (
 	DebugLevel = "debug"
 )
 
+const (
+	// DoneStage is the stage set when a command has finished
+	DoneStage = "done"
+	// EOFMessage is the message that marks the end of the output of a command in json mode
+	EOFMessage = "EOF"
+)
+
 type logger struct {
 	out    *logrus.Logger
 	file   *logrus.Entry
@@ -72,13 +80,18 @@ type logger struct {
 	stage      string
 	outputMode string
 
-	buf *bytes.Buffer
+	buf            *bytes.Buffer
+	bufferFinished bool
 
 	maskedWords []string
 	isMasked    bool
 	replacer    *strings.Replacer
 
 	spinner *spinnerLogger
+
+	warningsMu       sync.Mutex
+	warnings         []string
+	recordingWarning bool
 }
 
 var log = &logger{
@@ -99,6 +112,7 @@ func Init(level logrus.Level) {
 	log.writer = log.getWriter(TTYFormat)
 	log.maskedWords = []string{}
 	log.buf = &bytes.Buffer{}
+	log.bufferFinished = false
 	log.spinner = &spinnerLogger{
 		sp:             newSpinner(),
 		spinnerSupport: !loadBool(OktetoDisableSpinnerEnvVar) && IsInteractive(),
@@ -159,6 +173,9 @@ func GetOutput() io.Writer {
 // SetOutput sets the log output
 func SetOutput(output io.Writer) {
 	log.out.SetOutput(output)
+	if log.spinner != nil {
+		log.spinner.sp.Writer = output
+	}
 }
 
 // SetOutputFormat sets the output format
@@ -259,6 +276,30 @@ func Question(format string, args ...interface{}) error {
 // Warning prints a message with the warning symbol first, and the text in yellow
 func Warning(format string, args ...interface{}) {
 	log.writer.Warning(format, args...)
+	recordWarning(fmt.Sprintf(format, args...))
+}
+
+// RecordWarnings starts keeping the warnings logged from now on so they can be retrieved with GetWarnings
+func RecordWarnings() {
+	log.warningsMu.Lock()
+	defer log.warningsMu.Unlock()
+	log.recordingWarning = true
+	log.warnings = []string{}
+}
+
+// GetWarnings returns the warnings logged since RecordWarnings was called
+func GetWarnings() []string {
+	log.warningsMu.Lock()
+	defer log.warningsMu.Unlock()
+	return append([]string{}, log.warnings...)
+}
+
+func recordWarning(msg string) {
+	log.warningsMu.Lock()
+	defer log.warningsMu.Unlock()
+	if log.recordingWarning {
+		log.warnings = append(log.warnings, redactMessage(msg))
+	}
 }
 
 // FWarning prints a message with the warning symbol first, and the text in yellow to a specific writer
@@ -278,6 +319,16 @@ func Fail(format string, args ...interface{}) {
 	log.writer.Fail(msg)
 }
 
+// FailWithErrors prints a message with the error symbol first. The json output includes errs as a list
+func FailWithErrors(errs interface{}, format string, args ...interface{}) {
+	w, ok := log.writer.(*JSONWriter)
+	if !ok {
+		Fail(format, args...)
+		return
+	}
+	w.failWithErrors(errs, "%s", redactMessage(fmt.Sprintf(format, args...)))
+}
+
 // Println writes a line with colors
 func Println(args ...interface{}) {
 	msg := fmt.Sprint(args...)
@@ -318,6 +369,11 @@ func AddMaskedWord(word string) {
 	}
 }
 
+// GetMaskedWords returns the words that are redacted when masking is enabled
+func GetMaskedWords() []string {
+	return append([]string{}, log.maskedWords...)
+}
+
 // EnableMasking starts redacting all variables
 func EnableMasking() {
 	log.isMasked = true
@@ -354,6 +410,26 @@ func AddToBuffer(level, format string, args ...interface{}) {
 	log.writer.AddToBuffer(level, format, args...)
 }
 
+// FinishBuffer sets the done stage and, in json mode, adds the EOF marker used by
+// the okteto backend to know that the stream of logs of the command has finished.
+// The marker is added at most once per run.
+func FinishBuffer() {
+	SetStage(DoneStage)
+	if log.outputMode != JSONFormat || log.bufferFinished {
+		return
+	}
+	log.bufferFinished = true
+	log.writer.AddToBuffer(InfoLevel, EOFMessage)
+}
+
+// Flush returns the content of the buffer and resets it
+func Flush() []byte {
+	content := make([]byte, log.buf.Len())
+	copy(content, log.buf.Bytes())
+	log.buf.Reset()
+	return content
+}
+
 func loadBool(env string) bool {
 	value := os.Getenv(env)
 	if value == "" {
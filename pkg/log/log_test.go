@@ -14,8 +14,12 @@
 package log
 
 import (
+	"bytes"
+	"os"
+	"strings"
 	"testing"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -70,3 +74,82 @@ func Test_GetContextResource(t *testing.T) {
 		})
 	}
 }
+
+func TestFinishBuffer(t *testing.T) {
+	var tests = []struct {
+		name        string
+		format      string
+		expectedEOF int
+	}{
+		{
+			name:        "json",
+			format:      JSONFormat,
+			expectedEOF: 1,
+		},
+		{
+			name:        "plain",
+			format:      PlainFormat,
+			expectedEOF: 0,
+		},
+		{
+			name:        "tty",
+			format:      TTYFormat,
+			expectedEOF: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Init(logrus.InfoLevel)
+			out := &bytes.Buffer{}
+			SetOutput(out)
+			SetOutputFormat(tt.format)
+			defer Init(logrus.WarnLevel)
+
+			SetStage("test")
+			AddToBuffer(InfoLevel, "message")
+			FinishBuffer()
+			FinishBuffer()
+
+			assert.Equal(t, DoneStage, log.stage)
+			assert.Equal(t, tt.expectedEOF, strings.Count(GetOutputBuffer().String(), `"message":"EOF"`))
+			assert.Equal(t, tt.expectedEOF, strings.Count(out.String(), `"message":"EOF"`))
+		})
+	}
+}
+
+func TestFlush(t *testing.T) {
+	Init(logrus.InfoLevel)
+	defer Init(logrus.WarnLevel)
+
+	SetStage("test")
+	AddToBuffer(InfoLevel, "message")
+
+	content := Flush()
+	assert.Contains(t, string(content), `"message":"message"`)
+	assert.Empty(t, GetOutputBuffer().String())
+}
+
+func TestRecordWarnings(t *testing.T) {
+	out := &bytes.Buffer{}
+	SetOutput(out)
+	defer SetOutput(os.Stdout)
+	defer func() {
+		log.warningsMu.Lock()
+		log.recordingWarning = false
+		log.warningsMu.Unlock()
+	}()
+
+	Warning("not recorded")
+	assert.Empty(t, GetWarnings())
+
+	RecordWarnings()
+	Warning("volume %s is still in use", "data")
+	log.maskedWords = []string{"secret"}
+	EnableMasking()
+	Warning("token secret expired")
+	DisableMasking()
+
+	assert.Equal(t, []string{"volume data is still in use", "token *** expired"}, GetWarnings())
+	assert.Contains(t, out.String(), "volume data is still in use")
+}
@@ -0,0 +1,223 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// maxIncludeDepth is how many levels of included files can include other files
+const maxIncludeDepth = 5
+
+var (
+	includeFileFields = []string{"include", "commands"}
+	commandFields     = []string{"name", "command", "run", "retries", "retryInterval"}
+)
+
+// includeLoader merges the commands of the files included by the deploy and destroy sections
+type includeLoader struct {
+	readFile func(name string) ([]byte, error)
+	// rootDir is the folder of the manifest, the paths in the errors are relative to it
+	rootDir string
+	files   []string
+}
+
+// loadIncludes merges the commands of the included files into the deploy and destroy sections.
+// The include paths are relative to the file that includes them
+func (m *Manifest) loadIncludes(manifestPath string) error {
+	manifestPath, err := filepath.Abs(manifestPath)
+	if err != nil {
+		return err
+	}
+	l := &includeLoader{
+		readFile: os.ReadFile,
+		rootDir:  filepath.Dir(manifestPath),
+	}
+
+	if m.Deploy != nil && len(m.Deploy.Include) > 0 {
+		commands, err := l.load(m.Deploy.Include, []string{manifestPath})
+		if err != nil {
+			return fmt.Errorf("error loading 'deploy.include': %w", err)
+		}
+		m.Deploy.Commands = append(commands, m.Deploy.Commands...)
+		m.Deploy.Include = nil
+	}
+	if m.Destroy != nil && len(m.Destroy.Include) > 0 {
+		commands, err := l.load(m.Destroy.Include, []string{manifestPath})
+		if err != nil {
+			return fmt.Errorf("error loading 'destroy.include': %w", err)
+		}
+		m.Destroy.Commands = append(commands, m.Destroy.Commands...)
+		m.Destroy.Include = nil
+	}
+	m.IncludedFiles = l.files
+	return nil
+}
+
+// load returns the commands of the included files in order. chain is the list of files that lead to them,
+// starting with the manifest
+func (l *includeLoader) load(includes []string, chain []string) ([]DeployCommand, error) {
+	if len(chain) > maxIncludeDepth {
+		return nil, fmt.Errorf("'%s' can't include more files, includes can only be nested %d levels", l.display(chain[len(chain)-1]), maxIncludeDepth)
+	}
+	dir := filepath.Dir(chain[len(chain)-1])
+
+	commands := []DeployCommand{}
+	for _, include := range includes {
+		path := include
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		path = filepath.Clean(path)
+
+		for i, p := range chain {
+			if p == path {
+				cycle := []string{}
+				for _, c := range append(chain[i:], path) {
+					cycle = append(cycle, l.display(c))
+				}
+				return nil, fmt.Errorf("include cycle detected: %s", strings.Join(cycle, " -> "))
+			}
+		}
+
+		content, err := l.readFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the included file '%s': %w", l.display(path), err)
+		}
+		nestedIncludes, fileCommands, err := l.parse(path, content)
+		if err != nil {
+			return nil, err
+		}
+		nestedCommands, err := l.load(nestedIncludes, append(chain[:len(chain):len(chain)], path))
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, nestedCommands...)
+		commands = append(commands, fileCommands...)
+		l.addFile(path)
+	}
+	return commands, nil
+}
+
+// parse reads an included file. It is either a list of commands or an object with the fields 'include' and 'commands'
+func (l *includeLoader) parse(path string, content []byte) ([]string, []DeployCommand, error) {
+	var doc yaml3.Node
+	if err := yaml3.Unmarshal(content, &doc); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", l.display(path), err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil, fmt.Errorf("%s: the included file is empty", l.display(path))
+	}
+	root := doc.Content[0]
+
+	var includes []string
+	commandsNode := root
+	switch root.Kind {
+	case yaml3.SequenceNode:
+	case yaml3.MappingNode:
+		if err := l.checkFields(path, root, includeFileFields); err != nil {
+			return nil, nil, err
+		}
+		commandsNode = nil
+		for i := 0; i < len(root.Content); i += 2 {
+			key, value := root.Content[i], root.Content[i+1]
+			switch key.Value {
+			case "include":
+				if err := value.Decode(&includes); err != nil {
+					return nil, nil, l.errorAt(path, value, "the field 'include' must be a list of files")
+				}
+			case "commands":
+				commandsNode = value
+			}
+		}
+		if commandsNode == nil {
+			return includes, nil, nil
+		}
+	default:
+		return nil, nil, l.errorAt(path, root, "the included file must be a list of commands or define the field 'commands'")
+	}
+
+	if commandsNode.Kind != yaml3.SequenceNode {
+		return nil, nil, l.errorAt(path, commandsNode, "the field 'commands' must be a list")
+	}
+	commands := []DeployCommand{}
+	for _, node := range commandsNode.Content {
+		if node.Kind == yaml3.MappingNode {
+			if err := l.checkFields(path, node, commandFields); err != nil {
+				return nil, nil, err
+			}
+		}
+		var command DeployCommand
+		if err := node.Decode(&command); err != nil {
+			return nil, nil, l.errorAt(path, node, err.Error())
+		}
+		switch {
+		case strings.TrimSpace(command.Command) == "":
+			return nil, nil, l.errorAt(path, node, "the field 'command' is mandatory")
+		case command.Retries < 0:
+			return nil, nil, l.errorAt(path, node, "the field 'retries' can't be negative")
+		case command.RetryInterval < 0:
+			return nil, nil, l.errorAt(path, node, "the field 'retryInterval' can't be negative")
+		}
+		if command.Name == "" {
+			command.Name = command.Command
+		}
+		commands = append(commands, command)
+	}
+	return includes, commands, nil
+}
+
+func (l *includeLoader) checkFields(path string, node *yaml3.Node, allowed []string) error {
+	for i := 0; i < len(node.Content); i += 2 {
+		key := node.Content[i]
+		found := false
+		for _, field := range allowed {
+			if key.Value == field {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return l.errorAt(path, key, fmt.Sprintf("field '%s' is not allowed, the allowed fields are: %s", key.Value, strings.Join(allowed, ", ")))
+		}
+	}
+	return nil
+}
+
+func (l *includeLoader) errorAt(path string, node *yaml3.Node, msg string) error {
+	return fmt.Errorf("%s:%d: %s", l.display(path), node.Line, msg)
+}
+
+// display returns the path relative to the manifest folder when possible
+func (l *includeLoader) display(path string) string {
+	rel, err := filepath.Rel(l.rootDir, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+func (l *includeLoader) addFile(path string) {
+	for _, f := range l.files {
+		if f == path {
+			return
+		}
+	}
+	l.files = append(l.files, path)
+}
@@ -0,0 +1,169 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeIncludeTestFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+	return dir
+}
+
+func TestManifestIncludes(t *testing.T) {
+	dir := writeIncludeTestFiles(t, map[string]string{
+		"app/okteto.yml": `deploy:
+  include:
+  - ../shared/okteto-deploy.yml
+  commands:
+  - okteto build
+destroy:
+  include:
+  - ../shared/okteto-destroy.yml
+  commands:
+  - kubectl delete -f k8s.yml
+`,
+		"shared/okteto-deploy.yml": `include:
+- helm.yml
+commands:
+- name: apply manifests
+  command: kubectl apply -f k8s.yml
+  retries: 2
+  retryInterval: 5s
+`,
+		"shared/helm.yml": `- helm repo update
+`,
+		"shared/okteto-destroy.yml": `- helm uninstall app
+`,
+	})
+
+	manifest, err := getOktetoManifest(filepath.Join(dir, "app", "okteto.yml"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []DeployCommand{
+		{Name: "helm repo update", Command: "helm repo update"},
+		{Name: "apply manifests", Command: "kubectl apply -f k8s.yml", Retries: 2, RetryInterval: 5 * time.Second},
+		{Name: "okteto build", Command: "okteto build"},
+	}, manifest.Deploy.Commands)
+	assert.Equal(t, []DeployCommand{
+		{Name: "helm uninstall app", Command: "helm uninstall app"},
+		{Name: "kubectl delete -f k8s.yml", Command: "kubectl delete -f k8s.yml"},
+	}, manifest.Destroy.Commands)
+	assert.Empty(t, manifest.Deploy.Include)
+	assert.Empty(t, manifest.Destroy.Include)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "shared", "helm.yml"),
+		filepath.Join(dir, "shared", "okteto-deploy.yml"),
+		filepath.Join(dir, "shared", "okteto-destroy.yml"),
+	}, manifest.IncludedFiles)
+}
+
+func TestManifestIncludesErrors(t *testing.T) {
+	var tests = []struct {
+		files       map[string]string
+		name        string
+		expectedErr string
+	}{
+		{
+			name: "missing file",
+			files: map[string]string{
+				"okteto.yml": "deploy:\n  include:\n  - missing.yml\n  commands:\n  - okteto build\n",
+			},
+			expectedErr: "error loading 'deploy.include': could not read the included file 'missing.yml'",
+		},
+		{
+			name: "cycle",
+			files: map[string]string{
+				"okteto.yml": "deploy:\n  include:\n  - a.yml\n  commands:\n  - okteto build\n",
+				"a.yml":      "include:\n- b.yml\n",
+				"b.yml":      "include:\n- a.yml\ncommands:\n- echo b\n",
+			},
+			expectedErr: "include cycle detected: a.yml -> b.yml -> a.yml",
+		},
+		{
+			name: "manifest included",
+			files: map[string]string{
+				"okteto.yml": "destroy:\n  include:\n  - okteto.yml\n  commands:\n  - okteto destroy\n",
+			},
+			expectedErr: "error loading 'destroy.include': include cycle detected: okteto.yml -> okteto.yml",
+		},
+		{
+			name: "depth limit",
+			files: map[string]string{
+				"okteto.yml": "deploy:\n  include:\n  - 1.yml\n  commands:\n  - okteto build\n",
+				"1.yml":      "include:\n- 2.yml\n",
+				"2.yml":      "include:\n- 3.yml\n",
+				"3.yml":      "include:\n- 4.yml\n",
+				"4.yml":      "include:\n- 5.yml\n",
+				"5.yml":      "include:\n- 6.yml\n",
+				"6.yml":      "- echo too deep\n",
+			},
+			expectedErr: "'5.yml' can't include more files, includes can only be nested 5 levels",
+		},
+		{
+			name: "negative retries",
+			files: map[string]string{
+				"okteto.yml":   "deploy:\n  include:\n  - shared/a.yml\n  commands:\n  - okteto build\n",
+				"shared/a.yml": "commands:\n- echo a\n- command: echo b\n  retries: -1\n",
+			},
+			expectedErr: "shared/a.yml:3: the field 'retries' can't be negative",
+		},
+		{
+			name: "empty command",
+			files: map[string]string{
+				"okteto.yml": "deploy:\n  include:\n  - a.yml\n  commands:\n  - okteto build\n",
+				"a.yml":      "- name: empty\n",
+			},
+			expectedErr: "a.yml:1: the field 'command' is mandatory",
+		},
+		{
+			name: "unknown field",
+			files: map[string]string{
+				"okteto.yml": "deploy:\n  include:\n  - a.yml\n  commands:\n  - okteto build\n",
+				"a.yml":      "commands:\n- echo a\nimage: alpine\n",
+			},
+			expectedErr: "a.yml:3: field 'image' is not allowed, the allowed fields are: include, commands",
+		},
+		{
+			name: "invalid yaml",
+			files: map[string]string{
+				"okteto.yml": "deploy:\n  include:\n  - a.yml\n  commands:\n  - okteto build\n",
+				"a.yml":      "commands: [echo a\n",
+			},
+			expectedErr: "a.yml: yaml: line 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeIncludeTestFiles(t, tt.files)
+			_, err := getOktetoManifest(filepath.Join(dir, "okteto.yml"))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
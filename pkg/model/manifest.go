@@ -20,7 +20,9 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -103,10 +105,13 @@ type Manifest struct {
 	Dependencies  ManifestDependencies                     `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
 	GlobalForward []forward.GlobalForward                  `json:"forward,omitempty" yaml:"forward,omitempty"`
 	External      externalresource.ExternalResourceSection `json:"external,omitempty" yaml:"external,omitempty"`
+	Variables     ManifestVariables                        `json:"variables,omitempty" yaml:"variables,omitempty"`
 
 	Type     Archetype `json:"-" yaml:"-"`
 	Manifest []byte    `json:"-" yaml:"-"`
 	IsV2     bool      `json:"-" yaml:"-"`
+	// IncludedFiles are the absolute paths of the files included by the deploy and destroy sections
+	IncludedFiles []string `json:"-" yaml:"-"`
 }
 
 // ManifestDevs defines all the dev section
@@ -184,12 +189,40 @@ type DeployInfo struct {
 	ComposeSection *ComposeSectionInfo `json:"compose,omitempty" yaml:"compose,omitempty"`
 	Endpoints      EndpointSpec        `json:"endpoints,omitempty" yaml:"endpoints,omitempty"`
 	Divert         *DivertDeploy       `json:"divert,omitempty" yaml:"divert,omitempty"`
+	// Resources are the default resources applied to the containers deployed without explicit requests/limits
+	Resources *ResourceRequirements `json:"resources,omitempty" yaml:"resources,omitempty"`
+	// WaitConditions are the conditions that '--wait' checks on the custom resources deployed
+	WaitConditions []WaitCondition `json:"waitConditions,omitempty" yaml:"waitConditions,omitempty"`
+	// AllowClusterResources lets the deploy commands apply cluster-scoped objects, like ClusterRoles or CRDs
+	AllowClusterResources bool `json:"allowClusterResources,omitempty" yaml:"allowClusterResources,omitempty"`
+	// Include are files with commands that run before Commands. It is emptied once they are merged into Commands
+	Include []string `json:"include,omitempty" yaml:"include,omitempty"`
+}
+
+// WaitCondition is the status condition that a kind of custom resource must report to be ready.
+// An empty condition only requires the resources to exist
+type WaitCondition struct {
+	APIVersion string `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Condition  string `json:"condition,omitempty" yaml:"condition,omitempty"`
 }
 
 // DestroyInfo represents what must be destroyed for the app
 type DestroyInfo struct {
 	Image    string          `json:"image,omitempty" yaml:"image,omitempty"`
 	Commands []DeployCommand `json:"commands,omitempty" yaml:"commands,omitempty"`
+	// CACerts are paths to PEM files with extra CA certificates trusted by the remote destroy image
+	CACerts []string `json:"caCerts,omitempty" yaml:"caCerts,omitempty"`
+	// Platform is the platform (os/arch[/variant]) used to build the remote destroy image
+	Platform string `json:"platform,omitempty" yaml:"platform,omitempty"`
+	// Include are files with commands that run before Commands. It is emptied once they are merged into Commands
+	Include []string `json:"include,omitempty" yaml:"include,omitempty"`
+	// Context is the folder shipped as build context of the remote destroy. It defaults to the working directory
+	Context string `json:"context,omitempty" yaml:"context,omitempty"`
+	// SSH forwards the local ssh agent to the commands of the remote destroy
+	SSH bool `json:"ssh,omitempty" yaml:"ssh,omitempty"`
+	// ExtraDockerfile are Dockerfile instructions added to the remote destroy image to install extra tooling
+	ExtraDockerfile string `json:"extraDockerfile,omitempty" yaml:"extraDockerfile,omitempty"`
 }
 
 // DivertDeploy represents information about the deploy divert configuration
@@ -235,6 +268,10 @@ type ServicesToDeploy []string
 type DeployCommand struct {
 	Name    string `json:"name,omitempty" yaml:"name,omitempty"`
 	Command string `json:"command,omitempty" yaml:"command,omitempty"`
+	// Retries is the number of times the command runs again when it fails
+	Retries int `json:"retries,omitempty" yaml:"retries,omitempty"`
+	// RetryInterval is the time to wait before running a failed command again
+	RetryInterval time.Duration `json:"retryInterval,omitempty" yaml:"retryInterval,omitempty"`
 }
 
 // NewDeployInfo creates a deploy Info
@@ -620,6 +657,13 @@ func getOktetoManifest(devPath string) (*Manifest, error) {
 		if errors.Is(err, oktetoErrors.ErrNotManifestContentDetected) {
 			return nil, err
 		}
+		var validationErrs oktetoErrors.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			return nil, oktetoErrors.UserError{
+				E:    validationErrs,
+				Hint: "See https://okteto.com/docs/reference/manifest/ for details",
+			}
+		}
 		return nil, fmt.Errorf("%w: %s", oktetoErrors.ErrInvalidManifest, err.Error())
 	}
 
@@ -633,6 +677,10 @@ func getOktetoManifest(devPath string) (*Manifest, error) {
 		ef.LoadMarkdownContent(devPath)
 	}
 
+	if err := manifest.loadIncludes(devPath); err != nil {
+		return nil, fmt.Errorf("%w: %s", oktetoErrors.ErrInvalidManifest, err.Error())
+	}
+
 	for _, dev := range manifest.Dev {
 
 		if err := dev.loadAbsPaths(devPath); err != nil {
@@ -659,6 +707,7 @@ func isEmptyManifestFile(bytes []byte) bool {
 // Read reads an okteto manifests
 func Read(bytes []byte) (*Manifest, error) {
 	manifest := NewManifest()
+	var validationErrs oktetoErrors.ValidationErrors
 	if bytes != nil {
 		if err := yaml.UnmarshalStrict(bytes, manifest); err != nil {
 			if err := yaml.Unmarshal(bytes, manifest); err == nil {
@@ -667,21 +716,21 @@ func Read(bytes []byte) (*Manifest, error) {
 				}
 			}
 
-			if strings.HasPrefix(err.Error(), "yaml: unmarshal errors:") {
-				var sb strings.Builder
-				l := strings.Split(err.Error(), "\n")
-				for i := 1; i < len(l); i++ {
-					e := strings.TrimSuffix(l[i], "in type model.Manifest")
-					e = strings.TrimSpace(e)
-					_, _ = sb.WriteString(fmt.Sprintf("    - %s\n", e))
-				}
-
-				_, _ = sb.WriteString(fmt.Sprintf("    See %s for details", "https://okteto.com/docs/reference/manifest/"))
-				return nil, fmt.Errorf("\n%s", sb.String())
+			if !strings.HasPrefix(err.Error(), "yaml: unmarshal errors:") {
+				msg := strings.TrimSuffix(err.Error(), "in type model.Manifest")
+				return nil, fmt.Errorf("\n%s", msg)
 			}
 
-			msg := strings.TrimSuffix(err.Error(), "in type model.Manifest")
-			return nil, fmt.Errorf("\n%s", msg)
+			validationErrs = getUnmarshalValidationErrors(err)
+			// the unknown fields are removed to report the problems of the rest of the manifest too
+			withoutUnknownFields, ok := removeUnknownFields(bytes, validationErrs)
+			if !ok {
+				return nil, validationErrs.ErrorOrNil()
+			}
+			manifest = NewManifest()
+			if err := yaml.UnmarshalStrict(withoutUnknownFields, manifest); err != nil {
+				return nil, validationErrs.ErrorOrNil()
+			}
 		}
 	}
 
@@ -693,11 +742,9 @@ func Read(bytes []byte) (*Manifest, error) {
 		}
 	}
 
-	if err := manifest.setDefaults(); err != nil {
-		return nil, err
-	}
-
-	if err := manifest.validate(); err != nil {
+	validationErrs.Add(manifest.setDefaults())
+	validationErrs.Add(manifest.validate())
+	if err := validationErrs.ErrorOrNil(); err != nil {
 		return nil, err
 	}
 	manifest.Manifest = bytes
@@ -705,11 +752,107 @@ func Read(bytes []byte) (*Manifest, error) {
 	return manifest, nil
 }
 
+// unmarshalErrorRegex matches the errors listed by 'yaml: unmarshal errors:'
+var unmarshalErrorRegex = regexp.MustCompile(`^line (\d+): (.*?)( in type [\w.]+)?$`)
+
+// getUnmarshalValidationErrors returns the problems listed by an unmarshal error, with their lines
+func getUnmarshalValidationErrors(err error) oktetoErrors.ValidationErrors {
+	var errs oktetoErrors.ValidationErrors
+	lines := strings.Split(err.Error(), "\n")
+	for _, l := range lines[1:] {
+		l = strings.TrimSpace(l)
+		if l == "" {
+			continue
+		}
+		if match := unmarshalErrorRegex.FindStringSubmatch(l); match != nil {
+			line, _ := strconv.Atoi(match[1])
+			errs = append(errs, oktetoErrors.ValidationError{Message: match[2], Line: line})
+			continue
+		}
+		errs = append(errs, oktetoErrors.ValidationError{Message: l})
+	}
+	return errs
+}
+
+// removeUnknownFields returns the manifest without the fields reported as not found by the unmarshal errors.
+// It returns false if there is any other kind of unmarshal error, as the manifest can't be decoded then
+func removeUnknownFields(bytes []byte, errs oktetoErrors.ValidationErrors) ([]byte, bool) {
+	unknown := map[int]string{}
+	for _, e := range errs {
+		var field string
+		if _, err := fmt.Sscanf(e.Message, "field %s not found", &field); err != nil || e.Line == 0 {
+			return nil, false
+		}
+		unknown[e.Line] = field
+	}
+
+	var root yaml3.Node
+	if err := yaml3.Unmarshal(bytes, &root); err != nil {
+		return nil, false
+	}
+	removeFields(&root, unknown)
+	result, err := yaml3.Marshal(&root)
+	if err != nil {
+		return nil, false
+	}
+	return result, true
+}
+
+// removeFields removes the mapping keys found in the lines of unknown
+func removeFields(node *yaml3.Node, unknown map[int]string) {
+	if node.Kind == yaml3.MappingNode {
+		content := make([]*yaml3.Node, 0, len(node.Content))
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if field, ok := unknown[node.Content[i].Line]; ok && field == node.Content[i].Value {
+				continue
+			}
+			content = append(content, node.Content[i], node.Content[i+1])
+		}
+		node.Content = content
+	}
+	for _, child := range node.Content {
+		removeFields(child, unknown)
+	}
+}
+
+// validate returns all the problems of the manifest instead of stopping at the first one
 func (m *Manifest) validate() error {
-	if err := m.Build.validate(); err != nil {
-		return err
+	var errs oktetoErrors.ValidationErrors
+	errs.Add(m.Build.validate())
+	errs.Add(m.Variables.validate())
+	errs.Add(m.validateWaitConditions())
+	errs.Add(m.validateDeployCommands())
+	errs.Add(m.validateDivert())
+	return errs.ErrorOrNil()
+}
+
+func (m *Manifest) validateWaitConditions() error {
+	if m.Deploy == nil {
+		return nil
+	}
+	var errs oktetoErrors.ValidationErrors
+	for i, wc := range m.Deploy.WaitConditions {
+		if wc.APIVersion == "" || wc.Kind == "" {
+			errs.Add(fmt.Errorf("the fields 'apiVersion' and 'kind' are mandatory in 'deploy.waitConditions[%d]'", i))
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+func (m *Manifest) validateDeployCommands() error {
+	if m.Deploy == nil {
+		return nil
 	}
-	return m.validateDivert()
+	var errs oktetoErrors.ValidationErrors
+	for i, command := range m.Deploy.Commands {
+		if command.Retries < 0 {
+			errs.Add(fmt.Errorf("the field 'retries' can't be negative in 'deploy.commands[%d]'", i))
+		}
+		if command.RetryInterval < 0 {
+			errs.Add(fmt.Errorf("the field 'retryInterval' can't be negative in 'deploy.commands[%d]'", i))
+		}
+	}
+	return errs.ErrorOrNil()
 }
 
 func (b *ManifestBuild) validate() error {
@@ -794,88 +937,101 @@ func (m *Manifest) validateDivert() error {
 		return nil
 	}
 
+	var errs oktetoErrors.ValidationErrors
 	switch m.Deploy.Divert.Driver {
 	case constants.OktetoDivertWeaverDriver:
 		if m.Deploy.Divert.Namespace == "" {
-			return fmt.Errorf("the field 'deploy.divert.namespace' is mandatory")
+			errs.Add(fmt.Errorf("the field 'deploy.divert.namespace' is mandatory"))
 		}
 		if len(m.Deploy.Divert.VirtualServices) > 0 {
-			return fmt.Errorf("the field 'deploy.divert.virtualServices' is not supported with the weaver driver")
+			errs.Add(fmt.Errorf("the field 'deploy.divert.virtualServices' is not supported with the weaver driver"))
 		}
 		if len(m.Deploy.Divert.Hosts) > 0 {
-			return fmt.Errorf("the field 'deploy.divert.host' is not supported with the weaver driver")
+			errs.Add(fmt.Errorf("the field 'deploy.divert.host' is not supported with the weaver driver"))
 		}
 	case constants.OktetoDivertIstioDriver:
 		if m.Deploy.Divert.DeprecatedService != "" {
-			return fmt.Errorf("the field 'deploy.divert.service' is not supported with the istio driver")
+			errs.Add(fmt.Errorf("the field 'deploy.divert.service' is not supported with the istio driver"))
 		}
 		if m.Deploy.Divert.Namespace != "" {
-			return fmt.Errorf("the field 'deploy.divert.namespace' is not supported with the istio driver")
+			errs.Add(fmt.Errorf("the field 'deploy.divert.namespace' is not supported with the istio driver"))
 		}
 		if len(m.Deploy.Divert.VirtualServices) == 0 {
-			return fmt.Errorf("the field 'deploy.divert.virtualServices' is mandatory")
+			errs.Add(fmt.Errorf("the field 'deploy.divert.virtualServices' is mandatory"))
 		}
 		for i := range m.Deploy.Divert.VirtualServices {
 			if m.Deploy.Divert.VirtualServices[i].Name == "" {
-				return fmt.Errorf("the field 'deploy.divert.virtualServices[%d].name' is mandatory", i)
+				errs.Add(fmt.Errorf("the field 'deploy.divert.virtualServices[%d].name' is mandatory", i))
 			}
 			if m.Deploy.Divert.VirtualServices[i].Namespace == "" {
-				return fmt.Errorf("the field 'deploy.divert.virtualServices[%d].namespace' is mandatory", i)
+				errs.Add(fmt.Errorf("the field 'deploy.divert.virtualServices[%d].namespace' is mandatory", i))
 			}
 		}
 		for i := range m.Deploy.Divert.Hosts {
 			if m.Deploy.Divert.Hosts[i].VirtualService == "" {
-				return fmt.Errorf("the field 'deploy.divert.hosts[%d].virtualService' is mandatory", i)
+				errs.Add(fmt.Errorf("the field 'deploy.divert.hosts[%d].virtualService' is mandatory", i))
 			}
 			if m.Deploy.Divert.Hosts[i].Namespace == "" {
-				return fmt.Errorf("the field 'deploy.divert.hosts[%d].namespace' is mandatory", i)
+				errs.Add(fmt.Errorf("the field 'deploy.divert.hosts[%d].namespace' is mandatory", i))
 			}
 		}
 	default:
-		return fmt.Errorf("the divert driver '%s' isn't supported", m.Deploy.Divert.Driver)
+		errs.Add(fmt.Errorf("the divert driver '%s' isn't supported", m.Deploy.Divert.Driver))
 	}
-	return nil
+	return errs.ErrorOrNil()
 }
 
+// setDefaults returns all the problems expanding and defaulting the manifest instead of stopping at the first one
 func (m *Manifest) setDefaults() error {
+	var errs oktetoErrors.ValidationErrors
 	if m.Deploy != nil && m.Deploy.Divert != nil {
 		var err error
 		if m.Deploy.Divert.Driver == "" {
 			m.Deploy.Divert.Driver = constants.OktetoDivertWeaverDriver
 		}
 		m.Deploy.Divert.Namespace, err = ExpandEnv(m.Deploy.Divert.Namespace, false)
-		if err != nil {
-			return err
-		}
+		errs.Add(err)
 		for i := range m.Deploy.Divert.Hosts {
 			m.Deploy.Divert.Hosts[i].VirtualService, err = ExpandEnv(m.Deploy.Divert.Hosts[i].VirtualService, false)
-			if err != nil {
-				return err
-			}
+			errs.Add(err)
 			m.Deploy.Divert.Hosts[i].Namespace, err = ExpandEnv(m.Deploy.Divert.Hosts[i].Namespace, false)
-			if err != nil {
-				return err
-			}
+			errs.Add(err)
 		}
 	}
-	for dName, d := range m.Dev {
+	// the devs are sorted so their problems are always reported in the same order
+	devNames := make([]string, 0, len(m.Dev))
+	for dName := range m.Dev {
+		devNames = append(devNames, dName)
+	}
+	sort.Strings(devNames)
+	for _, dName := range devNames {
+		d := m.Dev[dName]
 		if d.Name == "" {
 			d.Name = dName
 		}
 		if err := d.expandEnvVars(); err != nil {
-			return fmt.Errorf("Error on dev '%s': %s", d.Name, err)
+			errs.Add(fmt.Errorf("Error on dev '%s': %s", d.Name, err))
+			continue
 		}
+		servicesFailed := false
 		for _, s := range d.Services {
 			if err := s.expandEnvVars(); err != nil {
-				return fmt.Errorf("Error on dev '%s': %s", d.Name, err)
+				errs.Add(fmt.Errorf("Error on dev '%s': %s", d.Name, err))
+				servicesFailed = true
+				continue
 			}
 			if err := s.validateForExtraFields(); err != nil {
-				return fmt.Errorf("Error on dev '%s': %s", d.Name, err)
+				errs.Add(fmt.Errorf("Error on dev '%s': %s", d.Name, err))
+				servicesFailed = true
 			}
 		}
+		if servicesFailed {
+			continue
+		}
 
 		if err := d.SetDefaults(); err != nil {
-			return fmt.Errorf("Error on dev '%s': %s", d.Name, err)
+			errs.Add(fmt.Errorf("Error on dev '%s': %s", d.Name, err))
+			continue
 		}
 
 		d.translateDeprecatedMetadataFields()
@@ -889,7 +1045,7 @@ func (m *Manifest) setDefaults() error {
 		})
 
 		if err := d.translateDeprecatedVolumeFields(); err != nil {
-			return err
+			errs.Add(err)
 		}
 	}
 
@@ -908,7 +1064,7 @@ func (m *Manifest) setDefaults() error {
 		m.Destroy = &DestroyInfo{}
 	}
 
-	return nil
+	return errs.ErrorOrNil()
 }
 
 func (m *Manifest) mergeWithOktetoManifest(other *Manifest) {
@@ -1000,12 +1156,21 @@ type Dependency struct {
 	Repository   string        `json:"repository" yaml:"repository"`
 	ManifestPath string        `json:"manifest,omitempty" yaml:"manifest,omitempty"`
 	Branch       string        `json:"branch,omitempty" yaml:"branch,omitempty"`
+	Ref          string        `json:"ref,omitempty" yaml:"ref,omitempty"`
 	Variables    Environment   `json:"variables,omitempty" yaml:"variables,omitempty"`
 	Wait         bool          `json:"wait,omitempty" yaml:"wait,omitempty"`
 	Timeout      time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
 	Namespace    string        `json:"namespace,omitempty" yaml:"namespace,omitempty"`
 }
 
+// GetRef returns the commit SHA or tag the dependency is pinned to, or its branch if it's not pinned
+func (d *Dependency) GetRef() string {
+	if d.Ref != "" {
+		return d.Ref
+	}
+	return d.Branch
+}
+
 // GetTimeout returns dependency.Timeout if it's set or the one passed as arg if it's not
 func (d *Dependency) GetTimeout(defaultTimeout time.Duration) time.Duration {
 	if d.Timeout != 0 {
@@ -25,6 +25,7 @@ import (
 	oktetoErrors "github.com/okteto/okteto/pkg/errors"
 	"github.com/okteto/okteto/pkg/model/forward"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/utils/pointer"
 )
@@ -329,6 +330,142 @@ func Test_validateDivert(t *testing.T) {
 	}
 }
 
+func Test_validateWaitConditions(t *testing.T) {
+	tests := []struct {
+		name           string
+		waitConditions []WaitCondition
+		expectedErr    error
+	}{
+		{
+			name: "valid",
+			waitConditions: []WaitCondition{
+				{APIVersion: "cert-manager.io/v1", Kind: "Certificate", Condition: "Ready"},
+				{APIVersion: "example.com/v1", Kind: "Database"},
+			},
+		},
+		{
+			name: "missing kind",
+			waitConditions: []WaitCondition{
+				{APIVersion: "cert-manager.io/v1", Kind: "Certificate", Condition: "Ready"},
+				{APIVersion: "example.com/v1"},
+			},
+			expectedErr: fmt.Errorf("the fields 'apiVersion' and 'kind' are mandatory in 'deploy.waitConditions[1]'"),
+		},
+		{
+			name: "missing apiVersion",
+			waitConditions: []WaitCondition{
+				{Kind: "Certificate"},
+			},
+			expectedErr: fmt.Errorf("the fields 'apiVersion' and 'kind' are mandatory in 'deploy.waitConditions[0]'"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Manifest{
+				Deploy: &DeployInfo{
+					WaitConditions: tt.waitConditions,
+				},
+			}
+			assert.Equal(t, tt.expectedErr, m.validateWaitConditions())
+		})
+	}
+}
+
+func Test_validateDeployCommands(t *testing.T) {
+	tests := []struct {
+		name        string
+		commands    []DeployCommand
+		expectedErr error
+	}{
+		{
+			name: "valid",
+			commands: []DeployCommand{
+				{Name: "deploy", Command: "helm upgrade --install api chart"},
+				{Name: "wait", Command: "curl -f https://api.example.com", Retries: 3, RetryInterval: 10 * time.Second},
+			},
+		},
+		{
+			name: "negative retries",
+			commands: []DeployCommand{
+				{Name: "deploy", Command: "helm upgrade --install api chart"},
+				{Name: "wait", Command: "curl -f https://api.example.com", Retries: -1},
+			},
+			expectedErr: fmt.Errorf("the field 'retries' can't be negative in 'deploy.commands[1]'"),
+		},
+		{
+			name: "negative retry interval",
+			commands: []DeployCommand{
+				{Name: "wait", Command: "curl -f https://api.example.com", Retries: 1, RetryInterval: -time.Second},
+			},
+			expectedErr: fmt.Errorf("the field 'retryInterval' can't be negative in 'deploy.commands[0]'"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Manifest{
+				Deploy: &DeployInfo{
+					Commands: tt.commands,
+				},
+			}
+			assert.Equal(t, tt.expectedErr, m.validateDeployCommands())
+		})
+	}
+}
+
+func Test_validateWaitConditionsReportsAllProblems(t *testing.T) {
+	m := &Manifest{
+		Deploy: &DeployInfo{
+			WaitConditions: []WaitCondition{{Kind: "Certificate"}, {APIVersion: "example.com/v1", Kind: "Database"}, {APIVersion: "example.com/v1"}},
+		},
+	}
+	assert.Equal(t, oktetoErrors.ValidationErrors{
+		{Message: "the fields 'apiVersion' and 'kind' are mandatory in 'deploy.waitConditions[0]'"},
+		{Message: "the fields 'apiVersion' and 'kind' are mandatory in 'deploy.waitConditions[2]'"},
+	}, m.validateWaitConditions())
+}
+
+func TestReadReportsAllProblems(t *testing.T) {
+	manifest := []byte(`deploy:
+  commands:
+  - name: deploy
+    command: helm upgrade --install api chart
+    retries: -1
+  waitConditions:
+  - kind: Certificate
+  unknown: true
+variables:
+- name: DB
+- name: DB
+other: field
+`)
+	_, err := Read(manifest)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, oktetoErrors.ErrInvalidManifest)
+
+	var validationErrs oktetoErrors.ValidationErrors
+	require.ErrorAs(t, err, &validationErrs)
+	assert.Equal(t, oktetoErrors.ValidationErrors{
+		{Message: "field unknown not found", Line: 8},
+		{Message: "field other not found", Line: 12},
+		{Message: "variables: 'DB' is declared more than once"},
+		{Message: "the fields 'apiVersion' and 'kind' are mandatory in 'deploy.waitConditions[0]'"},
+		{Message: "the field 'retries' can't be negative in 'deploy.commands[0]'"},
+	}, validationErrs)
+	assert.Equal(t, `invalid manifest, found 5 problems:
+  1. line 8: field unknown not found
+  2. line 12: field other not found
+  3. variables: 'DB' is declared more than once
+  4. the fields 'apiVersion' and 'kind' are mandatory in 'deploy.waitConditions[0]'
+  5. the field 'retries' can't be negative in 'deploy.commands[0]'`, err.Error())
+}
+
+func TestReadReportsSingleProblemAsPlainError(t *testing.T) {
+	_, err := Read([]byte("deploy:\n  commands:\n  - command: helm upgrade\n    retries: -1\n"))
+	assert.EqualError(t, err, "the field 'retries' can't be negative in 'deploy.commands[0]'")
+}
+
 func Test_validateManifestBuild(t *testing.T) {
 	tests := []struct {
 		name         string
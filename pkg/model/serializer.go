@@ -791,6 +791,7 @@ type manifestRaw struct {
 	Dependencies  ManifestDependencies                     `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
 	GlobalForward []forward.GlobalForward                  `json:"forward,omitempty" yaml:"forward,omitempty"`
 	External      externalresource.ExternalResourceSection `json:"external,omitempty" yaml:"external,omitempty"`
+	Variables     ManifestVariables                        `json:"variables,omitempty" yaml:"variables,omitempty"`
 
 	DeprecatedDevs []string `yaml:"devs"`
 }
@@ -847,6 +848,10 @@ func (d *Dependency) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	}
 	*d = Dependency(dependencyRaw)
 
+	if d.Branch != "" && d.Ref != "" {
+		return fmt.Errorf("dependency '%s' can't define both 'branch' and 'ref'", d.Repository)
+	}
+
 	return nil
 }
 
@@ -883,6 +888,7 @@ func (m *Manifest) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	m.Name = manifest.Name
 	m.GlobalForward = manifest.GlobalForward
 	m.External = manifest.External
+	m.Variables = manifest.Variables
 
 	err = m.SanitizeSvcNames()
 	if err != nil {
@@ -903,12 +909,25 @@ func (d *DeployCommand) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 	// prevent recursion
 	type deployCommand DeployCommand
-	var extendedCommand deployCommand
+	var extendedCommand struct {
+		deployCommand `yaml:",inline"`
+		// Run is an alias of Command
+		Run string `yaml:"run,omitempty"`
+	}
 	err = unmarshal(&extendedCommand)
 	if err != nil {
 		return err
 	}
-	*d = DeployCommand(extendedCommand)
+	*d = DeployCommand(extendedCommand.deployCommand)
+	if extendedCommand.Run != "" {
+		if d.Command != "" {
+			return fmt.Errorf("the fields 'command' and 'run' can't be used together")
+		}
+		d.Command = extendedCommand.Run
+	}
+	if d.Name == "" {
+		d.Name = d.Command
+	}
 	return nil
 }
 
@@ -916,9 +935,9 @@ func (d *DeployInfo) MarshalYAML() (interface{}, error) {
 	if d.ComposeSection != nil && len(d.ComposeSection.ComposesInfo) != 0 {
 		return d, nil
 	}
-	isCommandList := true
+	isCommandList := len(d.Include) == 0
 	for _, cmd := range d.Commands {
-		if cmd.Command != cmd.Name {
+		if cmd.Command != cmd.Name || cmd.Retries != 0 || cmd.RetryInterval != 0 {
 			isCommandList = false
 		}
 	}
@@ -1128,7 +1147,7 @@ func (d *DestroyInfo) UnmarshalYAML(unmarshal func(interface{}) error) error {
 }
 
 func (d *DestroyInfo) MarshalYAML() (interface{}, error) {
-	isCommandList := true
+	isCommandList := d.Image == "" && len(d.CACerts) == 0 && d.Platform == "" && len(d.Include) == 0 && d.Context == "" && !d.SSH && d.ExtraDockerfile == ""
 	for _, cmd := range d.Commands {
 		if cmd.Command != cmd.Name {
 			isCommandList = false
@@ -28,6 +28,7 @@ import (
 	"github.com/stretchr/testify/require"
 	yaml "gopkg.in/yaml.v2"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/utils/pointer"
 )
 
@@ -2106,6 +2107,42 @@ func TestDestroyInfoMarshalling(t *testing.T) {
 			}},
 			expected: "commands:\n- name: build\n  command: okteto build\n- name: deploy\n  command: okteto deploy\n",
 		},
+		{
+			name: "ca-certs",
+			destroyInfo: &DestroyInfo{
+				CACerts: []string{"certs/ca.pem"},
+				Commands: []DeployCommand{
+					{
+						Name:    "okteto deploy",
+						Command: "okteto deploy",
+					},
+				}},
+			expected: "commands:\n- name: okteto deploy\n  command: okteto deploy\ncaCerts:\n- certs/ca.pem\n",
+		},
+		{
+			name: "platform",
+			destroyInfo: &DestroyInfo{
+				Platform: "linux/amd64",
+				Commands: []DeployCommand{
+					{
+						Name:    "okteto deploy",
+						Command: "okteto deploy",
+					},
+				}},
+			expected: "commands:\n- name: okteto deploy\n  command: okteto deploy\nplatform: linux/amd64\n",
+		},
+		{
+			name: "context",
+			destroyInfo: &DestroyInfo{
+				Context: "services/api",
+				Commands: []DeployCommand{
+					{
+						Name:    "okteto deploy",
+						Command: "okteto deploy",
+					},
+				}},
+			expected: "commands:\n- name: okteto deploy\n  command: okteto deploy\ncontext: services/api\n",
+		},
 	}
 
 	for _, tt := range tests {
@@ -2156,6 +2193,36 @@ func TestDestroyInfoUnmarshalling(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "list of named steps",
+			input: []byte(`
+- name: destroy stack
+  run: okteto stack destroy
+- run: kubectl delete -f k8s.yml`),
+			expected: &DestroyInfo{
+				Commands: []DeployCommand{
+					{
+						Name:    "destroy stack",
+						Command: "okteto stack destroy",
+					},
+					{
+						Name:    "kubectl delete -f k8s.yml",
+						Command: "kubectl delete -f k8s.yml",
+					},
+				},
+			},
+		},
+		{
+			name: "step with command and run",
+			input: []byte(`
+- name: destroy stack
+  command: okteto stack destroy
+  run: okteto stack destroy`),
+			expected: &DestroyInfo{
+				Commands: []DeployCommand{},
+			},
+			isErrorExpected: true,
+		},
 		{
 			name: "commands",
 			input: []byte(`commands:
@@ -2169,6 +2236,55 @@ func TestDestroyInfoUnmarshalling(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "ca certs",
+			input: []byte(`caCerts:
+- certs/ca.pem
+- certs/bundle.pem
+commands:
+- okteto stack destroy`),
+			expected: &DestroyInfo{
+				CACerts: []string{"certs/ca.pem", "certs/bundle.pem"},
+				Commands: []DeployCommand{
+					{
+						Name:    "okteto stack destroy",
+						Command: "okteto stack destroy",
+					},
+				},
+			},
+		},
+		{
+			name: "platform",
+			input: []byte(`image: okteto/destroy:amd64
+platform: linux/amd64
+commands:
+- okteto stack destroy`),
+			expected: &DestroyInfo{
+				Image:    "okteto/destroy:amd64",
+				Platform: "linux/amd64",
+				Commands: []DeployCommand{
+					{
+						Name:    "okteto stack destroy",
+						Command: "okteto stack destroy",
+					},
+				},
+			},
+		},
+		{
+			name: "context",
+			input: []byte(`context: services/api
+commands:
+- okteto stack destroy`),
+			expected: &DestroyInfo{
+				Context: "services/api",
+				Commands: []DeployCommand{
+					{
+						Name:    "okteto stack destroy",
+						Command: "okteto stack destroy",
+					},
+				},
+			},
+		},
 		{
 			name: "compose with endpoints",
 			input: []byte(`compose:
@@ -2254,6 +2370,24 @@ func TestDeployInfoUnmarshalling(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "list of commands with retries",
+			deployInfoManifest: []byte(`
+- name: wait for api
+  command: curl -f https://api.example.com
+  retries: 3
+  retryInterval: 10s`),
+			expected: &DeployInfo{
+				Commands: []DeployCommand{
+					{
+						Name:          "wait for api",
+						Command:       "curl -f https://api.example.com",
+						Retries:       3,
+						RetryInterval: 10 * time.Second,
+					},
+				},
+			},
+		},
 		{
 			name: "commands",
 			deployInfoManifest: []byte(`commands:
@@ -2267,6 +2401,56 @@ func TestDeployInfoUnmarshalling(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "commands with default resources",
+			deployInfoManifest: []byte(`commands:
+- okteto stack deploy
+resources:
+  requests:
+    cpu: 100m
+  limits:
+    memory: 1Gi`),
+			expected: &DeployInfo{
+				Commands: []DeployCommand{
+					{
+						Name:    "okteto stack deploy",
+						Command: "okteto stack deploy",
+					},
+				},
+				Resources: &ResourceRequirements{
+					Requests: ResourceList{
+						v1.ResourceCPU: resource.MustParse("100m"),
+					},
+					Limits: ResourceList{
+						v1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+				},
+			},
+		},
+		{
+			name: "commands with wait conditions",
+			deployInfoManifest: []byte(`commands:
+- okteto stack deploy
+waitConditions:
+  - apiVersion: cert-manager.io/v1
+    kind: Certificate
+    condition: Ready`),
+			expected: &DeployInfo{
+				Commands: []DeployCommand{
+					{
+						Name:    "okteto stack deploy",
+						Command: "okteto stack deploy",
+					},
+				},
+				WaitConditions: []WaitCondition{
+					{
+						APIVersion: "cert-manager.io/v1",
+						Kind:       "Certificate",
+						Condition:  "Ready",
+					},
+				},
+			},
+		},
 		{
 			name: "compose with endpoints",
 			deployInfoManifest: []byte(`compose:
@@ -2848,6 +3032,15 @@ timeout: 15m`),
 				Timeout: 15 * time.Minute,
 			},
 		},
+		{
+			name: "repository and ref",
+			data: []byte(`repository: https://github/test
+ref: v1.2.0`),
+			expected: &Dependency{
+				Repository: "https://github/test",
+				Ref:        "v1.2.0",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -2862,3 +3055,17 @@ timeout: 15m`),
 		})
 	}
 }
+
+func TestDependencyUnmashallingBranchAndRef(t *testing.T) {
+	data := []byte(`repository: https://github/test
+branch: main
+ref: v1.2.0`)
+	var result *Dependency
+	assert.Error(t, yaml.UnmarshalStrict(data, &result))
+}
+
+func TestDependencyGetRef(t *testing.T) {
+	assert.Equal(t, "", (&Dependency{}).GetRef())
+	assert.Equal(t, "main", (&Dependency{Branch: "main"}).GetRef())
+	assert.Equal(t, "v1.2.0", (&Dependency{Ref: "v1.2.0"}).GetRef())
+}
@@ -54,6 +54,9 @@ type Stack struct {
 	Context   string                 `yaml:"context,omitempty"`
 	Services  ComposeServices        `yaml:"services,omitempty"`
 	Endpoints EndpointSpec           `yaml:"endpoints,omitempty"`
+
+	// DependsOnStrategy defines how the 'service_healthy' dependencies are enforced
+	DependsOnStrategy DependsOnStrategy `yaml:"x-okteto-depends-on-strategy,omitempty"`
 }
 
 // ComposeServices represents the services declared in the compose
@@ -216,6 +219,18 @@ const (
 	DependsOnServiceCompleted DependsOnCondition = "service_completed_successfully"
 )
 
+// DependsOnStrategy defines how the 'service_healthy' dependencies of a compose are enforced
+type DependsOnStrategy string
+
+const (
+	// DependsOnWaitStrategy applies each service once its dependencies are ready
+	DependsOnWaitStrategy DependsOnStrategy = "wait"
+
+	// DependsOnInitContainersStrategy applies every service at once and adds init containers
+	// that wait until the endpoints of their healthy dependencies are reachable
+	DependsOnInitContainersStrategy DependsOnStrategy = "init-containers"
+)
+
 // GetStackFromPath returns an okteto stack object from a given file
 func GetStackFromPath(name, stackPath string, isCompose bool) (*Stack, error) {
 	b, err := os.ReadFile(stackPath)
@@ -46,6 +46,8 @@ type StackRaw struct {
 	Endpoints EndpointSpec               `yaml:"endpoints,omitempty"`
 	Volumes   map[string]*VolumeTopLevel `yaml:"volumes,omitempty"`
 
+	DependsOnStrategy DependsOnStrategy `yaml:"x-okteto-depends-on-strategy,omitempty"`
+
 	// Extensions
 	Extensions map[string]interface{} `yaml:",inline" json:"-"`
 
@@ -302,6 +304,13 @@ func (s *Stack) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 	s.Endpoints = stackRaw.Endpoints
 
+	switch stackRaw.DependsOnStrategy {
+	case "", DependsOnWaitStrategy, DependsOnInitContainersStrategy:
+		s.DependsOnStrategy = stackRaw.DependsOnStrategy
+	default:
+		return fmt.Errorf("'x-okteto-depends-on-strategy' value '%s' is not supported. Must be one of '%s' or '%s'", stackRaw.DependsOnStrategy, DependsOnWaitStrategy, DependsOnInitContainersStrategy)
+	}
+
 	s.Volumes = make(map[string]*VolumeSpec)
 	for volumeName, volume := range stackRaw.Volumes {
 		volumeSpec, err := unmarshalVolume(volume)
@@ -20,6 +20,7 @@ import (
 
 	"github.com/okteto/okteto/pkg/constants"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 )
@@ -1095,6 +1096,58 @@ func Test_validateDependsOn(t *testing.T) {
 	}
 }
 
+func Test_validateDependsOnListsTheCycle(t *testing.T) {
+	manifest := []byte("services:\n  web:\n    image: okteto/vote:1\n    depends_on:\n      - api\n  api:\n    image: okteto/vote:1\n    depends_on:\n      - db\n  db:\n    image: okteto/vote:1\n    depends_on:\n      - cache\n  cache:\n    image: okteto/vote:1\n    depends_on:\n      - api")
+	s, err := ReadStack(manifest, false)
+	require.NoError(t, err)
+	s.Name = "test"
+
+	err = s.Validate()
+	require.ErrorIs(t, err, errDependsOn)
+	assert.Contains(t, err.Error(), "There was a cyclic dependendecy between api, db and cache.")
+}
+
+func TestReadStackDependsOnStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest []byte
+		expected DependsOnStrategy
+		expErr   bool
+	}{
+		{
+			name:     "default",
+			manifest: []byte("services:\n  app:\n    image: okteto/vote:1"),
+			expected: "",
+		},
+		{
+			name:     "wait",
+			manifest: []byte("x-okteto-depends-on-strategy: wait\nservices:\n  app:\n    image: okteto/vote:1"),
+			expected: DependsOnWaitStrategy,
+		},
+		{
+			name:     "init containers",
+			manifest: []byte("x-okteto-depends-on-strategy: init-containers\nservices:\n  app:\n    image: okteto/vote:1"),
+			expected: DependsOnInitContainersStrategy,
+		},
+		{
+			name:     "unsupported",
+			manifest: []byte("x-okteto-depends-on-strategy: parallel\nservices:\n  app:\n    image: okteto/vote:1"),
+			expErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ReadStack(tt.manifest, false)
+			if tt.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, s.DependsOnStrategy)
+		})
+	}
+}
+
 func Test_getStackName(t *testing.T) {
 	tests := []struct {
 		testName        string
@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/go-git/go-git/v5"
@@ -107,24 +108,15 @@ func GetRepositoryURL(path string) (string, error) {
 
 func getDependentCyclic(g graph) []string {
 	visited := make(map[string]bool)
-	stack := make(map[string]bool)
-	cycle := make([]string, 0)
 
 	// We need to iterate from all the nodes on the graph checking that there are no cycles in
-	// the graph.
-	for svcName := range g {
-		// dfs search for all the possible paths of a graph, starting from a node. If we find more
-		// than once one nodeit means that it has detected a cycle and returns the cycle
-		if dfs(g, svcName, visited, stack) {
-			for svc, isInStack := range stack {
-				if isInStack {
-					cycle = append(cycle, svc)
-				}
-			}
+	// the graph. Nodes are sorted so the reported cycle is always the same.
+	for _, svcName := range sortedNodes(g) {
+		if cycle := dfs(g, svcName, visited, []string{}); len(cycle) > 0 {
 			return cycle
 		}
 	}
-	return cycle
+	return []string{}
 }
 
 func getDependentNodes(g graph, startingNodes []string) []string {
@@ -148,25 +140,39 @@ func getDependentNodes(g graph, startingNodes []string) []string {
 	return startingNodes
 }
 
-// dfs executes deep first search algorithm.
+// dfs executes deep first search algorithm and returns the nodes of the first cycle found, in dependency order.
 // More information can be found at https://en.wikipedia.org/wiki/Depth-first_search
-func dfs(g graph, svcName string, visited, stack map[string]bool) bool {
-	isVisited := visited[svcName]
-	if !isVisited {
-		visited[svcName] = true
-		stack[svcName] = true
-
-		svc := g[svcName]
-		for _, dependentSvc := range svc {
-			if !visited[dependentSvc] && dfs(g, dependentSvc, visited, stack) {
-				return true
-			} else if value, ok := stack[dependentSvc]; ok && value {
-				return true
-			}
+func dfs(g graph, svcName string, visited map[string]bool, path []string) []string {
+	for idx, node := range path {
+		if node == svcName {
+			cycle := make([]string, len(path)-idx)
+			copy(cycle, path[idx:])
+			return cycle
+		}
+	}
+	if visited[svcName] {
+		return nil
+	}
+
+	path = append(path, svcName)
+	dependencies := append([]string{}, g[svcName]...)
+	sort.Strings(dependencies)
+	for _, dependentSvc := range dependencies {
+		if cycle := dfs(g, dependentSvc, visited, path); len(cycle) > 0 {
+			return cycle
 		}
 	}
-	stack[svcName] = false
-	return false
+	visited[svcName] = true
+	return nil
+}
+
+func sortedNodes(g graph) []string {
+	nodes := make([]string, 0, len(g))
+	for node := range g {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	return nodes
 }
 
 func pathExistsAndDir(path string) bool {
@@ -79,6 +79,7 @@ func TestGetCycles(t *testing.T) {
 	var tests = []struct {
 		name          string
 		g             graph
+		cycle         []string
 		expectedCycle bool
 	}{
 		{
@@ -117,12 +118,25 @@ func TestGetCycles(t *testing.T) {
 			},
 			expectedCycle: true,
 		},
+		{
+			name: "cycle - nodes outside the cycle are not listed",
+			g: graph{
+				"a": []string{"b"},
+				"b": []string{"c"},
+				"c": []string{"b"},
+			},
+			expectedCycle: true,
+			cycle:         []string{"b", "c"},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result := getDependentCyclic(tt.g)
 			assert.Equal(t, tt.expectedCycle, len(result) > 0)
+			if tt.cycle != nil {
+				assert.Equal(t, tt.cycle, result)
+			}
 		})
 	}
 
@@ -0,0 +1,114 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+)
+
+var variableNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ManifestVariables represents the variables declared at the manifest
+type ManifestVariables []ManifestVariable
+
+// ManifestVariable represents a variable declared at the manifest
+type ManifestVariable struct {
+	Name        string `json:"name" yaml:"name"`
+	Default     string `json:"default,omitempty" yaml:"default,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool   `json:"required,omitempty" yaml:"required,omitempty"`
+	Secret      bool   `json:"secret,omitempty" yaml:"secret,omitempty"`
+}
+
+func (vars ManifestVariables) validate() error {
+	var errs oktetoErrors.ValidationErrors
+	seen := map[string]bool{}
+	for _, v := range vars {
+		errs.Add(v.validate(seen))
+		seen[v.Name] = true
+	}
+	return errs.ErrorOrNil()
+}
+
+// validate returns the first problem of a variable, seen are the names of the variables declared before it
+func (v ManifestVariable) validate(seen map[string]bool) error {
+	if v.Name == "" {
+		return fmt.Errorf("variables: 'name' is mandatory")
+	}
+	if !variableNameRegex.MatchString(v.Name) {
+		return fmt.Errorf("variables: '%s' is not a valid variable name", v.Name)
+	}
+	if seen[v.Name] {
+		return fmt.Errorf("variables: '%s' is declared more than once", v.Name)
+	}
+	if v.Required && v.Default != "" {
+		return fmt.Errorf("variables: '%s' can't be required and have a default value", v.Name)
+	}
+	return nil
+}
+
+// Get returns the variable declared with the given name
+func (vars ManifestVariables) Get(name string) (ManifestVariable, bool) {
+	for _, v := range vars {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return ManifestVariable{}, false
+}
+
+// ApplyDefaults sets the default value of the declared variables that are not already set
+func (vars ManifestVariables) ApplyDefaults(lookupEnv func(string) (string, bool), setEnv func(string, string) error) error {
+	for _, v := range vars {
+		if v.Default == "" {
+			continue
+		}
+		if _, ok := lookupEnv(v.Name); ok {
+			continue
+		}
+		if err := setEnv(v.Name, v.Default); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckRequired returns an error listing the required variables that are not set
+func (vars ManifestVariables) CheckRequired(lookupEnv func(string) (string, bool)) error {
+	var missing []string
+	for _, v := range vars {
+		if !v.Required {
+			continue
+		}
+		if value, ok := lookupEnv(v.Name); ok && value != "" {
+			continue
+		}
+		missing = append(missing, v.describe())
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("the following required variables are not set:\n    - %s", strings.Join(missing, "\n    - "))
+}
+
+func (v ManifestVariable) describe() string {
+	if v.Description == "" {
+		return fmt.Sprintf("'%s'", v.Name)
+	}
+	return fmt.Sprintf("'%s' (%s)", v.Name, v.Description)
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadManifestVariables(t *testing.T) {
+	manifest := []byte(`
+deploy:
+  - helm upgrade --install api chart --set replicas=${API_REPLICAS}
+variables:
+  - name: API_REPLICAS
+    default: "2"
+    description: number of replicas of the api
+  - name: DB_PASSWORD
+    description: password of the database
+    required: true
+    secret: true
+`)
+	m, err := Read(manifest)
+	require.NoError(t, err)
+	expected := ManifestVariables{
+		{
+			Name:        "API_REPLICAS",
+			Default:     "2",
+			Description: "number of replicas of the api",
+		},
+		{
+			Name:        "DB_PASSWORD",
+			Description: "password of the database",
+			Required:    true,
+			Secret:      true,
+		},
+	}
+	assert.Equal(t, expected, m.Variables)
+}
+
+func TestManifestVariablesValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		vars    ManifestVariables
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			vars: ManifestVariables{{Name: "A"}, {Name: "B_2", Default: "b"}, {Name: "C", Required: true}},
+		},
+		{
+			name:    "empty name",
+			vars:    ManifestVariables{{Default: "a"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid name",
+			vars:    ManifestVariables{{Name: "1-A"}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicated",
+			vars:    ManifestVariables{{Name: "A"}, {Name: "A"}},
+			wantErr: true,
+		},
+		{
+			name:    "required with default",
+			vars:    ManifestVariables{{Name: "A", Default: "a", Required: true}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.vars.validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestManifestVariablesApplyDefaults(t *testing.T) {
+	env := map[string]string{"SET": "value"}
+	lookupEnv := func(k string) (string, bool) {
+		v, ok := env[k]
+		return v, ok
+	}
+	setEnv := func(k, v string) error {
+		env[k] = v
+		return nil
+	}
+	vars := ManifestVariables{
+		{Name: "SET", Default: "default"},
+		{Name: "UNSET", Default: "default"},
+		{Name: "NO_DEFAULT"},
+	}
+	require.NoError(t, vars.ApplyDefaults(lookupEnv, setEnv))
+	assert.Equal(t, map[string]string{"SET": "value", "UNSET": "default"}, env)
+}
+
+func TestManifestVariablesCheckRequired(t *testing.T) {
+	vars := ManifestVariables{
+		{Name: "SET", Required: true},
+		{Name: "EMPTY", Required: true},
+		{Name: "UNSET", Required: true, Description: "an unset variable"},
+		{Name: "OPTIONAL"},
+	}
+
+	err := vars.CheckRequired(func(k string) (string, bool) {
+		switch k {
+		case "SET":
+			return "value", true
+		case "EMPTY":
+			return "", true
+		}
+		return "", false
+	})
+	require.Error(t, err)
+	assert.Equal(t, "the following required variables are not set:\n    - 'EMPTY'\n    - 'UNSET' (an unset variable)", err.Error())
+
+	err = vars.CheckRequired(func(k string) (string, bool) { return "value", true })
+	assert.NoError(t, err)
+}
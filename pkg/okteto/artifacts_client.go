@@ -0,0 +1,109 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package okteto
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+)
+
+const artifactsPath = "api/artifacts"
+
+// ArtifactsClient uploads and downloads the artifacts of the development environments of a namespace
+type ArtifactsClient struct {
+	httpClient  *http.Client
+	url         string
+	contextName string
+}
+
+// NewArtifactsClient returns a client for the artifacts of the development environments of namespace
+func NewArtifactsClient(contextName, token, namespace string) (*ArtifactsClient, error) {
+	if contextName == "" {
+		return nil, oktetoErrors.ErrCtxNotSet
+	}
+
+	httpClient, url, err := newOktetoHttpClient(contextName, token, fmt.Sprintf("%s/%s", artifactsPath, namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ArtifactsClient{
+		httpClient:  httpClient,
+		url:         url,
+		contextName: contextName,
+	}, nil
+}
+
+// Upload stores the compressed artifacts of a development environment
+func (c *ArtifactsClient) Upload(name string, content []byte) error {
+	req, err := http.NewRequest(http.MethodPut, c.getURL(name), bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/gzip")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed PUT request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf(oktetoErrors.ErrNotLogged, c.contextName)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("PUT request returned status %s", resp.Status)
+	}
+	return nil
+}
+
+// Download returns the compressed artifacts of a development environment
+func (c *ArtifactsClient) Download(name string) ([]byte, error) {
+	resp, err := c.httpClient.Get(c.getURL(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed GET request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf(oktetoErrors.ErrNotLogged, c.contextName)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, oktetoErrors.UserError{
+			E:    fmt.Errorf("no artifacts found for development environment '%s'", name),
+			Hint: "Deploy the development environment with the '--upload-artifacts' flag",
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET request returned status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifacts response: %w", err)
+	}
+	return body, nil
+}
+
+func (c *ArtifactsClient) getURL(name string) string {
+	return fmt.Sprintf("%s/%s", c.url, url.PathEscape(name))
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package okteto
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewArtifactsClient(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewArtifactsClient("", "mytoken", "testns")
+	require.Equal(t, oktetoErrors.ErrCtxNotSet, err)
+}
+
+func TestArtifactsUploadAndDownload(t *testing.T) {
+	t.Parallel()
+
+	stored := map[string][]byte{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			b, _ := io.ReadAll(r.Body)
+			stored[r.URL.Path] = b
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			b, ok := stored[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write(b)
+		}
+	}))
+	defer s.Close()
+
+	c := &ArtifactsClient{
+		httpClient: s.Client(),
+		url:        s.URL,
+	}
+
+	require.NoError(t, c.Upload("movies app", []byte("bundle")))
+	content, err := c.Download("movies app")
+	require.NoError(t, err)
+	require.Equal(t, []byte("bundle"), content)
+
+	_, err = c.Download("other")
+	var userErr oktetoErrors.UserError
+	require.True(t, errors.As(err, &userErr))
+}
+
+func TestArtifactsUploadUnauthorizedErr(t *testing.T) {
+	t.Parallel()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer s.Close()
+
+	c := &ArtifactsClient{
+		httpClient:  s.Client(),
+		url:         s.URL,
+		contextName: "testctx",
+	}
+
+	err := c.Upload("movies", []byte("bundle"))
+	require.Equal(t, fmt.Errorf(oktetoErrors.ErrNotLogged, "testctx"), err)
+}
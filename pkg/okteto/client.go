@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -346,6 +347,22 @@ func SetInsecureSkipTLSVerifyPolicy(isInsecure bool) {
 	insecureSkipTLSVerify = isInsecure
 }
 
+// SetInsecureSkipTLSVerifyPolicyFromEnv enables the insecure mode when OKTETO_TLS_VERIFY is false
+func SetInsecureSkipTLSVerifyPolicyFromEnv(lookupEnv func(string) (string, bool)) {
+	value, ok := lookupEnv(constants.OktetoTLSVerifyEnvVar)
+	if !ok || value == "" {
+		return
+	}
+	verify, err := strconv.ParseBool(value)
+	if err != nil {
+		oktetoLog.Infof("invalid value '%s' for %s: %s", value, constants.OktetoTLSVerifyEnvVar, err)
+		return
+	}
+	if !verify {
+		SetInsecureSkipTLSVerifyPolicy(true)
+	}
+}
+
 func IsInsecureSkipTLSVerifyPolicy() bool {
 	return insecureSkipTLSVerify
 }
@@ -227,3 +227,42 @@ func TestBackgroundContextWithHttpClient(t *testing.T) {
 		})
 	}
 }
+
+func TestSetInsecureSkipTLSVerifyPolicyFromEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected bool
+	}{
+		{
+			name: "unset",
+			env:  map[string]string{},
+		},
+		{
+			name: "verify",
+			env:  map[string]string{constants.OktetoTLSVerifyEnvVar: "true"},
+		},
+		{
+			name: "invalid value",
+			env:  map[string]string{constants.OktetoTLSVerifyEnvVar: "nope"},
+		},
+		{
+			name:     "skip verify",
+			env:      map[string]string{constants.OktetoTLSVerifyEnvVar: "false"},
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer SetInsecureSkipTLSVerifyPolicy(false)
+			SetInsecureSkipTLSVerifyPolicy(false)
+			SetInsecureSkipTLSVerifyPolicyFromEnv(func(k string) (string, bool) {
+				v, ok := tt.env[k]
+				return v, ok
+			})
+			if got := IsInsecureSkipTLSVerifyPolicy(); got != tt.expected {
+				t.Errorf("expected insecure mode %t, got %t", tt.expected, got)
+			}
+		})
+	}
+}
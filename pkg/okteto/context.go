@@ -57,6 +57,10 @@ const (
 var (
 	CurrentStore *OktetoContextStore
 	reg          = regexp.MustCompile("[^A-Za-z0-9]+")
+
+	// loadedStore is the content of the context store when it was read from disk, to apply
+	// on write only the changes of this command over the ones done meanwhile by other okteto commands
+	loadedStore []byte
 )
 
 // OktetoContext contains the information related to an okteto context
@@ -230,15 +234,13 @@ func ContextStore() *OktetoContextStore {
 			oktetoLog.Fatalf(oktetoErrors.ErrCorruptedOktetoContexts, config.GetOktetoContextFolder())
 		}
 
-		dec := json.NewDecoder(bytes.NewReader(b))
-		dec.DisallowUnknownFields() // Force errors
-
-		ctxStore := &OktetoContextStore{}
-		if err := dec.Decode(&ctxStore); err != nil {
+		ctxStore, err := decodeContextStore(b)
+		if err != nil {
 			oktetoLog.Errorf("error decoding okteto contexts: %v", err)
 			oktetoLog.Fatalf(oktetoErrors.ErrCorruptedOktetoContexts, config.GetOktetoContextFolder())
 		}
 		CurrentStore = ctxStore
+		loadedStore = b
 
 		return CurrentStore
 	}
@@ -246,9 +248,24 @@ func ContextStore() *OktetoContextStore {
 	CurrentStore = &OktetoContextStore{
 		Contexts: map[string]*OktetoContext{},
 	}
+	loadedStore = nil
 	return CurrentStore
 }
 
+func decodeContextStore(b []byte) (*OktetoContextStore, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields() // Force errors
+
+	ctxStore := &OktetoContextStore{}
+	if err := dec.Decode(&ctxStore); err != nil {
+		return nil, err
+	}
+	if ctxStore.Contexts == nil {
+		ctxStore.Contexts = map[string]*OktetoContext{}
+	}
+	return ctxStore, nil
+}
+
 func Context() *OktetoContext {
 	c := ContextStore()
 	if c.CurrentContext == "" {
@@ -311,12 +328,10 @@ func NewContextConfigWriter() *ContextConfigWriter {
 	return &ContextConfigWriter{}
 }
 
+// Write saves the context store holding the okteto home lock from reading the store on disk until it is written,
+// so the contexts saved by other okteto commands since this one loaded the store are not lost
 func (*ContextConfigWriter) Write() error {
-	marshalled, err := json.MarshalIndent(ContextStore(), "", "\t")
-	if err != nil {
-		oktetoLog.Infof("failed to marshal context: %s", err)
-		return fmt.Errorf("failed to generate your context")
-	}
+	ctxStore := ContextStore()
 
 	contextFolder := config.GetOktetoContextFolder()
 	if err := os.MkdirAll(contextFolder, 0700); err != nil {
@@ -331,13 +346,111 @@ func (*ContextConfigWriter) Write() error {
 		}
 	}
 
-	if err := os.WriteFile(contextConfigPath, marshalled, 0600); err != nil {
-		return fmt.Errorf("couldn't save context: %s", err)
+	var errMarshal error
+	err := config.WithOktetoHomeLock(func() error {
+		merged := mergeContextStores(readContextStore(loadedStore), ctxStore, readContextStoreFile(contextConfigPath))
+
+		marshalled, err := json.MarshalIndent(merged, "", "\t")
+		if err != nil {
+			errMarshal = err
+			return err
+		}
+		if err := filesystem.WriteFileAtomic(contextConfigPath, marshalled, 0600); err != nil {
+			return err
+		}
+
+		// the contexts added by other commands are made visible to this one
+		for name, octx := range merged.Contexts {
+			if _, ok := ctxStore.Contexts[name]; !ok {
+				ctxStore.Contexts[name] = octx
+			}
+		}
+		snapshot, err := json.Marshal(ctxStore)
+		if err != nil {
+			errMarshal = err
+			return err
+		}
+		loadedStore = snapshot
+		return nil
+	})
+	if errMarshal != nil {
+		oktetoLog.Infof("failed to marshal context: %s", errMarshal)
+		return fmt.Errorf("failed to generate your context")
+	}
+	if err != nil {
+		return fmt.Errorf("couldn't save context: %w", err)
 	}
 
 	return nil
 }
 
+// readContextStoreFile returns the context store saved in path, or an empty store if it can't be read
+func readContextStoreFile(path string) *OktetoContextStore {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			oktetoLog.Infof("failed to read okteto contexts: %s", err)
+		}
+		return readContextStore(nil)
+	}
+	return readContextStore(b)
+}
+
+// readContextStore decodes b, returning an empty store if b is empty or can't be decoded
+func readContextStore(b []byte) *OktetoContextStore {
+	if len(b) > 0 {
+		ctxStore, err := decodeContextStore(b)
+		if err == nil {
+			return ctxStore
+		}
+		oktetoLog.Infof("failed to decode okteto contexts: %s", err)
+	}
+	return &OktetoContextStore{
+		Contexts: map[string]*OktetoContext{},
+	}
+}
+
+// mergeContextStores applies over the store on disk the changes done by this command to current
+// since it was loaded as base: added, updated and removed contexts and the current context.
+// The contexts this command didn't change keep the value written by other commands
+func mergeContextStores(base, current, disk *OktetoContextStore) *OktetoContextStore {
+	merged := &OktetoContextStore{
+		CurrentContext: disk.CurrentContext,
+		Contexts:       map[string]*OktetoContext{},
+	}
+	for name, octx := range disk.Contexts {
+		merged.Contexts[name] = octx
+	}
+
+	for name, octx := range current.Contexts {
+		if !isSameContext(base.Contexts[name], octx) {
+			merged.Contexts[name] = octx
+		}
+	}
+	for name := range base.Contexts {
+		if _, ok := current.Contexts[name]; !ok {
+			delete(merged.Contexts, name)
+		}
+	}
+
+	if current.CurrentContext != base.CurrentContext {
+		merged.CurrentContext = current.CurrentContext
+	}
+	return merged
+}
+
+func isSameContext(a, b *OktetoContext) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	aBytes, errA := json.Marshal(a)
+	bBytes, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return bytes.Equal(aBytes, bBytes)
+}
+
 func AddOktetoCredentialsToCfg(cfg *clientcmdapi.Config, cred *types.Credential, namespace, userName, oktetoURL string) {
 	// If the context is being initialized within the execution of `okteto deploy` deploy command it should not
 	// write the Okteto credentials into the kubeconfig. It would overwrite the proxy settings
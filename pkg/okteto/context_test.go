@@ -15,9 +15,15 @@ package okteto
 
 import (
 	"context"
+	"encoding/json"
+	"os"
 	"testing"
 
 	"github.com/okteto/okteto/internal/test"
+	"github.com/okteto/okteto/pkg/config"
+	"github.com/okteto/okteto/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_UrlToKubernetesContext(t *testing.T) {
@@ -122,3 +128,161 @@ func Test_RemoveSchema(t *testing.T) {
 		})
 	}
 }
+
+func Test_mergeContextStores(t *testing.T) {
+	var tests = []struct {
+		name     string
+		base     *OktetoContextStore
+		current  *OktetoContextStore
+		disk     *OktetoContextStore
+		expected *OktetoContextStore
+	}{
+		{
+			name: "keeps the contexts added by other commands",
+			base: &OktetoContextStore{
+				CurrentContext: "a",
+				Contexts:       map[string]*OktetoContext{"a": {Name: "a"}},
+			},
+			current: &OktetoContextStore{
+				CurrentContext: "a",
+				Contexts:       map[string]*OktetoContext{"a": {Name: "a"}, "c": {Name: "c"}},
+			},
+			disk: &OktetoContextStore{
+				CurrentContext: "a",
+				Contexts:       map[string]*OktetoContext{"a": {Name: "a"}, "b": {Name: "b"}},
+			},
+			expected: &OktetoContextStore{
+				CurrentContext: "a",
+				Contexts:       map[string]*OktetoContext{"a": {Name: "a"}, "b": {Name: "b"}, "c": {Name: "c"}},
+			},
+		},
+		{
+			name: "keeps the updates of other commands over unchanged contexts",
+			base: &OktetoContextStore{
+				CurrentContext: "a",
+				Contexts:       map[string]*OktetoContext{"a": {Name: "a", Token: "old"}, "b": {Name: "b"}},
+			},
+			current: &OktetoContextStore{
+				CurrentContext: "a",
+				Contexts:       map[string]*OktetoContext{"a": {Name: "a", Token: "old"}, "b": {Name: "b", Namespace: "ns"}},
+			},
+			disk: &OktetoContextStore{
+				CurrentContext: "a",
+				Contexts:       map[string]*OktetoContext{"a": {Name: "a", Token: "new"}, "b": {Name: "b"}},
+			},
+			expected: &OktetoContextStore{
+				CurrentContext: "a",
+				Contexts:       map[string]*OktetoContext{"a": {Name: "a", Token: "new"}, "b": {Name: "b", Namespace: "ns"}},
+			},
+		},
+		{
+			name: "removes the contexts deleted by this command",
+			base: &OktetoContextStore{
+				CurrentContext: "a",
+				Contexts:       map[string]*OktetoContext{"a": {Name: "a"}, "b": {Name: "b"}},
+			},
+			current: &OktetoContextStore{
+				CurrentContext: "a",
+				Contexts:       map[string]*OktetoContext{"a": {Name: "a"}},
+			},
+			disk: &OktetoContextStore{
+				CurrentContext: "a",
+				Contexts:       map[string]*OktetoContext{"a": {Name: "a"}, "b": {Name: "b"}, "c": {Name: "c"}},
+			},
+			expected: &OktetoContextStore{
+				CurrentContext: "a",
+				Contexts:       map[string]*OktetoContext{"a": {Name: "a"}, "c": {Name: "c"}},
+			},
+		},
+		{
+			name: "current context only changes if this command changed it",
+			base: &OktetoContextStore{
+				CurrentContext: "a",
+				Contexts:       map[string]*OktetoContext{"a": {Name: "a"}, "b": {Name: "b"}},
+			},
+			current: &OktetoContextStore{
+				CurrentContext: "a",
+				Contexts:       map[string]*OktetoContext{"a": {Name: "a"}, "b": {Name: "b"}},
+			},
+			disk: &OktetoContextStore{
+				CurrentContext: "b",
+				Contexts:       map[string]*OktetoContext{"a": {Name: "a"}, "b": {Name: "b"}},
+			},
+			expected: &OktetoContextStore{
+				CurrentContext: "b",
+				Contexts:       map[string]*OktetoContext{"a": {Name: "a"}, "b": {Name: "b"}},
+			},
+		},
+		{
+			name: "current context changed by this command",
+			base: &OktetoContextStore{
+				CurrentContext: "a",
+				Contexts:       map[string]*OktetoContext{"a": {Name: "a"}, "b": {Name: "b"}},
+			},
+			current: &OktetoContextStore{
+				CurrentContext: "b",
+				Contexts:       map[string]*OktetoContext{"a": {Name: "a"}, "b": {Name: "b"}},
+			},
+			disk: &OktetoContextStore{
+				CurrentContext: "a",
+				Contexts:       map[string]*OktetoContext{"a": {Name: "a"}, "b": {Name: "b"}},
+			},
+			expected: &OktetoContextStore{
+				CurrentContext: "b",
+				Contexts:       map[string]*OktetoContext{"a": {Name: "a"}, "b": {Name: "b"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, mergeContextStores(tt.base, tt.current, tt.disk))
+		})
+	}
+}
+
+func TestContextConfigWriterKeepsConcurrentChanges(t *testing.T) {
+	t.Setenv(constants.OktetoFolderEnvVar, t.TempDir())
+	previousStore, previousLoadedStore := CurrentStore, loadedStore
+	CurrentStore = nil
+	t.Cleanup(func() {
+		CurrentStore = previousStore
+		loadedStore = previousLoadedStore
+	})
+
+	writeStore := func(store *OktetoContextStore) {
+		b, err := json.Marshal(store)
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(config.GetOktetoContextFolder(), 0700))
+		require.NoError(t, os.WriteFile(config.GetOktetoContextsStorePath(), b, 0600))
+	}
+
+	writeStore(&OktetoContextStore{
+		CurrentContext: "a",
+		Contexts:       map[string]*OktetoContext{"a": {Name: "a"}},
+	})
+	ctxStore := ContextStore()
+
+	// another okteto command saves a context after this one loaded the store
+	writeStore(&OktetoContextStore{
+		CurrentContext: "a",
+		Contexts:       map[string]*OktetoContext{"a": {Name: "a"}, "b": {Name: "b"}},
+	})
+
+	AddKubernetesContext("c", "ns", "")
+	require.NoError(t, NewContextConfigWriter().Write())
+
+	CurrentStore = nil
+	saved := ContextStore()
+	assert.Equal(t, "c", saved.CurrentContext)
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, contextNames(saved))
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, contextNames(ctxStore))
+}
+
+func contextNames(store *OktetoContextStore) []string {
+	names := []string{}
+	for name := range store.Contexts {
+		names = append(names, name)
+	}
+	return names
+}
@@ -103,6 +103,10 @@ type metadataQueryItem struct {
 	Value graphql.String
 }
 
+type tokenRoleQuery struct {
+	TokenRole graphql.String `graphql:"tokenRole(namespace: $namespace)"`
+}
+
 type contextFileJSON struct {
 	Contexts map[string]struct {
 		Certificate string `yaml:"certificate"`
@@ -304,10 +308,24 @@ func (c *userClient) GetClusterMetadata(ctx context.Context, ns string) (types.C
 			metadata.PipelineInstallerImage = string(v.Value)
 		case "pipelineRunnerImage":
 			metadata.PipelineRunnerImage = string(v.Value)
+		case "uploadDeployArtifacts":
+			metadata.UploadArtifacts = string(v.Value) == "true"
 		}
 	}
-	if metadata.PipelineInstallerImage == "" || metadata.PipelineRunnerImage == "" {
-		return metadata, fmt.Errorf("missing metadata")
-	}
+	// older instances don't define every image, the remote commands fall back to the default ones
 	return metadata, nil
 }
+
+// GetTokenRole returns the role of the okteto token in the namespace.
+// Roles unknown to this version of the CLI are returned as reported by the server
+func (c *userClient) GetTokenRole(ctx context.Context, ns string) (types.TokenRole, error) {
+	var queryStruct tokenRoleQuery
+	vars := map[string]interface{}{
+		"namespace": graphql.String(ns),
+	}
+
+	if err := query(ctx, &queryStruct, vars, c.client); err != nil {
+		return "", err
+	}
+	return types.TokenRole(queryStruct.TokenRole), nil
+}
@@ -453,6 +453,10 @@ func TestGetClusterMetadata(t *testing.T) {
 								Name:  "pipelineRunnerImage",
 								Value: "installer-runner-image",
 							},
+							{
+								Name:  "uploadDeployArtifacts",
+								Value: "true",
+							},
 						},
 					},
 				},
@@ -463,11 +467,37 @@ func TestGetClusterMetadata(t *testing.T) {
 					ServerName:             "1.1.1.1",
 					PipelineInstallerImage: "installer-image",
 					PipelineRunnerImage:    "installer-runner-image",
+					UploadArtifacts:        true,
 				},
 			},
 		},
 		{
-			name: "pipelineInstallerImage and pipelineRunnerImage cant be empty",
+			name: "missing pipelineRunnerImage returns the partial metadata",
+			cfg: input{
+				client: &fakeGraphQLClient{
+					queryResult: &metadataQuery{
+						Metadata: []metadataQueryItem{
+							{
+								Name:  "internalIngressControllerNetworkAddress",
+								Value: "1.1.1.1",
+							},
+							{
+								Name:  "pipelineInstallerImage",
+								Value: "installer-image",
+							},
+						},
+					},
+				},
+			},
+			expected: expected{
+				metadata: types.ClusterMetadata{
+					ServerName:             "1.1.1.1",
+					PipelineInstallerImage: "installer-image",
+				},
+			},
+		},
+		{
+			name: "missing pipeline images returns the partial metadata",
 			cfg: input{
 				client: &fakeGraphQLClient{
 					queryResult: &metadataQuery{
@@ -489,7 +519,6 @@ func TestGetClusterMetadata(t *testing.T) {
 					Certificate: []byte("cert"),
 					ServerName:  "1.1.1.1",
 				},
-				expectErr: true,
 			},
 		},
 	}
@@ -514,6 +543,66 @@ func TestGetClusterMetadata(t *testing.T) {
 
 }
 
+func TestGetTokenRole(t *testing.T) {
+	testCases := []struct {
+		name         string
+		client       *fakeGraphQLClient
+		expectedRole types.TokenRole
+		expectErr    bool
+	}{
+		{
+			name: "owner",
+			client: &fakeGraphQLClient{
+				queryResult: &tokenRoleQuery{TokenRole: "owner"},
+			},
+			expectedRole: types.TokenRoleOwner,
+		},
+		{
+			name: "developer",
+			client: &fakeGraphQLClient{
+				queryResult: &tokenRoleQuery{TokenRole: "developer"},
+			},
+			expectedRole: types.TokenRoleDeveloper,
+		},
+		{
+			name: "viewer",
+			client: &fakeGraphQLClient{
+				queryResult: &tokenRoleQuery{TokenRole: "viewer"},
+			},
+			expectedRole: types.TokenRoleViewer,
+		},
+		{
+			name: "unknown role",
+			client: &fakeGraphQLClient{
+				queryResult: &tokenRoleQuery{TokenRole: "admin"},
+			},
+			expectedRole: types.TokenRole("admin"),
+		},
+		{
+			name: "errors are returned",
+			client: &fakeGraphQLClient{
+				err: assert.AnError,
+			},
+			expectErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			uc := &userClient{
+				client: tc.client,
+			}
+			role, err := uc.GetTokenRole(context.Background(), "test")
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedRole, role)
+		})
+	}
+}
+
 func TestGetClusterCertificate(t *testing.T) {
 	ctx := context.Background()
 
@@ -68,7 +68,7 @@ func handlerPipelineLogLine(line string) bool {
 			oktetoLog.Infof("error unmarshalling pipelineLog: %v", err)
 		}
 		// stop when the event log is in stage done and message is EOF
-		if pLog.Stage == "done" && pLog.Message == "EOF" {
+		if pLog.Stage == oktetoLog.DoneStage && pLog.Message == oktetoLog.EOFMessage {
 			return true
 		}
 		oktetoLog.Println(pLog.Message)
@@ -76,7 +76,7 @@ func handlerPipelineLogLine(line string) bool {
 	}
 	for _, pLog := range pipelineLogList {
 		// stop when the event log is in stage done and message is EOF
-		if pLog.Stage == "done" && pLog.Message == "EOF" {
+		if pLog.Stage == oktetoLog.DoneStage && pLog.Message == oktetoLog.EOFMessage {
 			return true
 		}
 		oktetoLog.Println(pLog.Message)
@@ -18,12 +18,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/types"
 	oktetoErrors "github.com/okteto/okteto/pkg/errors"
 	oktetoHttp "github.com/okteto/okteto/pkg/http"
 	oktetoLog "github.com/okteto/okteto/pkg/log"
@@ -32,7 +34,9 @@ import (
 type clientInterface interface {
 	GetDigest(image string) (string, error)
 	GetImageConfig(image string) (*v1.ConfigFile, error)
+	GetImagePlatforms(image string) ([]string, error)
 	HasPushAccess(image string) (bool, error)
+	ImageExists(image string) (bool, error)
 }
 
 type ClientConfigInterface interface {
@@ -67,12 +71,17 @@ func (oh oktetoHelper) Get(_ string) (string, string, error) {
 type client struct {
 	config ClientConfigInterface
 	get    func(ref name.Reference, options ...remote.Option) (*remote.Descriptor, error)
+	head   func(ref name.Reference, options ...remote.Option) (*v1.Descriptor, error)
+	// digests caches the digests of the image tags, nil disables it
+	digests *digestCache
 }
 
 func newOktetoRegistryClient(config ClientConfigInterface) client {
 	return client{
-		config: config,
-		get:    remote.Get,
+		config:  config,
+		get:     remote.Get,
+		head:    remote.Head,
+		digests: defaultDigestCache,
 	}
 }
 
@@ -96,11 +105,19 @@ func (c client) getDescriptor(image string) (*remote.Descriptor, error) {
 
 // GetDigest returns the digest of an image
 func (c client) GetDigest(image string) (string, error) {
+	if digest, ok := c.digests.get(image); ok {
+		oktetoLog.Debugf("digest of '%s' found in the local cache", image)
+		return digest, nil
+	}
+	start := time.Now()
 	descriptor, err := c.getDescriptor(image)
 	if err != nil {
 		return "", fmt.Errorf("error getting image digest: %w", err)
 	}
-	return descriptor.Digest.String(), nil
+	oktetoLog.Debugf("digest of '%s' resolved in %s", image, time.Since(start))
+	digest := descriptor.Digest.String()
+	c.digests.set(image, digest)
+	return digest, nil
 }
 
 // GetImageConfig returns the config of an image
@@ -121,6 +138,50 @@ func (c client) GetImageConfig(image string) (*v1.ConfigFile, error) {
 	return cfg, nil
 }
 
+// GetImagePlatforms returns the platforms (os/arch[/variant]) an image is available for
+func (c client) GetImagePlatforms(image string) ([]string, error) {
+	descriptor, err := c.getDescriptor(image)
+	if err != nil {
+		return nil, fmt.Errorf("error getting image platforms: %w", err)
+	}
+
+	if descriptor.MediaType == types.OCIImageIndex || descriptor.MediaType == types.DockerManifestList {
+		index, err := descriptor.ImageIndex()
+		if err != nil {
+			return nil, fmt.Errorf("error getting image platforms: %w", err)
+		}
+		manifest, err := index.IndexManifest()
+		if err != nil {
+			return nil, fmt.Errorf("error getting image platforms: %w", err)
+		}
+		platforms := []string{}
+		for _, m := range manifest.Manifests {
+			if m.Platform == nil {
+				continue
+			}
+			platforms = append(platforms, formatPlatform(m.Platform.OS, m.Platform.Architecture, m.Platform.Variant))
+		}
+		return platforms, nil
+	}
+
+	img, err := descriptor.Image()
+	if err != nil {
+		return nil, fmt.Errorf("error getting image platforms: %w", err)
+	}
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return nil, fmt.Errorf("error getting image platforms: %w", err)
+	}
+	return []string{formatPlatform(cfg.OS, cfg.Architecture, "")}, nil
+}
+
+func formatPlatform(os, arch, variant string) string {
+	if variant == "" {
+		return fmt.Sprintf("%s/%s", os, arch)
+	}
+	return fmt.Sprintf("%s/%s/%s", os, arch, variant)
+}
+
 func (c client) HasPushAccess(image string) (bool, error) {
 	ref, err := name.ParseReference(image)
 	if err != nil {
@@ -130,9 +191,28 @@ func (c client) HasPushAccess(image string) (bool, error) {
 	return err == nil, err
 }
 
+// ImageExists returns if the image exists in the registry. It only requests the headers of the image manifest
+func (c client) ImageExists(image string) (bool, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return false, fmt.Errorf("error checking if the image exists: %w", err)
+	}
+	if _, err := c.head(ref, c.getOptions(ref)...); err != nil {
+		if c.isNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error checking if the image exists: %w", err)
+	}
+	return true, nil
+}
+
 func (c client) isNotFound(err error) bool {
 	var transportErr *transport.Error
 	if errors.As(err, &transportErr) {
+		// the responses to HEAD requests don't have a body with the error codes
+		if transportErr.StatusCode == http.StatusNotFound {
+			return true
+		}
 		for _, err := range transportErr.Errors {
 			if err.Code == transport.ManifestUnknownErrorCode {
 				return true
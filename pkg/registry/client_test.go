@@ -15,12 +15,14 @@ package registry
 
 import (
 	"crypto/x509"
+	"net/http"
 	"testing"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	containerv1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/types"
 	oktetoErrors "github.com/okteto/okteto/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
@@ -30,6 +32,7 @@ type fakeClient struct {
 	GetImageDigest getDigest
 	GetConfig      getConfig
 	HasPushAcces   hasPushAccess
+	ImageExist     imageExists
 }
 
 // GetDigest has everything needed to mock a getDigest API call
@@ -44,6 +47,11 @@ type getConfig struct {
 	Err    error
 }
 
+type imageExists struct {
+	Result bool
+	Err    error
+}
+
 type hasPushAccess struct {
 	Result bool
 	Err    error
@@ -61,6 +69,14 @@ func (fc fakeClient) HasPushAccess(_ string) (bool, error) {
 	return fc.HasPushAcces.Result, fc.HasPushAcces.Err
 }
 
+func (fc fakeClient) ImageExists(_ string) (bool, error) {
+	return fc.ImageExist.Result, fc.ImageExist.Err
+}
+
+func (fakeClient) GetImagePlatforms(_ string) ([]string, error) {
+	return nil, nil
+}
+
 type fakeClientConfig struct {
 	registryURL string
 	userID      string
@@ -183,6 +199,91 @@ func TestGetDigest(t *testing.T) {
 	}
 }
 
+func TestImageExists(t *testing.T) {
+	unauthorizedErr := &transport.Error{
+		StatusCode: http.StatusUnauthorized,
+		Errors: []transport.Diagnostic{
+			{
+				Code: transport.UnauthorizedErrorCode,
+			},
+		},
+	}
+	var tests = []struct {
+		name     string
+		headErr  error
+		expected bool
+		err      error
+	}{
+		{
+			name:     "exists",
+			expected: true,
+		},
+		{
+			name:    "not found",
+			headErr: &transport.Error{StatusCode: http.StatusNotFound},
+		},
+		{
+			name:    "unauthorized",
+			headErr: unauthorizedErr,
+			err:     unauthorizedErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested string
+			c := client{
+				config: fakeClientConfig{
+					cert: &x509.Certificate{},
+				},
+				head: func(ref name.Reference, _ ...remote.Option) (*containerv1.Descriptor, error) {
+					requested = ref.String()
+					return &containerv1.Descriptor{}, tt.headErr
+				},
+			}
+			exists, err := c.ImageExists("okteto/test:latest")
+			assert.Equal(t, tt.expected, exists)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, "okteto/test:latest", requested)
+		})
+	}
+
+	_, err := client{}.ImageExists("Invalid:Image:Reference")
+	assert.Error(t, err)
+}
+
+func TestGetImagePlatforms(t *testing.T) {
+	index := `{
+  "schemaVersion": 2,
+  "mediaType": "application/vnd.docker.distribution.manifest.list.v2+json",
+  "manifests": [
+    {"mediaType": "application/vnd.docker.distribution.manifest.v2+json", "digest": "sha256:1111111111111111111111111111111111111111111111111111111111111111", "size": 1, "platform": {"os": "linux", "architecture": "amd64"}},
+    {"mediaType": "application/vnd.docker.distribution.manifest.v2+json", "digest": "sha256:2222222222222222222222222222222222222222222222222222222222222222", "size": 1, "platform": {"os": "linux", "architecture": "arm", "variant": "v7"}}
+  ]
+}`
+	c := client{
+		config: fakeClientConfig{
+			cert: &x509.Certificate{},
+		},
+		get: func(_ name.Reference, _ ...remote.Option) (*remote.Descriptor, error) {
+			return &remote.Descriptor{
+				Descriptor: containerv1.Descriptor{
+					MediaType: types.DockerManifestList,
+				},
+				Manifest: []byte(index),
+			}, nil
+		},
+	}
+	platforms, err := c.GetImagePlatforms("okteto/test:latest")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"linux/amd64", "linux/arm/v7"}, platforms)
+
+	c.get = func(_ name.Reference, _ ...remote.Option) (*remote.Descriptor, error) {
+		return nil, &transport.Error{StatusCode: http.StatusNotFound}
+	}
+	_, err = c.GetImagePlatforms("okteto/test:latest")
+	assert.ErrorIs(t, err, oktetoErrors.ErrNotFound)
+}
+
 func TestGetOptions(t *testing.T) {
 	type input struct {
 		config fakeClientConfig
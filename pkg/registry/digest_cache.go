@@ -0,0 +1,232 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/okteto/okteto/pkg/config"
+	"github.com/okteto/okteto/pkg/constants"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/spf13/afero"
+)
+
+const (
+	// digestCacheFileName is the file under OKTETO_HOME with the digests resolved recently
+	digestCacheFileName = "digests.json"
+
+	// defaultDigestCacheTTL is how long a digest is reused before it is resolved again
+	defaultDigestCacheTTL = 60 * time.Second
+
+	// maxDigestCacheEntries keeps the cache small, the oldest digests are dropped first
+	maxDigestCacheEntries = 256
+)
+
+// defaultDigestCache is the cache shared by the registry clients of the process
+var defaultDigestCache = newDigestCache(afero.NewOsFs())
+
+type digestCacheEntry struct {
+	Digest    string    `json:"digest"`
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// digestCache stores the digests of image tags under OKTETO_HOME so repeated lookups of the same tag,
+// from the same or other okteto commands, don't hit the registry while they are recent
+type digestCache struct {
+	fs   afero.Fs
+	path func() string
+	ttl  func() time.Duration
+	now  func() time.Time
+	mu   sync.Mutex
+}
+
+func newDigestCache(fs afero.Fs) *digestCache {
+	return &digestCache{
+		fs: fs,
+		path: func() string {
+			return filepath.Join(config.GetOktetoHome(), digestCacheFileName)
+		},
+		ttl: getDigestCacheTTL,
+		now: time.Now,
+	}
+}
+
+// getDigestCacheTTL returns the ttl set in OKTETO_DIGEST_CACHE_TTL or the default one. Zero disables the cache
+func getDigestCacheTTL() time.Duration {
+	value, ok := os.LookupEnv(constants.OktetoDigestCacheTTLEnvVar)
+	if !ok || value == "" {
+		return defaultDigestCacheTTL
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl < 0 {
+		oktetoLog.Infof("invalid value '%s' for %s, using %s", value, constants.OktetoDigestCacheTTLEnvVar, defaultDigestCacheTTL)
+		return defaultDigestCacheTTL
+	}
+	return ttl
+}
+
+// getDigestCacheKey returns the key of an image in the cache. Images referenced by digest aren't cached
+func getDigestCacheKey(image string) (string, bool) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", false
+	}
+	tag, ok := ref.(name.Tag)
+	if !ok {
+		return "", false
+	}
+	return tag.Name(), true
+}
+
+// get returns the digest of an image if it was resolved less than ttl ago
+func (c *digestCache) get(image string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	ttl := c.ttl()
+	key, ok := getDigestCacheKey(image)
+	if ttl == 0 || !ok {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.read()[key]
+	if !ok || c.now().Sub(entry.FetchedAt) >= ttl {
+		return "", false
+	}
+	return entry.Digest, true
+}
+
+// set stores the digest of an image, dropping the expired entries and the oldest ones over the size limit
+func (c *digestCache) set(image, digest string) {
+	if c == nil {
+		return
+	}
+	ttl := c.ttl()
+	key, ok := getDigestCacheKey(image)
+	if ttl == 0 || !ok {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries := c.read()
+	now := c.now()
+	entries[key] = digestCacheEntry{Digest: digest, FetchedAt: now}
+	for k, e := range entries {
+		if now.Sub(e.FetchedAt) >= ttl {
+			delete(entries, k)
+		}
+	}
+	if len(entries) > maxDigestCacheEntries {
+		keys := make([]string, 0, len(entries))
+		for k := range entries {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return entries[keys[i]].FetchedAt.Before(entries[keys[j]].FetchedAt)
+		})
+		for _, k := range keys[:len(keys)-maxDigestCacheEntries] {
+			delete(entries, k)
+		}
+	}
+	c.write(entries)
+}
+
+// invalidate removes the digests of the images, comma separated, from the cache
+func (c *digestCache) invalidate(images string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries := c.read()
+	changed := false
+	for _, image := range strings.Split(images, ",") {
+		key, ok := getDigestCacheKey(strings.TrimSpace(image))
+		if !ok {
+			continue
+		}
+		if _, ok := entries[key]; ok {
+			delete(entries, key)
+			changed = true
+		}
+	}
+	if changed {
+		c.write(entries)
+	}
+}
+
+// clear removes the file of the cache
+func (c *digestCache) clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.fs.Remove(c.path()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// read returns the entries of the cache. A missing or corrupted file is an empty cache
+func (c *digestCache) read() map[string]digestCacheEntry {
+	entries := map[string]digestCacheEntry{}
+	content, err := afero.ReadFile(c.fs, c.path())
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			oktetoLog.Infof("could not read the digest cache: %s", err)
+		}
+		return entries
+	}
+	if err := json.Unmarshal(content, &entries); err != nil {
+		oktetoLog.Infof("ignoring corrupted digest cache: %s", err)
+		return map[string]digestCacheEntry{}
+	}
+	return entries
+}
+
+// write replaces the file of the cache. Failures are only logged as the cache is an optimization
+func (c *digestCache) write(entries map[string]digestCacheEntry) {
+	content, err := json.Marshal(entries)
+	if err != nil {
+		oktetoLog.Infof("could not encode the digest cache: %s", err)
+		return
+	}
+	path := c.path()
+	tmp := path + ".tmp"
+	if err := afero.WriteFile(c.fs, tmp, content, 0600); err != nil {
+		oktetoLog.Infof("could not write the digest cache: %s", err)
+		return
+	}
+	if err := c.fs.Rename(tmp, path); err != nil {
+		oktetoLog.Infof("could not write the digest cache: %s", err)
+	}
+}
+
+// InvalidateDigest removes the digests of the images, comma separated, from the local digest cache. It must
+// be called after pushing them so the new digests are resolved
+func InvalidateDigest(images string) {
+	defaultDigestCache.invalidate(images)
+}
+
+// ClearDigestCache removes all the digests from the local digest cache
+func ClearDigestCache() error {
+	return defaultDigestCache.clear()
+}
@@ -0,0 +1,186 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"crypto/x509"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	containerv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/okteto/okteto/pkg/constants"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestDigestCache(clock *fakeClock, ttl time.Duration) *digestCache {
+	return &digestCache{
+		fs:   afero.NewMemMapFs(),
+		path: func() string { return "/okteto/digests.json" },
+		ttl:  func() time.Duration { return ttl },
+		now:  clock.Now,
+	}
+}
+
+func TestDigestCache(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	c := newTestDigestCache(clock, time.Minute)
+
+	_, ok := c.get("okteto.dev/api:1.0")
+	assert.False(t, ok)
+
+	c.set("okteto.dev/api:1.0", "sha256:a")
+	digest, ok := c.get("okteto.dev/api:1.0")
+	require.True(t, ok)
+	assert.Equal(t, "sha256:a", digest)
+
+	// the cache is read from the file, so other commands find it too
+	other := newTestDigestCache(clock, time.Minute)
+	other.fs = c.fs
+	digest, ok = other.get("okteto.dev/api:1.0")
+	require.True(t, ok)
+	assert.Equal(t, "sha256:a", digest)
+
+	clock.now = clock.now.Add(59 * time.Second)
+	_, ok = c.get("okteto.dev/api:1.0")
+	assert.True(t, ok)
+
+	clock.now = clock.now.Add(time.Second)
+	_, ok = c.get("okteto.dev/api:1.0")
+	assert.False(t, ok)
+}
+
+func TestDigestCacheKeys(t *testing.T) {
+	c := newTestDigestCache(&fakeClock{now: time.Now()}, time.Minute)
+
+	c.set("alpine", "sha256:a")
+	digest, ok := c.get("docker.io/library/alpine:latest")
+	require.True(t, ok)
+	assert.Equal(t, "sha256:a", digest)
+
+	c.set("okteto.dev/api@sha256:0000000000000000000000000000000000000000000000000000000000000000", "sha256:b")
+	_, ok = c.get("okteto.dev/api@sha256:0000000000000000000000000000000000000000000000000000000000000000")
+	assert.False(t, ok)
+}
+
+func TestDigestCacheDisabled(t *testing.T) {
+	c := newTestDigestCache(&fakeClock{now: time.Now()}, 0)
+
+	c.set("okteto.dev/api:1.0", "sha256:a")
+	_, ok := c.get("okteto.dev/api:1.0")
+	assert.False(t, ok)
+	exists, err := afero.Exists(c.fs, c.path())
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestDigestCacheInvalidate(t *testing.T) {
+	c := newTestDigestCache(&fakeClock{now: time.Now()}, time.Minute)
+	c.set("okteto.dev/api:1.0", "sha256:a")
+	c.set("okteto.dev/web:1.0", "sha256:b")
+	c.set("okteto.dev/db:1.0", "sha256:c")
+
+	c.invalidate("okteto.dev/api:1.0,okteto.dev/web:1.0")
+
+	_, ok := c.get("okteto.dev/api:1.0")
+	assert.False(t, ok)
+	_, ok = c.get("okteto.dev/web:1.0")
+	assert.False(t, ok)
+	_, ok = c.get("okteto.dev/db:1.0")
+	assert.True(t, ok)
+
+	require.NoError(t, c.clear())
+	_, ok = c.get("okteto.dev/db:1.0")
+	assert.False(t, ok)
+	require.NoError(t, c.clear())
+}
+
+func TestDigestCacheSizeLimit(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	c := newTestDigestCache(clock, time.Hour)
+	for i := 0; i <= maxDigestCacheEntries; i++ {
+		clock.now = clock.now.Add(time.Second)
+		c.set(fmt.Sprintf("okteto.dev/api:%d", i), "sha256:a")
+	}
+
+	assert.Len(t, c.read(), maxDigestCacheEntries)
+	_, ok := c.get("okteto.dev/api:0")
+	assert.False(t, ok)
+	_, ok = c.get(fmt.Sprintf("okteto.dev/api:%d", maxDigestCacheEntries))
+	assert.True(t, ok)
+}
+
+func TestDigestCacheCorruptedFile(t *testing.T) {
+	c := newTestDigestCache(&fakeClock{now: time.Now()}, time.Minute)
+	require.NoError(t, afero.WriteFile(c.fs, c.path(), []byte("{"), 0600))
+
+	_, ok := c.get("okteto.dev/api:1.0")
+	assert.False(t, ok)
+	c.set("okteto.dev/api:1.0", "sha256:a")
+	_, ok = c.get("okteto.dev/api:1.0")
+	assert.True(t, ok)
+}
+
+func TestGetDigestCacheTTL(t *testing.T) {
+	var tests = []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{name: "default", expected: defaultDigestCacheTTL},
+		{name: "custom", value: "5m", expected: 5 * time.Minute},
+		{name: "disabled", value: "0", expected: 0},
+		{name: "invalid", value: "soon", expected: defaultDigestCacheTTL},
+		{name: "negative", value: "-1s", expected: defaultDigestCacheTTL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(constants.OktetoDigestCacheTTLEnvVar, tt.value)
+			assert.Equal(t, tt.expected, getDigestCacheTTL())
+		})
+	}
+}
+
+func TestGetDigestUsesTheCache(t *testing.T) {
+	calls := 0
+	c := client{
+		config: fakeClientConfig{cert: &x509.Certificate{}},
+		get: func(_ name.Reference, _ ...remote.Option) (*remote.Descriptor, error) {
+			calls++
+			return &remote.Descriptor{
+				Descriptor: containerv1.Descriptor{
+					Digest: containerv1.Hash{Algorithm: "sha256", Hex: "a"},
+				},
+			}, nil
+		},
+		digests: newTestDigestCache(&fakeClock{now: time.Now()}, time.Minute),
+	}
+
+	for i := 0; i < 2; i++ {
+		digest, err := c.GetDigest("okteto.dev/api:1.0")
+		require.NoError(t, err)
+		assert.Equal(t, "sha256:a", digest)
+	}
+	assert.Equal(t, 1, calls)
+}
@@ -102,6 +102,11 @@ func (or OktetoRegistry) GetImageMetadata(image string) (ImageMetadata, error) {
 	}, nil
 }
 
+// GetImagePlatforms returns the platforms (os/arch[/variant]) an image is available for
+func (or OktetoRegistry) GetImagePlatforms(image string) ([]string, error) {
+	return or.client.GetImagePlatforms(or.imageCtrl.expandImageRegistries(image))
+}
+
 // IsOktetoRegistry returns if an image tag is pointing to the okteto registry
 func (or OktetoRegistry) IsOktetoRegistry(image string) bool {
 	expandedImage := or.imageCtrl.expandImageRegistries(image)
@@ -140,6 +145,11 @@ func (OktetoRegistry) GetImageReference(image string) (OktetoImageReference, err
 	}, nil
 }
 
+// ImageExists returns if the image exists in its registry
+func (or OktetoRegistry) ImageExists(image string) (bool, error) {
+	return or.client.ImageExists(or.imageCtrl.expandImageRegistries(image))
+}
+
 // HasGlobalPushAccess checks if the user has push access to the global registry
 func (or OktetoRegistry) HasGlobalPushAccess() (bool, error) {
 	if !or.config.IsOktetoCluster() {
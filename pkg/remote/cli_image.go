@@ -0,0 +1,69 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/okteto/okteto/pkg/constants"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+)
+
+// releaseVersionRegex matches the okteto releases, including pre-releases like 2.22.0-rc.1
+var releaseVersionRegex = regexp.MustCompile(`^\d+\.\d+\.\d+(-(alpha|beta|rc)\.\d+)?$`)
+
+// GetOktetoCLIImage returns the okteto CLI image that runs the remote command. OKTETO_REMOTE_CLI_IMAGE
+// takes precedence and accepts an image or a digest, otherwise the image of the local release is used.
+// It warns when the image doesn't match the local version
+func GetOktetoCLIImage(versionString string) string {
+	image := ResolveOktetoCLIImage(versionString)
+	if !strings.Contains(image, "@") && getImageTag(image) != versionString {
+		oktetoLog.Warning("The remote command runs the okteto CLI image '%s', which doesn't match your local okteto version '%s'", image, versionString)
+	}
+	return image
+}
+
+// ResolveOktetoCLIImage returns the okteto CLI image that runs the remote command, see GetOktetoCLIImage
+func ResolveOktetoCLIImage(versionString string) string {
+	if remoteOktetoImage := os.Getenv(constants.OKtetoDeployRemoteImage); remoteOktetoImage != "" {
+		if strings.HasPrefix(remoteOktetoImage, "sha256:") {
+			return fmt.Sprintf(constants.OktetoCLIImageForRemoteByDigestTemplate, remoteOktetoImage)
+		}
+		return remoteOktetoImage
+	}
+	if releaseVersionRegex.MatchString(versionString) {
+		return fmt.Sprintf(constants.OktetoCLIImageForRemoteTemplate, versionString)
+	}
+	return fmt.Sprintf(constants.OktetoCLIImageForRemoteTemplate, "latest")
+}
+
+// GetExpectedOktetoCLIVersion returns the okteto version of a CLI image, empty when its tag isn't a release
+func GetExpectedOktetoCLIVersion(image string) string {
+	if tag := getImageTag(image); releaseVersionRegex.MatchString(tag) {
+		return tag
+	}
+	return ""
+}
+
+// getImageTag returns the tag of an image reference, empty if it has none
+func getImageTag(image string) string {
+	i := strings.LastIndex(image, ":")
+	if i == -1 || strings.Contains(image[i:], "/") {
+		return ""
+	}
+	return image[i+1:]
+}
@@ -0,0 +1,127 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"testing"
+
+	"github.com/okteto/okteto/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOktetoCLIImage(t *testing.T) {
+	var tests = []struct {
+		name                                 string
+		versionString, expected, cliImageEnv string
+	}{
+		{
+			name:          "no version string and no env return latest",
+			versionString: "",
+			expected:      "okteto/okteto:latest",
+		},
+		{
+			name:          "no version string return env value",
+			versionString: "",
+			cliImageEnv:   "okteto/remote:test",
+			expected:      "okteto/remote:test",
+		},
+		{
+			name:          "found version string",
+			versionString: "2.2.2",
+			expected:      "okteto/okteto:2.2.2",
+		},
+		{
+			name:          "found incorrect version string return latest ",
+			versionString: "2.a.2",
+			expected:      "okteto/okteto:latest",
+		},
+		{
+			name:          "found release candidate version string",
+			versionString: "2.22.0-rc.1",
+			expected:      "okteto/okteto:2.22.0-rc.1",
+		},
+		{
+			name:          "found dirty dev version string return latest",
+			versionString: "2.22.0-dirty",
+			expected:      "okteto/okteto:latest",
+		},
+		{
+			name:          "found dev build version string return latest",
+			versionString: "2.22.0-3-g1a2b3c4",
+			expected:      "okteto/okteto:latest",
+		},
+		{
+			name:          "env value takes precedence over version string",
+			versionString: "2.2.2",
+			cliImageEnv:   "okteto/remote:test",
+			expected:      "okteto/remote:test",
+		},
+		{
+			name:          "env digest pins the okteto image",
+			versionString: "2.22.0-dirty",
+			cliImageEnv:   "sha256:3c3e7a1e1e2b5b8f0d0a7d6a4a1e5c9b0f1b2c3d4e5f60718293a4b5c6d7e8f9",
+			expected:      "okteto/okteto@sha256:3c3e7a1e1e2b5b8f0d0a7d6a4a1e5c9b0f1b2c3d4e5f60718293a4b5c6d7e8f9",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(constants.OKtetoDeployRemoteImage, tt.cliImageEnv)
+
+			require.Equal(t, tt.expected, GetOktetoCLIImage(tt.versionString))
+		})
+	}
+}
+
+func TestGetImageTag(t *testing.T) {
+	var tests = []struct {
+		name, image, expected string
+	}{
+		{
+			name:     "image with tag",
+			image:    "okteto/okteto:2.2.2",
+			expected: "2.2.2",
+		},
+		{
+			name:     "image without tag",
+			image:    "okteto/okteto",
+			expected: "",
+		},
+		{
+			name:     "registry with port and no tag",
+			image:    "registry.example.com:5000/okteto/okteto",
+			expected: "",
+		},
+		{
+			name:     "registry with port and tag",
+			image:    "registry.example.com:5000/okteto/okteto:latest",
+			expected: "latest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, getImageTag(tt.image))
+		})
+	}
+}
+
+func TestGetExpectedOktetoCLIVersion(t *testing.T) {
+	assert.Equal(t, "2.22.0", GetExpectedOktetoCLIVersion("okteto/okteto:2.22.0"))
+	assert.Equal(t, "2.22.0-rc.1", GetExpectedOktetoCLIVersion("okteto/okteto:2.22.0-rc.1"))
+	assert.Equal(t, "", GetExpectedOktetoCLIVersion("okteto/okteto:latest"))
+	assert.Equal(t, "", GetExpectedOktetoCLIVersion("okteto/okteto@sha256:abc"))
+}
@@ -0,0 +1,254 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package remote renders the images that run okteto deploy and okteto destroy in remote
+package remote
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/okteto/okteto/pkg/constants"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/spf13/afero"
+)
+
+const (
+	templateName = "remote-dockerfile"
+
+	redactedTokenValue = "<redacted>"
+
+	// deployStageSuffix ends the line that declares the stage running the remote command
+	deployStageSuffix = " as deploy\n"
+
+	dockerfileTemplate = `{{ validate .TokenValue "OKTETO_TOKEN must be set" }}
+FROM {{ .OktetoCLIImage }} as okteto-cli
+
+FROM {{ if .InstallerPlatform }}--platform={{ .InstallerPlatform }} {{ end }}{{ .InstallerImage }} as installer
+
+FROM alpine as certs
+RUN apk update && apk add ca-certificates
+
+FROM {{ .RunImage }} as deploy
+
+ENV PATH="${PATH}:/okteto/bin"
+COPY --from=certs /etc/ssl/certs /etc/ssl/certs
+COPY --from=installer /app/bin/* /okteto/bin/
+{{- if not .PreferImageCLI }}
+COPY --from=okteto-cli /usr/local/bin/* /okteto/bin/
+{{- end }}
+
+{{range $key, $val := .OktetoBuildEnvVars }}
+ENV {{$key}} {{$val}}
+{{end}}
+ENV {{ .NamespaceEnvVar }} {{ .Namespace }}
+ENV {{ .ContextEnvVar }} {{ .Context }}
+{{- if not .TokenSecretID }}
+ENV {{ .TokenEnvVar }} {{ .TokenValue }}
+{{- end }}
+ENV {{ .RemoteDeployEnvVar }} true
+{{ if ne .ActionName "" }}
+ENV {{ .ActionNameEnvVar }} {{ .ActionName }}
+{{ end }}
+{{ if ne .GitCommit "" }}
+ENV {{ .GitCommitEnvVar }} {{ .GitCommit }}
+{{ end }}
+
+COPY . /okteto/src
+WORKDIR /okteto/src
+{{- range .IncludedFiles }}
+ARG {{ .Arg }}
+RUN mkdir -p {{ .Dir }} && echo "${{ .Arg }}" | base64 -d > {{ .Path }}
+{{- end }}
+
+ENV OKTETO_INVALIDATE_CACHE {{ .CacheKey }}
+ARG OKTETO_TLS_CERT_BASE64
+ARG INTERNAL_SERVER_NAME=""
+RUN echo "$OKTETO_TLS_CERT_BASE64" | base64 -d > /etc/ssl/certs/okteto.crt
+{{- if .ExtraCACertsArg }}
+ARG {{ .ExtraCACertsArg }}
+RUN echo "${{ .ExtraCACertsArg }}" | base64 -d > /etc/ssl/certs/okteto-extra-ca.crt && \
+  cat /etc/ssl/certs/okteto-extra-ca.crt >> /etc/ssl/certs/ca-certificates.crt
+{{- end }}
+{{- if .RunIDArg }}
+ARG {{ .RunIDArg }}
+{{- end }}
+{{- range $key, $val := .ProxyEnvVars }}
+ENV {{ $key }}={{ printf "%q" $val }}
+{{- end }}
+{{- range .EnvVars }}
+ENV {{ .Name }}={{ printf "%q" .Value }}
+{{- end }}
+{{- range .Args }}
+ARG {{ . }}
+{{- end }}
+{{- range .ExposedArgs }}
+ARG {{ . }}
+ENV {{ . }}="${{ . }}"
+{{- end }}
+{{- if .SkipTLSVerify }}
+ENV {{ .TLSVerifyEnvVar }}=false
+{{- end }}
+RUN{{ if .TokenSecretID }} --mount=type=secret,id={{ .TokenSecretID }}{{ end }}{{ if .SSH }} --mount=type=ssh{{ end }} \
+{{- if .TokenSecretID }}
+  export {{ .TokenEnvVar }}="$(cat /run/secrets/{{ .TokenSecretID }})" && \
+{{- end }}
+{{- if .CLICheckMarker }}
+  echo "{{ .CLICheckMarker }} $(command -v okteto) $(okteto version)" && \
+{{- end }}
+  okteto {{ .Command }} --log-output={{ .LogOutput }} --server-name="$INTERNAL_SERVER_NAME" {{ join .Flags " " }}
+`
+)
+
+// Params are the properties of the Dockerfile that runs an okteto command in remote
+type Params struct {
+	// Command is the okteto command run in remote, like deploy or destroy
+	Command string
+	// Flags are the flags of Command, quoted for the shell of the RUN instruction
+	Flags []string
+	// LogOutput is the log format of Command
+	LogOutput string
+
+	OktetoCLIImage string
+	InstallerImage string
+	// InstallerPlatform is the platform of InstallerImage pulled by the build, the default one when empty
+	InstallerPlatform string
+	// RunImage is the image of the stage that runs Command
+	RunImage string
+	// PreferImageCLI skips copying the okteto CLI binaries, the ones of RunImage are used
+	PreferImageCLI bool
+
+	OktetoBuildEnvVars map[string]string
+	Namespace          string
+	Context            string
+	ActionName         string
+	GitCommit          string
+	TokenValue         string
+	// TokenSecretID mounts the token as a build secret. When empty the token is set as an env var of the image
+	TokenSecretID string
+
+	// CacheKey invalidates the cache of the layers that run Command
+	CacheKey      string
+	IncludedFiles []IncludedFile
+	// ExtraCACertsArg is the build arg with the extra CA certificates trusted by the image, if any
+	ExtraCACertsArg string
+	// RunIDArg is a build arg set to a different value on every run, if any
+	RunIDArg     string
+	ProxyEnvVars map[string]string
+	// EnvVars are set right before Command runs, in order
+	EnvVars []EnvVar
+	// Args are build args declared right before Command runs
+	Args []string
+	// ExposedArgs are build args declared and exposed as env vars right before Command runs
+	ExposedArgs []string
+	// SkipTLSVerify sets OKTETO_TLS_VERIFY=false so Command doesn't verify the server certificate
+	SkipTLSVerify bool
+	SSH           bool
+	// CLICheckMarker prefixes the line with the path and version of the okteto binary that runs Command
+	CLICheckMarker string
+	// ExtraDockerfile is inserted verbatim after the stage running Command is declared. It is never parsed as a template
+	ExtraDockerfile string
+}
+
+// EnvVar is an env var of the Dockerfile. Its value is quoted
+type EnvVar struct {
+	Name  string
+	Value string
+}
+
+// templateProperties adds the names of the env vars set by the Dockerfile to the params
+type templateProperties struct {
+	Params
+	NamespaceEnvVar    string
+	ContextEnvVar      string
+	TokenEnvVar        string
+	ActionNameEnvVar   string
+	GitCommitEnvVar    string
+	RemoteDeployEnvVar string
+	TLSVerifyEnvVar    string
+}
+
+// RenderDockerfile writes the Dockerfile that runs an okteto command in remote
+func RenderDockerfile(w io.Writer, params Params) error {
+	tmpl := template.Must(template.New(templateName).Funcs(template.FuncMap{
+		"validate": validateTemplateValue,
+		"join":     strings.Join,
+	}).Parse(dockerfileTemplate))
+	properties := templateProperties{
+		Params:             params,
+		NamespaceEnvVar:    model.OktetoNamespaceEnvVar,
+		ContextEnvVar:      model.OktetoContextEnvVar,
+		TokenEnvVar:        model.OktetoTokenEnvVar,
+		ActionNameEnvVar:   model.OktetoActionNameEnvVar,
+		GitCommitEnvVar:    constants.OktetoGitCommitEnvVar,
+		RemoteDeployEnvVar: constants.OKtetoDeployRemote,
+		TLSVerifyEnvVar:    constants.OktetoTLSVerifyEnvVar,
+	}
+	if params.ExtraDockerfile == "" {
+		return tmpl.Execute(w, properties)
+	}
+
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, properties); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, insertExtraDockerfile(rendered.String(), params.ExtraDockerfile))
+	return err
+}
+
+// PrintDryRun writes the rendered dockerfile and the ignore rules written to tmpDir with the okteto token redacted
+func PrintDryRun(w io.Writer, fs afero.Fs, dockerfile, tmpDir, token string) error {
+	content, err := afero.ReadFile(fs, dockerfile)
+	if err != nil {
+		return err
+	}
+	ignoreRules, err := afero.ReadFile(fs, filepath.Join(tmpDir, DockerignoreName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	out := fmt.Sprintf("# Dockerfile\n%s\n# %s\n%s\n", content, DockerignoreName, ignoreRules)
+	if token != "" {
+		out = strings.ReplaceAll(out, token, redactedTokenValue)
+	}
+	_, err = io.WriteString(w, out)
+	return err
+}
+
+// insertExtraDockerfile returns the dockerfile with the fragment right after the declaration of the deploy stage
+func insertExtraDockerfile(dockerfile, fragment string) string {
+	i := strings.Index(dockerfile, deployStageSuffix)
+	if i == -1 {
+		return dockerfile
+	}
+	i += len(deployStageSuffix)
+	if !strings.HasSuffix(fragment, "\n") {
+		fragment += "\n"
+	}
+	return dockerfile[:i] + "\n" + fragment + dockerfile[i:]
+}
+
+// validateTemplateValue is used from the dockerfile template to fail the rendering
+// when a required value is empty. It never renders anything.
+func validateTemplateValue(value, msg string) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		return "", errors.New(msg)
+	}
+	return "", nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files")
+
+// commonParams are the params shared by the remote deploy and the remote destroy dockerfiles
+func commonParams() Params {
+	return Params{
+		LogOutput:      "json",
+		OktetoCLIImage: "okteto/okteto:2.22.0",
+		InstallerImage: "okteto/installer:1.0.0",
+		RunImage:       "okteto/pipeline-runner:1.0.0",
+		OktetoBuildEnvVars: map[string]string{
+			"OKTETO_BUILD_API_IMAGE":      "registry.okteto.dev/test/api@sha256:123",
+			"OKTETO_BUILD_FRONTEND_IMAGE": "registry.okteto.dev/test/frontend@sha256:456",
+		},
+		Namespace:  "test",
+		Context:    "https://okteto.example.com",
+		ActionName: "cli",
+		GitCommit:  "1a2b3c4",
+		TokenValue: "token",
+		CacheKey:   "cache-key",
+		IncludedFiles: []IncludedFile{
+			{Arg: "OKTETO_INCLUDED_FILE_0", Dir: "/okteto/shared", Path: "/okteto/shared/okteto-deploy.yml"},
+		},
+	}
+}
+
+func deployParams() Params {
+	params := commonParams()
+	params.Command = "deploy"
+	params.Flags = []string{`--name "movies"`, "--namespace test"}
+	params.SkipTLSVerify = true
+	return params
+}
+
+func destroyParams() Params {
+	params := commonParams()
+	params.Command = "destroy"
+	params.Flags = []string{"--name 'movies'", "--namespace test", "--volumes"}
+	params.TokenSecretID = "okteto-token"
+	params.ExtraCACertsArg = "OKTETO_EXTRA_CA_CERTS_BASE64"
+	params.RunIDArg = "OKTETO_DESTROY_RUN_ID"
+	params.ProxyEnvVars = map[string]string{"HTTPS_PROXY": "http://proxy:3128"}
+	params.EnvVars = []EnvVar{{Name: "OKTETO_DESTROY_REASON", Value: "cleanup \"pr-1\""}}
+	params.Args = []string{"OKTETO_IMPERSONATE_USER", "OKTETO_IMPERSONATE_GROUP"}
+	params.ExposedArgs = []string{"AWS_REGION"}
+	params.SSH = true
+	params.CLICheckMarker = "okteto-cli-check:"
+	return params
+}
+
+func TestRenderDockerfile(t *testing.T) {
+	var tests = []struct {
+		name   string
+		params Params
+	}{
+		{
+			name:   "deploy",
+			params: deployParams(),
+		},
+		{
+			name:   "destroy",
+			params: destroyParams(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			require.NoError(t, RenderDockerfile(out, tt.params))
+
+			golden := filepath.Join("testdata", fmt.Sprintf("%s.golden", tt.name))
+			if *updateGolden {
+				require.NoError(t, os.WriteFile(golden, out.Bytes(), 0600))
+			}
+			expected, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), out.String())
+		})
+	}
+}
+
+func TestRenderDockerfileSharedSections(t *testing.T) {
+	deploy := &bytes.Buffer{}
+	require.NoError(t, RenderDockerfile(deploy, deployParams()))
+	destroy := &bytes.Buffer{}
+	require.NoError(t, RenderDockerfile(destroy, destroyParams()))
+
+	// everything up to the cache invalidation only depends on the common params
+	const sharedUntil = "ENV OKTETO_INVALIDATE_CACHE"
+	deployShared := strings.ReplaceAll(deploy.String()[:strings.Index(deploy.String(), sharedUntil)], "ENV OKTETO_TOKEN token\n", "")
+	destroyShared := destroy.String()[:strings.Index(destroy.String(), sharedUntil)]
+	assert.Equal(t, deployShared, destroyShared)
+}
+
+func TestRenderDockerfileWithoutToken(t *testing.T) {
+	params := deployParams()
+	params.TokenValue = ""
+	assert.EqualError(t, RenderDockerfile(&bytes.Buffer{}, params), `template: remote-dockerfile:1:3: executing "remote-dockerfile" at <validate .TokenValue "OKTETO_TOKEN must be set">: error calling validate: OKTETO_TOKEN must be set`)
+}
+
+func TestRenderDockerfileWithExtraDockerfile(t *testing.T) {
+	params := destroyParams()
+	// the fragment is inserted verbatim, template actions are not evaluated
+	params.ExtraDockerfile = "RUN apt-get update && apt-get install -y awscli\nRUN echo '{{ .TokenValue }}'"
+
+	out := &bytes.Buffer{}
+	require.NoError(t, RenderDockerfile(out, params))
+	assert.Contains(t, out.String(), "FROM okteto/pipeline-runner:1.0.0 as deploy\n\nRUN apt-get update && apt-get install -y awscli\nRUN echo '{{ .TokenValue }}'\n\nENV PATH")
+	assert.NotContains(t, out.String(), "RUN echo 'token'")
+}
+
+func TestRenderDockerfileWithInstallerPlatform(t *testing.T) {
+	params := destroyParams()
+	params.InstallerPlatform = "linux/arm64"
+
+	out := &bytes.Buffer{}
+	require.NoError(t, RenderDockerfile(out, params))
+	assert.Contains(t, out.String(), fmt.Sprintf("FROM --platform=linux/arm64 %s as installer\n", params.InstallerImage))
+}
+
+func TestPrintDryRun(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/tmp/deploy", []byte("ENV OKTETO_TOKEN secret-token"), 0600))
+
+	out := &bytes.Buffer{}
+	require.NoError(t, PrintDryRun(out, fs, "/tmp/deploy", "/tmp", "secret-token"))
+	assert.Equal(t, "# Dockerfile\nENV OKTETO_TOKEN <redacted>\n# .dockerignore\n\n", out.String())
+
+	require.NoError(t, afero.WriteFile(fs, filepath.Join("/tmp", DockerignoreName), []byte("node_modules"), 0600))
+	out.Reset()
+	require.NoError(t, PrintDryRun(out, fs, "/tmp/deploy", "/tmp", "secret-token"))
+	assert.Equal(t, "# Dockerfile\nENV OKTETO_TOKEN <redacted>\n# .dockerignore\nnode_modules\n", out.String())
+}
+
+// maxDockerfileRenderDuration is the time budget to render the remote dockerfile
+const maxDockerfileRenderDuration = time.Millisecond
+
+func BenchmarkRenderDockerfile(b *testing.B) {
+	params := destroyParams()
+	params.OktetoBuildEnvVars = map[string]string{}
+	for i := 0; i < 50; i++ {
+		params.OktetoBuildEnvVars[fmt.Sprintf("OKTETO_BUILD_SVC%d_IMAGE", i)] = fmt.Sprintf("registry.okteto.dev/test/svc%d@sha256:%064d", i, i)
+	}
+	out := &bytes.Buffer{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		out.Reset()
+		if err := RenderDockerfile(out, params); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+
+	if perRender := time.Since(start) / time.Duration(b.N); perRender > maxDockerfileRenderDuration {
+		b.Fatalf("rendering the dockerfile took %s, the budget is %s", perRender, maxDockerfileRenderDuration)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+
+	"github.com/docker/docker/pkg/fileutils"
+	"github.com/moby/buildkit/frontend/dockerfile/dockerignore"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/filesystem"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/spf13/afero"
+)
+
+const (
+	// DockerignoreName is the name of the ignore file of the build context of the remote commands
+	DockerignoreName = ".dockerignore"
+	// OktetoDockerignoreName is the ignore file of the remote commands. Its patterns take precedence over DockerignoreName
+	OktetoDockerignoreName = ".oktetodeployignore"
+)
+
+// WriteDockerignore writes to tmpDir the ignore rules of the build context at contextDir. The project's
+// .dockerignore is used as base and the .oktetodeployignore patterns take precedence, so they can re-include
+// the files needed by the remote command. The file is written even if empty, otherwise the .dockerignore
+// of the project would be used as is
+func WriteDockerignore(fs afero.Fs, contextDir, tmpDir string) error {
+	content, _, err := filesystem.ReadIgnoreFiles(
+		fs,
+		filepath.Join(contextDir, DockerignoreName),
+		filepath.Join(contextDir, OktetoDockerignoreName),
+	)
+	if err != nil {
+		return err
+	}
+	if err := validateDockerignoreContent(content); err != nil {
+		return err
+	}
+	return afero.WriteFile(fs, filepath.Join(tmpDir, DockerignoreName), content, 0600)
+}
+
+// validateDockerignoreContent checks the patterns of the ignore files used for the remote commands.
+// It fails on invalid patterns and warns about the ones that are valid but likely a mistake
+func validateDockerignoreContent(content []byte) error {
+	patterns, err := dockerignore.ReadAll(bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	if _, err := fileutils.NewPatternMatcher(patterns); err != nil {
+		return oktetoErrors.UserError{
+			E:    fmt.Errorf("invalid ignore pattern: %w", err),
+			Hint: fmt.Sprintf("Check the syntax of the patterns of your '%s' and '%s' files", DockerignoreName, OktetoDockerignoreName),
+		}
+	}
+
+	hasExclusions := false
+	excludesAll := false
+	for _, pattern := range patterns {
+		if pattern[0] != '!' {
+			hasExclusions = true
+			if matchesAll(pattern) {
+				excludesAll = true
+			}
+			continue
+		}
+
+		// any negation after excluding everything includes some files again
+		excludesAll = false
+		negated := pattern[1:]
+		switch {
+		case matchesAll(negated):
+			oktetoLog.Warning("The pattern '%s' includes every file again, the ignore patterns defined before it have no effect", pattern)
+		case !hasExclusions:
+			oktetoLog.Warning("The pattern '%s' has no effect because no previous pattern ignores '%s'", pattern, negated)
+		}
+	}
+	if excludesAll {
+		oktetoLog.Warning("Your ignore files exclude every file, the okteto manifest won't be available in remote")
+	}
+	return nil
+}
+
+func matchesAll(pattern string) bool {
+	return pattern == "*" || pattern == "**" || pattern == "."
+}
@@ -0,0 +1,148 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"path/filepath"
+	"testing"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDockerignoreContent(t *testing.T) {
+	var tests = []struct {
+		name             string
+		content          string
+		expectedWarnings []string
+		expectErr        bool
+	}{
+		{
+			name:             "empty",
+			expectedWarnings: []string{},
+		},
+		{
+			name:             "valid negation",
+			content:          "# dependencies\nnode_modules\n*.go\n!main.go\n",
+			expectedWarnings: []string{},
+		},
+		{
+			name:             "exclude all and include some files",
+			content:          "*\n!okteto.yml\n!k8s\n",
+			expectedWarnings: []string{},
+		},
+		{
+			name:    "negation of all rules",
+			content: "node_modules\n.git\n!*\n",
+			expectedWarnings: []string{
+				"The pattern '!*' includes every file again, the ignore patterns defined before it have no effect",
+			},
+		},
+		{
+			name:    "negation without previous rules",
+			content: "!main.go\nnode_modules\n",
+			expectedWarnings: []string{
+				"The pattern '!main.go' has no effect because no previous pattern ignores 'main.go'",
+			},
+		},
+		{
+			name:    "exclude all",
+			content: "node_modules\n**\n",
+			expectedWarnings: []string{
+				"Your ignore files exclude every file, the okteto manifest won't be available in remote",
+			},
+		},
+		{
+			name:      "invalid exclusion pattern",
+			content:   "node_modules\n!\n",
+			expectErr: true,
+		},
+		{
+			name:      "invalid pattern syntax",
+			content:   "[a-\n",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oktetoLog.RecordWarnings()
+			err := validateDockerignoreContent([]byte(tt.content))
+			if tt.expectErr {
+				var userErr oktetoErrors.UserError
+				require.ErrorAs(t, err, &userErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedWarnings, oktetoLog.GetWarnings())
+		})
+	}
+}
+
+func TestWriteDockerignore(t *testing.T) {
+	var tests = []struct {
+		name            string
+		files           map[string]string
+		expectedContent string
+	}{
+		{
+			name: "only .oktetodeployignore",
+			files: map[string]string{
+				".oktetodeployignore": "node_modules",
+			},
+			expectedContent: "node_modules",
+		},
+		{
+			name: "only .dockerignore",
+			files: map[string]string{
+				".dockerignore": "node_modules\n.git",
+			},
+			expectedContent: "node_modules\n.git",
+		},
+		{
+			name: "both files are merged with .oktetodeployignore taking precedence",
+			files: map[string]string{
+				".dockerignore":       "node_modules\nk8s",
+				".oktetodeployignore": "!k8s",
+			},
+			expectedContent: "node_modules\nk8s\n!k8s",
+		},
+		{
+			name:            "without ignore files generate empty dockerignore",
+			expectedContent: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			wd := "/test/"
+			require.NoError(t, fs.MkdirAll(wd, 0755))
+			for name, content := range tt.files {
+				require.NoError(t, afero.WriteFile(fs, filepath.Join(wd, name), []byte(content), 0644))
+			}
+			require.NoError(t, WriteDockerignore(fs, wd, "/temp"))
+			b, err := afero.ReadFile(fs, filepath.Join("/temp", DockerignoreName))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedContent, string(b))
+		})
+	}
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, filepath.Join("/test", DockerignoreName), []byte("[a-"), 0644))
+	assert.Error(t, WriteDockerignore(fs, "/test", "/temp"))
+}
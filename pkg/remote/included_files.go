@@ -0,0 +1,77 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/alessio/shellescape"
+	"github.com/spf13/afero"
+)
+
+const (
+	// remoteSourceDir is where the build context is copied in the images of the remote commands
+	remoteSourceDir = "/okteto/src"
+
+	includedFileArgPrefix = "OKTETO_INCLUDED_FILE_"
+)
+
+// IncludedFile is a file included by the manifest that is outside the build context of a remote command.
+// The dockerfile of the remote command writes it from a build arg
+type IncludedFile struct {
+	// Arg is the name of the build arg with the content of the file
+	Arg string
+	// Dir and Path are the shell quoted folder and path of the file in the remote image
+	Dir  string
+	Path string
+
+	content string
+}
+
+// BuildArg returns the build arg with the content of the file encoded in base64
+func (f IncludedFile) BuildArg() string {
+	return fmt.Sprintf("%s=%s", f.Arg, f.content)
+}
+
+// GetIncludedFilesOutsideContext returns the included files that the build context rooted at contextDir doesn't contain.
+// They keep the same path relative to the build context in the remote image
+func GetIncludedFilesOutsideContext(fs afero.Fs, contextDir string, files []string) ([]IncludedFile, error) {
+	result := []IncludedFile{}
+	for _, file := range files {
+		rel, err := filepath.Rel(contextDir, file)
+		if err != nil {
+			return nil, fmt.Errorf("included file '%s' can't be copied to the remote image: %w", file, err)
+		}
+		rel = filepath.ToSlash(rel)
+		if rel != ".." && !strings.HasPrefix(rel, "../") {
+			continue
+		}
+		content, err := afero.ReadFile(fs, file)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the included file '%s': %w", file, err)
+		}
+		remotePath := path.Join(remoteSourceDir, rel)
+		result = append(result, IncludedFile{
+			Arg:     fmt.Sprintf("%s%d", includedFileArgPrefix, len(result)),
+			Dir:     shellescape.Quote(path.Dir(remotePath)),
+			Path:    shellescape.Quote(remotePath),
+			content: base64.StdEncoding.EncodeToString(content),
+		})
+	}
+	return result, nil
+}
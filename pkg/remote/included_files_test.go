@@ -0,0 +1,49 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"encoding/base64"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetIncludedFilesOutsideContext(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	inside := filepath.FromSlash("/repo/app/shared/inside.yml")
+	outside := filepath.FromSlash("/repo/shared/okteto-deploy.yml")
+	withSpaces := filepath.FromSlash("/repo/shared/my snippets.yml")
+	require.NoError(t, afero.WriteFile(fs, inside, []byte("- echo inside"), 0600))
+	require.NoError(t, afero.WriteFile(fs, outside, []byte("- echo outside"), 0600))
+	require.NoError(t, afero.WriteFile(fs, withSpaces, []byte("- echo spaces"), 0600))
+
+	files, err := GetIncludedFilesOutsideContext(fs, filepath.FromSlash("/repo/app"), []string{inside, outside, withSpaces})
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	assert.Equal(t, "OKTETO_INCLUDED_FILE_0", files[0].Arg)
+	assert.Equal(t, "/okteto/shared", files[0].Dir)
+	assert.Equal(t, "/okteto/shared/okteto-deploy.yml", files[0].Path)
+	assert.Equal(t, "OKTETO_INCLUDED_FILE_0="+base64.StdEncoding.EncodeToString([]byte("- echo outside")), files[0].BuildArg())
+
+	assert.Equal(t, "OKTETO_INCLUDED_FILE_1", files[1].Arg)
+	assert.Equal(t, "'/okteto/shared/my snippets.yml'", files[1].Path)
+
+	_, err = GetIncludedFilesOutsideContext(fs, filepath.FromSlash("/repo/app"), []string{filepath.FromSlash("/repo/missing.yml")})
+	assert.Error(t, err)
+}
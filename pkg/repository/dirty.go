@@ -0,0 +1,78 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// GetDirtyFiles returns the sorted paths, relative to the root of the git repo containing dir, of the modified and
+// the untracked files that are not ignored. The changes of the initialized submodules are included
+func GetDirtyFiles(dir string) ([]string, error) {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, err
+	}
+	files, err := getDirtyFiles(repo, "")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func getDirtyFiles(repo *git.Repository, prefix string) ([]string, error) {
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the worktree of the git repo: %w", err)
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the status of the git repo: %w", err)
+	}
+
+	var result []string
+	for p, s := range status {
+		if s.Staging == git.Unmodified && s.Worktree == git.Unmodified {
+			continue
+		}
+		result = append(result, path.Join(prefix, p))
+	}
+
+	// the status of the parent repo only reports the submodules whose commit changed
+	submodules, err := worktree.Submodules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the submodules of the git repo: %w", err)
+	}
+	for _, sm := range submodules {
+		smRepo, err := sm.Repository()
+		if err != nil {
+			if errors.Is(err, git.ErrSubmoduleNotInitialized) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to open submodule '%s': %w", sm.Config().Path, err)
+		}
+		files, err := getDirtyFiles(smRepo, path.Join(prefix, sm.Config().Path))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, files...)
+	}
+	return result, nil
+}
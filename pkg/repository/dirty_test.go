@@ -0,0 +1,107 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFixtureRepo creates a git repo in a temporary folder with files committed
+func newFixtureRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	for name, content := range files {
+		writeFixtureFile(t, dir, name, content)
+		_, err := worktree.Add(name)
+		require.NoError(t, err)
+	}
+	_, err = worktree.Commit("initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "okteto", Email: "test@okteto.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+	return dir
+}
+
+func writeFixtureFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0700))
+	require.NoError(t, os.WriteFile(p, []byte(content), 0600))
+}
+
+func TestGetDirtyFiles(t *testing.T) {
+	dir := newFixtureRepo(t, map[string]string{
+		".gitignore":     "*.log\n",
+		"okteto.yml":     "deploy: []\n",
+		"scripts/run.sh": "echo hi\n",
+	})
+
+	files, err := GetDirtyFiles(dir)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	writeFixtureFile(t, dir, "scripts/run.sh", "echo bye\n")
+	writeFixtureFile(t, dir, "new.txt", "new")
+	writeFixtureFile(t, dir, "debug.log", "ignored")
+
+	// the repo is found from any of its folders
+	files, err = GetDirtyFiles(filepath.Join(dir, "scripts"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new.txt", "scripts/run.sh"}, files)
+}
+
+func TestGetDirtyFilesNotARepo(t *testing.T) {
+	_, err := GetDirtyFiles(t.TempDir())
+	require.ErrorIs(t, err, git.ErrRepositoryNotExists)
+}
+
+func TestGetDirtyFilesWithSubmodule(t *testing.T) {
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git is not installed")
+	}
+	sub := newFixtureRepo(t, map[string]string{"lib.sh": "echo lib\n"})
+	dir := newFixtureRepo(t, map[string]string{"okteto.yml": "deploy: []\n"})
+
+	runGit := func(args ...string) {
+		cmd := exec.Command(gitPath, append([]string{"-c", "protocol.file.allow=always", "-c", "user.name=okteto", "-c", "user.email=test@okteto.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	runGit("submodule", "add", sub, "vendor/lib")
+	runGit("commit", "-m", "add submodule")
+
+	files, err := GetDirtyFiles(dir)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	writeFixtureFile(t, dir, "vendor/lib/lib.sh", "echo changed\n")
+	files, err = GetDirtyFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vendor/lib/lib.sh"}, files)
+}
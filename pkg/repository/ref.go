@@ -0,0 +1,83 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+var commitSHARegex = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// IsCommitSHA returns true when ref is a full commit SHA
+func IsCommitSHA(ref string) bool {
+	return commitSHARegex.MatchString(ref)
+}
+
+// ResolveRemoteRef returns the SHA that the branch, tag or commit 'ref' points to in the remote repository,
+// without cloning it. An empty ref resolves the default branch of the repository
+func ResolveRemoteRef(ctx context.Context, repository, ref string) (string, error) {
+	if IsCommitSHA(ref) {
+		return ref, nil
+	}
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{repository},
+	})
+	refs, err := remote.ListContext(ctx, &git.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("could not list the references of '%s': %w", repository, err)
+	}
+	sha, err := resolveRef(refs, ref)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve '%s' in '%s': %w", ref, repository, err)
+	}
+	return sha, nil
+}
+
+// resolveRef looks for ref in the advertised references of a repository, branches take priority over tags.
+// Annotated tags resolve to the SHA of the tag object, which git dereferences to its commit on checkout
+func resolveRef(refs []*plumbing.Reference, ref string) (string, error) {
+	byName := map[plumbing.ReferenceName]*plumbing.Reference{}
+	for _, r := range refs {
+		byName[r.Name()] = r
+	}
+
+	candidates := []plumbing.ReferenceName{plumbing.HEAD}
+	if ref != "" {
+		candidates = []plumbing.ReferenceName{
+			plumbing.NewBranchReferenceName(ref),
+			plumbing.NewTagReferenceName(ref),
+			plumbing.ReferenceName(ref),
+		}
+	}
+
+	for _, name := range candidates {
+		r, ok := byName[name]
+		// symbolic references, like HEAD, point to another reference of the list
+		for depth := 0; ok && r.Type() == plumbing.SymbolicReference && depth < 10; depth++ {
+			r, ok = byName[r.Target()]
+		}
+		if ok && r.Type() == plumbing.HashReference {
+			return r.Hash().String(), nil
+		}
+	}
+	return "", fmt.Errorf("reference not found")
+}
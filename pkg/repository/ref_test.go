@@ -0,0 +1,99 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	mainSHA = "1111111111111111111111111111111111111111"
+	tagSHA  = "2222222222222222222222222222222222222222"
+	devSHA  = "3333333333333333333333333333333333333333"
+)
+
+func TestIsCommitSHA(t *testing.T) {
+	assert.True(t, IsCommitSHA(mainSHA))
+	assert.False(t, IsCommitSHA("main"))
+	assert.False(t, IsCommitSHA("1111111"))
+	assert.False(t, IsCommitSHA("111111111111111111111111111111111111111G"))
+}
+
+func TestResolveRef(t *testing.T) {
+	refs := []*plumbing.Reference{
+		plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("main")),
+		plumbing.NewHashReference(plumbing.NewBranchReferenceName("main"), plumbing.NewHash(mainSHA)),
+		plumbing.NewHashReference(plumbing.NewBranchReferenceName("dev"), plumbing.NewHash(devSHA)),
+		plumbing.NewHashReference(plumbing.NewTagReferenceName("v1.0.0"), plumbing.NewHash(tagSHA)),
+		plumbing.NewHashReference(plumbing.NewTagReferenceName("dev"), plumbing.NewHash(tagSHA)),
+	}
+	tt := []struct {
+		name        string
+		ref         string
+		expected    string
+		expectedErr bool
+	}{
+		{
+			name:     "default branch",
+			expected: mainSHA,
+		},
+		{
+			name:     "branch",
+			ref:      "main",
+			expected: mainSHA,
+		},
+		{
+			name:     "tag",
+			ref:      "v1.0.0",
+			expected: tagSHA,
+		},
+		{
+			name:     "branch takes priority over tag",
+			ref:      "dev",
+			expected: devSHA,
+		},
+		{
+			name:     "full reference name",
+			ref:      "refs/tags/dev",
+			expected: tagSHA,
+		},
+		{
+			name:        "not found",
+			ref:         "unknown",
+			expectedErr: true,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			sha, err := resolveRef(refs, tc.ref)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, sha)
+		})
+	}
+}
+
+func TestResolveRemoteRefWithCommitSHA(t *testing.T) {
+	sha, err := ResolveRemoteRef(context.Background(), "https://github.com/okteto/movies", mainSHA)
+	require.NoError(t, err)
+	assert.Equal(t, mainSHA, sha)
+}
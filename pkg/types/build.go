@@ -13,7 +13,10 @@
 
 package types
 
-import "github.com/okteto/okteto/pkg/model"
+import (
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+)
 
 // BuildOptions define the options available for build
 type BuildOptions struct {
@@ -34,6 +37,13 @@ type BuildOptions struct {
 	// CommandArgs comes from the user input on the command
 	CommandArgs  []string
 	EnableStages bool
+	// Renderer displays the build of ServiceName with one progress line per service
+	Renderer    *oktetoLog.BuildRenderer
+	ServiceName string
+	// SSH are the ssh agents exposed to the build, in the format id[=socket,...]
+	SSH []string
+	// ExpectedCLIVersion is the okteto version expected in a remote destroy, empty to skip the check
+	ExpectedCLIVersion string
 
 	Manifest *model.Manifest
 }
@@ -19,4 +19,6 @@ type ClusterMetadata struct {
 	ServerName             string
 	PipelineInstallerImage string
 	PipelineRunnerImage    string
+	// UploadArtifacts is the default policy for uploading the deploy artifacts
+	UploadArtifacts bool
 }
@@ -33,6 +33,7 @@ type UserInterface interface {
 	GetContext(ctx context.Context, ns string) (*UserContext, error)
 	GetClusterCertificate(ctx context.Context, cluster, ns string) ([]byte, error)
 	GetClusterMetadata(ctx context.Context, ns string) (ClusterMetadata, error)
+	GetTokenRole(ctx context.Context, ns string) (TokenRole, error)
 }
 
 // NamespaceInterface represents the client that connects to the namespace functions
@@ -0,0 +1,33 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// TokenRole is the permission level of an okteto token in a namespace
+type TokenRole string
+
+const (
+	// TokenRoleOwner can read and mutate the resources of the namespace and manage its members
+	TokenRoleOwner TokenRole = "owner"
+
+	// TokenRoleDeveloper can read and mutate the resources of the namespace
+	TokenRoleDeveloper TokenRole = "developer"
+
+	// TokenRoleViewer can only read the resources of the namespace
+	TokenRoleViewer TokenRole = "viewer"
+)
+
+// CanMutate returns if the role is allowed to create, update or delete resources
+func (r TokenRole) CanMutate() bool {
+	return r != TokenRoleViewer
+}
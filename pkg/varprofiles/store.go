@@ -0,0 +1,226 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package varprofiles stores the variables of okteto deploy so they can be reused by later deploys
+package varprofiles
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/okteto/okteto/pkg/config"
+	"github.com/spf13/afero"
+)
+
+// fileName is the file under OKTETO_HOME with the saved profiles
+const fileName = "vars.json"
+
+// ErrProfileNotFound is returned when there is no profile saved for a key
+var ErrProfileNotFound = errors.New("variables profile not found")
+
+var profileNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// Key identifies a profile. The variables of a profile are only reused by the deploys of the
+// same development environment in the same context and namespace
+type Key struct {
+	Context     string `json:"context"`
+	Namespace   string `json:"namespace"`
+	Environment string `json:"environment"`
+	Profile     string `json:"profile"`
+}
+
+// Variable is a saved variable. The value of the secret variables is never stored, they are
+// read from the environment when the profile is loaded
+type Variable struct {
+	Name   string `json:"name"`
+	Value  string `json:"value,omitempty"`
+	Secret bool   `json:"secret,omitempty"`
+}
+
+// Profile is a set of variables saved with 'okteto deploy --save-vars'
+type Profile struct {
+	Key
+	Variables []Variable `json:"variables"`
+	SavedAt   time.Time  `json:"savedAt"`
+}
+
+// Store reads and writes the profiles in a file only readable by the user
+type Store struct {
+	fs   afero.Fs
+	path func() string
+	lock func(func() error) error
+	now  func() time.Time
+}
+
+// NewStore returns the store of the profiles under OKTETO_HOME
+func NewStore(fs afero.Fs) *Store {
+	return &Store{
+		fs: fs,
+		path: func() string {
+			return filepath.Join(config.GetOktetoHome(), fileName)
+		},
+		lock: config.WithOktetoHomeLock,
+		now:  time.Now,
+	}
+}
+
+// ValidateProfileName returns an error if the name can't be used as profile name
+func ValidateProfileName(name string) error {
+	if !profileNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid profile name '%s': it must start with a letter or a number and only contain letters, numbers, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+// Get returns the profile saved for the key
+func (s *Store) Get(key Key) (*Profile, error) {
+	profiles, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	for i := range profiles {
+		if profiles[i].Key == key {
+			return &profiles[i], nil
+		}
+	}
+	return nil, ErrProfileNotFound
+}
+
+// Save stores the variables for the key, replacing the ones saved before
+func (s *Store) Save(key Key, variables []Variable) error {
+	if err := ValidateProfileName(key.Profile); err != nil {
+		return err
+	}
+	saved := make([]Variable, 0, len(variables))
+	for _, v := range variables {
+		if v.Secret {
+			v.Value = ""
+		}
+		saved = append(saved, v)
+	}
+	sort.Slice(saved, func(i, j int) bool {
+		return saved[i].Name < saved[j].Name
+	})
+	return s.lock(func() error {
+		profiles, err := s.read()
+		if err != nil {
+			return err
+		}
+		profile := Profile{Key: key, Variables: saved, SavedAt: s.now()}
+		replaced := false
+		for i := range profiles {
+			if profiles[i].Key == key {
+				profiles[i] = profile
+				replaced = true
+			}
+		}
+		if !replaced {
+			profiles = append(profiles, profile)
+		}
+		return s.write(profiles)
+	})
+}
+
+// List returns the saved profiles sorted by context, namespace, environment and profile name
+func (s *Store) List() ([]Profile, error) {
+	profiles, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		a, b := profiles[i].Key, profiles[j].Key
+		if a.Context != b.Context {
+			return a.Context < b.Context
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Environment != b.Environment {
+			return a.Environment < b.Environment
+		}
+		return a.Profile < b.Profile
+	})
+	return profiles, nil
+}
+
+// Delete removes the profiles named as filter.Profile. The empty fields of the filter match any value
+func (s *Store) Delete(filter Key) ([]Profile, error) {
+	var deleted []Profile
+	err := s.lock(func() error {
+		profiles, err := s.read()
+		if err != nil {
+			return err
+		}
+		kept := []Profile{}
+		for _, p := range profiles {
+			if matches(filter, p.Key) {
+				deleted = append(deleted, p)
+				continue
+			}
+			kept = append(kept, p)
+		}
+		if len(deleted) == 0 {
+			return nil
+		}
+		return s.write(kept)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(deleted) == 0 {
+		return nil, ErrProfileNotFound
+	}
+	return deleted, nil
+}
+
+func matches(filter, key Key) bool {
+	return filter.Profile == key.Profile &&
+		(filter.Context == "" || filter.Context == key.Context) &&
+		(filter.Namespace == "" || filter.Namespace == key.Namespace) &&
+		(filter.Environment == "" || filter.Environment == key.Environment)
+}
+
+// read returns the saved profiles. A missing file has no profiles
+func (s *Store) read() ([]Profile, error) {
+	content, err := afero.ReadFile(s.fs, s.path())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []Profile{}, nil
+		}
+		return nil, fmt.Errorf("failed to read the variables profiles: %w", err)
+	}
+	profiles := []Profile{}
+	if err := json.Unmarshal(content, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to read the variables profiles from '%s': %w", s.path(), err)
+	}
+	return profiles, nil
+}
+
+// write stores the profiles. The permissions are reset in case the file was created with others
+func (s *Store) write(profiles []Profile) error {
+	content, err := json.MarshalIndent(profiles, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := s.path()
+	if err := afero.WriteFile(s.fs, path, content, 0600); err != nil {
+		return fmt.Errorf("failed to write the variables profiles: %w", err)
+	}
+	return s.fs.Chmod(path, 0600)
+}
@@ -0,0 +1,173 @@
+// Copyright 2023 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package varprofiles
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(fs afero.Fs) *Store {
+	return &Store{
+		fs:   fs,
+		path: func() string { return "/home/.okteto/vars.json" },
+		lock: func(fn func() error) error { return fn() },
+		now:  func() time.Time { return time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC) },
+	}
+}
+
+func TestSaveAndGet(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s := newTestStore(fs)
+	key := Key{Context: "ctx", Namespace: "ns", Environment: "app", Profile: "staging"}
+
+	_, err := s.Get(key)
+	assert.ErrorIs(t, err, ErrProfileNotFound)
+
+	err = s.Save(key, []Variable{
+		{Name: "TOKEN", Value: "s3cr3t", Secret: true},
+		{Name: "REPLICAS", Value: "2"},
+	})
+	require.NoError(t, err)
+
+	p, err := s.Get(key)
+	require.NoError(t, err)
+	expected := []Variable{
+		{Name: "REPLICAS", Value: "2"},
+		{Name: "TOKEN", Secret: true},
+	}
+	assert.Equal(t, expected, p.Variables)
+
+	content, err := afero.ReadFile(fs, "/home/.okteto/vars.json")
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "s3cr3t")
+
+	info, err := fs.Stat("/home/.okteto/vars.json")
+	require.NoError(t, err)
+	assert.Equal(t, "-rw-------", info.Mode().String())
+
+	_, err = s.Get(Key{Context: "ctx", Namespace: "other", Environment: "app", Profile: "staging"})
+	assert.ErrorIs(t, err, ErrProfileNotFound)
+}
+
+func TestSaveReplacesProfile(t *testing.T) {
+	s := newTestStore(afero.NewMemMapFs())
+	key := Key{Context: "ctx", Namespace: "ns", Environment: "app", Profile: "staging"}
+	require.NoError(t, s.Save(key, []Variable{{Name: "A", Value: "1"}}))
+	require.NoError(t, s.Save(key, []Variable{{Name: "B", Value: "2"}}))
+
+	profiles, err := s.List()
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	assert.Equal(t, []Variable{{Name: "B", Value: "2"}}, profiles[0].Variables)
+}
+
+func TestSaveInvalidProfileName(t *testing.T) {
+	s := newTestStore(afero.NewMemMapFs())
+	assert.Error(t, s.Save(Key{Profile: "../staging"}, nil))
+	assert.Error(t, s.Save(Key{Profile: ""}, nil))
+}
+
+func TestSaveResetsPermissions(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/home/.okteto/vars.json", []byte("[]"), 0644))
+	s := newTestStore(fs)
+	require.NoError(t, s.Save(Key{Profile: "staging"}, nil))
+
+	info, err := fs.Stat("/home/.okteto/vars.json")
+	require.NoError(t, err)
+	assert.Equal(t, "-rw-------", info.Mode().String())
+}
+
+func TestReadCorruptedFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/home/.okteto/vars.json", []byte("{"), 0600))
+	s := newTestStore(fs)
+
+	_, err := s.List()
+	assert.Error(t, err)
+	assert.Error(t, s.Save(Key{Profile: "staging"}, nil))
+}
+
+func TestDelete(t *testing.T) {
+	keys := []Key{
+		{Context: "ctx", Namespace: "ns", Environment: "app", Profile: "staging"},
+		{Context: "ctx", Namespace: "other", Environment: "app", Profile: "staging"},
+		{Context: "ctx", Namespace: "ns", Environment: "app", Profile: "prod"},
+	}
+	tests := []struct {
+		name            string
+		filter          Key
+		expectedDeleted int
+		expectedErr     error
+	}{
+		{
+			name:            "all namespaces",
+			filter:          Key{Profile: "staging"},
+			expectedDeleted: 2,
+		},
+		{
+			name:            "one namespace",
+			filter:          Key{Namespace: "other", Profile: "staging"},
+			expectedDeleted: 1,
+		},
+		{
+			name:        "not found",
+			filter:      Key{Profile: "dev"},
+			expectedErr: ErrProfileNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore(afero.NewMemMapFs())
+			for _, k := range keys {
+				require.NoError(t, s.Save(k, nil))
+			}
+			deleted, err := s.Delete(tt.filter)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, deleted, tt.expectedDeleted)
+			profiles, err := s.List()
+			require.NoError(t, err)
+			assert.Len(t, profiles, len(keys)-tt.expectedDeleted)
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	s := newTestStore(afero.NewMemMapFs())
+	require.NoError(t, s.Save(Key{Context: "b", Namespace: "ns", Environment: "app", Profile: "staging"}, nil))
+	require.NoError(t, s.Save(Key{Context: "a", Namespace: "ns", Environment: "app", Profile: "staging"}, nil))
+	require.NoError(t, s.Save(Key{Context: "a", Namespace: "ns", Environment: "app", Profile: "dev"}, nil))
+
+	profiles, err := s.List()
+	require.NoError(t, err)
+	var keys []Key
+	for _, p := range profiles {
+		keys = append(keys, p.Key)
+	}
+	expected := []Key{
+		{Context: "a", Namespace: "ns", Environment: "app", Profile: "dev"},
+		{Context: "a", Namespace: "ns", Environment: "app", Profile: "staging"},
+		{Context: "b", Namespace: "ns", Environment: "app", Profile: "staging"},
+	}
+	assert.Equal(t, expected, keys)
+}